@@ -43,6 +43,8 @@ const (
 	Capture = "capture"
 	// Evolve indicates the binding is evolved by TiDB from old bindings.
 	Evolve = "evolve"
+	// Regression indicates the binding is created by TiDB automatically to pin the previous plan of a regressed statement.
+	Regression = "regression"
 	// Builtin indicates the binding is a builtin record for internal locking purpose. It is also the status for the builtin binding.
 	Builtin = "builtin"
 )
@@ -83,6 +83,12 @@ type BindHandle struct {
 
 	// pendingVerifyBindRecordMap indicates the pending verify bind records that found during query.
 	pendingVerifyBindRecordMap tmpBindRecordMap
+
+	// planRegressions records the plan regressions detected last time, which are used to report each regression once.
+	planRegressions struct {
+		sync.Mutex
+		reported map[string]struct{}
+	}
 }
 
 // Lease influences the duration of loading bind info and handling invalid bind.
@@ -428,9 +434,10 @@ func (h *BindHandle) GCBindRecord() (err error) {
 
 	// To make sure that all the deleted bind records have been acknowledged to all tidb,
 	// we only garbage collect those records with update_time before 10 leases.
+	// The deleted bind records for plan regressions are kept as the audit trail of the automatic decisions.
 	updateTime := time.Now().Add(-(10 * Lease))
 	updateTimeStr := types.NewTime(types.FromGoTime(updateTime), mysql.TypeTimestamp, 3).String()
-	_, err = exec.ExecuteInternal(context.TODO(), `DELETE FROM mysql.bind_info WHERE status = 'deleted' and update_time < %?`, updateTimeStr)
+	_, err = exec.ExecuteInternal(context.TODO(), `DELETE FROM mysql.bind_info WHERE status = 'deleted' and source != %? and update_time < %?`, Regression, updateTimeStr)
 	return err
 }
 
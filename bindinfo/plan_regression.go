@@ -0,0 +1,202 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/util/logutil"
+	utilparser "github.com/pingcap/tidb/util/parser"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"go.uber.org/zap"
+)
+
+const (
+	// planRegressionFactor is the factor to decide whether a plan regresses.
+	// The latest plan of a statement regresses if its average latency is at least `planRegressionFactor` times
+	// of a previous plan.
+	planRegressionFactor = 2.0
+	// planRegressionMinExecCount is the minimal execution count of the plans to compare, to avoid judging by
+	// a few occasional slow executions.
+	planRegressionMinExecCount = 5
+)
+
+// planRegression is a statement whose latest plan is slower than a previous plan.
+type planRegression struct {
+	regressed *stmtsummary.BindablePlanStats
+	previous  *stmtsummary.BindablePlanStats
+}
+
+func (r *planRegression) key() string {
+	return r.regressed.Schema + "\x00" + r.regressed.Digest + "\x00" + r.regressed.PlanDigest
+}
+
+func avgLatency(stats *stmtsummary.BindablePlanStats) time.Duration {
+	return stats.SumLatency / time.Duration(stats.ExecCount)
+}
+
+// detectPlanRegressions compares the latest plan of each statement with its previous plans, and returns the statements
+// whose latest plans regress, along with their fastest previous plans.
+func detectPlanRegressions(stats []*stmtsummary.BindablePlanStats) []planRegression {
+	type digestKey struct {
+		schema string
+		digest string
+	}
+	// A plan may be summarized in several summaries with different previous statements, so they are merged first.
+	plansByDigest := make(map[digestKey]map[string]*stmtsummary.BindablePlanStats)
+	for _, planStats := range stats {
+		if planStats.ExecCount == 0 {
+			continue
+		}
+		key := digestKey{schema: planStats.Schema, digest: planStats.Digest}
+		plans, ok := plansByDigest[key]
+		if !ok {
+			plans = make(map[string]*stmtsummary.BindablePlanStats)
+			plansByDigest[key] = plans
+		}
+		merged, ok := plans[planStats.PlanDigest]
+		if !ok {
+			copied := *planStats
+			plans[planStats.PlanDigest] = &copied
+			continue
+		}
+		merged.ExecCount += planStats.ExecCount
+		merged.SumLatency += planStats.SumLatency
+		if planStats.LastSeen.After(merged.LastSeen) {
+			merged.LastSeen = planStats.LastSeen
+			merged.BindableStmt = planStats.BindableStmt
+		}
+	}
+
+	var regressions []planRegression
+	for _, plans := range plansByDigest {
+		if len(plans) < 2 {
+			continue
+		}
+		var latest, previous *stmtsummary.BindablePlanStats
+		for _, plan := range plans {
+			if latest == nil || plan.LastSeen.After(latest.LastSeen) {
+				latest = plan
+			}
+		}
+		for _, plan := range plans {
+			if plan == latest || plan.PlanHint == "" {
+				continue
+			}
+			if previous == nil || avgLatency(plan) < avgLatency(previous) {
+				previous = plan
+			}
+		}
+		if previous != nil && float64(avgLatency(latest)) >= float64(avgLatency(previous))*planRegressionFactor {
+			regressions = append(regressions, planRegression{regressed: latest, previous: previous})
+		}
+	}
+	return regressions
+}
+
+// HandlePlanRegressions detects the statements whose latest plans are slower than their previous plans by the
+// statement summary. If autoBind is true, it creates bindings with the source `regression` to pin the previous plans.
+// The bindings can be listed by `SHOW GLOBAL BINDINGS` and undone by `DROP GLOBAL BINDING`. The undone bindings are
+// kept in mysql.bind_info as the audit trail, and the statements won't be bound automatically again.
+func (h *BindHandle) HandlePlanRegressions(autoBind bool) {
+	regressions := detectPlanRegressions(stmtsummary.StmtSummaryByDigestMap.GetBindablePlanStats(planRegressionMinExecCount))
+	h.planRegressions.Lock()
+	reported := h.planRegressions.reported
+	h.planRegressions.reported = make(map[string]struct{}, len(regressions))
+	for _, regression := range regressions {
+		h.planRegressions.reported[regression.key()] = struct{}{}
+	}
+	h.planRegressions.Unlock()
+
+	parser4Regression := parser.New()
+	for _, regression := range regressions {
+		regressed, previous := regression.regressed, regression.previous
+		// Only report the regressions once.
+		if _, ok := reported[regression.key()]; !ok {
+			metrics.PlanRegressionCounter.WithLabelValues("detected").Inc()
+			logutil.BgLogger().Warn("[sql-bind] plan regression detected",
+				zap.String("schema", regressed.Schema),
+				zap.String("digest", regressed.Digest),
+				zap.String("regressedPlanDigest", regressed.PlanDigest),
+				zap.Duration("regressedAvgLatency", avgLatency(regressed)),
+				zap.String("previousPlanDigest", previous.PlanDigest),
+				zap.Duration("previousAvgLatency", avgLatency(previous)))
+		}
+		if !autoBind {
+			continue
+		}
+		stmt, err := parser4Regression.ParseOneStmt(previous.Query, previous.Charset, previous.Collation)
+		if err != nil {
+			logutil.BgLogger().Debug("[sql-bind] parse SQL failed in plan regression handling", zap.String("SQL", previous.Query), zap.Error(err))
+			continue
+		}
+		dbName := utilparser.GetDefaultDB(stmt, previous.Schema)
+		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, previous.Query))
+		if r := h.GetBindRecord(digest.String(), normalizedSQL, dbName); r != nil && r.HasUsingBinding() {
+			continue
+		}
+		undone, err := h.isRegressionBindingUndone(normalizedSQL, dbName)
+		if err != nil {
+			logutil.BgLogger().Warn("[sql-bind] failed to load the plan regression bindings", zap.Error(err))
+			continue
+		}
+		if undone {
+			continue
+		}
+		bindSQL := GenerateBindSQL(context.TODO(), stmt, previous.PlanHint, true, dbName)
+		if bindSQL == "" {
+			continue
+		}
+		charset, collation := h.sctx.GetSessionVars().GetCharsetInfo()
+		binding := Binding{
+			BindSQL:   bindSQL,
+			Status:    Using,
+			Charset:   charset,
+			Collation: collation,
+			Source:    Regression,
+		}
+		// We don't need to pass the `sctx` because the BindSQL has been validated already.
+		err = h.CreateBindRecord(nil, &BindRecord{OriginalSQL: normalizedSQL, Db: dbName, Bindings: []Binding{binding}})
+		if err != nil {
+			logutil.BgLogger().Debug("[sql-bind] create bind record failed in plan regression handling", zap.String("SQL", previous.Query), zap.Error(err))
+			continue
+		}
+		metrics.PlanRegressionCounter.WithLabelValues("bound").Inc()
+		logutil.BgLogger().Info("[sql-bind] bind the previous plan for plan regression",
+			zap.String("digest", regressed.Digest),
+			zap.String("planDigest", previous.PlanDigest))
+	}
+}
+
+// isRegressionBindingUndone checks whether a binding created for the plan regression of the statement has been dropped.
+func (h *BindHandle) isRegressionBindingUndone(normalizedSQL, dbName string) (bool, error) {
+	exec := h.sctx.Context.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT 1 FROM mysql.bind_info WHERE original_sql = %? AND default_db = %? AND source = %? AND status = %? LIMIT 1`,
+		normalizedSQL, dbName, Regression, deleted)
+	if err != nil {
+		return false, err
+	}
+	// No need to acquire the session context lock for ExecRestrictedStmt, it
+	// uses another background session.
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return false, err
+	}
+	return len(rows) > 0, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPlanRegressions(t *testing.T) {
+	now := time.Now()
+	newStats := func(digest, planDigest string, execCount int64, avgLatency time.Duration, lastSeen time.Time) *stmtsummary.BindablePlanStats {
+		return &stmtsummary.BindablePlanStats{
+			BindableStmt: stmtsummary.BindableStmt{
+				Schema:   "test",
+				Query:    "select * from t where a = 1",
+				PlanHint: "use_index(@`sel_1` `test`.`t` `idx_" + planDigest + "`)",
+			},
+			Digest:     digest,
+			PlanDigest: planDigest,
+			ExecCount:  execCount,
+			SumLatency: avgLatency * time.Duration(execCount),
+			LastSeen:   lastSeen,
+		}
+	}
+
+	// A single plan never regresses.
+	regressions := detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		newStats("d1", "a", 10, time.Second, now),
+	})
+	require.Len(t, regressions, 0)
+
+	// The latest plan is not slow enough.
+	regressions = detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		newStats("d1", "a", 10, time.Second, now.Add(-time.Minute)),
+		newStats("d1", "b", 10, 1500*time.Millisecond, now),
+	})
+	require.Len(t, regressions, 0)
+
+	// The previous plan is slower than the latest one.
+	regressions = detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		newStats("d1", "a", 10, 3*time.Second, now.Add(-time.Minute)),
+		newStats("d1", "b", 10, time.Second, now),
+	})
+	require.Len(t, regressions, 0)
+
+	// The latest plan regresses, and the fastest previous plan is chosen.
+	regressions = detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		newStats("d1", "a", 10, 2*time.Second, now.Add(-2*time.Minute)),
+		newStats("d1", "b", 10, time.Second, now.Add(-time.Minute)),
+		newStats("d1", "c", 10, 3*time.Second, now),
+		newStats("d2", "a", 10, time.Second, now),
+	})
+	require.Len(t, regressions, 1)
+	require.Equal(t, "d1", regressions[0].regressed.Digest)
+	require.Equal(t, "c", regressions[0].regressed.PlanDigest)
+	require.Equal(t, "b", regressions[0].previous.PlanDigest)
+
+	// The summaries of the same plan are merged.
+	regressions = detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		newStats("d1", "a", 10, time.Second, now.Add(-2*time.Minute)),
+		newStats("d1", "b", 10, 3*time.Second, now.Add(-time.Minute)),
+		newStats("d1", "a", 30, 10*time.Second, now),
+	})
+	require.Len(t, regressions, 1)
+	require.Equal(t, "a", regressions[0].regressed.PlanDigest)
+	require.Equal(t, int64(40), regressions[0].regressed.ExecCount)
+	require.Equal(t, "b", regressions[0].previous.PlanDigest)
+
+	// The previous plan without hints can't be bound.
+	noHint := newStats("d1", "a", 10, time.Second, now.Add(-time.Minute))
+	noHint.PlanHint = ""
+	regressions = detectPlanRegressions([]*stmtsummary.BindablePlanStats{
+		noHint,
+		newStats("d1", "b", 10, 3*time.Second, now),
+	})
+	require.Len(t, regressions, 0)
+}
@@ -84,7 +84,6 @@ type tidbEncoder struct {
 	// the index of table columns for each data field.
 	// index == len(table.columns) means this field is `_tidb_rowid`
 	columnIdx []int
-	columnCnt int
 }
 
 type tidbBackend struct {
@@ -283,26 +282,34 @@ func getColumnByIndex(cols []*table.Column, index int) *table.Column {
 func (enc *tidbEncoder) Encode(logger log.Logger, row []types.Datum, _ int64, columnPermutation []int, path string, offset int64) (kv.Row, error) {
 	cols := enc.tbl.Cols()
 
-	if len(enc.columnIdx) == 0 {
-		columnCount := 0
-		columnIdx := make([]int, len(columnPermutation))
-		for i, idx := range columnPermutation {
-			if idx >= 0 {
-				columnIdx[idx] = i
-				columnCount++
-			}
+	// the columns of the absent values are omitted from the permutation, which
+	// thus may vary between the rows, so the indexes are computed for every row.
+	columnCount := 0
+	enc.columnIdx = enc.columnIdx[:0]
+	for range columnPermutation {
+		enc.columnIdx = append(enc.columnIdx, -1)
+	}
+	for i, idx := range columnPermutation {
+		if idx >= 0 {
+			enc.columnIdx[idx] = i
+			columnCount++
+		}
+	}
+	// the absent values are NULL without a column, which take the default values.
+	absentCount := 0
+	for i := 0; i < len(row) && i < len(enc.columnIdx); i++ {
+		if enc.columnIdx[i] < 0 && row[i].IsNull() {
+			absentCount++
 		}
-		enc.columnIdx = columnIdx
-		enc.columnCnt = columnCount
 	}
 
 	// TODO: since the column count doesn't exactly reflect the real column names, we only check the upper bound currently.
 	// See: tests/generated_columns/data/gencol.various_types.0.sql this sql has no columns, so encodeLoop will fill the
-	// column permutation with default, thus enc.columnCnt > len(row).
-	if len(row) > enc.columnCnt {
+	// column permutation with default, thus columnCount > len(row).
+	if len(row) > columnCount+absentCount {
 		logger.Error("column count mismatch", zap.Ints("column_permutation", columnPermutation),
 			zap.Array("data", kv.RowArrayMarshaler(row)))
-		return emptyTiDBRow, errors.Errorf("column count mismatch, expected %d, got %d", enc.columnCnt, len(row))
+		return emptyTiDBRow, errors.Errorf("column count mismatch, expected %d, got %d", columnCount, len(row))
 	}
 
 	var encoded strings.Builder
@@ -312,6 +319,10 @@ func (enc *tidbEncoder) Encode(logger log.Logger, row []types.Datum, _ int64, co
 		if i != 0 {
 			encoded.WriteByte(',')
 		}
+		if enc.columnIdx[i] < 0 {
+			encoded.WriteString("DEFAULT")
+			continue
+		}
 		datum := field
 		if err := enc.appendSQL(&encoded, &datum, getColumnByIndex(cols, enc.columnIdx[i])); err != nil {
 			logger.Error("tidb encode failed",
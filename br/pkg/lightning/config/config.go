@@ -436,6 +436,13 @@ type CSVConfig struct {
 	BackslashEscape bool   `toml:"backslash-escape" json:"backslash-escape"`
 }
 
+// JSONConfig is the config of the newline-delimited JSON source files.
+type JSONConfig struct {
+	// ColumnMapping maps the column names to the JSON paths of the values, e.g. `id = "$.user.id"`.
+	// Leave ColumnMapping empty will map the top-level keys of the JSON objects to the columns with the same name.
+	ColumnMapping map[string]string `toml:"column-mapping" json:"column-mapping"`
+}
+
 type MydumperRuntime struct {
 	ReadBlockSize    ByteSize         `toml:"read-block-size" json:"read-block-size"`
 	BatchSize        ByteSize         `toml:"batch-size" json:"batch-size"`
@@ -443,6 +450,7 @@ type MydumperRuntime struct {
 	SourceDir        string           `toml:"data-source-dir" json:"data-source-dir"`
 	CharacterSet     string           `toml:"character-set" json:"character-set"`
 	CSV              CSVConfig        `toml:"csv" json:"csv"`
+	JSON             JSONConfig       `toml:"json" json:"json"`
 	MaxRegionSize    ByteSize         `toml:"max-region-size" json:"max-region-size"`
 	Filter           []string         `toml:"filter" json:"filter"`
 	FileRouters      []*FileRouteRule `toml:"files" json:"files"`
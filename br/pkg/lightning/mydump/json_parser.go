@@ -0,0 +1,416 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/config"
+	"github.com/pingcap/tidb/br/pkg/lightning/worker"
+	"github.com/pingcap/tidb/types"
+	binaryJSON "github.com/pingcap/tidb/types/json"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// RowError is the error of a single row of the source file. The parser has
+// skipped the whole row when returning it, so the caller could record the
+// row and continue reading the next one.
+type RowError struct {
+	// RowText is the content of the malformed row.
+	RowText string
+	err     error
+}
+
+func newRowError(line []byte, err error) *RowError {
+	return &RowError{RowText: string(line), err: err}
+}
+
+func (e *RowError) Error() string {
+	return e.err.Error()
+}
+
+// jsonPathLeg is a leg of a JSON path, which is either an object key or an
+// array index.
+type jsonPathLeg struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the JSON path like `$.a."b c"[0]`.
+func parseJSONPath(path string) ([]jsonPathLeg, error) {
+	s := strings.TrimSpace(path)
+	if !strings.HasPrefix(s, "$") {
+		return nil, errors.Errorf("invalid JSON path '%s': must start with '$'", path)
+	}
+	s = s[1:]
+	legs := make([]jsonPathLeg, 0, 2)
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if strings.HasPrefix(s, `"`) {
+				end := 1
+				for end < len(s) && s[end] != '"' {
+					if s[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if end >= len(s) {
+					return nil, errors.Errorf("invalid JSON path '%s': unterminated quoted key", path)
+				}
+				key, err := strconv.Unquote(s[:end+1])
+				if err != nil {
+					return nil, errors.Errorf("invalid JSON path '%s': %s", path, err.Error())
+				}
+				legs = append(legs, jsonPathLeg{key: key})
+				s = s[end+1:]
+				continue
+			}
+			end := strings.IndexAny(s, ".[ \t")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, errors.Errorf("invalid JSON path '%s': empty key", path)
+			}
+			legs = append(legs, jsonPathLeg{key: s[:end]})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, errors.Errorf("invalid JSON path '%s': unterminated array index", path)
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(s[1:end]))
+			if err != nil || index < 0 {
+				return nil, errors.Errorf("invalid JSON path '%s': invalid array index '%s'", path, s[1:end])
+			}
+			legs = append(legs, jsonPathLeg{index: index, isIndex: true})
+			s = s[end+1:]
+		default:
+			return nil, errors.Errorf("invalid JSON path '%s': unexpected character '%c'", path, s[0])
+		}
+	}
+	if len(legs) == 0 {
+		return nil, errors.Errorf("invalid JSON path '%s': the path should contain at least one key", path)
+	}
+	return legs, nil
+}
+
+// JSONParser is a parser of the newline-delimited JSON files, in which every
+// line is a JSON object representing a row.
+//
+// Without the column mapping, the top-level keys of the objects are mapped to
+// the columns set by SetColumns, which are the columns of the target table when
+// restoring. The unknown keys cause a RowError. If the columns are not set, they
+// are determined by the keys of the first row.
+//
+// With the column mapping, the columns are the keys of the mapping, and the
+// values are extracted by the JSON paths.
+//
+// The columns of the missing keys or paths are marked in Row.Absent, so that
+// they take the default values rather than NULL. An explicit JSON null is NULL.
+type JSONParser struct {
+	blockParser
+
+	// mapping is the lower-case column name -> JSON path mapping.
+	mapping map[string][]jsonPathLeg
+	// paths are the JSON paths of the columns when the column mapping is
+	// configured.
+	paths [][]jsonPathLeg
+	// columnIndex is the lower-case column name -> index mapping when the
+	// column mapping is not configured.
+	columnIndex map[string]int
+}
+
+// NewJSONParser creates a JSON parser.
+func NewJSONParser(
+	cfg *config.JSONConfig,
+	reader ReadSeekCloser,
+	blockBufSize int64,
+	ioWorkers *worker.Pool,
+) (*JSONParser, error) {
+	parser := &JSONParser{
+		blockParser: makeBlockParser(reader, blockBufSize, ioWorkers),
+	}
+	if len(cfg.ColumnMapping) > 0 {
+		parser.mapping = make(map[string][]jsonPathLeg, len(cfg.ColumnMapping))
+		columns := make([]string, 0, len(cfg.ColumnMapping))
+		for column, path := range cfg.ColumnMapping {
+			legs, err := parseJSONPath(path)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid column mapping of column '%s'", column)
+			}
+			column = strings.ToLower(column)
+			parser.mapping[column] = legs
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		parser.SetColumns(columns)
+	}
+	return parser, nil
+}
+
+// SetColumns sets the restored column names to the parser.
+func (parser *JSONParser) SetColumns(columns []string) {
+	parser.columns = columns
+	if parser.mapping != nil {
+		parser.paths = make([][]jsonPathLeg, 0, len(columns))
+		for _, column := range columns {
+			// the columns not in the mapping are always absent.
+			parser.paths = append(parser.paths, parser.mapping[strings.ToLower(column)])
+		}
+		return
+	}
+	parser.columnIndex = make(map[string]int, len(columns))
+	for i, column := range columns {
+		parser.columnIndex[strings.ToLower(column)] = i
+	}
+}
+
+// readLine reads a line without the trailing "\n".
+func (parser *JSONParser) readLine() ([]byte, error) {
+	searched := 0
+	for {
+		if index := bytes.IndexByte(parser.buf[searched:], '\n'); index >= 0 {
+			line := parser.buf[:searched+index]
+			parser.buf = parser.buf[searched+index+1:]
+			parser.pos += int64(len(line) + 1)
+			return line, nil
+		}
+		searched = len(parser.buf)
+		if parser.isLastChunk {
+			if len(parser.buf) == 0 {
+				return nil, io.EOF
+			}
+			line := parser.buf
+			parser.buf = nil
+			parser.pos += int64(len(line))
+			return line, nil
+		}
+		if err := parser.readBlock(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ReadUntilTerminator seeks the file until the end of the current line, and
+// returns the file offset right after it.
+func (parser *JSONParser) ReadUntilTerminator() (int64, error) {
+	_, err := parser.readLine()
+	return parser.pos, err
+}
+
+// ReadRow reads a row from the datafile.
+func (parser *JSONParser) ReadRow() error {
+	row := &parser.lastRow
+	row.Length = 0
+	row.RowID++
+	row.Absent = nil
+
+	var line []byte
+	for len(line) == 0 {
+		startPos := parser.pos
+		l, err := parser.readLine()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if startPos == 0 {
+			l = bytes.TrimPrefix(l, utf8BOM)
+		}
+		line = bytes.TrimSpace(l)
+	}
+	row.Length = len(line)
+
+	keys, values, err := decodeJSONObject(line)
+	if err != nil {
+		return errors.Trace(newRowError(line, err))
+	}
+	if parser.columns == nil && parser.mapping == nil {
+		columns := make([]string, 0, len(keys))
+		for _, key := range keys {
+			columns = append(columns, strings.ToLower(key))
+		}
+		parser.SetColumns(columns)
+	}
+
+	row.Row = parser.acquireDatumSlice()
+	if cap(row.Row) >= len(parser.columns) {
+		row.Row = row.Row[:len(parser.columns)]
+	} else {
+		row.Row = make([]types.Datum, len(parser.columns))
+	}
+	absent := make([]bool, len(row.Row))
+	for i := range row.Row {
+		row.Row[i].SetNull()
+		absent[i] = true
+	}
+
+	if parser.mapping != nil {
+		object := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			object[key] = values[i]
+		}
+		for i, legs := range parser.paths {
+			if len(legs) == 0 {
+				continue
+			}
+			value, found, err := extractJSONPath(object, legs)
+			if err == nil && found {
+				err = setJSONDatum(&row.Row[i], value)
+				absent[i] = false
+			}
+			if err != nil {
+				return errors.Trace(newRowError(line, errors.Annotatef(err, "column '%s'", parser.columns[i])))
+			}
+		}
+		setAbsent(row, absent)
+		return nil
+	}
+
+	for i, key := range keys {
+		index, ok := parser.columnIndex[strings.ToLower(key)]
+		if !ok {
+			return errors.Trace(newRowError(line, errors.Errorf("unknown key '%s'", key)))
+		}
+		if err := setJSONDatum(&row.Row[index], values[i]); err != nil {
+			return errors.Trace(newRowError(line, errors.Annotatef(err, "key '%s'", key)))
+		}
+		absent[index] = false
+	}
+	setAbsent(row, absent)
+	return nil
+}
+
+// setAbsent sets the absent columns to the row, which is nil if all the columns
+// are present.
+func setAbsent(row *Row, absent []bool) {
+	row.Absent = nil
+	for _, a := range absent {
+		if a {
+			row.Absent = absent
+			return
+		}
+	}
+}
+
+// decodeJSONObject decodes the JSON object in the line, and returns its keys
+// and values in order.
+func decodeJSONObject(line []byte) (keys []string, values []interface{}, err error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, errors.New("the row is not a JSON object")
+	}
+	for decoder.More() {
+		tok, err = decoder.Token()
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, nil, errors.Errorf("unexpected token '%v', expecting a key", tok)
+		}
+		var value interface{}
+		if err = decoder.Decode(&value); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	// read the closing '}'.
+	if _, err = decoder.Token(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, nil, errors.New("unexpected data after the JSON object")
+	}
+	return keys, values, nil
+}
+
+// extractJSONPath extracts the value by the JSON path. It returns false if a
+// key is missing or an index is out of range.
+func extractJSONPath(object map[string]interface{}, legs []jsonPathLeg) (value interface{}, found bool, err error) {
+	value = object
+	for _, leg := range legs {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if leg.isIndex {
+				return nil, false, errors.Errorf("cannot extract array index %d from a JSON object", leg.index)
+			}
+			if value, found = v[leg.key]; !found {
+				return nil, false, nil
+			}
+		case []interface{}:
+			if !leg.isIndex {
+				return nil, false, errors.Errorf("cannot extract key '%s' from a JSON array", leg.key)
+			}
+			if leg.index >= len(v) {
+				return nil, false, nil
+			}
+			value = v[leg.index]
+		case nil:
+			return nil, false, nil
+		default:
+			return nil, false, errors.Errorf("cannot extract the nested value from the JSON scalar %v", v)
+		}
+	}
+	return value, true, nil
+}
+
+// setJSONDatum sets the decoded JSON value to the datum. The strings and
+// numbers are kept as strings, so that they can be casted to the column type
+// losslessly, and the objects and arrays are kept as JSON.
+func setJSONDatum(d *types.Datum, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		d.SetNull()
+	case bool:
+		if v {
+			d.SetInt64(1)
+		} else {
+			d.SetInt64(0)
+		}
+	case json.Number:
+		d.SetString(v.String(), "utf8mb4_bin")
+	case string:
+		d.SetString(v, "utf8mb4_bin")
+	default:
+		// the nested numbers may be out of the range of the binary JSON, so the
+		// value is converted through the text to get an error instead of a panic.
+		text, err := json.Marshal(v)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		bj, err := binaryJSON.ParseBinaryFromString(string(text))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.SetMysqlJSON(bj)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mydump_test
+
+import (
+	"context"
+	"io"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/lightning/config"
+	"github.com/pingcap/tidb/br/pkg/lightning/mydump"
+	"github.com/pingcap/tidb/br/pkg/lightning/worker"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+)
+
+var _ = Suite(&testMydumpJSONParserSuite{})
+
+type testMydumpJSONParserSuite struct {
+	ioWorkers *worker.Pool
+}
+
+func (s *testMydumpJSONParserSuite) SetUpSuite(c *C) {
+	s.ioWorkers = worker.NewPool(context.Background(), 5, "test_json")
+}
+func (s *testMydumpJSONParserSuite) TearDownSuite(c *C) {}
+
+func (s *testMydumpJSONParserSuite) newParser(c *C, cfg *config.JSONConfig, input string, blockBufSize int64) *mydump.JSONParser {
+	parser, err := mydump.NewJSONParser(cfg, mydump.NewStringReader(input), blockBufSize, s.ioWorkers)
+	c.Assert(err, IsNil)
+	return parser
+}
+
+func (s *testMydumpJSONParserSuite) TestTopLevelKeys(c *C) {
+	input := "\xef\xbb\xbf" + `{"ID": 1, "name": "alice", "score": 12.50, "vip": true, "tags": ["a", "b"]}` + "\r\n" +
+		"\n" +
+		`  {"name": "bob", "id": 2, "tags": null, "vip": false}` + "\n" +
+		`{"id": 18446744073709551616, "extra": {"k": "v"}}`
+
+	for _, blockBufSize := range []int64{1, 4, int64(config.ReadBlockSize)} {
+		parser := s.newParser(c, &config.JSONConfig{}, input, blockBufSize)
+
+		c.Assert(parser.ReadRow(), IsNil)
+		c.Assert(parser.Columns(), DeepEquals, []string{"id", "name", "score", "vip", "tags"})
+		c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{
+			types.NewStringDatum("1"),
+			types.NewStringDatum("alice"),
+			types.NewStringDatum("12.50"),
+			types.NewIntDatum(1),
+			types.NewJSONDatum(json.CreateBinary([]interface{}{"a", "b"})),
+		})
+		c.Assert(parser.LastRow().Absent, IsNil)
+		c.Assert(parser, posEq, 80, 1)
+
+		// blank lines are skipped, and the missing keys are absent.
+		c.Assert(parser.ReadRow(), IsNil)
+		c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{
+			types.NewStringDatum("2"),
+			types.NewStringDatum("bob"),
+			nullDatum,
+			types.NewIntDatum(0),
+			nullDatum,
+		})
+		c.Assert(parser.LastRow().Absent, DeepEquals, []bool{false, false, true, false, false})
+		c.Assert(parser, posEq, 136, 2)
+
+		// the unknown key makes the row malformed.
+		err := parser.ReadRow()
+		rowErr, ok := errors.Cause(err).(*mydump.RowError)
+		c.Assert(ok, IsTrue)
+		c.Assert(rowErr, ErrorMatches, "unknown key 'extra'")
+		c.Assert(rowErr.RowText, Equals, `{"id": 18446744073709551616, "extra": {"k": "v"}}`)
+		c.Assert(parser, posEq, 185, 3)
+
+		c.Assert(errors.Cause(parser.ReadRow()), Equals, io.EOF)
+	}
+}
+
+func (s *testMydumpJSONParserSuite) TestColumnMapping(c *C) {
+	cfg := &config.JSONConfig{
+		ColumnMapping: map[string]string{
+			"ID":      "$.id",
+			"name":    "$.user.name",
+			"tag":     "$.tags[1]",
+			"profile": `$.user."profile info"`,
+		},
+	}
+	input := `{"id": 1, "user": {"name": "alice", "profile info": {"age": 20}}, "tags": ["a", "b"], "ignored": 1}` + "\n" +
+		`{"id": 2, "user": {"name": "bob"}, "tags": ["c"]}` + "\n" +
+		`{"id": 3, "user": "carol"}` + "\n"
+
+	parser := s.newParser(c, cfg, input, int64(config.ReadBlockSize))
+	c.Assert(parser.Columns(), DeepEquals, []string{"id", "name", "profile", "tag"})
+
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{
+		types.NewStringDatum("1"),
+		types.NewStringDatum("alice"),
+		types.NewJSONDatum(json.CreateBinary(map[string]interface{}{"age": int64(20)})),
+		types.NewStringDatum("b"),
+	})
+
+	// the columns can be reordered, and the missing paths are absent.
+	parser.SetColumns([]string{"tag", "ID", "profile", "name"})
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{
+		nullDatum,
+		types.NewStringDatum("2"),
+		nullDatum,
+		types.NewStringDatum("bob"),
+	})
+	c.Assert(parser.LastRow().Absent, DeepEquals, []bool{true, false, true, false})
+
+	// extracting the nested value from a scalar makes the row malformed.
+	err := parser.ReadRow()
+	rowErr, ok := errors.Cause(err).(*mydump.RowError)
+	c.Assert(ok, IsTrue)
+	c.Assert(rowErr, ErrorMatches, "column 'profile': cannot extract the nested value from the JSON scalar carol")
+
+	c.Assert(errors.Cause(parser.ReadRow()), Equals, io.EOF)
+}
+
+func (s *testMydumpJSONParserSuite) TestInvalidColumnMapping(c *C) {
+	for _, path := range []string{"id", "$", "$.", "$.a[", "$.a[-1]", `$."a`, "$.a b", "$[x]"} {
+		cfg := &config.JSONConfig{ColumnMapping: map[string]string{"a": path}}
+		_, err := mydump.NewJSONParser(cfg, mydump.NewStringReader(""), int64(config.ReadBlockSize), s.ioWorkers)
+		c.Assert(err, ErrorMatches, "invalid column mapping of column 'a': invalid JSON path.*", Commentf("path = %s", path))
+	}
+}
+
+func (s *testMydumpJSONParserSuite) TestMalformedRows(c *C) {
+	input := `{"a": 1, "b": 2}` + "\n" +
+		`{"a": 1, "b": }` + "\n" +
+		`[1, 2]` + "\n" +
+		`{"a": 1} {"a": 2}` + "\n" +
+		`"a"` + "\n" +
+		`{"b": 3, "a": 4}` + "\n"
+
+	parser := s.newParser(c, &config.JSONConfig{}, input, int64(config.ReadBlockSize))
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{types.NewStringDatum("1"), types.NewStringDatum("2")})
+
+	for _, expected := range []string{
+		".*invalid character '}' looking for beginning of value",
+		"the row is not a JSON object",
+		"unexpected data after the JSON object",
+		"the row is not a JSON object",
+	} {
+		err := parser.ReadRow()
+		rowErr, ok := errors.Cause(err).(*mydump.RowError)
+		c.Assert(ok, IsTrue, Commentf("err = %v", err))
+		c.Assert(rowErr, ErrorMatches, expected)
+	}
+
+	// the parser continues to read the rows after the malformed ones.
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{types.NewStringDatum("4"), types.NewStringDatum("3")})
+	c.Assert(parser, posEq, len(input), 6)
+	c.Assert(errors.Cause(parser.ReadRow()), Equals, io.EOF)
+}
+
+func (s *testMydumpJSONParserSuite) TestOutOfRangeNestedNumber(c *C) {
+	input := `{"id": 1, "doc": {"x": 1e400}}` + "\n" +
+		`{"id": 2, "doc": [1e10, -18446744073709551616]}` + "\n"
+
+	parser := s.newParser(c, &config.JSONConfig{}, input, int64(config.ReadBlockSize))
+	parser.SetColumns([]string{"id", "doc"})
+
+	// the out-of-range nested number makes the row malformed instead of panicking.
+	err := parser.ReadRow()
+	rowErr, ok := errors.Cause(err).(*mydump.RowError)
+	c.Assert(ok, IsTrue, Commentf("err = %v", err))
+	c.Assert(rowErr, ErrorMatches, "key 'doc': .*")
+	c.Assert(rowErr.RowText, Equals, `{"id": 1, "doc": {"x": 1e400}}`)
+
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{
+		types.NewStringDatum("2"),
+		types.NewJSONDatum(json.CreateBinary([]interface{}{float64(1e10), float64(-18446744073709551616)})),
+	})
+	c.Assert(errors.Cause(parser.ReadRow()), Equals, io.EOF)
+}
+
+func (s *testMydumpJSONParserSuite) TestReadUntilTerminator(c *C) {
+	input := `{"a": "x"}` + "\n" + `{"a": "y"}` + "\n" + `{"a": "z"}`
+
+	parser := s.newParser(c, &config.JSONConfig{}, input, int64(config.ReadBlockSize))
+	c.Assert(parser.SetPos(3, 10), IsNil)
+	pos, err := parser.ReadUntilTerminator()
+	c.Assert(err, IsNil)
+	c.Assert(pos, Equals, int64(11))
+
+	c.Assert(parser.ReadRow(), IsNil)
+	c.Assert(parser.LastRow().Row, DeepEquals, []types.Datum{types.NewStringDatum("y")})
+	c.Assert(parser, posEq, 22, 11)
+
+	pos, err = parser.ReadUntilTerminator()
+	c.Assert(err, IsNil)
+	c.Assert(pos, Equals, int64(len(input)))
+	_, err = parser.ReadUntilTerminator()
+	c.Assert(errors.Cause(err), Equals, io.EOF)
+}
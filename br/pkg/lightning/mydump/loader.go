@@ -289,6 +289,9 @@ func (s *mdLoaderSetup) listFiles(ctx context.Context, store storage.ExternalSto
 			logger.Info("[loader] file is filtered by file router")
 			return nil
 		}
+		if res.Type == SourceTypeParquet && res.Compression != CompressionNone {
+			return errors.Errorf("compressed parquet file '%s' is not supported", path)
+		}
 
 		info := FileInfo{
 			TableName: filter.Table{Schema: res.Schema, Name: res.Name},
@@ -308,7 +311,7 @@ func (s *mdLoaderSetup) listFiles(ctx context.Context, store storage.ExternalSto
 			s.tableSchemas = append(s.tableSchemas, info)
 		case SourceTypeViewSchema:
 			s.viewSchemas = append(s.viewSchemas, info)
-		case SourceTypeSQL, SourceTypeCSV, SourceTypeParquet:
+		case SourceTypeSQL, SourceTypeCSV, SourceTypeParquet, SourceTypeJSON:
 			s.tableDatas = append(s.tableDatas, info)
 		}
 
@@ -324,6 +324,72 @@ func (s *testMydumpLoaderSuite) TestTablesWithDots(c *C) {
 	}})
 }
 
+func (s *testMydumpLoaderSuite) TestCompressedFiles(c *C) {
+	s.touch(c, "db-schema-create.sql.gz")
+	s.touch(c, "db.tbl-schema.sql.zst")
+	s.touch(c, "db.tbl.0001.sql.gz")
+	s.touch(c, "db.tbl.0002.sql.snappy")
+	s.touch(c, "db.tbl.0003.sql.zstd")
+
+	// insert some files which we're going to ignore.
+	s.touch(c, "db.tbl-schema-trigger.sql.gz")
+	s.touch(c, "db.tbl-schema-post.sql.zst")
+	s.touch(c, "db.tbl.0004.sql.bak")
+
+	mdl, err := md.NewMyDumpLoader(context.Background(), s.cfg)
+	c.Assert(err, IsNil)
+	tableName := filter.Table{Schema: "db", Name: "tbl"}
+	c.Assert(mdl.GetDatabases(), DeepEquals, []*md.MDDatabaseMeta{{
+		Name:       "db",
+		SchemaFile: "db-schema-create.sql.gz",
+		Tables: []*md.MDTableMeta{{
+			DB:         "db",
+			Name:       "tbl",
+			SchemaFile: md.FileInfo{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl-schema.sql.zst", Type: md.SourceTypeTableSchema, Compression: md.CompressionZStd}},
+			DataFiles: []md.FileInfo{
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0001.sql.gz", Type: md.SourceTypeSQL, Compression: md.CompressionGZ, SortKey: "0001"}},
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0002.sql.snappy", Type: md.SourceTypeSQL, Compression: md.CompressionSnappy, SortKey: "0002"}},
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0003.sql.zstd", Type: md.SourceTypeSQL, Compression: md.CompressionZStd, SortKey: "0003"}},
+			},
+			IsRowOrdered: true,
+			IndexRatio:   0.0,
+		}},
+	}})
+
+	// compressed parquet files are not supported.
+	s.touch(c, "db.tbl.0005.parquet.gz")
+	_, err = md.NewMyDumpLoader(context.Background(), s.cfg)
+	c.Assert(err, ErrorMatches, ".*compressed parquet file 'db.tbl.0005.parquet.gz' is not supported")
+}
+
+func (s *testMydumpLoaderSuite) TestJSONFiles(c *C) {
+	s.touch(c, "db-schema-create.sql")
+	s.touch(c, "db.tbl-schema.sql")
+	s.touch(c, "db.tbl.0001.json")
+	s.touch(c, "db.tbl.0002.ndjson")
+	s.touch(c, "db.tbl.0003.jsonl.gz")
+
+	mdl, err := md.NewMyDumpLoader(context.Background(), s.cfg)
+	c.Assert(err, IsNil)
+	tableName := filter.Table{Schema: "db", Name: "tbl"}
+	c.Assert(mdl.GetDatabases(), DeepEquals, []*md.MDDatabaseMeta{{
+		Name:       "db",
+		SchemaFile: "db-schema-create.sql",
+		Tables: []*md.MDTableMeta{{
+			DB:         "db",
+			Name:       "tbl",
+			SchemaFile: md.FileInfo{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl-schema.sql", Type: md.SourceTypeTableSchema}},
+			DataFiles: []md.FileInfo{
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0001.json", Type: md.SourceTypeJSON, SortKey: "0001"}},
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0002.ndjson", Type: md.SourceTypeJSON, SortKey: "0002"}},
+				{TableName: tableName, FileMeta: md.SourceFileMeta{Path: "db.tbl.0003.jsonl.gz", Type: md.SourceTypeJSON, Compression: md.CompressionGZ, SortKey: "0003"}},
+			},
+			IsRowOrdered: true,
+			IndexRatio:   0.0,
+		}},
+	}})
+}
+
 func (s *testMydumpLoaderSuite) TestRouter(c *C) {
 	s.cfg.Routes = []*router.TableRule{
 		{
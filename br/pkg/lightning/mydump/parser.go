@@ -95,6 +95,10 @@ type Row struct {
 	RowID  int64
 	Row    []types.Datum
 	Length int
+	// Absent marks the values which are absent from the source row, whose
+	// columns should take the default values. It is nil if all the values are
+	// present.
+	Absent []bool
 }
 
 // MarshalLogArray implements the zapcore.ArrayMarshaler interface
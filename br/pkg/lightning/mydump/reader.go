@@ -67,8 +67,27 @@ func decodeCharacterSet(data []byte, characterSet string) ([]byte, error) {
 	return data, nil
 }
 
+// OpenReader opens a reader of the source file. The compressed file is decompressed by the reader, and the offsets
+// used to seek the reader are the offsets in the decompressed data.
+func OpenReader(ctx context.Context, fileMeta SourceFileMeta, store storage.ExternalStorage) (storage.ReadSeekCloser, error) {
+	switch {
+	case fileMeta.Type == SourceTypeParquet:
+		return OpenParquetReader(ctx, store, fileMeta.Path, fileMeta.FileSize)
+	case fileMeta.Compression != CompressionNone:
+		compressType, err := ToStorageCompressType(fileMeta.Compression)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := storage.WithCompression(store, compressType).Open(ctx, fileMeta.Path)
+		return reader, errors.Trace(err)
+	default:
+		reader, err := store.Open(ctx, fileMeta.Path)
+		return reader, errors.Trace(err)
+	}
+}
+
 func ExportStatement(ctx context.Context, store storage.ExternalStorage, sqlFile FileInfo, characterSet string) ([]byte, error) {
-	fd, err := store.Open(ctx, sqlFile.FileMeta.Path)
+	fd, err := OpenReader(ctx, sqlFile.FileMeta, store)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -179,3 +179,33 @@ func (s *testMydumpReaderSuite) TestExportStatementHandleNonEOFError(c *C) {
 	_, err := ExportStatement(ctx, mockStorage, f, "auto")
 	c.Assert(err, ErrorMatches, "read error")
 }
+
+func (s *testMydumpReaderSuite) TestExportStatementCompressed(c *C) {
+	dir := c.MkDir()
+	store, err := storage.NewLocalStorage(dir)
+	c.Assert(err, IsNil)
+	ctx := context.Background()
+
+	compressions := map[string]Compression{
+		"gz":     CompressionGZ,
+		"zst":    CompressionZStd,
+		"snappy": CompressionSnappy,
+	}
+	for ext, compression := range compressions {
+		compressType, err := ToStorageCompressType(compression)
+		c.Assert(err, IsNil)
+		fileName := "db.tbl-schema.sql." + ext
+		err = storage.WithCompression(store, compressType).WriteFile(ctx, fileName, []byte("CREATE TABLE whatever(a int);\n"))
+		c.Assert(err, IsNil)
+		stat, err := os.Stat(filepath.Join(dir, fileName))
+		c.Assert(err, IsNil)
+
+		f := FileInfo{FileMeta: SourceFileMeta{Path: fileName, Compression: compression, FileSize: stat.Size()}}
+		data, err := ExportStatement(ctx, store, f, "auto")
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, []byte("CREATE TABLE whatever(a int);"))
+	}
+
+	_, err = ToStorageCompressType(CompressionLZ4)
+	c.Assert(err, NotNil)
+}
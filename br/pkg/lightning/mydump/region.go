@@ -34,6 +34,8 @@ const (
 	tableRegionSizeWarningThreshold int64 = 1024 * 1024 * 1024
 	// the increment ratio of large CSV file size threshold by `region-split-size`
 	largeCSVLowerThresholdRation = 10
+	// TableFileSizeINF is the end offset of the regions of the compressed files, which are read until EOF.
+	TableFileSizeINF = math.MaxInt64 / 2
 )
 
 type TableRegion struct {
@@ -270,29 +272,45 @@ func makeSourceFileRegion(
 	if !isCsvFile {
 		divisor += 2
 	}
+	isCompressed := fi.FileMeta.Compression != CompressionNone
 	// If a csv file is overlarge, we need to split it into multiple regions.
 	// Note: We can only split a csv file whose format is strict.
+	// A newline-delimited JSON file can always be split since a JSON object never contains a raw newline.
 	// We increase the check threshold by 1/10 of the `max-region-size` because the source file size dumped by tools
 	// like dumpling might be slight exceed the threshold when it is equal `max-region-size`, so we can
 	// avoid split a lot of small chunks.
-	if isCsvFile && cfg.Mydumper.StrictFormat && dataFileSize > int64(cfg.Mydumper.MaxRegionSize+cfg.Mydumper.MaxRegionSize/largeCSVLowerThresholdRation) {
+	// A compressed file can't be split because the offsets in the decompressed data can't be located directly.
+	canSplit := (isCsvFile && cfg.Mydumper.StrictFormat) || fi.FileMeta.Type == SourceTypeJSON
+	if canSplit && !isCompressed && dataFileSize > int64(cfg.Mydumper.MaxRegionSize+cfg.Mydumper.MaxRegionSize/largeCSVLowerThresholdRation) {
 		_, regions, subFileSizes, err := SplitLargeFile(ctx, meta, cfg, fi, divisor, 0, ioWorkers, store)
 		return regions, subFileSizes, err
 	}
 
+	endOffset := fi.FileMeta.FileSize
+	rowIDMax := fi.FileMeta.FileSize / divisor
+	// The compressed file is read until EOF, and its rows are estimated by the size of the decompressed data
+	// since the compression ratio is unbounded.
+	if isCompressed {
+		decompressedSize, err := getDecompressedSize(ctx, fi.FileMeta, ioWorkers, store)
+		if err != nil {
+			return nil, nil, err
+		}
+		endOffset = TableFileSizeINF
+		rowIDMax = decompressedSize / divisor
+	}
 	tableRegion := &TableRegion{
 		DB:       meta.DB,
 		Table:    meta.Name,
 		FileMeta: fi.FileMeta,
 		Chunk: Chunk{
 			Offset:       0,
-			EndOffset:    fi.FileMeta.FileSize,
+			EndOffset:    endOffset,
 			PrevRowIDMax: 0,
-			RowIDMax:     fi.FileMeta.FileSize / divisor,
+			RowIDMax:     rowIDMax,
 		},
 	}
 
-	if tableRegion.Size() > tableRegionSizeWarningThreshold {
+	if dataFileSize > tableRegionSizeWarningThreshold {
 		log.L().Warn(
 			"file is too big to be processed efficiently; we suggest splitting it at 256 MB each",
 			zap.String("file", fi.FileMeta.Path),
@@ -301,6 +319,25 @@ func makeSourceFileRegion(
 	return []*TableRegion{tableRegion}, []float64{float64(fi.FileMeta.FileSize)}, nil
 }
 
+// getDecompressedSize reads the compressed file through to get the size of the decompressed data.
+func getDecompressedSize(
+	ctx context.Context,
+	fileMeta SourceFileMeta,
+	ioWorkers *worker.Pool,
+	store storage.ExternalStorage,
+) (int64, error) {
+	reader, err := OpenReader(ctx, fileMeta, store)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	w := ioWorkers.Apply()
+	defer ioWorkers.Recycle(w)
+	size, err := io.Copy(io.Discard, reader)
+	return size, errors.Annotatef(err, "decompress file '%s' failed", fileMeta.Path)
+}
+
 // because parquet files can't seek efficiently, there is no benefit in split.
 // parquet file are column orient, so the offset is read line number
 func makeParquetFileRegion(
@@ -333,8 +370,15 @@ func makeParquetFileRegion(
 	return rowIDMax, region, nil
 }
 
-// SplitLargeFile splits a large csv file into multiple regions, the size of
-// each regions is specified by `config.MaxRegionSize`.
+// terminatorParser is a parser which can skip to the end of the current row.
+type terminatorParser interface {
+	Parser
+	ReadUntilTerminator() (int64, error)
+}
+
+// SplitLargeFile splits a large csv or newline-delimited JSON file into
+// multiple regions, the size of each regions is specified by
+// `config.MaxRegionSize`.
 // Note: We split the file coarsely, thus the format of csv file is needed to be
 // strict.
 // e.g.
@@ -354,7 +398,7 @@ func SplitLargeFile(
 	dataFileSizes = make([]float64, 0, dataFile.FileMeta.FileSize/maxRegionSize+1)
 	startOffset, endOffset := int64(0), maxRegionSize
 	var columns []string
-	if cfg.Mydumper.CSV.Header {
+	if dataFile.FileMeta.Type == SourceTypeCSV && cfg.Mydumper.CSV.Header {
 		r, err := store.Open(ctx, dataFile.FileMeta.Path)
 		if err != nil {
 			return 0, nil, nil, err
@@ -386,12 +430,18 @@ func SplitLargeFile(
 			if err != nil {
 				return 0, nil, nil, err
 			}
-			// Create a utf8mb4 convertor to encode and decode data with the charset of CSV files.
-			charsetConvertor, err := NewCharsetConvertor(cfg.Mydumper.DataCharacterSet, cfg.Mydumper.DataInvalidCharReplace)
-			if err != nil {
-				return 0, nil, nil, err
+			var parser terminatorParser
+			if dataFile.FileMeta.Type == SourceTypeJSON {
+				parser, err = NewJSONParser(&cfg.Mydumper.JSON, r, int64(cfg.Mydumper.ReadBlockSize), ioWorker)
+			} else {
+				// Create a utf8mb4 convertor to encode and decode data with the charset of CSV files.
+				var charsetConvertor *CharsetConvertor
+				charsetConvertor, err = NewCharsetConvertor(cfg.Mydumper.DataCharacterSet, cfg.Mydumper.DataInvalidCharReplace)
+				if err != nil {
+					return 0, nil, nil, err
+				}
+				parser, err = NewCSVParser(&cfg.Mydumper.CSV, r, int64(cfg.Mydumper.ReadBlockSize), ioWorker, false, charsetConvertor)
 			}
-			parser, err := NewCSVParser(&cfg.Mydumper.CSV, r, int64(cfg.Mydumper.ReadBlockSize), ioWorker, false, charsetConvertor)
 			if err != nil {
 				return 0, nil, nil, err
 			}
@@ -15,6 +15,7 @@
 package mydump_test
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -387,3 +388,106 @@ func (s *testMydumpRegionSuite) TestSplitLargeFileOnlyOneChunk(c *C) {
 		c.Assert(regions[i].Chunk.Columns, DeepEquals, columns)
 	}
 }
+
+func (s *testMydumpRegionSuite) TestCompressedFileRegion(c *C) {
+	dir := c.MkDir()
+	store, err := storage.NewLocalStorage(dir)
+	c.Assert(err, IsNil)
+	ctx := context.Background()
+
+	fileName := "csv.large_csv_file.csv.gz"
+	content := []byte("123,456\r\n789,101\r\n234,567\r\n")
+	err = storage.WithCompression(store, storage.Gzip).WriteFile(ctx, fileName, content)
+	c.Assert(err, IsNil)
+	dataFileInfo, err := os.Stat(filepath.Join(dir, fileName))
+	c.Assert(err, IsNil)
+	fileSize := dataFileInfo.Size()
+
+	meta := &MDTableMeta{
+		DB:   "csv",
+		Name: "large_csv_file",
+		DataFiles: []FileInfo{{FileMeta: SourceFileMeta{
+			Path:        fileName,
+			Type:        SourceTypeCSV,
+			Compression: CompressionGZ,
+			FileSize:    fileSize,
+		}}},
+	}
+	cfg := &config.Config{
+		Mydumper: config.MydumperRuntime{
+			ReadBlockSize: config.ReadBlockSize,
+			CSV: config.CSVConfig{
+				Separator:       ",",
+				Null:            "NULL",
+				BackslashEscape: true,
+			},
+			StrictFormat:  true,
+			Filter:        []string{"*.*"},
+			MaxRegionSize: 5,
+		},
+	}
+	ioWorkers := worker.NewPool(ctx, 4, "io")
+
+	// the compressed file is never split, and it's read until EOF.
+	regions, err := MakeTableRegions(ctx, meta, 2, cfg, ioWorkers, store)
+	c.Assert(err, IsNil)
+	c.Assert(regions, HasLen, 1)
+	c.Assert(regions[0].Chunk.Offset, Equals, int64(0))
+	c.Assert(regions[0].Chunk.EndOffset, Equals, int64(TableFileSizeINF))
+	c.Assert(regions[0].Chunk.RowIDMax, Greater, int64(3))
+
+	// the rows of the file compressed better than 100x are still covered by the row IDs.
+	content = bytes.Repeat([]byte("1,1\r\n"), 100000)
+	err = storage.WithCompression(store, storage.Gzip).WriteFile(ctx, fileName, content)
+	c.Assert(err, IsNil)
+	dataFileInfo, err = os.Stat(filepath.Join(dir, fileName))
+	c.Assert(err, IsNil)
+	c.Assert(dataFileInfo.Size()*100, Less, int64(len(content)))
+	meta.DataFiles[0].FileMeta.FileSize = dataFileInfo.Size()
+
+	regions, err = MakeTableRegions(ctx, meta, 2, cfg, ioWorkers, store)
+	c.Assert(err, IsNil)
+	c.Assert(regions, HasLen, 1)
+	c.Assert(regions[0].Chunk.RowIDMax, GreaterEqual, int64(100000))
+}
+
+func (s *testMydumpRegionSuite) TestJSONFileRegion(c *C) {
+	dir := c.MkDir()
+	store, err := storage.NewLocalStorage(dir)
+	c.Assert(err, IsNil)
+	ctx := context.Background()
+
+	fileName := "json.large_json_file.json"
+	content := []byte(`{"a":1}` + "\n" + `{"a":2}` + "\n" + `{"a":3}` + "\n" + `{"a":4}` + "\n" + `{"a":5}` + "\n")
+	err = store.WriteFile(ctx, fileName, content)
+	c.Assert(err, IsNil)
+
+	meta := &MDTableMeta{
+		DB:   "json",
+		Name: "large_json_file",
+		DataFiles: []FileInfo{{FileMeta: SourceFileMeta{
+			Path:     fileName,
+			Type:     SourceTypeJSON,
+			FileSize: int64(len(content)),
+		}}},
+	}
+	cfg := &config.Config{
+		Mydumper: config.MydumperRuntime{
+			ReadBlockSize: config.ReadBlockSize,
+			Filter:        []string{"*.*"},
+			MaxRegionSize: 10,
+		},
+	}
+	ioWorkers := worker.NewPool(ctx, 4, "io")
+
+	// the JSON file is split at the line boundaries even if the format is not strict.
+	regions, err := MakeTableRegions(ctx, meta, 1, cfg, ioWorkers, store)
+	c.Assert(err, IsNil)
+	offsets := [][]int64{{0, 16}, {16, 32}, {32, 40}}
+	c.Assert(regions, HasLen, len(offsets))
+	for i := range offsets {
+		c.Assert(regions[i].Chunk.Offset, Equals, offsets[i][0])
+		c.Assert(regions[i].Chunk.EndOffset, Equals, offsets[i][1])
+		c.Assert(regions[i].Chunk.Columns, IsNil)
+	}
+}
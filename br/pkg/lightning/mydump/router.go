@@ -8,6 +8,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb-tools/pkg/filter"
 	"github.com/pingcap/tidb/br/pkg/lightning/config"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/util/slice"
 )
 
@@ -21,6 +22,7 @@ const (
 	SourceTypeCSV
 	SourceTypeParquet
 	SourceTypeViewSchema
+	SourceTypeJSON
 )
 
 const (
@@ -30,6 +32,7 @@ const (
 	TypeSQL      = "sql"
 	TypeCSV      = "csv"
 	TypeParquet  = "parquet"
+	TypeJSON     = "json"
 	TypeIgnore   = "ignore"
 )
 
@@ -41,6 +44,7 @@ const (
 	CompressionLZ4
 	CompressionZStd
 	CompressionXZ
+	CompressionSnappy
 )
 
 func parseSourceType(t string) (SourceType, error) {
@@ -55,6 +59,8 @@ func parseSourceType(t string) (SourceType, error) {
 		return SourceTypeCSV, nil
 	case TypeParquet:
 		return SourceTypeParquet, nil
+	case TypeJSON, "ndjson", "jsonl":
+		return SourceTypeJSON, nil
 	case TypeIgnore:
 		return SourceTypeIgnore, nil
 	case ViewSchema:
@@ -76,6 +82,8 @@ func (s SourceType) String() string {
 		return TypeSQL
 	case SourceTypeParquet:
 		return TypeParquet
+	case SourceTypeJSON:
+		return TypeJSON
 	case SourceTypeViewSchema:
 		return ViewSchema
 	default:
@@ -85,14 +93,16 @@ func (s SourceType) String() string {
 
 func parseCompressionType(t string) (Compression, error) {
 	switch strings.ToLower(strings.TrimSpace(t)) {
-	case "gz":
+	case "gz", "gzip":
 		return CompressionGZ, nil
 	case "lz4":
 		return CompressionLZ4, nil
-	case "zstd":
+	case "zstd", "zst":
 		return CompressionZStd, nil
 	case "xz":
 		return CompressionXZ, nil
+	case "snappy":
+		return CompressionSnappy, nil
 	case "":
 		return CompressionNone, nil
 	default:
@@ -100,19 +110,35 @@ func parseCompressionType(t string) (Compression, error) {
 	}
 }
 
+// ToStorageCompressType converts the compression of the source file to the compression type of the external storage.
+func ToStorageCompressType(compression Compression) (storage.CompressType, error) {
+	switch compression {
+	case CompressionGZ:
+		return storage.Gzip, nil
+	case CompressionZStd:
+		return storage.Zstd, nil
+	case CompressionSnappy:
+		return storage.Snappy, nil
+	case CompressionNone:
+		return storage.NoCompression, nil
+	default:
+		return storage.NoCompression, errors.Errorf("compression %d doesn't have related storage compressType", compression)
+	}
+}
+
 var expandVariablePattern = regexp.MustCompile(`\$(?:\$|[\pL\p{Nd}_]+|\{[\pL\p{Nd}_]+\})`)
 
 var defaultFileRouteRules = []*config.FileRouteRule{
 	// ignore *-schema-trigger.sql, *-schema-post.sql files
-	{Pattern: `(?i).*(-schema-trigger|-schema-post)\.sql$`, Type: "ignore"},
-	// db schema create file pattern, matches files like '{schema}-schema-create.sql'
-	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)-schema-create\.sql$`, Schema: "$1", Table: "", Type: SchemaSchema},
-	// table schema create file pattern, matches files like '{schema}.{table}-schema.sql'
-	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)-schema\.sql$`, Schema: "$1", Table: "$2", Type: TableSchema},
-	// view schema create file pattern, matches files like '{schema}.{table}-schema-view.sql'
-	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)-schema-view\.sql$`, Schema: "$1", Table: "$2", Type: ViewSchema},
-	// source file pattern, matches files like '{schema}.{table}.0001.{sql|csv}'
-	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)(?:\.([0-9]+))?\.(sql|csv|parquet)$`, Schema: "$1", Table: "$2", Type: "$4", Key: "$3"},
+	{Pattern: `(?i).*(-schema-trigger|-schema-post)\.sql(?:\.(?:gz|gzip|zst|zstd|snappy))?$`, Type: "ignore"},
+	// db schema create file pattern, matches files like '{schema}-schema-create.sql[.{compress}]'
+	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)-schema-create\.sql(?:\.(gz|gzip|zst|zstd|snappy))?$`, Schema: "$1", Table: "", Type: SchemaSchema, Compression: "$2"},
+	// table schema create file pattern, matches files like '{schema}.{table}-schema.sql[.{compress}]'
+	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)-schema\.sql(?:\.(gz|gzip|zst|zstd|snappy))?$`, Schema: "$1", Table: "$2", Type: TableSchema, Compression: "$3"},
+	// view schema create file pattern, matches files like '{schema}.{table}-schema-view.sql[.{compress}]'
+	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)-schema-view\.sql(?:\.(gz|gzip|zst|zstd|snappy))?$`, Schema: "$1", Table: "$2", Type: ViewSchema, Compression: "$3"},
+	// source file pattern, matches files like '{schema}.{table}.0001.{sql|csv|json}[.{compress}]'
+	{Pattern: `(?i)^(?:[^/]*/)*([^/.]+)\.(.*?)(?:\.([0-9]+))?\.(sql|csv|parquet|json|ndjson|jsonl)(?:\.(gz|gzip|zst|zstd|snappy))?$`, Schema: "$1", Table: "$2", Type: "$4", Key: "$3", Compression: "$5"},
 }
 
 // // RouteRule is a rule to route file path to target schema/table
@@ -248,13 +274,12 @@ func (p regexRouterParser) Parse(r *config.FileRouteRule) (*RegexRouter, error)
 
 	if len(r.Compression) > 0 {
 		err = p.parseFieldExtractor(rule, "compression", r.Compression, func(result *RouteResult, value string) error {
-			// TODO: should support restore compressed source files
 			compression, err := parseCompressionType(value)
 			if err != nil {
 				return err
 			}
-			if compression != CompressionNone {
-				return errors.New("Currently we don't support restore compressed source file yet")
+			if compression == CompressionLZ4 || compression == CompressionXZ {
+				return errors.Errorf("Currently we don't support restore %s compressed source file yet", value)
 			}
 			result.Compression = compression
 			return nil
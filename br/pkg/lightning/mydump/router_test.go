@@ -113,7 +113,7 @@ func (t *testFileRouterSuite) TestSingleRouteRule(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(r, NotNil)
 	invalidMatchPaths := []string{
-		"my_schema.my_table.sql.gz",
+		"my_schema.my_table.sql.lz4",
 		"my_schema.my_table.sql.rar",
 		"my_schema.my_table.txt",
 	}
@@ -122,6 +122,29 @@ func (t *testFileRouterSuite) TestSingleRouteRule(c *C) {
 		c.Assert(res, IsNil)
 		c.Assert(err, NotNil)
 	}
+	compressedPaths := map[string]Compression{
+		"my_schema.my_table.sql.gz":      CompressionGZ,
+		"my_schema.my_table.csv.zst":     CompressionZStd,
+		"my_schema.my_table.sql.snappy":  CompressionSnappy,
+		"my_schema.my_table.0001.csv.gz": CompressionGZ,
+	}
+	for p, compression := range compressedPaths {
+		res, err := r.Route(p)
+		c.Assert(err, IsNil)
+		c.Assert(res, NotNil)
+		c.Assert(res.Compression, Equals, compression)
+	}
+	jsonPaths := []string{
+		"my_schema.my_table.json",
+		"my_schema.my_table.0001.ndjson",
+		"my_schema.my_table.jsonl.gz",
+	}
+	for _, p := range jsonPaths {
+		res, err := r.Route(p)
+		c.Assert(err, IsNil)
+		c.Assert(res, NotNil)
+		c.Assert(res.Type, Equals, SourceTypeJSON)
+	}
 }
 
 func (t *testFileRouterSuite) TestMultiRouteRule(c *C) {
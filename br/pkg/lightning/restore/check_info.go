@@ -578,12 +578,7 @@ func hasDefault(col *model.ColumnInfo) bool {
 }
 
 func (rc *Controller) readColumnsAndCount(ctx context.Context, dataFileMeta mydump.SourceFileMeta) (cols []string, colCnt int, err error) {
-	var reader storage.ReadSeekCloser
-	if dataFileMeta.Type == mydump.SourceTypeParquet {
-		reader, err = mydump.OpenParquetReader(ctx, rc.store, dataFileMeta.Path, dataFileMeta.FileSize)
-	} else {
-		reader, err = rc.store.Open(ctx, dataFileMeta.Path)
-	}
+	reader, err := mydump.OpenReader(ctx, dataFileMeta, rc.store)
 	if err != nil {
 		return nil, 0, errors.Trace(err)
 	}
@@ -609,6 +604,11 @@ func (rc *Controller) readColumnsAndCount(ctx context.Context, dataFileMeta mydu
 		if err != nil {
 			return nil, 0, errors.Trace(err)
 		}
+	case mydump.SourceTypeJSON:
+		parser, err = mydump.NewJSONParser(&rc.cfg.Mydumper.JSON, reader, blockBufSize, rc.ioWorkers)
+		if err != nil {
+			return nil, 0, errors.Trace(err)
+		}
 	default:
 		panic(fmt.Sprintf("unknown file type '%s'", dataFileMeta.Type))
 	}
@@ -665,7 +665,8 @@ func (rc *Controller) SchemaIsValid(ctx context.Context, tableInfo *mydump.MDTab
 		// get columns name from data file.
 		dataFileMeta := dataFile.FileMeta
 
-		if tp := dataFileMeta.Type; tp != mydump.SourceTypeCSV && tp != mydump.SourceTypeSQL && tp != mydump.SourceTypeParquet {
+		if tp := dataFileMeta.Type; tp != mydump.SourceTypeCSV && tp != mydump.SourceTypeSQL && tp != mydump.SourceTypeParquet &&
+			tp != mydump.SourceTypeJSON {
 			msgs = append(msgs, fmt.Sprintf("file '%s' with unknown source type '%s'", dataFileMeta.Path, dataFileMeta.Type.String()))
 			return msgs, nil
 		}
@@ -745,13 +746,7 @@ func (rc *Controller) sampleDataFromTable(ctx context.Context, dbName string, ta
 		return nil
 	}
 	sampleFile := tableMeta.DataFiles[0].FileMeta
-	var reader storage.ReadSeekCloser
-	var err error
-	if sampleFile.Type == mydump.SourceTypeParquet {
-		reader, err = mydump.OpenParquetReader(ctx, rc.store, sampleFile.Path, sampleFile.FileSize)
-	} else {
-		reader, err = rc.store.Open(ctx, sampleFile.Path)
-	}
+	reader, err := mydump.OpenReader(ctx, sampleFile, rc.store)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -786,6 +781,14 @@ func (rc *Controller) sampleDataFromTable(ctx context.Context, dbName string, ta
 		if err != nil {
 			return errors.Trace(err)
 		}
+	case mydump.SourceTypeJSON:
+		parser, err = mydump.NewJSONParser(&rc.cfg.Mydumper.JSON, reader, blockBufSize, rc.ioWorkers)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(rc.cfg.Mydumper.JSON.ColumnMapping) == 0 {
+			parser.SetColumns(getJSONColumnNames(tableInfo))
+		}
 	default:
 		panic(fmt.Sprintf("file '%s' with unknown source type '%s'", sampleFile.Path, sampleFile.Type.String()))
 	}
@@ -835,7 +838,7 @@ outloop:
 		rowCount += 1
 
 		var dataChecksum, indexChecksum verification.KVChecksum
-		kvs, encodeErr := kvEncoder.Encode(logTask.Logger, lastRow.Row, lastRow.RowID, columnPermutation, sampleFile.Path, offset)
+		kvs, encodeErr := kvEncoder.Encode(logTask.Logger, lastRow.Row, lastRow.RowID, omitAbsentColumns(columnPermutation, lastRow.Absent), sampleFile.Path, offset)
 		parser.RecycleRow(lastRow)
 		if encodeErr != nil {
 			err = errors.Annotatef(encodeErr, "in file at offset %d", offset)
@@ -926,14 +926,16 @@ func (rc *Controller) estimateChunkCountIntoMetrics(ctx context.Context) error {
 					estimatedChunkCount += cnt
 					continue
 				}
-				if fileMeta.FileMeta.Type == mydump.SourceTypeCSV {
-					cfg := rc.cfg.Mydumper
-					if fileMeta.FileMeta.FileSize > int64(cfg.MaxRegionSize) && cfg.StrictFormat && !cfg.CSV.Header {
+				cfg := rc.cfg.Mydumper
+				switch {
+				case fileMeta.FileMeta.Type == mydump.SourceTypeCSV && cfg.StrictFormat && !cfg.CSV.Header,
+					fileMeta.FileMeta.Type == mydump.SourceTypeJSON:
+					if fileMeta.FileMeta.FileSize > int64(cfg.MaxRegionSize) && fileMeta.FileMeta.Compression == mydump.CompressionNone {
 						estimatedChunkCount += math.Round(float64(fileMeta.FileMeta.FileSize) / float64(cfg.MaxRegionSize))
 					} else {
 						estimatedChunkCount++
 					}
-				} else {
+				default:
 					estimatedChunkCount++
 				}
 			}
@@ -1998,13 +2000,7 @@ func newChunkRestore(
 ) (*chunkRestore, error) {
 	blockBufSize := int64(cfg.Mydumper.ReadBlockSize)
 
-	var reader storage.ReadSeekCloser
-	var err error
-	if chunk.FileMeta.Type == mydump.SourceTypeParquet {
-		reader, err = mydump.OpenParquetReader(ctx, store, chunk.FileMeta.Path, chunk.FileMeta.FileSize)
-	} else {
-		reader, err = store.Open(ctx, chunk.FileMeta.Path)
-	}
+	reader, err := mydump.OpenReader(ctx, chunk.FileMeta, store)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -2029,6 +2025,11 @@ func newChunkRestore(
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+	case mydump.SourceTypeJSON:
+		parser, err = mydump.NewJSONParser(&cfg.Mydumper.JSON, reader, blockBufSize, ioWorkers)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	default:
 		panic(fmt.Sprintf("file '%s' with unknown source type '%s'", chunk.Key.Path, chunk.FileMeta.Type.String()))
 	}
@@ -2038,6 +2039,10 @@ func newChunkRestore(
 	}
 	if len(chunk.ColumnPermutation) > 0 {
 		parser.SetColumns(getColumnNames(tableInfo.Core, chunk.ColumnPermutation))
+	} else if chunk.FileMeta.Type == mydump.SourceTypeJSON && len(cfg.Mydumper.JSON.ColumnMapping) == 0 {
+		// The keys may vary between the JSON objects, so they are matched against
+		// the columns of the table rather than the keys of the first row.
+		parser.SetColumns(getJSONColumnNames(tableInfo.Core))
 	}
 
 	return &chunkRestore{
@@ -2051,6 +2056,36 @@ func (cr *chunkRestore) close() {
 	cr.parser.Close()
 }
 
+// getJSONColumnNames gets the columns which the keys of the JSON objects are
+// matched against. The generated columns are excluded since their values can't
+// be specified.
+func getJSONColumnNames(tableInfo *model.TableInfo) []string {
+	names := make([]string, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Columns {
+		if !col.IsGenerated() {
+			names = append(names, col.Name.O)
+		}
+	}
+	return names
+}
+
+// omitAbsentColumns returns the column permutation of a row, in which the
+// columns of the absent values are omitted so that they take the default
+// values.
+func omitAbsentColumns(permutation []int, absent []bool) []int {
+	if absent == nil {
+		return permutation
+	}
+	res := make([]int, len(permutation))
+	for i, j := range permutation {
+		if j >= 0 && j < len(absent) && absent[j] {
+			j = -1
+		}
+		res[i] = j
+	}
+	return res
+}
+
 func getColumnNames(tableInfo *model.TableInfo, permutation []int) []string {
 	colIndexes := make([]int, 0, len(permutation))
 	for i := 0; i < len(permutation); i++ {
@@ -2337,6 +2372,17 @@ func (cr *chunkRestore) encodeLoop(
 				reachEOF = true
 				break outLoop
 			default:
+				// the malformed row is skipped by the parser, so we can record it like the encode errors.
+				if rowErr, ok := errors.Cause(err).(*mydump.RowError); ok { // nolint:errorlint
+					err = rc.errorMgr.RecordTypeError(ctx, logger, t.tableName, cr.chunk.Key.Path, newOffset, rowErr.RowText, rowErr)
+					if err == nil {
+						readDur += time.Since(readDurStart)
+						curOffset = newOffset
+						// stop at the end of the chunk even if the last row is malformed.
+						canDeliver = newOffset >= cr.chunk.Chunk.EndOffset
+						continue
+					}
+				}
 				err = errors.Annotatef(err, "in file %s at offset %d", &cr.chunk.Key, newOffset)
 				return
 			}
@@ -2344,7 +2390,8 @@ func (cr *chunkRestore) encodeLoop(
 			encodeDurStart := time.Now()
 			lastRow := cr.parser.LastRow()
 			// sql -> kv
-			kvs, encodeErr := kvEncoder.Encode(logger, lastRow.Row, lastRow.RowID, cr.chunk.ColumnPermutation, cr.chunk.Key.Path, curOffset)
+			permutation := omitAbsentColumns(cr.chunk.ColumnPermutation, lastRow.Absent)
+			kvs, encodeErr := kvEncoder.Encode(logger, lastRow.Row, lastRow.RowID, permutation, cr.chunk.Key.Path, curOffset)
 			encodeDur += time.Since(encodeDurStart)
 
 			hasIgnoredEncodeErr := false
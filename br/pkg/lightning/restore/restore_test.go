@@ -64,6 +64,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/types"
 	"github.com/pingcap/tidb/table/tables"
+	tidbtypes "github.com/pingcap/tidb/types"
 	tmock "github.com/pingcap/tidb/util/mock"
 	"github.com/tikv/pd/server/api"
 )
@@ -1391,6 +1392,87 @@ func (s *chunkRestoreSuite) TestEncodeLoopColumnsMismatch(c *C) {
 	c.Assert(kvsCh, HasLen, 0)
 }
 
+func (s *chunkRestoreSuite) TestJSONKeysMatchTableColumns(c *C) {
+	dir := c.MkDir()
+	fileName := "db.table.000.json"
+	content := []byte(`{"a": 1}` + "\n" + `{"c": 6, "a": 4}` + "\n" + `{"d": 7}` + "\n")
+	err := os.WriteFile(filepath.Join(dir, fileName), content, 0o644)
+	c.Assert(err, IsNil)
+
+	store, err := storage.NewLocalStorage(dir)
+	c.Assert(err, IsNil)
+
+	ctx := context.Background()
+	w := worker.NewPool(ctx, 5, "io")
+	chunk := checkpoints.ChunkCheckpoint{
+		Key:      checkpoints.ChunkCheckpointKey{Path: fileName, Offset: 0},
+		FileMeta: mydump.SourceFileMeta{Path: fileName, Type: mydump.SourceTypeJSON, FileSize: int64(len(content))},
+		Chunk: mydump.Chunk{
+			Offset:    0,
+			EndOffset: int64(len(content)),
+			RowIDMax:  3,
+		},
+	}
+	cr, err := newChunkRestore(ctx, 1, s.cfg, &chunk, w, store, s.tr.tableInfo)
+	c.Assert(err, IsNil)
+	defer cr.close()
+
+	// the key `c` first appears on the second row.
+	c.Assert(cr.parser.ReadRow(), IsNil)
+	c.Assert(cr.parser.Columns(), DeepEquals, []string{"a", "b", "c"})
+	row := cr.parser.LastRow().Row
+	c.Assert(row, HasLen, 3)
+	c.Assert(row[0].GetString(), Equals, "1")
+	c.Assert(row[1].IsNull(), IsTrue)
+	c.Assert(row[2].IsNull(), IsTrue)
+	c.Assert(cr.parser.ReadRow(), IsNil)
+	row = cr.parser.LastRow().Row
+	c.Assert(row[0].GetString(), Equals, "4")
+	c.Assert(row[1].IsNull(), IsTrue)
+	c.Assert(row[2].GetString(), Equals, "6")
+	// the key which isn't a column of the table is unknown.
+	c.Assert(cr.parser.ReadRow(), ErrorMatches, "unknown key 'd'")
+}
+
+func (s *chunkRestoreSuite) TestJSONAbsentKeysTakeDefaults(c *C) {
+	p := parser.New()
+	se := tmock.NewContext()
+	node, err := p.ParseOneStmt("CREATE TABLE t (id BIGINT PRIMARY KEY AUTO_INCREMENT, v INT DEFAULT 7, w INT)", "", "")
+	c.Assert(err, IsNil)
+	core, err := ddl.MockTableInfo(se, node.(*ast.CreateTableStmt), 0xabcdef)
+	c.Assert(err, IsNil)
+	core.State = model.StatePublic
+	tbl, err := tables.TableFromMeta(kv.NewPanickingAllocators(0), core)
+	c.Assert(err, IsNil)
+
+	ctx := context.Background()
+	jsonParser, err := mydump.NewJSONParser(&config.JSONConfig{}, mydump.NewStringReader(`{"w": null}`), int64(config.ReadBlockSize), worker.NewPool(ctx, 1, "io"))
+	c.Assert(err, IsNil)
+	jsonParser.SetColumns(getJSONColumnNames(core))
+	c.Assert(jsonParser.ReadRow(), IsNil)
+	row := jsonParser.LastRow()
+	c.Assert(row.Absent, DeepEquals, []bool{true, true, false})
+
+	// the absent `id` takes the row ID, and the absent `v` takes the default value
+	// rather than NULL, while the explicit NULL of `w` is kept.
+	permutation := []int{0, 1, 2, -1}
+	kvEncoder, err := kv.NewTableKVEncoder(tbl, &kv.SessionOptions{SQLMode: mysql.ModeStrictAllTables})
+	c.Assert(err, IsNil)
+	kvs, err := kvEncoder.Encode(s.tr.logger, row.Row, 10, omitAbsentColumns(permutation, row.Absent), "t.json", 0)
+	c.Assert(err, IsNil)
+	expected, err := kvEncoder.Encode(s.tr.logger, []tidbtypes.Datum{
+		tidbtypes.NewIntDatum(10), tidbtypes.NewIntDatum(7), {},
+	}, 10, permutation, "t.json", 0)
+	c.Assert(err, IsNil)
+	c.Assert(kvs, DeepEquals, expected)
+
+	tidbEncoder, err := tidb.NewTiDBBackend(nil, config.ReplaceOnDup, nil).NewEncoder(tbl, &kv.SessionOptions{})
+	c.Assert(err, IsNil)
+	tidbRow, err := tidbEncoder.Encode(s.tr.logger, row.Row, 10, omitAbsentColumns(permutation, row.Absent), "t.json", 0)
+	c.Assert(err, IsNil)
+	c.Assert(fmt.Sprint(tidbRow), Equals, "(DEFAULT,DEFAULT,NULL)")
+}
+
 func (s *chunkRestoreSuite) TestRestore(c *C) {
 	ctx := context.Background()
 
@@ -318,7 +318,7 @@ func (tr *TableRestore) restoreEngines(pCtx context.Context, rc *Controller, cp
 						err = tr.importEngine(ctx, dataClosedEngine, rc, eid, ecp)
 						if rc.status != nil {
 							for _, chunk := range ecp.Chunks {
-								rc.status.FinishedFileSize.Add(chunk.Chunk.EndOffset - chunk.Key.Offset)
+								rc.status.FinishedFileSize.Add(chunkSourceSize(chunk))
 							}
 						}
 					}
@@ -328,7 +328,7 @@ func (tr *TableRestore) restoreEngines(pCtx context.Context, rc *Controller, cp
 				}(restoreWorker, engineID, engine)
 			} else {
 				for _, chunk := range engine.Chunks {
-					rc.status.FinishedFileSize.Add(chunk.Chunk.EndOffset - chunk.Key.Offset)
+					rc.status.FinishedFileSize.Add(chunkSourceSize(chunk))
 				}
 			}
 		}
@@ -972,8 +972,8 @@ func estimateCompactionThreshold(cp *checkpoints.TableCheckpoint, factor int64)
 				continue
 			}
 			size := chunk.FileMeta.FileSize
-			if chunk.FileMeta.Type == mydump.SourceTypeParquet {
-				// parquet file is compressed, thus estimates with a factor of 2
+			if chunk.FileMeta.Type == mydump.SourceTypeParquet || chunk.FileMeta.Compression != mydump.CompressionNone {
+				// parquet file and compressed file are compressed, thus estimates with a factor of 2
 				size *= 2
 			}
 			totalRawFileSize += size
@@ -994,3 +994,12 @@ func estimateCompactionThreshold(cp *checkpoints.TableCheckpoint, factor int64)
 
 	return threshold
 }
+
+// chunkSourceSize returns the size of the source file restored by the chunk, which is used to compute the progress.
+func chunkSourceSize(chunk *checkpoints.ChunkCheckpoint) int64 {
+	// the compressed file is restored as a whole, and its end offset is not a real offset of the source file.
+	if chunk.FileMeta.Compression != mydump.CompressionNone {
+		return chunk.FileMeta.FileSize
+	}
+	return chunk.Chunk.EndOffset - chunk.Key.Offset
+}
@@ -0,0 +1,436 @@
+// Copyright 2021 PingCAP, Inc. Licensed under Apache-2.0.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/pingcap/errors"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+	berrors "github.com/pingcap/tidb/br/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+const (
+	azblobEndpointOption   = "azblob.endpoint"
+	azblobAccessTierOption = "azblob.access-tier"
+	azblobAccountName      = "azblob.account-name"
+	azblobAccountKey       = "azblob.account-key"
+	azblobSASToken         = "azblob.sas-token"
+)
+
+const (
+	// azblobProviderName is the provider name of the Azure Blob Storage in the cloud dynamic backend.
+	// TiKV doesn't support the cloud dynamic backend yet, so it can't be used by BR.
+	azblobProviderName = "azure"
+
+	// The attributes of the Azure Blob Storage in the cloud dynamic backend.
+	azblobAttrAccountName = "account_name"
+	azblobAttrSharedKey   = "shared_key"
+	azblobAttrSASToken    = "sas_token"
+	azblobAttrAccessTier  = "access_tier"
+
+	// hardcodedAzblobChunkSize is the size of each block staged by the writer created by `Create`.
+	hardcodedAzblobChunkSize = 5 * 1024 * 1024
+	// azblobMaxRetryRequests is the maximum number of the retried requests when a download is interrupted.
+	azblobMaxRetryRequests = 3
+)
+
+// AzblobBackendOptions contains options for the Azure Blob Storage.
+type AzblobBackendOptions struct {
+	Endpoint    string `json:"endpoint" toml:"endpoint"`
+	AccountName string `json:"account-name" toml:"account-name"`
+	AccountKey  string `json:"account-key" toml:"account-key"`
+	SASToken    string `json:"sas-token" toml:"sas-token"`
+	AccessTier  string `json:"access-tier" toml:"access-tier"`
+}
+
+func (options *AzblobBackendOptions) apply(cloud *backuppb.CloudDynamic) error {
+	// Use the environment variables of the Azure CLI if the credentials are not specified.
+	if options.AccountName == "" {
+		options.AccountName = os.Getenv("AZURE_STORAGE_ACCOUNT")
+	}
+	if options.AccountKey == "" && options.SASToken == "" {
+		options.AccountKey = os.Getenv("AZURE_STORAGE_KEY")
+		options.SASToken = os.Getenv("AZURE_STORAGE_SAS_TOKEN")
+	}
+	if options.Endpoint == "" && options.AccountName == "" {
+		return errors.Annotate(berrors.ErrStorageInvalidConfig, "either the endpoint or the account name of azblob should be specified")
+	}
+	if options.AccountKey != "" && options.AccountName == "" {
+		return errors.Annotate(berrors.ErrStorageInvalidConfig, "the account name of azblob should be specified with the account key")
+	}
+	cloud.Bucket.Endpoint = options.Endpoint
+	cloud.Attrs = map[string]string{
+		azblobAttrAccountName: options.AccountName,
+		azblobAttrSharedKey:   options.AccountKey,
+		azblobAttrSASToken:    strings.TrimPrefix(options.SASToken, "?"),
+		azblobAttrAccessTier:  options.AccessTier,
+	}
+	return nil
+}
+
+func defineAzblobFlags(flags *pflag.FlagSet) {
+	// TODO: remove experimental tag if it's stable
+	flags.String(azblobEndpointOption, "", "(experimental) Set the Azure Blob Storage endpoint URL, "+
+		"default to https://<account-name>.blob.core.windows.net")
+	flags.String(azblobAccessTierOption, "", "(experimental) Specify the access tier of the blobs, can be Hot, Cool or Archive")
+	flags.String(azblobAccountName, "", "(experimental) Set the Azure storage account name, "+
+		"default to the environment variable AZURE_STORAGE_ACCOUNT")
+	flags.String(azblobAccountKey, "", "(experimental) Set the Azure storage account key, "+
+		"default to the environment variable AZURE_STORAGE_KEY")
+	flags.String(azblobSASToken, "", "(experimental) Set the shared access signature token of the container, "+
+		"default to the environment variable AZURE_STORAGE_SAS_TOKEN")
+}
+
+func (options *AzblobBackendOptions) parseFromFlags(flags *pflag.FlagSet) error {
+	var err error
+	options.Endpoint, err = flags.GetString(azblobEndpointOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccessTier, err = flags.GetString(azblobAccessTierOption)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccountName, err = flags.GetString(azblobAccountName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.AccountKey, err = flags.GetString(azblobAccountKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	options.SASToken, err = flags.GetString(azblobSASToken)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// azblobStorage is the ExternalStorage of the Azure Blob Storage. The container and the prefix are the bucket and
+// the prefix of the cloud dynamic backend.
+type azblobStorage struct {
+	options    *backuppb.CloudDynamic
+	container  azblob.ContainerURL
+	prefix     string
+	accessTier azblob.AccessTierType
+}
+
+func newAzblobStorage(ctx context.Context, options *backuppb.CloudDynamic, opts *ExternalStorageOptions) (*azblobStorage, error) {
+	if options.Bucket == nil || options.Bucket.Bucket == "" {
+		return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "azblob container not found")
+	}
+	accountName := options.Attrs[azblobAttrAccountName]
+	sharedKey := options.Attrs[azblobAttrSharedKey]
+	sasToken := options.Attrs[azblobAttrSASToken]
+
+	endpoint := options.Bucket.Endpoint
+	if endpoint == "" {
+		if accountName == "" {
+			return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "azblob account name not found")
+		}
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", accountName)
+	}
+	containerURL, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + options.Bucket.Bucket)
+	if err != nil {
+		return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "invalid azblob endpoint %s: %v", endpoint, err)
+	}
+
+	var credential azblob.Credential
+	switch {
+	case sasToken != "":
+		// The SAS token is carried by the query parameters of the requests.
+		credential = azblob.NewAnonymousCredential()
+		containerURL.RawQuery = sasToken
+	case sharedKey != "":
+		credential, err = azblob.NewSharedKeyCredential(accountName, sharedKey)
+		if err != nil {
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "invalid azblob account key: %v", err)
+		}
+	case opts.NoCredentials:
+		credential = azblob.NewAnonymousCredential()
+	default:
+		return nil, errors.Annotate(berrors.ErrStorageInvalidConfig,
+			"You should provide '--azblob.account-key' or '--azblob.sas-token' to access the Azure Blob Storage")
+	}
+	if !opts.SendCredentials {
+		// Clear the credentials if exists so that they will not be sent to TiKV
+		delete(options.Attrs, azblobAttrSharedKey)
+		delete(options.Attrs, azblobAttrSASToken)
+	}
+
+	pipelineOptions := azblob.PipelineOptions{}
+	if opts.HTTPClient != nil {
+		pipelineOptions.HTTPSender = newAzblobHTTPSender(opts.HTTPClient)
+	}
+	container := azblob.NewContainerURL(*containerURL, azblob.NewPipeline(credential, pipelineOptions))
+	// TODO remove it after BR remove cfg skip-check-path
+	if !opts.SkipCheckPath {
+		// check container exists
+		if _, err = container.GetProperties(ctx, azblob.LeaseAccessConditions{}); err != nil {
+			return nil, errors.Annotatef(err, "azure://%s/%s", options.Bucket.Bucket, options.Bucket.Prefix)
+		}
+	}
+
+	prefix := strings.Trim(options.Bucket.Prefix, "/")
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	return &azblobStorage{
+		options:    options,
+		container:  container,
+		prefix:     prefix,
+		accessTier: azblob.AccessTierType(options.Attrs[azblobAttrAccessTier]),
+	}, nil
+}
+
+// newAzblobHTTPSender sends the requests of the azblob pipeline by the specified HTTP client.
+func newAzblobHTTPSender(client *http.Client) pipeline.Factory {
+	return pipeline.FactoryFunc(func(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.PolicyFunc {
+		return func(ctx context.Context, request pipeline.Request) (pipeline.Response, error) {
+			resp, err := client.Do(request.WithContext(ctx))
+			if err != nil {
+				err = pipeline.NewError(err, "HTTP request failed")
+			}
+			return pipeline.NewHTTPResponse(resp), err
+		}
+	})
+}
+
+func (s *azblobStorage) objectName(name string) string {
+	return s.prefix + strings.TrimPrefix(name, "/")
+}
+
+// WriteFile writes data to a file to storage.
+func (s *azblobStorage) WriteFile(ctx context.Context, name string, data []byte) error {
+	blob := s.container.NewBlockBlobURL(s.objectName(name))
+	_, err := azblob.UploadBufferToBlockBlob(ctx, data, blob, azblob.UploadToBlockBlobOptions{
+		BlobAccessTier: s.accessTier,
+	})
+	return errors.Annotatef(err, "failed to write azblob file, file info: container=%s, key=%s",
+		s.options.Bucket.Bucket, s.objectName(name))
+}
+
+// ReadFile reads the file from the storage and returns the contents.
+func (s *azblobStorage) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	blob := s.container.NewBlobURL(s.objectName(name))
+	resp, err := blob.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to read azblob file, file info: container=%s, key=%s",
+			s.options.Bucket.Bucket, s.objectName(name))
+	}
+	body := resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: azblobMaxRetryRequests})
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	return data, errors.Trace(err)
+}
+
+// FileExists return true if file exists.
+func (s *azblobStorage) FileExists(ctx context.Context, name string) (bool, error) {
+	blob := s.container.NewBlobURL(s.objectName(name))
+	_, err := blob.GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		if isAzblobNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// DeleteFile delete the file in storage
+func (s *azblobStorage) DeleteFile(ctx context.Context, name string) error {
+	blob := s.container.NewBlobURL(s.objectName(name))
+	_, err := blob.Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{})
+	return errors.Trace(err)
+}
+
+// Open a Reader by file path.
+func (s *azblobStorage) Open(ctx context.Context, path string) (ExternalFileReader, error) {
+	blob := s.container.NewBlobURL(s.objectName(path))
+	props, err := blob.GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to read azblob file, file info: container=%s, key=%s",
+			s.options.Bucket.Bucket, s.objectName(path))
+	}
+	return &azblobObjectReader{
+		blob:      blob,
+		totalSize: props.ContentLength(),
+		ctx:       ctx,
+	}, nil
+}
+
+// WalkDir traverse all the files in a dir.
+//
+// fn is the function called for each regular file visited by WalkDir.
+// The first argument is the file path that can be used in `Open`
+// function; the second argument is the size in byte of the file determined
+// by path.
+func (s *azblobStorage) WalkDir(ctx context.Context, opt *WalkOption, fn func(string, int64) error) error {
+	if opt == nil {
+		opt = &WalkOption{}
+	}
+	prefix := s.prefix
+	if len(opt.SubDir) > 0 {
+		prefix = s.objectName(opt.SubDir)
+		if !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+	}
+	listOptions := azblob.ListBlobsSegmentOptions{Prefix: prefix}
+	if opt.ListCount > 0 {
+		listOptions.MaxResults = int32(opt.ListCount)
+	}
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := s.container.ListBlobsFlatSegment(ctx, marker, listOptions)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, item := range resp.Segment.BlobItems {
+			var size int64
+			if item.Properties.ContentLength != nil {
+				size = *item.Properties.ContentLength
+			}
+			// when walk on specify directory, the result include the prefix of the storage,
+			// which can not be reuse in other API(Open/Read) directly.
+			// so we use TrimPrefix to filter the prefix for next Open/Read.
+			if err = fn(strings.TrimPrefix(item.Name, s.prefix), size); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		marker = resp.NextMarker
+	}
+	return nil
+}
+
+func (s *azblobStorage) URI() string {
+	return "azure://" + s.options.Bucket.Bucket + "/" + s.prefix
+}
+
+// Create implements ExternalStorage interface.
+func (s *azblobStorage) Create(ctx context.Context, name string) (ExternalFileWriter, error) {
+	uploader := &azblobUploader{
+		blob:       s.container.NewBlockBlobURL(s.objectName(name)),
+		accessTier: s.accessTier,
+	}
+	return newBufferedWriter(uploader, hardcodedAzblobChunkSize, NoCompression)
+}
+
+func isAzblobNotFound(err error) bool {
+	storageErr, ok := errors.Cause(err).(azblob.StorageError) // nolint:errorlint
+	if !ok {
+		return false
+	}
+	if storageErr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+		return true
+	}
+	// The response of a HEAD request has no body, so the service code may be missing.
+	resp := storageErr.Response()
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+// azblobUploader uploads the data as the blocks of a block blob, and commits the blocks when it's closed.
+type azblobUploader struct {
+	blob       azblob.BlockBlobURL
+	accessTier azblob.AccessTierType
+	blockIDs   []string
+}
+
+// Write stages the data as a new block of the blob.
+func (u *azblobUploader) Write(ctx context.Context, data []byte) (int, error) {
+	// The block IDs of a blob must have the same length.
+	blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%016d", len(u.blockIDs))))
+	_, err := u.blob.StageBlock(ctx, blockID, bytes.NewReader(data), azblob.LeaseAccessConditions{}, nil, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	u.blockIDs = append(u.blockIDs, blockID)
+	return len(data), nil
+}
+
+// Close commits all the staged blocks to complete the blob.
+func (u *azblobUploader) Close(ctx context.Context) error {
+	_, err := u.blob.CommitBlockList(ctx, u.blockIDs, azblob.BlobHTTPHeaders{}, azblob.Metadata{},
+		azblob.BlobAccessConditions{}, u.accessTier, nil, azblob.ClientProvidedKeyOptions{})
+	return errors.Trace(err)
+}
+
+// azblobObjectReader reads a blob by range requests, and implements the `Seek` method.
+type azblobObjectReader struct {
+	blob      azblob.BlobURL
+	reader    io.ReadCloser
+	pos       int64
+	totalSize int64
+	// reader context used for implement `io.Seek`
+	ctx context.Context
+}
+
+// Read implement the io.Reader interface.
+func (r *azblobObjectReader) Read(p []byte) (n int, err error) {
+	if r.pos >= r.totalSize {
+		return 0, io.EOF
+	}
+	if r.reader == nil {
+		resp, err := r.blob.Download(r.ctx, r.pos, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+		if err != nil {
+			return 0, errors.Annotatef(err, "failed to read azblob file, file info: url=%s", r.blob.String())
+		}
+		r.reader = resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: azblobMaxRetryRequests})
+	}
+	n, err = r.reader.Read(p)
+	r.pos += int64(n)
+	return n, err
+}
+
+// Close implement the io.Closer interface.
+func (r *azblobObjectReader) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+	err := r.reader.Close()
+	r.reader = nil
+	return errors.Trace(err)
+}
+
+// Seek implement the io.Seeker interface.
+func (r *azblobObjectReader) Seek(offset int64, whence int) (int64, error) {
+	var realOffset int64
+	switch whence {
+	case io.SeekStart:
+		realOffset = offset
+	case io.SeekCurrent:
+		realOffset = r.pos + offset
+	case io.SeekEnd:
+		realOffset = r.totalSize + offset
+	default:
+		return 0, errors.Annotatef(berrors.ErrStorageUnknown, "Seek: invalid whence '%d'", whence)
+	}
+	if realOffset < 0 {
+		return 0, errors.Annotatef(berrors.ErrInvalidArgument, "Seek: offset '%v' out of range.", realOffset)
+	}
+
+	if realOffset == r.pos {
+		return realOffset, nil
+	}
+	// The blob is downloaded from the new position by the next `Read`.
+	if err := r.Close(); err != nil {
+		return 0, err
+	}
+	r.pos = realOffset
+	return realOffset, nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2021 PingCAP, Inc. Licensed under Apache-2.0.
+
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/pingcap/check"
+	backuppb "github.com/pingcap/kvproto/pkg/brpb"
+)
+
+const (
+	fakeAzblobAccountName = "devstoreaccount1"
+	fakeAzblobContainer   = "testcontainer"
+)
+
+// fakeAzblobServer is a minimal in-memory implementation of the Azure Blob Storage REST API,
+// which serves the requests for a single container.
+type fakeAzblobServer struct {
+	sync.Mutex
+	blobs  map[string][]byte
+	blocks map[string][]byte
+}
+
+func newFakeAzblobServer() *httptest.Server {
+	s := &fakeAzblobServer{
+		blobs:  make(map[string][]byte),
+		blocks: make(map[string][]byte),
+	}
+	return httptest.NewServer(s)
+}
+
+func (s *fakeAzblobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	containerPath := "/" + fakeAzblobAccountName + "/" + fakeAzblobContainer
+	if !strings.HasPrefix(r.URL.Path, containerPath) {
+		s.writeError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, containerPath), "/")
+	query := r.URL.Query()
+
+	if name == "" {
+		switch {
+		case query.Get("restype") == "container" && query.Get("comp") == "list":
+			s.listBlobs(w, query.Get("prefix"), query.Get("marker"), query.Get("maxresults"))
+		case query.Get("restype") == "container":
+			w.WriteHeader(http.StatusOK)
+		default:
+			s.writeError(w, http.StatusBadRequest, "InvalidQueryParameterValue")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "InvalidInput")
+			return
+		}
+		switch query.Get("comp") {
+		case "block":
+			s.blocks[name+"/"+query.Get("blockid")] = data
+		case "blocklist":
+			var blockList struct {
+				Latest []string `xml:"Latest"`
+			}
+			if err := xml.Unmarshal(data, &blockList); err != nil {
+				s.writeError(w, http.StatusBadRequest, "InvalidXmlDocument")
+				return
+			}
+			var content []byte
+			for _, id := range blockList.Latest {
+				block, ok := s.blocks[name+"/"+id]
+				if !ok {
+					s.writeError(w, http.StatusBadRequest, "InvalidBlockList")
+					return
+				}
+				content = append(content, block...)
+				delete(s.blocks, name+"/"+id)
+			}
+			s.blobs[name] = content
+		default:
+			s.blobs[name] = data
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead, http.MethodGet:
+		data, ok := s.blobs[name]
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		status := http.StatusOK
+		if rng := r.Header.Get("x-ms-range"); rng != "" {
+			var start int
+			if _, err := fmt.Sscanf(rng, "bytes=%d-", &start); err != nil || start > len(data) {
+				s.writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+				return
+			}
+			data = data[start:]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		if _, ok := s.blobs[name]; !ok {
+			s.writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(s.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+	}
+}
+
+func (s *fakeAzblobServer) listBlobs(w http.ResponseWriter, prefix, marker, maxResults string) {
+	names := make([]string, 0, len(s.blobs))
+	for name := range s.blobs {
+		if strings.HasPrefix(name, prefix) && name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	nextMarker := ""
+	if limit, err := strconv.Atoi(maxResults); err == nil && limit < len(names) {
+		names = names[:limit]
+		nextMarker = names[limit-1]
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length></Properties></Blob>",
+			name, len(s.blobs[name]))
+	}
+	fmt.Fprintf(&sb, "</Blobs><NextMarker>%s</NextMarker></EnumerationResults>", nextMarker)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, sb.String())
+}
+
+func (s *fakeAzblobServer) writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
+
+func newFakeAzblobStorage(c *C, server *httptest.Server, prefix string) *azblobStorage {
+	backend := &backuppb.CloudDynamic{
+		ProviderName: azblobProviderName,
+		Bucket:       &backuppb.Bucket{Bucket: fakeAzblobContainer, Prefix: prefix},
+	}
+	options := &AzblobBackendOptions{
+		Endpoint:    server.URL + "/" + fakeAzblobAccountName,
+		AccountName: fakeAzblobAccountName,
+		AccountKey:  base64.StdEncoding.EncodeToString([]byte("fake account key")),
+	}
+	c.Assert(options.apply(backend), IsNil)
+	stg, err := newAzblobStorage(context.Background(), backend, &ExternalStorageOptions{
+		SendCredentials: false,
+		HTTPClient:      server.Client(),
+	})
+	c.Assert(err, IsNil)
+	return stg
+}
+
+func (r *testStorageSuite) TestAzblob(c *C) {
+	ctx := context.Background()
+	server := newFakeAzblobServer()
+	defer server.Close()
+	stg := newFakeAzblobStorage(c, server, "a/b/")
+	c.Assert(stg.URI(), Equals, "azure://testcontainer/a/b/")
+	c.Assert(stg.options.Attrs[azblobAttrSharedKey], Equals, "")
+
+	err := stg.WriteFile(ctx, "key", []byte("data"))
+	c.Assert(err, IsNil)
+	err = stg.WriteFile(ctx, "key1", []byte("data1"))
+	c.Assert(err, IsNil)
+	err = stg.WriteFile(ctx, "sub/key2", []byte("data22223346757222222222289722222"))
+	c.Assert(err, IsNil)
+
+	d, err := stg.ReadFile(ctx, "key")
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, []byte("data"))
+
+	exist, err := stg.FileExists(ctx, "key")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsTrue)
+
+	exist, err = stg.FileExists(ctx, "key_not_exist")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsFalse)
+
+	err = stg.DeleteFile(ctx, "key1")
+	c.Assert(err, IsNil)
+	exist, err = stg.FileExists(ctx, "key1")
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsFalse)
+
+	// Walk with a small list count so that the result is paginated.
+	var list []string
+	var totalSize int64
+	err = stg.WalkDir(ctx, &WalkOption{ListCount: 1}, func(name string, size int64) error {
+		list = append(list, name)
+		totalSize += size
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(list, DeepEquals, []string{"key", "sub/key2"})
+	c.Assert(totalSize, Equals, int64(37))
+
+	list = list[:0]
+	err = stg.WalkDir(ctx, &WalkOption{SubDir: "sub"}, func(name string, size int64) error {
+		list = append(list, name)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(list, DeepEquals, []string{"sub/key2"})
+
+	// The storage without prefix sees the full names of the blobs.
+	rootStg := newFakeAzblobStorage(c, server, "")
+	d, err = rootStg.ReadFile(ctx, "a/b/sub/key2")
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, []byte("data22223346757222222222289722222"))
+}
+
+func (r *testStorageSuite) TestAzblobReaderAndWriter(c *C) {
+	ctx := context.Background()
+	server := newFakeAzblobServer()
+	defer server.Close()
+	stg := newFakeAzblobStorage(c, server, "prefix")
+
+	// Write more than one block.
+	content := []byte(strings.Repeat("0123456789", hardcodedAzblobChunkSize/10+1))
+	w, err := stg.Create(ctx, "large")
+	c.Assert(err, IsNil)
+	for i := 0; i < len(content); i += 1000 {
+		end := i + 1000
+		if end > len(content) {
+			end = len(content)
+		}
+		_, err = w.Write(ctx, content[i:end])
+		c.Assert(err, IsNil)
+	}
+	c.Assert(w.Close(ctx), IsNil)
+
+	d, err := stg.ReadFile(ctx, "large")
+	c.Assert(err, IsNil)
+	c.Assert(d, DeepEquals, content)
+
+	reader, err := stg.Open(ctx, "large")
+	c.Assert(err, IsNil)
+	defer reader.Close()
+
+	buf := make([]byte, 10)
+	_, err = io.ReadFull(reader, buf)
+	c.Assert(err, IsNil)
+	c.Assert(string(buf), Equals, "0123456789")
+
+	offset, err := reader.Seek(15, io.SeekStart)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(15))
+	_, err = io.ReadFull(reader, buf)
+	c.Assert(err, IsNil)
+	c.Assert(string(buf), Equals, "5678901234")
+
+	offset, err = reader.Seek(-3, io.SeekEnd)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(len(content)-3))
+	d, err = io.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(string(d), Equals, "789")
+
+	_, err = reader.Seek(-1, io.SeekStart)
+	c.Assert(err, NotNil)
+
+	_, err = stg.Open(ctx, "not_exist")
+	c.Assert(err, NotNil)
+}
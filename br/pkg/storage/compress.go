@@ -37,7 +37,10 @@ func (w *withCompression) Create(ctx context.Context, name string) (ExternalFile
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	compressedWriter := newBufferedWriter(writer, hardcodedS3ChunkSize, w.compressType)
+	compressedWriter, err := newBufferedWriter(writer, hardcodedS3ChunkSize, w.compressType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return compressedWriter, nil
 }
 
@@ -55,8 +58,11 @@ func (w *withCompression) Open(ctx context.Context, path string) (ExternalFileRe
 
 func (w *withCompression) WriteFile(ctx context.Context, name string, data []byte) error {
 	bf := bytes.NewBuffer(make([]byte, 0, len(data)))
-	compressBf := newCompressWriter(w.compressType, bf)
-	_, err := compressBf.Write(data)
+	compressBf, err := newCompressWriter(w.compressType, bf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = compressBf.Write(data)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -77,11 +83,16 @@ func (w *withCompression) ReadFile(ctx context.Context, name string) ([]byte, er
 	if err != nil {
 		return nil, err
 	}
+	defer compressBf.Close()
 	return io.ReadAll(compressBf)
 }
 
+// compressReader decompresses the data read from the underlying file reader. Since the compressed data can't be
+// located by the offsets of the decompressed data, it only supports seeking forward by skipping the data.
 type compressReader struct {
 	io.ReadCloser
+	fileReader ExternalFileReader
+	pos        int64
 }
 
 // nolint:interfacer
@@ -95,11 +106,42 @@ func newInterceptReader(fileReader ExternalFileReader, compressType CompressType
 	}
 	return &compressReader{
 		ReadCloser: r,
+		fileReader: fileReader,
 	}, nil
 }
 
-func (r *compressReader) Seek(_ int64, _ int) (int64, error) {
-	return int64(0), errors.Annotatef(berrors.ErrStorageInvalidConfig, "compressReader doesn't support Seek now")
+func (r *compressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.pos += int64(n)
+	return n, err
+}
+
+func (r *compressReader) Seek(offset int64, whence int) (int64, error) {
+	var realOffset int64
+	switch whence {
+	case io.SeekStart:
+		realOffset = offset
+	case io.SeekCurrent:
+		realOffset = r.pos + offset
+	default:
+		return 0, errors.Annotatef(berrors.ErrStorageInvalidConfig, "compressReader doesn't support Seek with whence '%d'", whence)
+	}
+	if realOffset < r.pos {
+		return 0, errors.Annotatef(berrors.ErrStorageInvalidConfig,
+			"compressReader doesn't support Seek backward, current position: %d, required position: %d", r.pos, realOffset)
+	}
+	if _, err := io.CopyN(io.Discard, r, realOffset-r.pos); err != nil {
+		return r.pos, errors.Trace(err)
+	}
+	return r.pos, nil
+}
+
+func (r *compressReader) Close() error {
+	err := r.ReadCloser.Close()
+	if err1 := r.fileReader.Close(); err == nil {
+		err = err1
+	}
+	return errors.Trace(err)
 }
 
 type flushStorageWriter struct {
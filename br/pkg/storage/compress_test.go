@@ -4,6 +4,7 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -19,24 +20,64 @@ func (r *testStorageSuite) TestWithCompressReadWriteFile(c *C) {
 	ctx := context.Background()
 	storage, err := Create(ctx, backend, true)
 	c.Assert(err, IsNil)
-	storage = WithCompression(storage, Gzip)
-	name := "with compress test"
-	content := "hello,world!"
-	fileName := strings.ReplaceAll(name, " ", "-") + ".txt.gz"
-	err = storage.WriteFile(ctx, fileName, []byte(content))
-	c.Assert(err, IsNil)
+	for _, compressType := range []CompressType{Gzip, Snappy, Zstd} {
+		compressStorage := WithCompression(storage, compressType)
+		name := fmt.Sprintf("with compress test %d", compressType)
+		content := "hello,world!"
+		fileName := strings.ReplaceAll(name, " ", "-") + ".txt"
+		err = compressStorage.WriteFile(ctx, fileName, []byte(content))
+		c.Assert(err, IsNil)
+
+		// make sure compressed file is written correctly
+		file, err := os.Open(filepath.Join(dir, fileName))
+		c.Assert(err, IsNil)
+		uncompressedFile, err := newCompressReader(compressType, file)
+		c.Assert(err, IsNil)
+		newContent, err := io.ReadAll(uncompressedFile)
+		c.Assert(err, IsNil)
+		c.Assert(string(newContent), Equals, content)
+		c.Assert(uncompressedFile.Close(), IsNil)
+		c.Assert(file.Close(), IsNil)
+
+		// test withCompression ReadFile
+		newContent, err = compressStorage.ReadFile(ctx, fileName)
+		c.Assert(err, IsNil)
+		c.Assert(string(newContent), Equals, content)
+	}
+}
 
-	// make sure compressed file is written correctly
-	file, err := os.Open(filepath.Join(dir, fileName))
+func (r *testStorageSuite) TestCompressReaderSeek(c *C) {
+	dir := c.MkDir()
+	backend, err := ParseBackend("local://"+filepath.ToSlash(dir), nil)
+	c.Assert(err, IsNil)
+	ctx := context.Background()
+	storage, err := Create(ctx, backend, true)
 	c.Assert(err, IsNil)
-	uncompressedFile, err := newCompressReader(Gzip, file)
+	storage = WithCompression(storage, Zstd)
+	err = storage.WriteFile(ctx, "seek.txt.zst", []byte("0123456789"))
 	c.Assert(err, IsNil)
-	newContent, err := io.ReadAll(uncompressedFile)
+
+	reader, err := storage.Open(ctx, "seek.txt.zst")
 	c.Assert(err, IsNil)
-	c.Assert(string(newContent), Equals, content)
+	defer reader.Close()
 
-	// test withCompression ReadFile
-	newContent, err = storage.ReadFile(ctx, fileName)
+	// seeking forward skips the decompressed data.
+	offset, err := reader.Seek(3, io.SeekStart)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(3))
+	offset, err = reader.Seek(2, io.SeekCurrent)
 	c.Assert(err, IsNil)
-	c.Assert(string(newContent), Equals, content)
+	c.Assert(offset, Equals, int64(5))
+	offset, err = reader.Seek(0, io.SeekCurrent)
+	c.Assert(err, IsNil)
+	c.Assert(offset, Equals, int64(5))
+	content, err := io.ReadAll(reader)
+	c.Assert(err, IsNil)
+	c.Assert(string(content), Equals, "56789")
+
+	// seeking backward or from the end is not supported.
+	_, err = reader.Seek(0, io.SeekStart)
+	c.Assert(err, ErrorMatches, ".*doesn't support Seek backward.*")
+	_, err = reader.Seek(0, io.SeekEnd)
+	c.Assert(err, NotNil)
 }
@@ -132,6 +132,38 @@ func (r *testStorageSuite) TestCreateStorage(c *C) {
 	c.Assert(gcs.Prefix, Equals, "backup")
 	c.Assert(gcs.CredentialsBlob, Equals, "fakeCreds2")
 
+	s, err = ParseBackend("azure://container/backup/?account-name=user&account-key=cGFzc3dk&endpoint="+
+		url.QueryEscape("http://127.0.0.1:10000/user"), nil)
+	c.Assert(err, IsNil)
+	azblob := s.GetCloudDynamic()
+	c.Assert(azblob, NotNil)
+	c.Assert(azblob.ProviderName, Equals, "azure")
+	c.Assert(azblob.Bucket.Bucket, Equals, "container")
+	c.Assert(azblob.Bucket.Prefix, Equals, "backup")
+	c.Assert(azblob.Bucket.Endpoint, Equals, "http://127.0.0.1:10000/user")
+	c.Assert(azblob.Attrs["account_name"], Equals, "user")
+	c.Assert(azblob.Attrs["shared_key"], Equals, "cGFzc3dk")
+
+	azblobOpt := &BackendOptions{
+		Azblob: AzblobBackendOptions{
+			AccountName: "user",
+			SASToken:    "?sv=2020-08-04&sig=xxx",
+			AccessTier:  "Cool",
+		},
+	}
+	s, err = ParseBackend("azblob://container2", azblobOpt)
+	c.Assert(err, IsNil)
+	azblob = s.GetCloudDynamic()
+	c.Assert(azblob, NotNil)
+	c.Assert(azblob.Bucket.Bucket, Equals, "container2")
+	c.Assert(azblob.Bucket.Prefix, Equals, "")
+	c.Assert(azblob.Bucket.Endpoint, Equals, "")
+	c.Assert(azblob.Attrs["sas_token"], Equals, "sv=2020-08-04&sig=xxx")
+	c.Assert(azblob.Attrs["access_tier"], Equals, "Cool")
+
+	_, err = ParseBackend("azure:///prefix", azblobOpt)
+	c.Assert(err, ErrorMatches, "please specify the container for azblob.*")
+
 	s, err = ParseBackend("/test", nil)
 	c.Assert(err, IsNil)
 	local := s.GetLocal()
@@ -177,4 +209,17 @@ func (r *testStorageSuite) TestFormatBackendURL(c *C) {
 		},
 	})
 	c.Assert(url.String(), Equals, "gcs://bucket/some%20prefix/")
+
+	url = FormatBackendURL(&backuppb.StorageBackend{
+		Backend: &backuppb.StorageBackend_CloudDynamic{
+			CloudDynamic: &backuppb.CloudDynamic{
+				ProviderName: "azure",
+				Bucket: &backuppb.Bucket{
+					Bucket: "container",
+					Prefix: "/some prefix/",
+				},
+			},
+		},
+	})
+	c.Assert(url.String(), Equals, "azure://container/some%20prefix/")
 }
@@ -178,6 +178,16 @@ func New(ctx context.Context, backend *backuppb.StorageBackend, opts *ExternalSt
 			return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "GCS config not found")
 		}
 		return newGCSStorage(ctx, backend.Gcs, opts)
+	case *backuppb.StorageBackend_CloudDynamic:
+		if backend.CloudDynamic == nil {
+			return nil, errors.Annotate(berrors.ErrStorageInvalidConfig, "cloud dynamic config not found")
+		}
+		switch backend.CloudDynamic.ProviderName {
+		case azblobProviderName, "azblob":
+			return newAzblobStorage(ctx, backend.CloudDynamic, opts)
+		default:
+			return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "cloud provider %s is not supported yet", backend.CloudDynamic.ProviderName)
+		}
 	default:
 		return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "storage %T is not supported yet", backend)
 	}
@@ -6,7 +6,10 @@ import (
 	"context"
 	"io"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
+	berrors "github.com/pingcap/tidb/br/pkg/errors"
 )
 
 // CompressType represents the type of compression.
@@ -17,6 +20,10 @@ const (
 	NoCompression CompressType = iota
 	// Gzip will compress given bytes in gzip format.
 	Gzip
+	// Snappy will compress given bytes in snappy format.
+	Snappy
+	// Zstd will compress given bytes in zstd format.
+	Zstd
 )
 
 type flusher interface {
@@ -38,19 +45,27 @@ type interceptBuffer interface {
 	Reset()
 }
 
-func newInterceptBuffer(chunkSize int, compressType CompressType) interceptBuffer {
+func newInterceptBuffer(chunkSize int, compressType CompressType) (interceptBuffer, error) {
 	if compressType == NoCompression {
-		return newNoCompressionBuffer(chunkSize)
+		return newNoCompressionBuffer(chunkSize), nil
 	}
 	return newSimpleCompressBuffer(chunkSize, compressType)
 }
 
-func newCompressWriter(compressType CompressType, w io.Writer) simpleCompressWriter {
+func newCompressWriter(compressType CompressType, w io.Writer) (simpleCompressWriter, error) {
 	switch compressType {
 	case Gzip:
-		return gzip.NewWriter(w)
+		return gzip.NewWriter(w), nil
+	case Snappy:
+		return snappy.NewBufferedWriter(w), nil
+	case Zstd:
+		newWriter, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, errors.Annotate(err, "failed to create zstd writer")
+		}
+		return newWriter, nil
 	default:
-		return nil
+		return nil, errors.Annotatef(berrors.ErrStorageInvalidConfig, "unsupported compress type %d", compressType)
 	}
 }
 
@@ -58,6 +73,14 @@ func newCompressReader(compressType CompressType, r io.Reader) (io.ReadCloser, e
 	switch compressType {
 	case Gzip:
 		return gzip.NewReader(r)
+	case Snappy:
+		return io.NopCloser(snappy.NewReader(r)), nil
+	case Zstd:
+		newReader, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return newReader.IOReadCloser(), nil
 	default:
 		return nil, nil
 	}
@@ -118,14 +141,18 @@ func (b *simpleCompressBuffer) Close() error {
 	return b.compressWriter.Close()
 }
 
-func newSimpleCompressBuffer(chunkSize int, compressType CompressType) *simpleCompressBuffer {
+func newSimpleCompressBuffer(chunkSize int, compressType CompressType) (*simpleCompressBuffer, error) {
 	bf := bytes.NewBuffer(make([]byte, 0, chunkSize))
+	compressWriter, err := newCompressWriter(compressType, bf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &simpleCompressBuffer{
 		Buffer:         bf,
 		len:            0,
 		cap:            chunkSize,
-		compressWriter: newCompressWriter(compressType, bf),
-	}
+		compressWriter: compressWriter,
+	}, nil
 }
 
 type bufferedWriter struct {
@@ -181,16 +208,20 @@ func (u *bufferedWriter) Close(ctx context.Context) error {
 }
 
 // NewUploaderWriter wraps the Writer interface over an uploader.
-func NewUploaderWriter(writer ExternalFileWriter, chunkSize int, compressType CompressType) ExternalFileWriter {
+func NewUploaderWriter(writer ExternalFileWriter, chunkSize int, compressType CompressType) (ExternalFileWriter, error) {
 	return newBufferedWriter(writer, chunkSize, compressType)
 }
 
 // newBufferedWriter is used to build a buffered writer.
-func newBufferedWriter(writer ExternalFileWriter, chunkSize int, compressType CompressType) *bufferedWriter {
+func newBufferedWriter(writer ExternalFileWriter, chunkSize int, compressType CompressType) (*bufferedWriter, error) {
+	buf, err := newInterceptBuffer(chunkSize, compressType)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return &bufferedWriter{
 		writer: writer,
-		buf:    newInterceptBuffer(chunkSize, compressType),
-	}
+		buf:    buf,
+	}, nil
 }
 
 // BytesWriter is a Writer implementation on top of bytes.Buffer that is useful for testing.
@@ -101,7 +101,7 @@ func (r *testStorageSuite) TestCompressReaderWriter(c *C) {
 		ctx := context.Background()
 		storage, err := Create(ctx, backend, true)
 		c.Assert(err, IsNil)
-		storage = WithCompression(storage, Gzip)
+		storage = WithCompression(storage, test.compressType)
 		fileName := strings.ReplaceAll(test.name, " ", "-") + ".txt.gz"
 		writer, err := storage.Create(ctx, fileName)
 		c.Assert(err, IsNil)
@@ -134,7 +134,7 @@ func (r *testStorageSuite) TestCompressReaderWriter(c *C) {
 
 		c.Assert(file.Close(), IsNil)
 	}
-	compressTypeArr := []CompressType{Gzip}
+	compressTypeArr := []CompressType{Gzip, Snappy, Zstd}
 	tests := []testcase{
 		{
 			name: "long text medium chunks",
@@ -166,3 +166,19 @@ func (r *testStorageSuite) TestCompressReaderWriter(c *C) {
 		}
 	}
 }
+
+func (r *testStorageSuite) TestCompressWriterUnsupportedType(c *C) {
+	backend, err := ParseBackend("local://"+filepath.ToSlash(c.MkDir()), nil)
+	c.Assert(err, IsNil)
+	ctx := context.Background()
+	storage, err := Create(ctx, backend, true)
+	c.Assert(err, IsNil)
+
+	// the error of creating the compress writer should be returned rather
+	// than leaving a nil writer behind.
+	storage = WithCompression(storage, CompressType(100))
+	_, err = storage.Create(ctx, "test.txt")
+	c.Assert(err, ErrorMatches, "unsupported compress type 100.*")
+	err = storage.WriteFile(ctx, "test.txt", []byte("hello world"))
+	c.Assert(err, ErrorMatches, "unsupported compress type 100.*")
+}
@@ -135,6 +135,9 @@ func RunBackupRaw(c context.Context, g glue.Glue, cmdName string, cfg *RawKvConf
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkStorageSupportedByTiKV(u); err != nil {
+		return errors.Trace(err)
+	}
 	// Backup raw does not need domain.
 	needDomain := false
 	mgr, err := NewMgr(ctx, g, cfg.PD, cfg.TLS, GetKeepalive(&cfg.Config), cfg.CheckRequirements, needDomain)
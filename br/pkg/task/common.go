@@ -528,6 +528,17 @@ func NewMgr(ctx context.Context,
 	)
 }
 
+// checkStorageSupportedByTiKV checks whether the storage backend can be accessed by TiKV, which reads and writes
+// the backup files directly during backup and restore. The Azure Blob Storage is described by the cloud dynamic
+// backend, which TiKV doesn't support yet, so it can only be used by Dumpling and TiDB Lightning.
+func checkStorageSupportedByTiKV(u *backuppb.StorageBackend) error {
+	if _, ok := u.Backend.(*backuppb.StorageBackend_CloudDynamic); ok {
+		return errors.Annotatef(berrors.ErrStorageInvalidConfig, "storage %s is not supported by BR yet, "+
+			"it can only be used by Dumpling and TiDB Lightning", storage.FormatBackendURL(u).Scheme)
+	}
+	return nil
+}
+
 // GetStorage gets the storage backend from the config.
 func GetStorage(
 	ctx context.Context,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/br/pkg/storage"
 	"github.com/pingcap/tidb/config"
 	"github.com/spf13/pflag"
 )
@@ -62,3 +63,13 @@ func (s *testCommonSuite) TestStripingPDURL(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(noChange, Equals, "127.0.0.1:2379")
 }
+
+func (s *testCommonSuite) TestCheckStorageSupportedByTiKV(c *C) {
+	u, err := storage.ParseBackend("s3://bucket/prefix", nil)
+	c.Assert(err, IsNil)
+	c.Assert(checkStorageSupportedByTiKV(u), IsNil)
+
+	u, err = storage.ParseBackend("azure://container/prefix?account-name=account&account-key=key", nil)
+	c.Assert(err, IsNil)
+	c.Assert(checkStorageSupportedByTiKV(u), ErrorMatches, "storage azure is not supported by BR yet.*")
+}
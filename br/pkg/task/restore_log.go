@@ -110,6 +110,9 @@ func RunLogRestore(c context.Context, g glue.Glue, cfg *LogRestoreConfig) error
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkStorageSupportedByTiKV(u); err != nil {
+		return errors.Trace(err)
+	}
 	keepaliveCfg := GetKeepalive(&cfg.Config)
 	keepaliveCfg.PermitWithoutStream = true
 	client, err := restore.NewRestoreClient(g, mgr.GetPDClient(), mgr.GetStorage(), mgr.GetTLSConfig(), keepaliveCfg)
@@ -544,6 +544,16 @@ type StmtSummary struct {
 	RefreshInterval int `toml:"refresh-interval" json:"refresh-interval"`
 	// The maximum history size of statement summary.
 	HistorySize int `toml:"history-size" json:"history-size"`
+	// Persist the expired statement summary history to local files or not.
+	EnablePersistent bool `toml:"enable-persistent" json:"enable-persistent"`
+	// The file to persist the statement summary history.
+	Filename string `toml:"filename" json:"filename"`
+	// The maximum number of days to retain the persisted history files.
+	FileMaxDays int `toml:"file-max-days" json:"file-max-days"`
+	// The maximum size in MB of a persisted history file before it gets rotated.
+	FileMaxSize int `toml:"file-max-size" json:"file-max-size"`
+	// The maximum number of rotated history files to retain, 0 means no limit.
+	FileMaxBackups int `toml:"file-max-backups" json:"file-max-backups"`
 }
 
 // TopSQL is the config for TopSQL.
@@ -689,6 +699,11 @@ var defaultConf = Config{
 		MaxSQLLength:        4096,
 		RefreshInterval:     1800,
 		HistorySize:         24,
+		EnablePersistent:    false,
+		Filename:            "tidb-statements.log",
+		FileMaxDays:         3,
+		FileMaxSize:         64,
+		FileMaxBackups:      0,
 	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
@@ -922,6 +937,14 @@ func (c *Config) Valid() error {
 	if c.StmtSummary.RefreshInterval <= 0 {
 		return fmt.Errorf("refresh-interval in [stmt-summary] should be greater than 0")
 	}
+	if c.StmtSummary.EnablePersistent {
+		if len(c.StmtSummary.Filename) == 0 {
+			return fmt.Errorf("filename in [stmt-summary] should not be empty when enable-persistent is true")
+		}
+		if c.StmtSummary.FileMaxDays < 0 || c.StmtSummary.FileMaxSize < 0 || c.StmtSummary.FileMaxBackups < 0 {
+			return fmt.Errorf("file-max-days, file-max-size and file-max-backups in [stmt-summary] should be greater than or equal to 0")
+		}
+	}
 
 	if c.PreparedPlanCache.Capacity < 1 {
 		return fmt.Errorf("capacity in [prepared-plan-cache] should be at least 1")
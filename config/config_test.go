@@ -222,6 +222,9 @@ max-stmt-count=1000
 max-sql-length=1024
 refresh-interval=100
 history-size=100
+enable-persistent=true
+filename="tidb-statements-test.log"
+file-max-days=7
 [experimental]
 allow-expression-index = true
 [isolation-read]
@@ -270,6 +273,10 @@ receiver-address = "127.0.0.1:10100"
 	require.Equal(t, uint(1024), conf.StmtSummary.MaxSQLLength)
 	require.Equal(t, 100, conf.StmtSummary.RefreshInterval)
 	require.Equal(t, 100, conf.StmtSummary.HistorySize)
+	require.True(t, conf.StmtSummary.EnablePersistent)
+	require.Equal(t, "tidb-statements-test.log", conf.StmtSummary.Filename)
+	require.Equal(t, 7, conf.StmtSummary.FileMaxDays)
+	require.Equal(t, 64, conf.StmtSummary.FileMaxSize)
 	require.True(t, conf.EnableBatchDML)
 	require.True(t, conf.RepairMode)
 	require.Equal(t, uint64(16), conf.TiKVClient.ResolveLockLiteThreshold)
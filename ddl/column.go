@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -145,19 +146,20 @@ func createColumnInfo(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *
 	return colInfo, pos, offset, nil
 }
 
-func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, error) {
+func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, *model.IndexInfo, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 	col := &model.ColumnInfo{}
 	pos := &ast.ColumnPosition{}
 	offset := 0
-	err = job.DecodeArgs(col, pos, &offset)
+	var idxInfo *model.IndexInfo
+	err = job.DecodeArgs(col, pos, &offset, &idxInfo)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 
 	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
@@ -165,13 +167,38 @@ func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Colu
 		if columnInfo.State == model.StatePublic {
 			// We already have a column with the same column name.
 			job.State = model.JobStateCancelled
-			return nil, nil, nil, nil, 0, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+			return nil, nil, nil, nil, 0, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+		}
+		// The index is created along with the column, get its latest meta.
+		if idxInfo != nil {
+			idxInfo = tblInfo.FindIndexByName(idxInfo.Name.L)
 		}
 	}
-	return tblInfo, columnInfo, col, pos, offset, nil
+	return tblInfo, columnInfo, col, pos, offset, idxInfo, nil
+}
+
+// needBackfillAddingColumn checks whether the values of the adding column must be filled for the existing rows,
+// rather than being read as the original default value.
+func needBackfillAddingColumn(col *model.ColumnInfo) bool {
+	return (col.IsGenerated() && col.GeneratedStored) || mysql.HasAutoIncrementFlag(col.Flag)
 }
 
-func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+// createAddingColumnIndexInfo adds the index which is created along with the adding column to the table.
+func createAddingColumnIndexInfo(tblInfo *model.TableInfo, columnInfo *model.ColumnInfo, idxInfo *model.IndexInfo) error {
+	if tblInfo.FindIndexByName(idxInfo.Name.L) != nil {
+		return ErrDupKeyName.GenWithStack("index already exist %s", idxInfo.Name)
+	}
+	if idxInfo.Primary && (tblInfo.PKIsHandle || tblInfo.IsCommonHandle) {
+		return infoschema.ErrMultiplePriKey
+	}
+	idxInfo.ID = allocateIndexID(tblInfo)
+	idxInfo.State = model.StateNone
+	idxInfo.Columns[0].Offset = columnInfo.Offset
+	tblInfo.Indices = append(tblInfo.Indices, idxInfo)
+	return nil
+}
+
+func (w *worker) onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// Handle the rolling back job.
 	if job.IsRollingback() {
 		ver, err = onDropColumn(t, job)
@@ -187,7 +214,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 	})
 
-	tblInfo, columnInfo, col, pos, offset, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, pos, offset, idxInfo, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -200,19 +227,29 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		logutil.BgLogger().Info("[ddl] run add column job", zap.String("job", job.String()), zap.Reflect("columnInfo", *columnInfo), zap.Int("offset", offset))
 		// Set offset arg to job.
 		if offset != 0 {
-			job.Args = []interface{}{columnInfo, pos, offset}
+			job.Args = []interface{}{columnInfo, pos, offset, idxInfo}
 		}
 		if err = checkAddColumnTooManyColumns(len(tblInfo.Columns)); err != nil {
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
+		if idxInfo != nil {
+			if err = createAddingColumnIndexInfo(tblInfo, columnInfo, idxInfo); err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+		}
+	}
+	var idxInfos []*model.IndexInfo
+	if idxInfo != nil {
+		idxInfos = []*model.IndexInfo{idxInfo}
 	}
 
 	originalState := columnInfo.State
 	switch columnInfo.State {
 	case model.StateNone:
 		// none -> delete only
-		columnInfo.State = model.StateDeleteOnly
+		updateChangingInfo(columnInfo, idxInfos, model.StateDeleteOnly)
 		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -220,7 +257,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		job.SchemaState = model.StateDeleteOnly
 	case model.StateDeleteOnly:
 		// delete only -> write only
-		columnInfo.State = model.StateWriteOnly
+		updateChangingInfo(columnInfo, idxInfos, model.StateWriteOnly)
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -229,18 +266,27 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		job.SchemaState = model.StateWriteOnly
 	case model.StateWriteOnly:
 		// write only -> reorganization
-		columnInfo.State = model.StateWriteReorganization
+		updateChangingInfo(columnInfo, idxInfos, model.StateWriteReorganization)
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
 		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
 		// Update the job state when all affairs done.
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
+		if needBackfillAddingColumn(columnInfo) || idxInfo != nil {
+			var done bool
+			done, ver, err = w.doReorgWorkForAddColumn(d, t, job, tblInfo, columnInfo, idxInfo)
+			if !done {
+				return ver, err
+			}
+		}
 		// reorganization -> public
 		// Adjust table column offset.
 		adjustColumnInfoInAddColumn(tblInfo, offset)
-		columnInfo.State = model.StatePublic
+		updateChangingInfo(columnInfo, idxInfos, model.StatePublic)
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -256,6 +302,68 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 	return ver, errors.Trace(err)
 }
 
+// doReorgWorkForAddColumn fills the values of the adding column for the existing rows,
+// and then builds the index created along with the column.
+func (w *worker) doReorgWorkForAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	columnInfo *model.ColumnInfo, idxInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	var idxInfos []*model.IndexInfo
+	if idxInfo != nil {
+		idxInfos = []*model.IndexInfo{idxInfo}
+	}
+	fillColumn := needBackfillAddingColumn(columnInfo)
+	var elements []*meta.Element
+	if fillColumn {
+		elements = BuildElements(columnInfo, idxInfos)
+	} else {
+		elements = []*meta.Element{{ID: idxInfo.ID, TypeKey: meta.IndexElementKey}}
+	}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addColumnErr error) {
+		defer util.Recover(metrics.LabelDDL, "onAddColumn",
+			func() {
+				addColumnErr = errCancelledDDLJob.GenWithStack("add table `%v` column `%v` panic", tblInfo.Name, columnInfo.Name)
+			}, false)
+		if fillColumn {
+			return w.updateColumnAndIndexes(tbl, nil, columnInfo, idxInfos, reorgInfo)
+		}
+		return w.addTableIndex(tbl, idxInfo, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) {
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] run add column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("[ddl] run add column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+		ver, err = convertAddColumnJob2RollbackJob(t, job, tblInfo, columnInfo, idxInfos, err)
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.ColumnInfo, []*model.ColumnInfo, []*ast.ColumnPosition, []int, []bool, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -543,6 +651,8 @@ func checkDropColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.
 }
 
 func checkDropColumnForStatePublic(tblInfo *model.TableInfo, colInfo *model.ColumnInfo) (err error) {
+	// The check constraints which only refer to the dropping column are dropped with it.
+	removeCheckConstraintsByColumn(tblInfo, colInfo.Name)
 	// Set this column's offset to the last and reset all following columns' offsets.
 	adjustColumnInfoInDropColumn(tblInfo, colInfo.Offset)
 	// When the dropping column has not-null flag and it hasn't the default value, we can backfill the column value like "add column".
@@ -628,10 +738,10 @@ func onDropColumn(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		if job.IsRollingback() {
 			job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
 		} else {
-			// We should set related index IDs for job
 			job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
-			job.Args = append(job.Args, indexIDs, getPartitionIDs(tblInfo))
 		}
+		// We should set related index IDs for job
+		job.Args = append(job.Args, indexIDs, getPartitionIDs(tblInfo))
 	default:
 		err = errInvalidDDLJob.GenWithStackByArgs("table", tblInfo.State)
 	}
@@ -657,11 +767,15 @@ func checkDropColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Col
 		job.State = model.JobStateCancelled
 		return nil, nil, nil, ErrCantDropFieldOrKey.GenWithStack("column %s doesn't exist", colName)
 	}
+	idxInfos := listIndicesWithColumn(colName.L, tblInfo.Indices)
+	// The adding column of a rolling back job is never public, nothing can depend on it.
+	if job.IsRollingback() {
+		return tblInfo, colInfo, idxInfos, nil
+	}
 	if err = isDroppableColumn(job.MultiSchemaInfo != nil, tblInfo, colName); err != nil {
 		job.State = model.JobStateCancelled
 		return nil, nil, nil, errors.Trace(err)
 	}
-	idxInfos := listIndicesWithColumn(colName.L, tblInfo.Indices)
 	if len(idxInfos) > 0 {
 		for _, idxInfo := range idxInfos {
 			err = checkDropIndexOnAutoIncrementColumn(tblInfo, idxInfo)
@@ -745,6 +859,24 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 	return true
 }
 
+// needRewriteColumnData checks whether modifying the column from oldCol to newCol needs to reorganize the data.
+// Besides the column type change, changing the charset of a string column rewrites the row data, and changing
+// the collation of an indexed string column rebuilds the index keys with the new collation.
+func needRewriteColumnData(tblInfo *model.TableInfo, oldCol, newCol *model.ColumnInfo) bool {
+	if needChangeColumnData(oldCol, newCol) {
+		return true
+	}
+	if !types.IsNonBinaryStr(&oldCol.FieldType) || !types.IsNonBinaryStr(&newCol.FieldType) || len(oldCol.Charset) == 0 {
+		return false
+	}
+	// utf8 is a subset of utf8mb4, the row data can be kept.
+	if oldCol.Charset != newCol.Charset && !(oldCol.Charset == charset.CharsetUTF8 && newCol.Charset == charset.CharsetUTF8MB4) {
+		return true
+	}
+	return collate.NewCollationEnabled() && !collate.CompatibleCollate(oldCol.Collate, newCol.Collate) &&
+		isColumnWithIndex(oldCol.Name.L, tblInfo.Indices)
+}
+
 // Column type conversion between varchar to char need reorganization because
 // 1. varchar -> char: char type is stored with the padding removed. All the indexes need to be rewritten.
 // 2. char -> varchar: the index value encoding of secondary index on clustered primary key tables is different.
@@ -845,7 +977,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 
 	if job.IsRollingback() {
 		// For those column-type-change jobs which don't reorg the data.
-		if !needChangeColumnData(oldCol, jobParam.newCol) {
+		if !needRewriteColumnData(tblInfo, oldCol, jobParam.newCol) {
 			return rollbackModifyColumnJob(t, tblInfo, job, oldCol, jobParam.modifyColumnTp)
 		}
 		// For those column-type-change jobs which reorg the data.
@@ -875,7 +1007,7 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		return ver, errors.Trace(err)
 	}
 
-	if !needChangeColumnData(oldCol, jobParam.newCol) {
+	if !needRewriteColumnData(tblInfo, oldCol, jobParam.newCol) {
 		return w.doModifyColumn(d, t, job, dbInfo, tblInfo, jobParam.newCol, oldCol, jobParam.pos)
 	}
 
@@ -1333,6 +1465,11 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("column", err))
 	}
 
+	if w.oldColInfo == nil {
+		// The column is being added, fill the value of the column.
+		return w.getAddingColumnRowRecord(recordKey)
+	}
+
 	if _, ok := w.rowMap[w.newColInfo.ID]; ok {
 		// The column is already added by update or insert statement, skip it.
 		w.cleanRowMap()
@@ -1366,7 +1503,47 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 	})
 
 	w.rowMap[w.newColInfo.ID] = newColVal
-	_, err = w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, timeutil.SystemLocation(), w.rowMap)
+	return w.appendRowRecord(recordKey, recordWarning)
+}
+
+// getAddingColumnRowRecord fills the value of the adding column for the row.
+// The stored generated column is evaluated by the row decoder, and the AUTO_INCREMENT column is allocated.
+func (w *updateColumnWorker) getAddingColumnRowRecord(recordKey []byte) error {
+	if w.newColInfo.IsGenerated() {
+		if _, ok := w.rowMap[w.newColInfo.ID]; ok {
+			// The column is already added by update or insert statement, skip it.
+			w.cleanRowMap()
+			return nil
+		}
+		return w.appendRowRecord(recordKey, nil)
+	}
+
+	// The original default value of the AUTO_INCREMENT column is zero or NULL,
+	// both of them mean the value hasn't been allocated by update or insert statement.
+	if oldVal, ok := w.rowMap[w.newColInfo.ID]; ok && !oldVal.IsNull() {
+		isAllocated, err := oldVal.ToBool(w.sessCtx.GetSessionVars().StmtCtx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if isAllocated != 0 {
+			w.cleanRowMap()
+			return nil
+		}
+	}
+	autoID, err := table.AllocAutoIncrementValue(context.Background(), w.table, w.sessCtx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newColVal, err := table.CastValue(w.sessCtx, types.NewIntDatum(autoID), w.newColInfo, false, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowMap[w.newColInfo.ID] = newColVal
+	return w.appendRowRecord(recordKey, nil)
+}
+
+func (w *updateColumnWorker) appendRowRecord(recordKey []byte, recordWarning *terror.Error) error {
+	_, err := w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, timeutil.SystemLocation(), w.rowMap)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -0,0 +1,402 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+func onAddCheckConstraint(w *worker, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constraintInfoInJob *model.ConstraintInfo
+	if err = job.DecodeArgs(&constraintInfoInJob); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintInfoInJob.Name.L)
+	if constraintInfo != nil && constraintInfo.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	if constraintInfo == nil {
+		// The columns may be changed after the job was submitted, check them again.
+		if err = checkCheckConstraintCols(tblInfo, constraintInfoInJob); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		constraintInfo = constraintInfoInJob.Clone()
+		constraintInfo.ID = allocateConstraintID(tblInfo)
+		constraintInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StateNone:
+		// none -> write only
+		job.SchemaState = model.StateWriteOnly
+		constraintInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+	case model.StateWriteOnly:
+		// write only -> write reorganization
+		job.SchemaState = model.StateWriteReorganization
+		constraintInfo.State = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+	case model.StateWriteReorganization:
+		// All the servers enforce the constraint for the new written rows now, check the existing rows.
+		if constraintInfo.Enforced {
+			err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo)
+			if err != nil {
+				if ErrCheckConstraintIsViolated.Equal(err) {
+					return rollbackAddCheckConstraint(t, job, tblInfo, constraintInfo, err)
+				}
+				return ver, errors.Trace(err)
+			}
+		}
+		// write reorganization -> public
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// rollbackAddCheckConstraint removes the constraint which is violated by the existing rows.
+func rollbackAddCheckConstraint(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo, err error) (ver int64, _ error) {
+	removeCheckConstraint(tblInfo, constraintInfo.Name)
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, errors.Trace(err)
+}
+
+// rollbackAlterCheckConstraint restores the constraint to not enforced, since it's violated by the existing rows.
+func rollbackAlterCheckConstraint(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo, err error) (ver int64, _ error) {
+	constraintInfo.Enforced = false
+	constraintInfo.State = model.StatePublic
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StatePublic, ver, tblInfo)
+	return ver, errors.Trace(err)
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constraintName model.CIStr
+	if err = job.DecodeArgs(&constraintName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constraintName.O)
+	}
+
+	// The constraint only restricts the written rows, so it can be removed directly.
+	// public -> none
+	removeCheckConstraint(tblInfo, constraintInfo.Name)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// Finish this job.
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func onAlterCheckConstraint(w *worker, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var (
+		constraintName model.CIStr
+		enforced       bool
+	)
+	if err = job.DecodeArgs(&constraintName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constraintName.O)
+	}
+
+	// Not enforced constraint doesn't restrict anything, so it can be switched off directly.
+	if !enforced {
+		constraintInfo.Enforced = false
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	// Enforcing the constraint goes through the same states as adding a constraint,
+	// the existing rows are checked after all the servers enforce the constraint for the new written rows.
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StatePublic:
+		if constraintInfo.Enforced {
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		// public -> write only
+		job.SchemaState = model.StateWriteOnly
+		constraintInfo.Enforced = true
+		constraintInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+	case model.StateWriteOnly:
+		// write only -> write reorganization
+		job.SchemaState = model.StateWriteReorganization
+		constraintInfo.State = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+	case model.StateWriteReorganization:
+		err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo)
+		if err != nil {
+			if !ErrCheckConstraintIsViolated.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			return rollbackAlterCheckConstraint(t, job, tblInfo, constraintInfo, err)
+		}
+		// write reorganization -> public
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// verifyRemainRecordsForCheckConstraint checks whether the existing rows satisfy the check constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	var sctx sessionctx.Context
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	// Since the expression string may contain the identifier, which couldn't be escaped in our ParseWithParams(...)
+	// So we write it to the origin sql string here, and the `%` in it should be escaped.
+	sql := fmt.Sprintf("select 1 from %%n.%%n where not (%s) limit 1", strings.ReplaceAll(constraintInfo.ExprString, "%", "%%"))
+	stmt, err := sctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(w.ddlJobCtx, sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := sctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(w.ddlJobCtx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) != 0 {
+		return ErrCheckConstraintIsViolated.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	return nil
+}
+
+func allocateConstraintID(tblInfo *model.TableInfo) int64 {
+	tblInfo.MaxConstraintID++
+	return tblInfo.MaxConstraintID
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, name model.CIStr) {
+	for i, constr := range tblInfo.Constraints {
+		if constr.Name.L == name.L {
+			tblInfo.Constraints = append(tblInfo.Constraints[:i], tblInfo.Constraints[i+1:]...)
+			return
+		}
+	}
+}
+
+// buildCheckConstraints builds the check constraints of the table to be created.
+func buildCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, constrs []*ast.Constraint) error {
+	// Check the specified names first, the generated names shouldn't conflict with them.
+	names := make(map[string]struct{}, len(constrs))
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := names[name]; ok {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		names[name] = struct{}{}
+	}
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			constr.Name = genCheckConstraintName(tblInfo.Name, names)
+		}
+		constraintInfo, err := buildConstraintInfo(ctx, tblInfo, constr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		constraintInfo.ID = allocateConstraintID(tblInfo)
+		constraintInfo.State = model.StatePublic
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+	return nil
+}
+
+// genCheckConstraintName generates the check constraint name like MySQL, it looks like `t_chk_1`.
+func genCheckConstraintName(tblName model.CIStr, names map[string]struct{}) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_chk_%d", tblName.O, i)
+		if _, ok := names[strings.ToLower(name)]; !ok {
+			names[strings.ToLower(name)] = struct{}{}
+			return name
+		}
+	}
+}
+
+// buildConstraintInfo builds the check constraint from the AST and checks whether the expression is valid.
+// The returned constraint has neither ID nor state.
+func buildConstraintInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint) (*model.ConstraintInfo, error) {
+	if err := checkIllegalFn4Generated(constr.Name, typeConstraint, constr.Expr); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	colNames := findColumnNamesInExpr(constr.Expr)
+	dependedCols := make([]model.CIStr, 0, len(colNames))
+	dependedColsMap := make(map[string]struct{}, len(colNames))
+	for _, colName := range colNames {
+		if constr.InColumn && colName.Name.L != strings.ToLower(constr.InColumnName) {
+			return nil, ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constr.Name)
+		}
+		if _, ok := dependedColsMap[colName.Name.L]; ok {
+			continue
+		}
+		dependedColsMap[colName.Name.L] = struct{}{}
+		dependedCols = append(dependedCols, colName.Name)
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := constr.Expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	constraintInfo := &model.ConstraintInfo{
+		Name:           model.NewCIStr(constr.Name),
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+	}
+	if err := checkCheckConstraintCols(tblInfo, constraintInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Make sure the expression could be built on the table.
+	if _, err := expression.ParseSimpleExprWithTableInfo(ctx, constraintInfo.ExprString, tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return constraintInfo, nil
+}
+
+// checkCheckConstraintCols checks whether the columns referred by the check constraint are valid.
+func checkCheckConstraintCols(tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	for _, colName := range constraintInfo.ConstraintCols {
+		col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+		if col == nil || col.State != model.StatePublic || col.Hidden {
+			return ErrCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constraintInfo.Name.O, colName.O)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constraintInfo.Name.O)
+		}
+	}
+	return nil
+}
+
+// findConstraintsByColumn returns the check constraints which refer to the column.
+func findConstraintsByColumn(tblInfo *model.TableInfo, colName model.CIStr) []*model.ConstraintInfo {
+	var constraints []*model.ConstraintInfo
+	for _, constr := range tblInfo.Constraints {
+		for _, col := range constr.ConstraintCols {
+			if col.L == colName.L {
+				constraints = append(constraints, constr)
+				break
+			}
+		}
+	}
+	return constraints
+}
+
+// checkDropColumnWithCheckConstraint checks whether the column could be dropped with the check constraints.
+// The column can't be dropped if any constraint refers to it and other columns.
+func checkDropColumnWithCheckConstraint(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, constr := range findConstraintsByColumn(tblInfo, colName) {
+		if len(constr.ConstraintCols) > 1 {
+			return errDependentByCheckConstraint.GenWithStackByArgs(constr.Name.O, colName.O)
+		}
+	}
+	return nil
+}
+
+// removeCheckConstraintsByColumn removes the check constraints which only refer to the dropped column.
+func removeCheckConstraintsByColumn(tblInfo *model.TableInfo, colName model.CIStr) {
+	for _, constr := range findConstraintsByColumn(tblInfo, colName) {
+		removeCheckConstraint(tblInfo, constr.Name)
+	}
+}
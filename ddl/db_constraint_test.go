@@ -0,0 +1,121 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testDBSuite5) TestCreateTableWithCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@tidb_enable_check_constraint = 1")
+	defer tk.MustExec("set @@tidb_enable_check_constraint = 0")
+
+	tk.MustExec("create table t (a int check (a > 0), b int, constraint c_b check (b < 10) not enforced)")
+	tk.MustQuery("show create table t").Check(testutil.RowsWithSep("|", "t|CREATE TABLE `t` (\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT NULL,\n"+
+		"  CONSTRAINT `c_b` CHECK ((`b` < 10)) /*!80016 NOT ENFORCED */,\n"+
+		"  CONSTRAINT `t_chk_1` CHECK ((`a` > 0))\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = 'test'").Sort().Check(
+		testkit.Rows("c_b (`b` < 10)", "t_chk_1 (`a` > 0)"))
+
+	// The not enforced constraint doesn't restrict the written rows.
+	tk.MustExec("insert into t values (1, 20)")
+	tk.MustGetErrCode("insert into t values (0, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into t values (null, 1)")
+	tk.MustExec("insert ignore into t values (-1, 1)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|3819|Check constraint 't_chk_1' is violated."))
+	tk.MustGetErrCode("update t set a = -1 where b = 20", errno.ErrCheckConstraintViolated)
+	tk.MustExec("update ignore t set a = -1 where b = 20")
+	tk.MustQuery("select * from t order by b").Check(testkit.Rows("<nil> 1", "1 20"))
+
+	tk.MustExec("drop table t")
+	tk.MustGetErrCode("create table t (a int check (b > 0), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t (a int, check (c > 0))", errno.ErrCheckConstraintRefersUnknownColumn)
+	tk.MustGetErrCode("create table t (a int auto_increment primary key, check (a > 0))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t (a int, check (a > @v))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("create table t (a int, check (a > rand()))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t (a int, b int, check ((a, b) > (1, 1)))", errno.ErrCheckConstraintRowValue)
+	tk.MustGetErrCode("create table t (a int, constraint c check (a > 0), constraint c check (a < 10))", errno.ErrCheckConstraintDupName)
+
+	// The check constraint is ignored if it isn't enabled.
+	tk.MustExec("set @@tidb_enable_check_constraint = 0")
+	tk.MustExec("create table t (a int check (a > 0))")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	tk.MustExec("insert into t values (0)")
+}
+
+func (s *testDBSuite5) TestAlterTableCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@tidb_enable_check_constraint = 1")
+	defer tk.MustExec("set @@tidb_enable_check_constraint = 0")
+
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 0)")
+	// The existing rows violate the constraint.
+	tk.MustGetErrCode("alter table t add constraint c check (b > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = 'test'").Check(testkit.Rows("0"))
+
+	tk.MustExec("alter table t add constraint c check (a > 0)")
+	tk.MustGetErrCode("alter table t add constraint c check (a < 10)", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("insert into t values (0, 1)", errno.ErrCheckConstraintViolated)
+
+	// Disable and enable the constraint.
+	tk.MustExec("alter table t alter check c not enforced")
+	tk.MustExec("insert into t values (0, 1)")
+	tk.MustGetErrCode("alter table t alter check c enforced", errno.ErrCheckConstraintViolated)
+	tk.MustExec("delete from t where a = 0")
+	tk.MustExec("alter table t alter check c enforced")
+	tk.MustGetErrCode("insert into t values (0, 1)", errno.ErrCheckConstraintViolated)
+
+	// The column used by the constraint can't be renamed.
+	tk.MustGetErrCode("alter table t rename column a to c", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table t change column a c int", errno.ErrDependentByCheckConstraint)
+
+	tk.MustGetErrCode("alter table t drop check d", errno.ErrCheckConstraintNotFound)
+	tk.MustExec("alter table t drop check c")
+	tk.MustExec("insert into t values (0, 1)")
+
+	// The constraint only depends on the dropped column is dropped with the column.
+	tk.MustExec("alter table t add constraint c_a check (a < 10)")
+	tk.MustExec("alter table t add constraint c_ab check (a < b + 10)")
+	tk.MustGetErrCode("alter table t drop column b", errno.ErrDependentByCheckConstraint)
+	tk.MustExec("alter table t drop check c_ab")
+	tk.MustExec("alter table t drop column a")
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = 'test'").Check(testkit.Rows("0"))
+
+	// The column check constraint isn't added along with the column.
+	tk.MustGetErrCode("alter table t add column c int check (c > 0)", errno.ErrUnsupportedConstraintCheck)
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'test' and table_name = 't' and column_name = 'c'").Check(testkit.Rows("0"))
+	tk.MustExec("alter table t add column c int default 0")
+	tk.MustGetErrCode("alter table t add constraint c_c check (c > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("update t set c = 1")
+	tk.MustExec("alter table t add constraint c_c check (c > 0)")
+	tk.MustGetErrCode("insert into t values (1, 0)", errno.ErrCheckConstraintViolated)
+
+	// It's ignored with a warning if the check constraint isn't enabled.
+	tk.MustExec("set @@tidb_enable_check_constraint = 0")
+	tk.MustExec("alter table t add column d int check (d > 0)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+}
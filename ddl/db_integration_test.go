@@ -798,10 +798,12 @@ func (s *testIntegrationSuite3) TestChangingCharsetToUtf8(c *C) {
 	tk.MustExec("alter table t modify column a varchar(20) charset latin1")
 	tk.MustQuery("select * from t;").Check(testkit.Rows("t_value"))
 
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8 collate utf8_bin", errno.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4 collate utf8mb4_general_ci", errno.ErrUnsupportedDDLOperation)
+	// Changing the charset from latin1 rewrites the column data.
+	tk.MustExec("alter table t modify column a varchar(20) charset utf8")
+	tk.MustExec("alter table t modify column a varchar(20) charset utf8mb4")
+	tk.MustExec("alter table t modify column a varchar(20) charset utf8mb4 collate utf8mb4_general_ci")
+	tk.MustQuery("select * from t;").Check(testkit.Rows("t_value"))
+	tk.MustGetErrCode("alter table t modify column a varchar(20) charset latin1", errno.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t modify column a varchar(20) charset utf8mb4 collate utf8bin", errno.ErrUnknownCollation)
 	tk.MustGetErrCode("alter table t collate LATIN1_GENERAL_CI charset utf8 collate utf8_bin", errno.ErrConflictingDeclarations)
@@ -2899,8 +2901,11 @@ func (s *testIntegrationSuite3) TestCreateTemporaryTable(c *C) {
 	tk.MustGetErrCode("create table t(id int) on commit delete rows", errno.ErrParse)
 	tk.MustGetErrCode("create table t(id int) on commit preserve rows", errno.ErrParse)
 
-	// Not support yet.
-	tk.MustGetErrCode("create global temporary table t (id int) on commit preserve rows", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("create global temporary table t (id int) on commit preserve rows")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE GLOBAL TEMPORARY TABLE `t` (\n" +
+		"  `id` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin ON COMMIT PRESERVE ROWS"))
+	tk.MustExec("drop table t")
 
 	// Engine type can be anyone, see https://github.com/pingcap/tidb/issues/28541.
 	tk.MustExec("drop table if exists tengine")
@@ -1301,7 +1301,41 @@ func (s *testIntegrationSuite3) TestCreateTableWithKeyPartition(c *C) {
 	partition by key(s1) partitions 10;`)
 
 	tk.MustExec(`drop table if exists tm2`)
-	tk.MustExec(`create table tm2 (a char(5), unique key(a(5))) partition by key() partitions 5;`)
+	// The unique key used by `PARTITION BY KEY()` must be NOT NULL.
+	tk.MustGetErrCode(`create table tm2 (a char(5), unique key(a)) partition by key() partitions 5;`, tmysql.ErrFieldNotFoundPart)
+	tk.MustExec(`create table tm2 (a char(5) not null, b int, unique key(a)) partition by key() partitions 5;`)
+	tbl := testGetTableByName(c, tk.Se, "test", "tm2")
+	part := tbl.Meta().Partition
+	c.Assert(part.Type, Equals, model.PartitionTypeKey)
+	c.Assert(part.Columns, DeepEquals, []model.CIStr{model.NewCIStr("a")})
+	c.Assert(part.Definitions, HasLen, 5)
+	c.Assert(part.Linear, IsFalse)
+
+	tk.MustExec(`drop table if exists tm3`)
+	tk.MustExec(`create table tm3 (a int, b varchar(10), c date, primary key(a, b)) partition by linear key(b, a) partitions 3;`)
+	tbl = testGetTableByName(c, tk.Se, "test", "tm3")
+	part = tbl.Meta().Partition
+	c.Assert(part.Columns, DeepEquals, []model.CIStr{model.NewCIStr("b"), model.NewCIStr("a")})
+	c.Assert(part.Linear, IsTrue)
+	tk.MustQuery("show create table tm3").Check(testkit.Rows("tm3 CREATE TABLE `tm3` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(10) NOT NULL,\n" +
+		"  `c` date DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`,`b`) /*T![clustered_index] NONCLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY LINEAR KEY (`b`,`a`)\n" +
+		"PARTITIONS 3"))
+	tk.MustQuery("select partition_method, partition_expression from information_schema.partitions where table_name = 'tm3' and partition_name = 'p0'").
+		Check(testkit.Rows("LINEAR KEY b,a"))
+
+	tk.MustExec(`drop table if exists tm4`)
+	tk.MustGetErrCode(`create table tm4 (a int, b text) partition by key(b) partitions 3;`, tmysql.ErrBlobFieldInPartFunc)
+	tk.MustGetErrCode(`create table tm4 (a int, b json) partition by key(b) partitions 3;`, tmysql.ErrBlobFieldInPartFunc)
+	tk.MustGetErrCode(`create table tm4 (a int, b int) partition by key(a, a) partitions 3;`, tmysql.ErrSameNamePartitionField)
+	tk.MustGetErrCode(`create table tm4 (a int, b int) partition by key(c) partitions 3;`, tmysql.ErrFieldNotFoundPart)
+	tk.MustGetErrCode(`create table tm4 (a int, b int) partition by key() partitions 3;`, tmysql.ErrFieldNotFoundPart)
+	tk.MustGetErrCode(`create table tm4 (a int, b int, unique key(a)) partition by key(b) partitions 3;`, tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustExec(`drop table if exists tm1, tm2, tm3`)
 }
 
 func (s *testIntegrationSuite5) TestAlterTableAddPartition(c *C) {
@@ -3041,11 +3075,8 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash(store_id)
 	partitions 4;`)
-	_, err := tk.Exec("alter table employees add partition partitions 8;")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
-
-	_, err = tk.Exec("alter table employees add partition (partition p5 values less than (42));")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table employees add partition (partition p5 values less than (42));", tmysql.ErrPartitionWrongValues)
+	tk.MustGetErrCode("alter table employees add partition partitions 0;", tmysql.ErrAddPartitionNoNewPartition)
 
 	// coalesce partition
 	tk.MustExec(`create table clients (
@@ -3056,24 +3087,25 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash( month(signed) )
 	partitions 12;`)
-	_, err = tk.Exec("alter table clients coalesce partition 4;")
-	c.Assert(ddl.ErrUnsupportedCoalescePartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table clients coalesce partition 12;", tmysql.ErrDropLastPartition)
+	tk.MustGetErrCode("alter table clients coalesce partition 0;", tmysql.ErrCoalescePartitionNoPartition)
 
 	tk.MustExec(`create table t_part (a int key)
 		partition by range(a) (
 		partition p0 values less than (10),
 		partition p1 values less than (20)
 		);`)
-	_, err = tk.Exec("alter table t_part coalesce partition 4;")
+	_, err := tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
-			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode(`alter table t_part reorganize partition p0 into (
+			partition p0 values less than (5));`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p2 into (
+			partition p0 values less than (20));`, tmysql.ErrDropPartitionNonExistent)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
 
 	// Reduce the impact on DML when executing partition DDL
@@ -3211,10 +3243,10 @@ func (s *testIntegrationSuite5) TestConstAndTimezoneDepent2(c *C) {
 	);`)
 }
 
-func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
+func (s *testIntegrationSuite3) TestAlterTablePartitioning(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test;")
-	tk.MustExec("drop table if exists test_1465;")
+	tk.MustExec("drop table if exists test_1465, t_np, t_uk, t_cache;")
 	tk.MustExec(`
 		create table test_1465 (a int)
 		partition by range(a) (
@@ -3223,9 +3255,109 @@ func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
 			partition p3 values less than (30)
 		);
 	`)
+	tk.MustExec("insert into test_1465 values (1), (11), (21), (null)")
+	tk.MustExec("alter table test_1465 partition by hash(a) partitions 2")
+	tk.MustQuery("show create table test_1465").Check(testkit.Rows("test_1465 CREATE TABLE `test_1465` (\n" +
+		"  `a` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY HASH( `a` )\n" +
+		"PARTITIONS 2"))
+	tk.MustQuery("select * from test_1465 partition (p0)").Sort().Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select * from test_1465 partition (p1)").Sort().Check(testkit.Rows("1", "11", "21"))
+	tk.MustExec("admin check table test_1465")
+
+	// Partition a non-partitioned table.
+	tk.MustExec("create table t_np (a int primary key, b varchar(10), key idx_b(b))")
+	tk.MustExec("insert into t_np values (1, 'a'), (5, 'b'), (15, 'c')")
+	tk.MustExec("alter table t_np partition by range(a) (partition p0 values less than (10), partition p1 values less than (maxvalue))")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = 't_np'").Sort().
+		Check(testkit.Rows("p0", "p1"))
+	tk.MustQuery("select * from t_np partition (p0)").Sort().Check(testkit.Rows("1 a", "5 b"))
+	tk.MustQuery("select * from t_np partition (p1)").Check(testkit.Rows("15 c"))
+	tk.MustQuery("select a from t_np use index(idx_b) where b = 'c'").Check(testkit.Rows("15"))
+	tk.MustExec("admin check table t_np")
+	tk.MustExec("insert into t_np values (20, 'd')")
+	tk.MustQuery("select * from t_np partition (p1)").Sort().Check(testkit.Rows("15 c", "20 d"))
+
+	// Remove the partitioning.
+	tk.MustExec("alter table t_np remove partitioning")
+	tk.MustQuery("show create table t_np").Check(testkit.Rows("t_np CREATE TABLE `t_np` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select * from t_np").Sort().Check(testkit.Rows("1 a", "15 c", "20 d", "5 b"))
+	tk.MustExec("admin check table t_np")
+	tk.MustGetErrCode("alter table t_np remove partitioning", tmysql.ErrPartitionMgmtOnNonpartitioned)
+
+	// Every unique key must include the new partition columns.
+	tk.MustExec("create table t_uk (a int, b int, unique key (a))")
+	tk.MustGetErrCode("alter table t_uk partition by hash(b) partitions 2", tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustExec("alter table t_uk partition by key(a) partitions 2")
+	tk.MustQuery("select count(*) from information_schema.partitions where table_schema = 'test' and table_name = 't_uk'").Check(testkit.Rows("2"))
+
+	tk.MustExec("create table t_cache (a int)")
+	tk.MustExec("alter table t_cache cache")
+	tk.MustGetErrCode("alter table t_cache partition by hash(a) partitions 2", tmysql.ErrOptOnCacheTable)
+	tk.MustExec("alter table t_cache nocache")
+}
+
+func (s *testIntegrationSuite3) TestReorganizePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test;")
+	tk.MustExec("drop table if exists t_range, t_list, t_hash;")
+	tk.MustExec(`create table t_range (a int primary key nonclustered, b varchar(10), key idx_b(b))
+		partition by range(a) (
+			partition p0 values less than (10),
+			partition p1 values less than (20),
+			partition p2 values less than (30)
+		);`)
+	tk.MustExec("insert into t_range values (1, 'a'), (8, 'b'), (12, 'c'), (18, 'd'), (25, 'e')")
+
+	// Split a partition.
+	tk.MustExec("alter table t_range reorganize partition p1 into (partition p1a values less than (15), partition p1b values less than (20))")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = 't_range' order by partition_ordinal_position").
+		Check(testkit.Rows("p0", "p1a", "p1b", "p2"))
+	tk.MustQuery("select * from t_range partition (p1a)").Check(testkit.Rows("12 c"))
+	tk.MustQuery("select * from t_range partition (p1b)").Check(testkit.Rows("18 d"))
+	tk.MustExec("admin check table t_range")
+
+	// Merge the adjacent partitions and extend the last one.
+	tk.MustExec("alter table t_range reorganize partition p1a, p1b, p2 into (partition p1 values less than (40))")
+	tk.MustQuery("select * from t_range partition (p1)").Sort().Check(testkit.Rows("12 c", "18 d", "25 e"))
+	tk.MustQuery("select * from t_range partition (p0)").Sort().Check(testkit.Rows("1 a", "8 b"))
+	tk.MustQuery("select a from t_range use index(idx_b) where b = 'e'").Check(testkit.Rows("25"))
+	tk.MustExec("admin check table t_range")
+	tk.MustExec("insert into t_range values (35, 'f')")
+	tk.MustGetErrCode("alter table t_range reorganize partition p0 into (partition p0 values less than (5), partition p0b values less than (9))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t_range reorganize partition p1 into (partition p0 values less than (40))", tmysql.ErrSameNamePartition)
 
-	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec(`create table t_list (a int) partition by list(a) (
+			partition p0 values in (1, 2, 3),
+			partition p1 values in (4, 5, 6)
+		);`)
+	tk.MustExec("insert into t_list values (1), (2), (4), (6)")
+	tk.MustExec("alter table t_list reorganize partition p0, p1 into (partition p0 values in (1, 4), partition p1 values in (2, 3, 5, 6))")
+	tk.MustQuery("select * from t_list partition (p0)").Sort().Check(testkit.Rows("1", "4"))
+	tk.MustQuery("select * from t_list partition (p1)").Sort().Check(testkit.Rows("2", "6"))
+	tk.MustExec("admin check table t_list")
+	// The rows which don't belong to any new partition make the job roll back.
+	tk.MustGetErrCode("alter table t_list reorganize partition p1 into (partition p1 values in (2, 3, 5))", tmysql.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select * from t_list").Sort().Check(testkit.Rows("1", "2", "4", "6"))
+
+	tk.MustExec("create table t_hash (a int) partition by hash(a) partitions 2")
+	tk.MustExec("insert into t_hash values (1), (2), (3), (4), (5)")
+	tk.MustExec("alter table t_hash add partition partitions 2")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = 't_hash' order by partition_ordinal_position").
+		Check(testkit.Rows("p0", "p1", "p2", "p3"))
+	tk.MustQuery("select * from t_hash partition (p1)").Sort().Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select * from t_hash partition (p3)").Sort().Check(testkit.Rows("3"))
+	tk.MustExec("alter table t_hash coalesce partition 3")
+	tk.MustQuery("select * from t_hash partition (p0)").Sort().Check(testkit.Rows("1", "2", "3", "4", "5"))
+	tk.MustExec("admin check table t_hash")
+	tk.MustGetErrCode("alter table t_hash reorganize partition p0 into (partition p0)", tmysql.ErrOnlyOnRangeListPartition)
 }
 
 func (s *testSerialDBSuite1) TestCommitWhenSchemaChange(c *C) {
@@ -3465,6 +3597,96 @@ func (s *testSerialDBSuite1) TestAddTableWithPartition(c *C) {
 	tk.MustExec("drop table if exists local_partition_list_table;")
 }
 
+func (s *testSerialDBSuite1) TestAlterTablePartitioningWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t, t_expect")
+	tk.MustExec("create table t (a int primary key, b int, c int, key idx_b(b), unique key uk_ac(a, c))")
+	tk.MustExec("create table t_expect (a int primary key, b int, c int)")
+	for i := 1; i <= 8; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d, %d)", i, i, i))
+		tk.MustExec(fmt.Sprintf("insert into t_expect values (%d, %d, %d)", i, i, i))
+	}
+
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	dom.DDL().SetHook(hook)
+
+	// The rows are written to t in every state of the change, the same changes are made to t_expect
+	// which isn't changed by the DDL.
+	states := []model.SchemaState{model.StateDeleteOnly, model.StateWriteOnly, model.StateWriteReorganization, model.StateDeleteReorganization}
+	for run, sql := range []string{
+		"alter table t partition by range(a) (partition p0 values less than (10), partition p1 values less than (maxvalue))",
+		"alter table t partition by hash(a) partitions 3",
+		"alter table t remove partitioning",
+	} {
+		var checkErr error
+		done := make(map[model.SchemaState]bool)
+		hook.OnJobUpdatedExported = func(job *model.Job) {
+			if (job.Type != model.ActionAlterTablePartitioning && job.Type != model.ActionRemovePartitioning) || checkErr != nil || done[job.SchemaState] {
+				return
+			}
+			for i, state := range states {
+				if job.SchemaState != state {
+					continue
+				}
+				done[state] = true
+				v := 100*(run+1) + i
+				for _, tbl := range []string{"t", "t_expect"} {
+					for _, dml := range []string{
+						fmt.Sprintf("insert into %s values (%d, %d, %d)", tbl, v, v, v),
+						fmt.Sprintf("update %s set b = b + 1, c = c + 1 where a = %d", tbl, i+1),
+						// Move the row inserted by the last change to another partition.
+						fmt.Sprintf("update %s set a = a + 1000 where a = %d", tbl, v-100),
+						fmt.Sprintf("delete from %s where a = %d", tbl, i+5),
+					} {
+						if _, checkErr = tk2.Exec(dml); checkErr != nil {
+							return
+						}
+					}
+				}
+			}
+		}
+		tk.MustExec(sql)
+		c.Assert(checkErr, IsNil)
+		for _, state := range states {
+			c.Assert(done[state], IsTrue, Commentf("%s in %s", sql, state))
+		}
+		tk.MustExec("admin check table t")
+		tk.MustQuery("select * from t order by a").Check(tk.MustQuery("select * from t_expect order by a").Rows())
+		tk.MustQuery("select a from t use index(idx_b) where b > 0 order by a").Check(tk.MustQuery("select a from t_expect order by a").Rows())
+	}
+}
+
+// The partitions can't share the ID of the non-partitioned table, so the table ID changes when a non-partitioned
+// table is partitioned and when the partitioning is removed. It's observed as a new table by the tools tracking the
+// tables by their IDs, like BR and TiCDC.
+func (s *testSerialDBSuite1) TestAlterTablePartitioningTableID(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1), (2), (3)")
+	oldID := testGetTableByName(c, tk.Se, "test", "t").Meta().ID
+
+	tk.MustExec("alter table t partition by hash(a) partitions 2")
+	tblInfo := testGetTableByName(c, tk.Se, "test", "t").Meta()
+	c.Assert(tblInfo.ID, Not(Equals), oldID)
+	for _, def := range tblInfo.Partition.Definitions {
+		c.Assert(def.ID, Not(Equals), oldID)
+	}
+	partitionedID := tblInfo.ID
+
+	// Reorganizing the partitions of a partitioned table keeps the table ID.
+	tk.MustExec("alter table t partition by hash(a) partitions 3")
+	c.Assert(testGetTableByName(c, tk.Se, "test", "t").Meta().ID, Equals, partitionedID)
+
+	tk.MustExec("alter table t remove partitioning")
+	c.Assert(testGetTableByName(c, tk.Se, "test", "t").Meta().ID, Not(Equals), partitionedID)
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1", "2", "3"))
+}
+
 func (s *testSerialDBSuite1) TestTruncatePartitionMultipleTimes(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("drop table if exists test.t;")
@@ -2469,12 +2469,13 @@ LOOP:
 
 	// test add unsupported constraint
 	s.mustExec(tk, c, "create table t_add_unsupported_constraint (a int);")
-	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD id int AUTO_INCREMENT;")
-	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint AUTO_INCREMENT when altering 'test_db.t_add_unsupported_constraint'")
-	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD id int KEY;")
-	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint PRIMARY KEY when altering 'test_db.t_add_unsupported_constraint'")
-	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD id int UNIQUE;")
-	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint UNIQUE KEY when altering 'test_db.t_add_unsupported_constraint'")
+	tk.MustGetErrCode("ALTER TABLE t_add_unsupported_constraint ADD id int AUTO_INCREMENT;", errno.ErrWrongAutoKey)
+	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD (id int AUTO_INCREMENT, b int);")
+	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint AUTO_INCREMENT when altering 'test_db.t_add_unsupported_constraint' with multiple columns")
+	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD (id int KEY, b int);")
+	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint PRIMARY KEY when altering 'test_db.t_add_unsupported_constraint' with multiple columns")
+	_, err = tk.Exec("ALTER TABLE t_add_unsupported_constraint ADD (id int UNIQUE, b int);")
+	c.Assert(err.Error(), Equals, "[ddl:8200]unsupported add column 'id' constraint UNIQUE KEY when altering 'test_db.t_add_unsupported_constraint' with multiple columns")
 }
 
 func (s *testDBSuite) testDropColumn(tk *testkit.TestKit, c *C) {
@@ -3305,6 +3306,38 @@ func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk.MustExec("drop table if exists t1,t2,t3,t4;")
 }
 
+func (s *testDBSuite2) TestForeignKeyIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3, parent")
+	tk.MustExec("create table parent (id int primary key)")
+	// The index is created on the foreign key columns if there isn't one.
+	tk.MustExec("create table t1 (id int, pid int, constraint fk_pid foreign key (pid) references parent (id))")
+	tk.MustQuery("select key_name, column_name from information_schema.tidb_indexes where table_schema = 'test' and table_name = 't1'").Check(testkit.Rows("fk_pid pid"))
+	tk.MustExec("create table t2 (id int, pid int, key idx_pid(pid, id), constraint fk_pid foreign key (pid) references parent (id))")
+	tk.MustQuery("select key_name, column_name from information_schema.tidb_indexes where table_schema = 'test' and table_name = 't2'").Check(testkit.Rows("idx_pid pid", "idx_pid id"))
+	tk.MustExec("create table t3 (id int, pid int, key fk_pid(id), constraint fk_pid foreign key (pid) references parent (id))")
+	tk.MustQuery("select key_name, column_name from information_schema.tidb_indexes where table_schema = 'test' and table_name = 't3'").Sort().Check(testkit.Rows("fk_pid id", "fk_pid_2 pid"))
+
+	// The index should be created before adding the foreign key.
+	tk.MustExec("alter table t3 drop foreign key fk_pid")
+	tk.MustGetErrCode("alter table t3 add constraint fk_id foreign key (id, pid) references parent (id, id)", errno.ErrFkNoIndexChild)
+	tk.MustExec("alter table t3 drop index fk_pid_2")
+	tk.MustGetErrCode("alter table t3 add constraint fk_pid foreign key (pid) references parent (id)", errno.ErrFkNoIndexChild)
+	tk.MustExec("alter table t3 add index idx_pid(pid)")
+	tk.MustExec("alter table t3 add constraint fk_pid foreign key (pid) references parent (id)")
+
+	// The only index on the foreign key columns can't be dropped.
+	tk.MustGetErrCode("alter table t1 drop index fk_pid", errno.ErrDropIndexFk)
+	tk.MustGetErrCode("alter table t3 drop index idx_pid, drop index fk_pid", errno.ErrDropIndexFk)
+	tk.MustExec("alter table t1 add index idx_pid_id(pid, id)")
+	tk.MustExec("alter table t1 drop index fk_pid")
+	tk.MustGetErrCode("alter table t1 drop index idx_pid_id", errno.ErrDropIndexFk)
+	tk.MustExec("alter table t1 drop foreign key fk_pid")
+	tk.MustExec("alter table t1 drop index idx_pid_id")
+	tk.MustExec("drop table t1, t2, t3, parent")
+}
+
 func (s *testDBSuite2) TestDuplicateForeignKey(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -3362,14 +3395,14 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	// foreign key constraint can be defined on a stored generated column.
 	tk.MustExec("create table t2 (a int primary key);")
 	tk.MustExec("create table t1 (a int, b int as (a+1) stored, foreign key (b) references t2(a));")
-	tk.MustExec("create table t3 (a int, b int generated always as (a+1) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a+1) stored, key(b));")
 	tk.MustExec("alter table t3 add foreign key (b) references t2(a);")
 	tk.MustExec("drop table t1, t2, t3;")
 
 	// foreign key constraint can reference a stored generated column.
 	tk.MustExec("create table t1 (a int, b int generated always as (a+1) stored primary key);")
 	tk.MustExec("create table t2 (a int, foreign key (a) references t1(b));")
-	tk.MustExec("create table t3 (a int);")
+	tk.MustExec("create table t3 (a int, key(a));")
 	tk.MustExec("alter table t3 add foreign key (a) references t1(b);")
 	tk.MustExec("drop table t1, t2, t3;")
 
@@ -3409,15 +3442,15 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, foreign key (b) references t1(a) on delete cascade);")
 	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored, foreign key (b) references t1(a) on delete no action);")
 	tk.MustExec("drop table t2,t3,t4,t5,t6;")
-	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t2 add foreign key (b) references t1(a) on update restrict;")
-	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t3 add foreign key (b) references t1(a) on update no action;")
-	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t4 add foreign key (b) references t1(a) on delete restrict;")
-	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t5 add foreign key (b) references t1(a) on delete cascade;")
-	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t6 add foreign key (b) references t1(a) on delete no action;")
 	tk.MustExec("drop table t1,t2,t3,t4,t5,t6;")
 
@@ -3445,13 +3478,13 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, foreign key (a) references t1(a) on delete restrict);")
 	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, foreign key (a) references t1(a) on delete no action);")
 	tk.MustExec("drop table t2,t3,t4,t5")
-	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t2 add foreign key (a) references t1(a) on update restrict;")
-	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t3 add foreign key (a) references t1(a) on update no action;")
-	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t4 add foreign key (a) references t1(a) on delete restrict;")
-	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t5 add foreign key (a) references t1(a) on delete no action;")
 	tk.MustExec("drop table t1,t2,t3,t4,t5;")
 }
@@ -3944,7 +3977,7 @@ func (s *testDBSuite3) TestGeneratedColumnDDL(c *C) {
 		{`create table test_gv_ddl_bad (a int, b int, c int as (a+b), primary key(a, c))`, errno.ErrUnsupportedOnGeneratedColumn},
 
 		// Add stored generated column through alter table.
-		{`alter table test_gv_ddl add column d int as (b+2) stored`, errno.ErrUnsupportedOnGeneratedColumn},
+		{`alter table test_gv_ddl add column (d int as (b+2) stored, e int)`, errno.ErrUnsupportedOnGeneratedColumn},
 		{`alter table test_gv_ddl modify column b int as (a + 8) stored`, errno.ErrUnsupportedOnGeneratedColumn},
 
 		// Add generated column with incorrect parameter count.
@@ -4775,6 +4808,76 @@ func (s *testDBSuite3) TestTransactionWithWriteOnlyColumn(c *C) {
 	tk.MustQuery("select a from t1").Check(testkit.Rows("2"))
 }
 
+func (s *testDBSuite4) TestAddColumnWithReorg(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.mustExec(tk, c, "use test_db")
+	s.mustExec(tk, c, "drop table if exists t_reorg_col, t_reorg_col_part")
+	s.mustExec(tk, c, "create table t_reorg_col (a int);")
+	defer s.mustExec(tk, c, "drop table if exists t_reorg_col, t_reorg_col_part")
+	s.mustExec(tk, c, "insert into t_reorg_col values (1), (2), (3);")
+
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test_db")
+	originHook := s.dom.DDL().GetHook()
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	var checkErr error
+	var writeOnlyDone, writeReorgDone bool
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionAddColumn || checkErr != nil {
+			return
+		}
+		switch job.SchemaState {
+		case model.StateWriteOnly:
+			if !writeOnlyDone {
+				writeOnlyDone = true
+				if _, checkErr = tk2.Exec("insert into t_reorg_col (a) values (10)"); checkErr != nil {
+					return
+				}
+				_, checkErr = tk2.Exec("update t_reorg_col set a = 101 where a = 1")
+			}
+		case model.StateWriteReorganization:
+			if !writeReorgDone {
+				writeReorgDone = true
+				_, checkErr = tk2.Exec("insert into t_reorg_col (a) values (20)")
+			}
+		}
+	}
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	// Add a stored generated column.
+	s.mustExec(tk, c, "alter table t_reorg_col add column b int as (a * 2) stored")
+	c.Assert(checkErr, IsNil)
+	tk.MustQuery("select a, b from t_reorg_col order by a").Check(testkit.Rows("2 4", "3 6", "10 20", "20 40", "101 202"))
+
+	// Add an AUTO_INCREMENT primary key column.
+	writeOnlyDone, writeReorgDone = false, false
+	s.mustExec(tk, c, "alter table t_reorg_col add column id int auto_increment primary key")
+	c.Assert(checkErr, IsNil)
+	tk.MustQuery("select count(*), count(distinct id), min(id) > 0 from t_reorg_col").Check(testkit.Rows("7 7 1"))
+	tk.MustQuery("select b from t_reorg_col where a = 10").Check(testkit.Rows("20", "20"))
+	tk.MustExec("admin check table t_reorg_col")
+	tk.MustExec("insert into t_reorg_col (a) values (30)")
+	tk.MustQuery("select count(distinct id) from t_reorg_col").Check(testkit.Rows("8"))
+
+	// Add a unique column, the duplicated values make the job roll back.
+	s.dom.DDL().(ddl.DDLForTest).SetHook(originHook)
+	tk.MustGetErrCode("alter table t_reorg_col add column c int default 5 unique", errno.ErrDupEntry)
+	tk.MustGetErrCode("select c from t_reorg_col", errno.ErrBadField)
+	s.mustExec(tk, c, "alter table t_reorg_col add column c int unique")
+	tk.MustExec("admin check table t_reorg_col")
+	tk.MustExec("update t_reorg_col set c = id")
+	tk.MustGetErrCode("update t_reorg_col set c = 0 where a = 10", errno.ErrDupEntry)
+
+	// Add columns which break the constraints.
+	tk.MustGetErrCode("alter table t_reorg_col add column d int primary key", errno.ErrMultiplePriKey)
+	tk.MustGetErrCode("alter table t_reorg_col add column d int auto_increment unique", errno.ErrWrongAutoKey)
+	tk.MustGetErrCode("alter table t_reorg_col add column d int as (a + 1) unique", errno.ErrUnsupportedOnGeneratedColumn)
+	s.mustExec(tk, c, "create table t_reorg_col_part (a int) partition by hash(a) partitions 2;")
+	tk.MustGetErrCode("alter table t_reorg_col_part add column b int as (a + 1) stored", errno.ErrUnsupportedOnGeneratedColumn)
+	tk.MustGetErrCode("alter table t_reorg_col_part add column b int unique", errno.ErrUniqueKeyNeedAllFieldsInPf)
+}
+
 func (s *testDBSuite4) TestAddColumn2(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	s.mustExec(tk, c, "use test_db")
@@ -831,7 +831,20 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				if !ctx.GetSessionVars().EnableCheckConstraint {
+					ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+					break
+				}
+				// Column check constraint is converted to table check constraint, which only refers to the column itself.
+				constraint := &ast.Constraint{
+					Tp:           ast.ConstraintCheck,
+					Name:         v.ConstraintName,
+					Expr:         v.Expr,
+					Enforced:     v.Enforced,
+					InColumn:     true,
+					InColumnName: colDef.Name.Name.O,
+				}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -1360,7 +1373,11 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 	fkNames := map[string]bool{}
 
 	// Check not empty constraint name whether is duplicated.
+	// The names of check constraints are checked and set when building the check constraints.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			err := checkDuplicateConstraint(fkNames, constr.Name, true)
 			if err != nil {
@@ -1376,6 +1393,9 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 
 	// Set empty constraint names.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			setEmptyConstraintName(fkNames, constr, true)
 		} else {
@@ -1527,6 +1547,7 @@ func buildTableInfo(
 		tbInfo.Columns = append(tbInfo.Columns, v.ToInfo())
 		tblColumns = append(tblColumns, table.ToColumn(v.ToInfo()))
 	}
+	var checkConstraints []*ast.Constraint
 	for _, constr := range constraints {
 		// Build hidden columns if necessary.
 		hiddenCols, err := buildHiddenColumnInfo(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
@@ -1590,7 +1611,11 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			if !ctx.GetSessionVars().EnableCheckConstraint {
+				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				continue
+			}
+			checkConstraints = append(checkConstraints, constr)
 			continue
 		}
 		// build index info.
@@ -1610,6 +1635,9 @@ func buildTableInfo(
 		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
 			idxInfo.Unique = true
 		}
+		if idxInfo.Unique && idxInfo.MVIndex {
+			return nil, errUnsupportedMultiValuedIndex.GenWithStackByArgs("unique multi-valued index")
+		}
 		// set index type.
 		if constr.Option != nil {
 			idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), constr.Option)
@@ -1632,6 +1660,14 @@ func buildTableInfo(
 		idxInfo.ID = allocateIndexID(tbInfo)
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
+	if err = buildForeignKeyIndexes(tbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(checkConstraints) > 0 {
+		if err = buildCheckConstraints(ctx, tbInfo, checkConstraints); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if tbInfo.IsCommonHandle {
 		// Ensure tblInfo's each non-unique secondary-index's len + primary-key's len <= MaxIndexLength for clustered index table.
 		var pkLen, idxLen int
@@ -1776,7 +1812,7 @@ func checkPartitionDefinitionConstraints(ctx sessionctx.Context, tbInfo *model.T
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
 		err = checkPartitionByRange(ctx, tbInfo)
-	case model.PartitionTypeHash:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
 		err = checkPartitionByHash(ctx, tbInfo)
 	case model.PartitionTypeList:
 		err = checkPartitionByList(ctx, tbInfo)
@@ -2011,6 +2047,9 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	if err = checkTableForeignKeysValid(ctx, is, schema.Name, tbInfo); err != nil {
+		return err
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
@@ -2025,9 +2064,7 @@ func setTemporaryType(ctx sessionctx.Context, tbInfo *model.TableInfo, s *ast.Cr
 	case ast.TemporaryGlobal:
 		tbInfo.TempTableType = model.TempTableGlobal
 		// "create global temporary table ... on commit preserve rows"
-		if !s.OnCommitDelete {
-			return errors.Trace(errUnsupportedOnCommitPreserve)
-		}
+		tbInfo.TempTablePreserveRows = !s.OnCommitDelete
 	case ast.TemporaryLocal:
 		tbInfo.TempTableType = model.TempTableLocal
 	default:
@@ -2247,8 +2284,14 @@ func buildViewInfo(ctx sessionctx.Context, s *ast.CreateViewStmt) (*model.ViewIn
 		return nil, err
 	}
 
+	// Keep the check option as the older versions do when the clause is absent, so
+	// that they can still read it.
+	checkOption, specified := s.CheckOption, s.CheckOption != model.CheckOptionNone
+	if !specified {
+		checkOption = model.CheckOptionCascaded
+	}
 	return &model.ViewInfo{Definer: s.Definer, Algorithm: s.Algorithm,
-		Security: s.Security, SelectStmt: sb.String(), CheckOption: s.CheckOption, Cols: nil}, nil
+		Security: s.Security, SelectStmt: sb.String(), CheckOption: checkOption, CheckOptionSpecified: specified, Cols: nil}, nil
 }
 
 func checkPartitionByHash(ctx sessionctx.Context, tbInfo *model.TableInfo) error {
@@ -2685,7 +2728,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(sctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(sctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2693,7 +2736,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableOptimizePartition:
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(errUnsupportedRemovePartition)
+			err = d.RemovePartitioning(sctx, ident, spec)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2746,7 +2789,11 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			case ast.ConstraintFulltext:
 				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				if !sctx.GetSessionVars().EnableCheckConstraint {
+					sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				} else {
+					err = d.CreateCheckConstraint(sctx, ident, model.NewCIStr(constr.Name), spec.Constraint)
+				}
 			default:
 				// Nothing to do now.
 			}
@@ -2772,8 +2819,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 				err = errors.New("alter partition alter placement is experimental and it is switched off by tidb_enable_alter_placement")
 			}
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(sctx, ident, spec)
 		case ast.AlterTableOption:
 			var placementSettings *model.PlacementSettings
 			var placementPolicyRef *model.PolicyRefInfo
@@ -2845,9 +2891,17 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableIndexInvisible:
 			err = d.AlterIndexVisibility(sctx, ident, spec.IndexName, spec.Visibility)
 		case ast.AlterTableAlterCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			if !sctx.GetSessionVars().EnableCheckConstraint {
+				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			} else {
+				err = d.AlterCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name), spec.Constraint.Enforced)
+			}
 		case ast.AlterTableDropCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			if !sctx.GetSessionVars().EnableCheckConstraint {
+				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			} else {
+				err = d.DropCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name))
+			}
 		case ast.AlterTableWithValidation:
 			sctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -2988,17 +3042,31 @@ func (d *ddl) getSchemaAndTableByIdent(ctx sessionctx.Context, tableIdent ast.Id
 }
 
 func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
+	for _, constraint := range col.Options {
+		if constraint.Tp == ast.ColumnOptionAutoRandom {
+			errMsg := fmt.Sprintf(autoid.AutoRandomAlterAddColumn, col.Name, ti.Schema, ti.Name)
+			return ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
+		}
+	}
+
+	return nil
+}
+
+// checkUnsupportedMultiColumnsConstraint checks the constraints which need to backfill the existing rows,
+// they are only supported when adding a single column.
+func checkUnsupportedMultiColumnsConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 	for _, constraint := range col.Options {
 		switch constraint.Tp {
 		case ast.ColumnOptionAutoIncrement:
-			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint AUTO_INCREMENT when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
+			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint AUTO_INCREMENT when altering '%s.%s' with multiple columns", col.Name, ti.Schema, ti.Name)
 		case ast.ColumnOptionPrimaryKey:
-			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint PRIMARY KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
+			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint PRIMARY KEY when altering '%s.%s' with multiple columns", col.Name, ti.Schema, ti.Name)
 		case ast.ColumnOptionUniqKey:
-			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint UNIQUE KEY when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
-		case ast.ColumnOptionAutoRandom:
-			errMsg := fmt.Sprintf(autoid.AutoRandomAlterAddColumn, col.Name, ti.Schema, ti.Name)
-			return ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
+			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint UNIQUE KEY when altering '%s.%s' with multiple columns", col.Name, ti.Schema, ti.Name)
+		case ast.ColumnOptionGenerated:
+			if constraint.Stored {
+				return ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE with multiple columns")
+			}
 		}
 	}
 
@@ -3038,8 +3106,8 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 				return nil, errors.Trace(err)
 			}
 
-			if option.Stored {
-				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
+			if option.Stored && t.Meta().GetPartitionInfo() != nil {
+				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column to a partitioned table through ALTER TABLE")
 			}
 
 			_, dependColNames := findDependedColumnNames(specNewColumn)
@@ -3086,7 +3154,7 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	}
 	// Ignore table constraints now, they will be checked later.
 	// We use length(t.Cols()) as the default offset firstly, we will change the column's offset later.
-	col, _, err = buildColumnAndConstraint(
+	col, constraints, err := buildColumnAndConstraint(
 		ctx,
 		len(t.Cols()),
 		specNewColumn,
@@ -3097,6 +3165,13 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The constraint can't be added along with the column since the existing rows are not validated,
+	// it should be added by ALTER TABLE ... ADD CONSTRAINT ... CHECK after the column is added.
+	for _, constraint := range constraints {
+		if constraint.Tp == ast.ConstraintCheck {
+			return nil, errors.Trace(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD COLUMN CHECK"))
+		}
+	}
 
 	originDefVal, err := generateOriginDefaultValue(col.ToInfo())
 	if err != nil {
@@ -3107,6 +3182,74 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	return col, err
 }
 
+// buildAddingColumnIndexInfo builds the index created by the PRIMARY KEY or UNIQUE option of the adding column.
+// It returns nil if the column doesn't have such an option.
+func buildAddingColumnIndexInfo(t table.Table, ti ast.Ident, specNewColumn *ast.ColumnDef, col *table.Column) (*model.IndexInfo, error) {
+	tblInfo := t.Meta()
+	var primary, unique bool
+	for _, option := range specNewColumn.Options {
+		switch option.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			primary = true
+		case ast.ColumnOptionUniqKey:
+			unique = true
+		}
+	}
+	if mysql.HasAutoIncrementFlag(col.Flag) {
+		if tblInfo.GetAutoIncrementColInfo() != nil || (!primary && !unique) {
+			return nil, autoid.ErrWrongAutoKey
+		}
+		if tblInfo.GetPartitionInfo() != nil {
+			return nil, errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint AUTO_INCREMENT when altering partitioned table '%s.%s'", col.Name, ti.Schema, ti.Name)
+		}
+	}
+	if !primary && !unique {
+		return nil, nil
+	}
+
+	var indexName model.CIStr
+	if primary {
+		if tblInfo.IsCommonHandle {
+			return nil, ErrUnsupportedModifyPrimaryKey.GenWithStackByArgs("add")
+		}
+		if tblInfo.PKIsHandle || tblInfo.FindIndexByName(strings.ToLower(mysql.PrimaryKeyName)) != nil {
+			return nil, infoschema.ErrMultiplePriKey
+		}
+		indexName = model.NewCIStr(mysql.PrimaryKeyName)
+	} else {
+		indexName = getAnonymousIndex(t, col.Name, model.NewCIStr(""))
+	}
+	if col.IsGenerated() && !col.GeneratedStored {
+		return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding an index on a virtual generated column through ADD COLUMN")
+	}
+
+	columns := append(append(make([]*model.ColumnInfo, 0, len(tblInfo.Columns)+1), tblInfo.Columns...), col.ColumnInfo)
+	indexPartSpecifications := []*ast.IndexPartSpecification{{Column: specNewColumn.Name, Length: types.UnspecifiedLength}}
+	idxColumns, err := buildIndexColumns(columns, indexPartSpecifications)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		ck, err := checkPartitionKeysConstraint(pi, idxColumns, tblInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !ck {
+			if primary {
+				return nil, ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			}
+			return nil, ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+	}
+	return &model.IndexInfo{
+		Name:    indexName,
+		Columns: idxColumns,
+		Primary: primary,
+		Unique:  true,
+		Tp:      model.IndexTypeBtree,
+	}, nil
+}
+
 // AddColumn will add a new column to the table.
 func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
 	specNewColumn := spec.NewColumns[0]
@@ -3126,13 +3269,26 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		return nil
 	}
 
+	idxInfo, err := buildAddingColumnIndexInfo(t, ti, specNewColumn, col)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	job := &model.Job{
 		SchemaID:   schema.ID,
 		TableID:    t.Meta().ID,
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddColumn,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{col, spec.Position, 0},
+		Args:       []interface{}{col, spec.Position, 0, idxInfo},
+	}
+	if idxInfo != nil || needBackfillAddingColumn(col.ColumnInfo) {
+		job.ReorgMeta = &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		}
+		job.Priority = ctx.GetSessionVars().DDLReorgPriority
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -3180,6 +3336,9 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				ctx.GetSessionVars().StmtCtx.AppendNote(err)
 				continue
 			}
+			if err = checkUnsupportedMultiColumnsConstraint(specNewColumn, ti); err != nil {
+				return errors.Trace(err)
+			}
 			col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 			if err != nil {
 				return errors.Trace(err)
@@ -3236,6 +3395,22 @@ func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	if pi == nil {
 		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
+	if pi.Type == model.PartitionTypeHash || pi.Type == model.PartitionTypeKey {
+		// The rows of all the partitions are rehashed into the new number of partitions.
+		num := spec.Num
+		if len(spec.PartDefinitions) > 0 {
+			for _, def := range spec.PartDefinitions {
+				if err := def.Clause.Validate(pi.Type, 0); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			num = uint64(len(spec.PartDefinitions))
+		}
+		if num == 0 {
+			return errors.Trace(ErrAddPartitionNoNewPartition)
+		}
+		return d.reorganizeHashPartitions(ctx, schema, meta, spec.PartDefinitions, uint64(len(pi.Definitions))+num)
+	}
 
 	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
 	if err != nil {
@@ -3298,21 +3473,351 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	}
 
 	switch meta.Partition.Type {
-	// We don't support coalesce partitions hash type partition now.
-	case model.PartitionTypeHash:
-		return errors.Trace(ErrUnsupportedCoalescePartition)
-
-	// Key type partition cannot be constructed currently, ignoring it for now.
-	case model.PartitionTypeKey:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
+		num := uint64(len(meta.Partition.Definitions))
+		if spec.Num == 0 {
+			return errors.Trace(ErrCoalescePartitionNoPartition)
+		}
+		if spec.Num >= num {
+			return errors.Trace(ErrDropLastPartition)
+		}
+		return d.reorganizeHashPartitions(ctx, schema, meta, nil, num-spec.Num)
 
 	// Coalesce partition can only be used on hash/key partitions.
 	default:
 		return errors.Trace(ErrCoalesceOnlyOnHashPartition)
 	}
+}
+
+// reorganizeHashPartitions changes the number of the hash or key partitions, the rows of all the partitions are
+// rehashed into the new partitions. The existing partitions keep their names and options, the added ones are named
+// by defs or by their positions.
+func (d *ddl) reorganizeHashPartitions(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, defs []*ast.PartitionDefinition, num uint64) error {
+	if err := checkChangePartitioning(meta); err != nil {
+		return errors.Trace(err)
+	}
+	pi := meta.Partition
+	partInfo := &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Linear:      pi.Linear,
+		Enable:      pi.Enable,
+		Num:         num,
+		Definitions: make([]model.PartitionDefinition, 0, num),
+	}
+	for i := 0; i < int(num); i++ {
+		if i < len(pi.Definitions) {
+			def := pi.Definitions[i].Clone()
+			def.ID = 0
+			partInfo.Definitions = append(partInfo.Definitions, def)
+			continue
+		}
+		def := model.PartitionDefinition{Name: model.NewCIStr(fmt.Sprintf("p%d", i))}
+		if j := i - len(pi.Definitions); j < len(defs) {
+			def.Name = defs[j].Name
+			def.Comment, _ = defs[j].Comment()
+		}
+		partInfo.Definitions = append(partInfo.Definitions, def)
+	}
+	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	newMeta := meta.Clone()
+	newMeta.Partition = partInfo
+	if err := checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	return d.doReorganizePartitionJob(ctx, schema, meta, partInfo, nil)
+}
+
+// ReorganizePartitions reorganizes the consecutive range or list partitions into the new ones, e.g. splits a
+// partition into many or merges the adjacent ones.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if spec.OnAllPartitions || len(spec.PartDefinitions) == 0 {
+		// Rebuilding the hash partitions by REORGANIZE PARTITION without INTO is not supported.
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if pi.Type != model.PartitionTypeRange && pi.Type != model.PartitionTypeList {
+		return errors.Trace(errOnlyOnRangeListPartition.GenWithStackByArgs("REORGANIZE"))
+	}
+	if err = checkChangePartitioning(meta); err != nil {
+		return errors.Trace(err)
+	}
+
+	partNames := make([]string, 0, len(spec.PartitionNames))
+	for _, name := range spec.PartitionNames {
+		if _, err = tables.FindPartitionByName(meta, name.L); err != nil {
+			return errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+		partNames = append(partNames, name.L)
+	}
+	droppingDefs, err := getReorganizedPartitionDefs(pi, partNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	defs, err := buildPartitionDefinitionsInfo(ctx, spec.PartDefinitions, meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(defs); err != nil {
+		return errors.Trace(err)
+	}
+	// The partition keeps its placement if it is not given in the new definition.
+	for i := range defs {
+		for _, old := range droppingDefs {
+			if defs[i].Name.L == old.Name.L && defs[i].PlacementPolicyRef == nil && defs[i].DirectPlacementOpts == nil {
+				defs[i].PlacementPolicyRef, defs[i].DirectPlacementOpts = old.PlacementPolicyRef, old.DirectPlacementOpts
+			}
+		}
+	}
+	partInfo := &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Linear:      pi.Linear,
+		Enable:      pi.Enable,
+		Definitions: defs,
+	}
+
+	// Check the partitions after the reorganization.
+	// The partition info isn't copied by Clone.
+	newMeta := meta.Clone()
+	newPi := *meta.Partition
+	newMeta.Partition = &newPi
+	newPi.DDLState, newPi.DroppingDefinitions, newPi.AddingDefinitions = model.StateNone, droppingDefs, defs
+	newPi.Definitions = newPi.ReorganizedDefinitions()
+	newPi.DroppingDefinitions, newPi.AddingDefinitions = nil, nil
+	newPi.Num = uint64(len(newPi.Definitions))
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if pi.Type == model.PartitionTypeRange {
+		if err = checkReorganizedRange(ctx, meta, droppingDefs[len(droppingDefs)-1], defs[len(defs)-1]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return d.doReorganizePartitionJob(ctx, schema, meta, partInfo, partNames)
+}
 
+// checkReorganizedRange checks that the reorganized range partitions cover the same range as the old ones, except
+// that the range can be extended if the last partition of the table is reorganized.
+func checkReorganizedRange(ctx sessionctx.Context, tblInfo *model.TableInfo, oldLast, newLast model.PartitionDefinition) error {
+	less, err := isRangePartitionLess(ctx, tblInfo, newLast, oldLast)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if less {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	defs := tblInfo.Partition.Definitions
+	if defs[len(defs)-1].ID == oldLast.ID {
+		return nil
+	}
+	greater, err := isRangePartitionLess(ctx, tblInfo, oldLast, newLast)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if greater {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	return nil
+}
+
+// isRangePartitionLess reports whether the upper bound of range partition a is less than that of b.
+func isRangePartitionLess(ctx sessionctx.Context, tblInfo *model.TableInfo, a, b model.PartitionDefinition) (bool, error) {
+	pi := tblInfo.Partition
+	if len(pi.Columns) > 0 {
+		return checkTwoRangeColumns(ctx, &b, &a, pi, tblInfo)
+	}
+	aMax := strings.EqualFold(a.LessThan[0], partitionMaxValue)
+	bMax := strings.EqualFold(b.LessThan[0], partitionMaxValue)
+	if aMax || bMax {
+		return !aMax && bMax, nil
+	}
+	tmpPi := *pi
+	tmpPi.Definitions = []model.PartitionDefinition{
+		{LessThan: []string{a.LessThan[0]}},
+		{LessThan: []string{b.LessThan[0]}},
+	}
+	tmp := *tblInfo
+	tmp.Partition = &tmpPi
+	err := checkRangePartitionValue(ctx, &tmp)
+	if ErrRangeNotIncreasing.Equal(err) {
+		return false, nil
+	}
+	return err == nil, errors.Trace(err)
+}
+
+// doReorganizePartitionJob runs the job which reorganizes the partitions in partNames into the ones in partInfo,
+// all the partitions are reorganized if partNames is empty.
+func (d *ddl) doReorganizePartitionJob(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, partInfo *model.PartitionInfo, partNames []string) error {
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{partInfo, partNames},
+	}
+
+	err := d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
 	return errors.Trace(err)
 }
 
+// AlterTablePartitioning changes the partitioning of the table, the rows are reorganized into the new partitions.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	if err = checkChangePartitioning(meta); err != nil {
+		return errors.Trace(err)
+	}
+
+	newMeta := meta.Clone()
+	newMeta.Partition = nil
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := newMeta.Partition
+	if partInfo == nil {
+		// The partition type is not supported, a warning has been appended.
+		return nil
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitioningKeysConstraints(ctx, &ast.CreateTableStmt{Partition: spec.Partition}, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	// The global index is not supported by the reorganization, so every unique key must include the partition columns.
+	for _, idx := range newMeta.Indices {
+		if !idx.Unique || idx.Primary {
+			continue
+		}
+		ok, err := checkPartitionKeysConstraint(partInfo, idx.Columns, newMeta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return errors.Trace(ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX"))
+		}
+	}
+	if meta.Partition == nil {
+		// The partitions can't share the ID of the non-partitioned table, so the table gets a new ID.
+		// Note that the tools tracking the tables by their IDs, like BR and TiCDC, see it as a new table.
+		genIDs, err := d.genGlobalIDs(1)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		partInfo.NewTableID = genIDs[0]
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTablePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// RemovePartitioning removes the partitioning of the table, the rows of all the partitions are put into a single one
+// which becomes the non-partitioned table.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	if meta.GetPartitionInfo() == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if err = checkChangePartitioning(meta); err != nil {
+		return errors.Trace(err)
+	}
+
+	genIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The single partition becomes the table, so it has the same ID as the new table.
+	// Like partitioning a non-partitioned table, the table ID is changed.
+	partInfo := &model.PartitionInfo{
+		Type:        model.PartitionTypeKey,
+		Enable:      true,
+		Num:         1,
+		Definitions: []model.PartitionDefinition{{ID: genIDs[0], Name: model.NewCIStr(fullTablePartitionName)}},
+		NewTableID:  genIDs[0],
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// checkChangePartitioning checks whether the partitioning of the table can be changed.
+func checkChangePartitioning(tblInfo *model.TableInfo) error {
+	if tblInfo.TempTableType != model.TempTableNone {
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("partition mode")
+	}
+	if tblInfo.TableCacheStatusType != model.TableCacheStatusDisable {
+		return ErrOptOnCacheTable.GenWithStackByArgs("partition mode")
+	}
+	if hasGlobalIndex(tblInfo) {
+		return errUnsupportedGlobalIndexPartitioning
+	}
+	return nil
+}
+
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3933,13 +4438,43 @@ func checkModifyTypes(ctx sessionctx.Context, origin *types.FieldType, to *types
 	}
 
 	err = checkModifyCharsetAndCollation(to.Charset, to.Collate, origin.Charset, origin.Collate, needRewriteCollationData)
-	// column type change can handle the charset change between these two types in the process of the reorg.
-	if err != nil && errUnsupportedModifyCharset.Equal(err) && canReorg {
+	// The unknown charset or collation can't be handled in any way.
+	if err == nil || ErrUnknownCharacterSet.Equal(err) {
+		return errors.Trace(err)
+	}
+	// Otherwise the charset or collation change is unsupported without a reorg.
+	// column type change can handle the charset and collation change between these two types in the process of the reorg.
+	if canReorg {
+		return nil
+	}
+	// The reorg also rewrites the row data and rebuilds the indexes for the charset or collation change of a string column.
+	if canReorgCharsetAndCollation(origin, to) {
 		return nil
 	}
 	return errors.Trace(err)
 }
 
+// canReorgCharsetAndCollation checks whether the data of a string column can be converted
+// from the origin charset and collation to the new ones by a reorg.
+func canReorgCharsetAndCollation(origin *types.FieldType, to *types.FieldType) bool {
+	if !types.IsNonBinaryStr(origin) || !types.IsNonBinaryStr(to) {
+		return false
+	}
+	if origin.Charset == to.Charset {
+		return true
+	}
+	switch to.Charset {
+	case charset.CharsetUTF8, charset.CharsetUTF8MB4:
+		switch origin.Charset {
+		case charset.CharsetASCII, charset.CharsetLatin1, charset.CharsetUTF8, charset.CharsetUTF8MB4:
+			return true
+		}
+	case charset.CharsetLatin1:
+		return origin.Charset == charset.CharsetASCII
+	}
+	return false
+}
+
 func setDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.ColumnOption) (bool, error) {
 	hasDefaultValue := false
 	value, isSeqExpr, err := getDefaultValue(ctx, col, option)
@@ -4116,6 +4651,9 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if constraints := findConstraintsByColumn(t.Meta(), originalColName); len(constraints) > 0 {
+			return nil, errDependentByCheckConstraint.GenWithStackByArgs(constraints[0].Name.O, originalColName.O)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -4202,7 +4740,7 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		}
 		return nil, errors.Trace(err)
 	}
-	if needChangeColumnData(col.ColumnInfo, newCol.ColumnInfo) {
+	if needRewriteColumnData(t.Meta(), col.ColumnInfo, newCol.ColumnInfo) {
 		if err = isGeneratedRelatedColumn(t.Meta(), newCol.ColumnInfo, col.ColumnInfo); err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -4496,6 +5034,11 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 		}
 	}
 
+	// Check check constraint.
+	if constraints := findConstraintsByColumn(tbl.Meta(), oldColName); len(constraints) > 0 {
+		return errDependentByCheckConstraint.GenWithStackByArgs(constraints[0].Name.O, oldColName.O)
+	}
+
 	newCol := oldCol.Clone()
 	newCol.Name = newColName
 	job := &model.Job{
@@ -4799,6 +5342,9 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeMCV {
+		return errors.New("Only support MCV statistics type on at least 2 columns")
+	}
 	// TODO: check whether covering index exists for cardinality / dependency types.
 
 	// Call utilities of statistics.Handle to modify system tables instead of doing DML directly,
@@ -5444,6 +5990,13 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + len(hiddenCols)); err != nil {
 		return errors.Trace(err)
 	}
+	if unique {
+		for _, col := range hiddenCols {
+			if col.FieldType.Array {
+				return errUnsupportedMultiValuedIndex.GenWithStackByArgs("unique multi-valued index")
+			}
+		}
+	}
 
 	finalColumns := make([]*model.ColumnInfo, len(tblInfo.Columns), len(tblInfo.Columns)+len(hiddenCols))
 	copy(finalColumns, tblInfo.Columns)
@@ -5618,6 +6171,15 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// The index can't be created along with the foreign key, it should be created before adding the foreign key.
+	if !hasIndexOnColumns(t.Meta(), fkInfo.Cols) {
+		return ErrFkNoIndexChild.GenWithStackByArgs(fkName.O, t.Meta().Name.O)
+	}
+	if ctx.GetSessionVars().ForeignKeyChecks {
+		if err = checkTableForeignKeyValid(is, schema.Name, t.Meta(), fkInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5691,6 +6253,9 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkDropIndexOnForeignKey(t.Meta(), indexInfo); err != nil {
+		return errors.Trace(err)
+	}
 
 	jobTp := model.ActionDropIndex
 	if isPK {
@@ -5741,6 +6306,9 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 			if err := checkDropIndexOnAutoIncrementColumn(t.Meta(), indexInfo); err != nil {
 				return errors.Trace(err)
 			}
+			if err := checkDropIndexOnForeignKey(t.Meta(), indexInfo); err != nil {
+				return errors.Trace(err)
+			}
 		}
 
 		indexNames = append(indexNames, indexName)
@@ -5804,7 +6372,8 @@ func isDroppableColumn(multiSchemaChange bool, tblInfo *model.TableInfo, colName
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
-	return nil
+	// Check the column with check constraint.
+	return checkDropColumnWithCheckConstraint(tblInfo, colName)
 }
 
 // validateCommentLength checks comment length of table, column, index and partition.
@@ -6305,6 +6874,97 @@ func (d *ddl) AlterIndexVisibility(ctx sessionctx.Context, ident ast.Ident, inde
 	return errors.Trace(err)
 }
 
+// CreateCheckConstraint adds a check constraint to the table.
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tblInfo := t.Meta()
+	if constrName.L == "" {
+		names := make(map[string]struct{}, len(tblInfo.Constraints))
+		for _, constraintInfo := range tblInfo.Constraints {
+			names[constraintInfo.Name.L] = struct{}{}
+		}
+		constr.Name = genCheckConstraintName(tblInfo.Name, names)
+	} else if tblInfo.FindConstraintInfoByName(constrName.L) != nil {
+		return ErrCheckConstraintDupName.GenWithStackByArgs(constrName.O)
+	}
+
+	constraintInfo, err := buildConstraintInfo(ctx, tblInfo, constr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constraintInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropCheckConstraint drops the check constraint of the table.
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterCheckConstraint changes whether the check constraint of the table is enforced.
+func (d *ddl) AlterCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, enforced bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	constraintInfo := t.Meta().FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+	if constraintInfo.Enforced == enforced {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName, enforced},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) AlterTableAlterPartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) (err error) {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
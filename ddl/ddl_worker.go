@@ -390,15 +390,17 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 
 	if !job.IsCancelled() {
 		switch job.Type {
-		case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionAddColumn:
 			if job.State != model.JobStateRollbackDone {
 				break
 			}
 
 			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
+			// It's the same for the index created along with the column of an AddColumn operation.
 			err = w.deleteRange(w.ddlJobCtx, job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes,
+			model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
 			err = w.deleteRange(w.ddlJobCtx, job)
 		}
 	}
@@ -761,7 +763,7 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
 	case model.ActionAddColumn:
-		ver, err = onAddColumn(d, t, job)
+		ver, err = w.onAddColumn(d, t, job)
 	case model.ActionAddColumns:
 		ver, err = onAddColumns(d, t, job)
 	case model.ActionDropColumn:
@@ -802,6 +804,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onModifyTableAutoIDCache(t, job)
 	case model.ActionAddTablePartition:
 		ver, err = w.onAddTablePartition(d, t, job)
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionModifyTableCharsetAndCollate:
 		ver, err = onModifyTableCharsetAndCollate(t, job)
 	case model.ActionRecoverTable:
@@ -840,6 +844,12 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterTablePlacement(d, t, job)
 	case model.ActionAlterCacheTable:
 		ver, err = onAlterCacheTable(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = onAddCheckConstraint(w, t, job)
+	case model.ActionDropCheckConstraint:
+		ver, err = onDropCheckConstraint(t, job)
+	case model.ActionAlterCheckConstraint:
+		ver, err = onAlterCheckConstraint(w, t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -1060,6 +1070,13 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 				diff.AffectedOpts = buildPlacementAffects(oldIDs, oldIDs)
 			}
 		}
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		diff.TableID = job.TableID
+		// The table ID is changed when the table becomes partitioned or non-partitioned.
+		if len(job.CtxVars) > 0 {
+			diff.OldTableID = job.TableID
+			diff.TableID = job.CtxVars[0].(int64)
+		}
 	case model.ActionAlterTableAlterPartition:
 		diff.TableID = job.TableID
 		if len(job.CtxVars) > 0 {
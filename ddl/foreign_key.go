@@ -15,10 +15,15 @@
 package ddl
 
 import (
+	"fmt"
+
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 )
 
 func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
@@ -40,7 +45,7 @@ func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := fkInfo.State
 	switch fkInfo.State {
 	case model.StateNone:
-		// We just support record the foreign key, so we just make it public.
+		// The existing rows are not checked against the foreign key, so we just make it public.
 		// none -> public
 		fkInfo.State = model.StatePublic
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != fkInfo.State)
@@ -111,3 +116,111 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 
 }
+
+// checkTableForeignKeysValid checks the referred tables of the foreign keys if foreign_key_checks is on.
+func checkTableForeignKeysValid(ctx sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr, tbInfo *model.TableInfo) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	for _, fk := range tbInfo.ForeignKeys {
+		if err := checkTableForeignKeyValid(is, schema, tbInfo, fk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkTableForeignKeyValid checks the referred table exists and has an index on the referred columns,
+// which are required to enforce the foreign key.
+func checkTableForeignKeyValid(is infoschema.InfoSchema, schema model.CIStr, tbInfo *model.TableInfo, fk *model.FKInfo) error {
+	referTblInfo := tbInfo
+	if fk.RefTable.L != tbInfo.Name.L {
+		referTbl, err := is.TableByName(schema, fk.RefTable)
+		if err != nil {
+			return ErrFkCannotOpenParent.GenWithStackByArgs(fk.RefTable.O)
+		}
+		referTblInfo = referTbl.Meta()
+	}
+	if tbInfo.GetPartitionInfo() != nil || referTblInfo.GetPartitionInfo() != nil {
+		return ErrForeignKeyOnPartitioned
+	}
+	if !hasIndexOnColumns(referTblInfo, fk.RefCols) {
+		return ErrFkNoIndexParent.GenWithStackByArgs(fk.Name.O, fk.RefTable.O)
+	}
+	return nil
+}
+
+// hasIndexOnColumns checks whether the table has an index whose leading columns are the cols.
+func hasIndexOnColumns(tblInfo *model.TableInfo, cols []model.CIStr) bool {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return true
+		}
+	}
+	for _, idx := range tblInfo.Indices {
+		if isIndexOnColumns(idx, cols) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIndexOnColumns checks whether the leading columns of the index are the cols.
+func isIndexOnColumns(idx *model.IndexInfo, cols []model.CIStr) bool {
+	if len(idx.Columns) < len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		if idx.Columns[i].Name.L != col.L || idx.Columns[i].Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+// buildForeignKeyIndexes creates an index on the columns of each foreign key if there isn't one, like MySQL.
+// The index is used to look up the rows referring to the changed rows of the referred table.
+func buildForeignKeyIndexes(tbInfo *model.TableInfo) error {
+	for _, fk := range tbInfo.ForeignKeys {
+		if hasIndexOnColumns(tbInfo, fk.Cols) {
+			continue
+		}
+		keys := make([]*ast.IndexPartSpecification, 0, len(fk.Cols))
+		for _, col := range fk.Cols {
+			keys = append(keys, &ast.IndexPartSpecification{Column: &ast.ColumnName{Name: col}, Length: types.UnspecifiedLength})
+		}
+		idxName := fk.Name
+		for i := 2; tbInfo.FindIndexByName(idxName.L) != nil; i++ {
+			idxName = model.NewCIStr(fmt.Sprintf("%s_%d", fk.Name.O, i))
+		}
+		idxInfo, err := buildIndexInfo(tbInfo, idxName, keys, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		idxInfo.Tp = model.IndexTypeBtree
+		idxInfo.ID = allocateIndexID(tbInfo)
+		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
+	}
+	return nil
+}
+
+// checkDropIndexOnForeignKey checks whether the index is the only one on the columns of a foreign key of the table,
+// which can't be dropped.
+func checkDropIndexOnForeignKey(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	for _, fk := range tblInfo.ForeignKeys {
+		if !isIndexOnColumns(idxInfo, fk.Cols) {
+			continue
+		}
+		remained := *tblInfo
+		remained.Indices = make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+		for _, idx := range tblInfo.Indices {
+			if idx.ID != idxInfo.ID {
+				remained.Indices = append(remained.Indices, idx)
+			}
+		}
+		if !hasIndexOnColumns(&remained, fk.Cols) {
+			return ErrDropIndexFk.GenWithStackByArgs(idxInfo.Name.O)
+		}
+	}
+	return nil
+}
@@ -272,6 +272,8 @@ type illegalFunctionChecker struct {
 	hasRowVal            bool // hasRowVal checks whether the functional index refers to a row value
 	hasWindowFunc        bool
 	hasNotGAFunc4ExprIdx bool
+	hasVariable          bool // hasVariable checks whether the check constraint refers to a user or system variable
+	hasCastArray         bool // hasCastArray checks whether the expression contains `CAST(... AS ... ARRAY)`
 	otherErr             error
 }
 
@@ -293,10 +295,15 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 		if !isFuncGA {
 			c.hasNotGAFunc4ExprIdx = true
 		}
-	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.VariableExpr:
-		// Subquery & `values(x)` & variable is not allowed
+	case *ast.SubqueryExpr, *ast.ValuesExpr:
+		// Subquery & `values(x)` is not allowed
 		c.hasIllegalFunc = true
 		return inNode, true
+	case *ast.VariableExpr:
+		// Variable is not allowed
+		c.hasIllegalFunc = true
+		c.hasVariable = true
+		return inNode, true
 	case *ast.AggregateFuncExpr:
 		// Aggregate function is not allowed
 		c.hasAggFunc = true
@@ -307,6 +314,10 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 	case *ast.WindowFuncExpr:
 		c.hasWindowFunc = true
 		return inNode, true
+	case *ast.FuncCastExpr:
+		if node.Tp.Array {
+			c.hasCastArray = true
+		}
 	}
 	return inNode, false
 }
@@ -318,6 +329,7 @@ func (c *illegalFunctionChecker) Leave(inNode ast.Node) (node ast.Node, ok bool)
 const (
 	typeColumn = iota
 	typeIndex
+	typeConstraint
 )
 
 func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error {
@@ -326,12 +338,17 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 	}
 	var c illegalFunctionChecker
 	expr.Accept(&c)
+	if genType == typeConstraint && c.hasVariable {
+		return ErrCheckConstraintVariables.GenWithStackByArgs(name)
+	}
 	if c.hasIllegalFunc {
 		switch genType {
 		case typeColumn:
 			return ErrGeneratedColumnFunctionIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(name)
+		case typeConstraint:
+			return ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(name)
 		}
 	}
 	if c.hasAggFunc {
@@ -343,6 +360,8 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 			return ErrGeneratedColumnRowValueIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexRowValueIsNotAllowed.GenWithStackByArgs(name)
+		case typeConstraint:
+			return ErrCheckConstraintRowValue.GenWithStackByArgs(name)
 		}
 	}
 	if c.hasWindowFunc {
@@ -351,6 +370,16 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 	if c.otherErr != nil {
 		return c.otherErr
 	}
+	if c.hasCastArray {
+		if genType != typeIndex {
+			return errUnsupportedMultiValuedIndex.GenWithStackByArgs("CAST(... AS ... ARRAY) outside of functional index")
+		}
+		if castExpr, ok := expr.(*ast.FuncCastExpr); !ok || !castExpr.Tp.Array {
+			return errUnsupportedMultiValuedIndex.GenWithStackByArgs("CAST(... AS ... ARRAY) must be the whole key part")
+		}
+		// Multi-valued indexes are not restricted by the experimental switch of expression index.
+		return nil
+	}
 	if genType == typeIndex && c.hasNotGAFunc4ExprIdx && !config.GetGlobalConfig().Experimental.AllowsExpressionIndex {
 		return ErrUnsupportedExpressionIndex
 	}
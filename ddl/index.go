@@ -60,11 +60,18 @@ func buildIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*a
 
 	// The sum of length of all index columns.
 	sumLength := 0
+	hasArrayCol := false
 	for _, ip := range indexPartSpecifications {
 		col = model.FindColumnInfo(columns, ip.Column.Name.L)
 		if col == nil {
 			return nil, errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
 		}
+		if col.FieldType.Array {
+			if hasArrayCol {
+				return nil, errUnsupportedMultiValuedIndex.GenWithStackByArgs("more than one multi-valued key part per index")
+			}
+			hasArrayCol = true
+		}
 
 		if err := checkIndexColumn(col, ip.Length); err != nil {
 			return nil, err
@@ -237,6 +244,11 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 		Columns: idxColumns,
 		State:   state,
 	}
+	for _, idxCol := range idxColumns {
+		if tblInfo.Columns[idxCol.Offset].FieldType.Array {
+			idxInfo.MVIndex = true
+		}
+	}
 	return idxInfo, nil
 }
 
@@ -1077,7 +1089,12 @@ func (w *baseIndexWorker) getIndexRecord(idxInfo *model.IndexInfo, handle kv.Han
 			idxVal[j] = idxColumnVal
 			continue
 		}
-		idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		// The rows written before the column is added have no value of it, which is the original default value.
+		if col.State != model.StatePublic && col.ChangeStateInfo == nil {
+			idxColumnVal, err = table.GetColOriginDefaultValue(w.sessCtx, col.ToInfo())
+		} else {
+			idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1575,13 +1592,18 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 	}
 
 	var pid int64
-	for i, pi := range partitionIDs {
-		if pi == reorg.PhysicalTableID {
+	for i, id := range partitionIDs {
+		if id == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
+	}
+	if pid == 0 {
+		// Fatal error, should not run here.
+		return false, errors.Errorf("can not find partition id %d in %v", reorg.PhysicalTableID, partitionIDs)
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
@@ -355,6 +355,12 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 	case model.PartitionTypeList:
 		// Partition by list is enabled only when tidb_enable_list_partition is 'ON'.
 		enable = ctx.GetSessionVars().EnableListTablePartition
+	case model.PartitionTypeKey:
+		// Partition by [linear] key is enabled by default.
+		// Note that only the default key algorithm 2 is supported.
+		if s.Sub == nil && (s.KeyAlgorithm == nil || s.KeyAlgorithm.Type == 2) {
+			enable = true
+		}
 	}
 
 	if !enable {
@@ -366,9 +372,14 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 		Type:   s.Tp,
 		Enable: enable,
 		Num:    s.Num,
+		Linear: s.Linear,
 	}
 	tbInfo.Partition = pi
-	if s.Expr != nil {
+	if s.Tp == model.PartitionTypeKey {
+		if err := buildKeyPartitionColumns(tbInfo, s.ColumnNames); err != nil {
+			return errors.Trace(err)
+		}
+	} else if s.Expr != nil {
 		if err := checkPartitionFuncValid(ctx, tbInfo, s.Expr); err != nil {
 			return errors.Trace(err)
 		}
@@ -397,12 +408,81 @@ func buildTablePartitionInfo(ctx sessionctx.Context, s *ast.PartitionOptions, tb
 	return nil
 }
 
+// buildKeyPartitionColumns sets the columns of the key partition. When no column is given, the primary key is used,
+// or the unique key whose columns are all NOT NULL if the table has no primary key.
+func buildKeyPartitionColumns(tbInfo *model.TableInfo, colNames []*ast.ColumnName) error {
+	pi := tbInfo.Partition
+	if len(colNames) == 0 {
+		pi.Columns = getKeyPartitionDefaultColumns(tbInfo)
+		if len(pi.Columns) == 0 {
+			return errors.Trace(ErrFieldNotFoundPart)
+		}
+	} else {
+		pi.Columns = make([]model.CIStr, 0, len(colNames))
+		for _, cn := range colNames {
+			for _, col := range pi.Columns {
+				if col.L == cn.Name.L {
+					return ErrSameNamePartitionField.GenWithStackByArgs(cn.Name.O)
+				}
+			}
+			pi.Columns = append(pi.Columns, cn.Name)
+		}
+	}
+	for _, col := range pi.Columns {
+		colInfo := getColumnInfoByName(tbInfo, col.L)
+		if colInfo == nil {
+			return errors.Trace(ErrFieldNotFoundPart)
+		}
+		// MySQL hashes the stored format of the columns, so all the types are permitted except BLOB, TEXT and JSON.
+		switch colInfo.Tp {
+		case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeJSON, mysql.TypeGeometry:
+			return errors.Trace(ErrBlobFieldInPartFunc)
+		}
+	}
+	return nil
+}
+
+// getKeyPartitionDefaultColumns gets the columns used by `PARTITION BY KEY()`.
+func getKeyPartitionDefaultColumns(tbInfo *model.TableInfo) []model.CIStr {
+	if tbInfo.PKIsHandle {
+		return []model.CIStr{tbInfo.GetPkName()}
+	}
+	var uniqueKey *model.IndexInfo
+	for _, idx := range tbInfo.Indices {
+		if idx.Primary {
+			uniqueKey = idx
+			break
+		}
+		if !idx.Unique || uniqueKey != nil {
+			continue
+		}
+		allNotNull := true
+		for _, idxCol := range idx.Columns {
+			if !mysql.HasNotNullFlag(tbInfo.Columns[idxCol.Offset].Flag) {
+				allNotNull = false
+				break
+			}
+		}
+		if allNotNull {
+			uniqueKey = idx
+		}
+	}
+	if uniqueKey == nil {
+		return nil
+	}
+	cols := make([]model.CIStr, 0, len(uniqueKey.Columns))
+	for _, idxCol := range uniqueKey.Columns {
+		cols = append(cols, idxCol.Name)
+	}
+	return cols
+}
+
 // buildPartitionDefinitionsInfo build partition definitions info without assign partition id. tbInfo will be constant
 func buildPartitionDefinitionsInfo(ctx sessionctx.Context, defs []*ast.PartitionDefinition, tbInfo *model.TableInfo) (partitions []model.PartitionDefinition, err error) {
 	switch tbInfo.Partition.Type {
 	case model.PartitionTypeRange:
 		partitions, err = buildRangePartitionDefinitions(ctx, defs, tbInfo)
-	case model.PartitionTypeHash:
+	case model.PartitionTypeHash, model.PartitionTypeKey:
 		partitions, err = buildHashPartitionDefinitions(ctx, defs, tbInfo)
 	case model.PartitionTypeList:
 		partitions, err = buildListPartitionDefinitions(ctx, defs, tbInfo)
@@ -661,8 +741,8 @@ func checkAndOverridePartitionID(newTableInfo, oldTableInfo *model.TableInfo) er
 	if newTableInfo.Partition.Type != oldTableInfo.Partition.Type {
 		return ErrRepairTableFail.GenWithStackByArgs("Partition type should be the same")
 	}
-	// Check whether partitionType is hash partition.
-	if newTableInfo.Partition.Type == model.PartitionTypeHash {
+	// Check whether partitionType is hash or key partition.
+	if newTableInfo.Partition.Type == model.PartitionTypeHash || newTableInfo.Partition.Type == model.PartitionTypeKey {
 		if newTableInfo.Partition.Num != oldTableInfo.Partition.Num {
 			return ErrRepairTableFail.GenWithStackByArgs("Hash partition num should be the same")
 		}
@@ -1495,6 +1575,13 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 		} else if len(pi.Columns) == 1 {
 			sql, paramList = buildCheckSQLForRangeColumnsPartition(pi, index, schemaName, tableName)
 		}
+	case model.PartitionTypeKey:
+		// The hash function of the key partition can't be expressed in SQL, so only the
+		// table with one partition is supported.
+		if pi.Num == 1 {
+			return nil
+		}
+		return errUnsupportedPartitionType.GenWithStackByArgs(pt.Name.O)
 	case model.PartitionTypeList:
 		if len(pi.Columns) == 0 {
 			sql, paramList = buildCheckSQLForListPartition(pi, index, schemaName, tableName)
@@ -1666,8 +1753,15 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 		partCols = columnInfoSlice(partColumns)
 	} else if len(s.Partition.ColumnNames) > 0 {
 		partCols = columnNameSlice(s.Partition.ColumnNames)
+	} else if tblInfo.Partition.Type == model.PartitionTypeKey {
+		// The columns of `PARTITION BY KEY()` are derived from the primary key or the unique key.
+		partColumns := make([]*model.ColumnInfo, 0, len(tblInfo.Partition.Columns))
+		for _, col := range tblInfo.Partition.Columns {
+			partColumns = append(partColumns, getColumnInfoByName(tblInfo, col.L))
+		}
+		partCols = columnInfoSlice(partColumns)
 	} else {
-		// TODO: Check keys constraints for list, key partition type and so on.
+		// TODO: Check keys constraints for list partition type and so on.
 		return nil
 	}
 
@@ -0,0 +1,625 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/ddl/placement"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	tidbutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// fullTablePartitionName is the name of the single partition that a non-partitioned table is regarded as when
+// its partitioning is being changed.
+const fullTablePartitionName = "pFullTable"
+
+// onReorganizePartition changes the partitioning of a table, e.g. ALTER TABLE ... PARTITION BY and
+// ALTER TABLE ... REMOVE PARTITIONING. The rows are written to both the old and the new partitioning scheme
+// during the change, and the existing rows are copied to the new partitions by the reorg workers.
+//
+// The states of the change are none, delete only, write only, write reorganization, delete reorganization and
+// public. The new partitions are in AddingDefinitions until the data is reorganized, then they are swapped with the old
+// ones which are in DroppingDefinitions, so the table is read from the new partitions in delete reorganization.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	// Handle the rolling back job.
+	if job.IsRollingback() {
+		ver, err := onRollbackReorganizePartition(d, t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		return ver, nil
+	}
+
+	partInfo := &model.PartitionInfo{}
+	// partNames are the names of the partitions to be reorganized, all the partitions are reorganized if it is empty.
+	var partNames []string
+	if err := job.DecodeArgs(partInfo, &partNames); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	originalState := job.SchemaState
+	switch job.SchemaState {
+	case model.StateNone:
+		pi := tblInfo.GetPartitionInfo()
+		if pi == nil {
+			// The non-partitioned table is regarded as a single partition which has the same ID as the table,
+			// so the rows are read and written in the same way as the partitioned table.
+			pi = &model.PartitionInfo{
+				Type:        model.PartitionTypeKey,
+				Enable:      true,
+				Definitions: []model.PartitionDefinition{{ID: tblInfo.ID, Name: model.NewCIStr(fullTablePartitionName)}},
+				Num:         1,
+			}
+			tblInfo.Partition = pi
+			if tblInfo.TiFlashReplica != nil && tblInfo.TiFlashReplica.Available {
+				tblInfo.TiFlashReplica.AvailablePartitionIDs = []int64{tblInfo.ID}
+			}
+		}
+		droppingDefs, err := getReorganizedPartitionDefs(pi, partNames)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		err = checkAddPartitionTooManyPartitions(uint64(len(pi.Definitions) - len(droppingDefs) + len(partInfo.Definitions)))
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		pi.AddingDefinitions = partInfo.Definitions
+		pi.DroppingDefinitions = droppingDefs
+		pi.DDLAction = job.Type
+		pi.DDLType, pi.DDLExpr, pi.DDLColumns, pi.DDLLinear = partInfo.Type, partInfo.Expr, partInfo.Columns, partInfo.Linear
+		pi.NewTableID = partInfo.NewTableID
+		pi.DDLState = model.StateDeleteOnly
+
+		var bundles []*placement.Bundle
+		bundles, err = newBundlesFromPartitionDefs(t, job, partInfo.Definitions)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if err = infosync.PutRuleBundles(context.TODO(), bundles); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateDeleteOnly:
+		// The rows are only removed from the new partitions in this state, it makes sure that all the TiDB servers
+		// can remove the rows from the new partitions before they are written there.
+		tblInfo.Partition.DDLState = model.StateWriteOnly
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteOnly:
+		tblInfo.Partition.DDLState = model.StateWriteReorganization
+		job.SchemaState = model.StateWriteReorganization
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteReorganization:
+		tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		done, err := w.runReorgPartitionJob(d, t, job, tbl)
+		if err != nil || !done {
+			return ver, errors.Trace(err)
+		}
+
+		// Read from the new partitions, the rows are still written to the old partitions since some TiDB servers
+		// may read them until the schema is synced.
+		pi := tblInfo.Partition
+		pi.Definitions = pi.ReorganizedDefinitions()
+		pi.Type, pi.DDLType = pi.DDLType, pi.Type
+		pi.Expr, pi.DDLExpr = pi.DDLExpr, pi.Expr
+		pi.Columns, pi.DDLColumns = pi.DDLColumns, pi.Columns
+		pi.Linear, pi.DDLLinear = pi.DDLLinear, pi.Linear
+		pi.Num = uint64(len(pi.Definitions))
+		pi.States = nil
+		pi.DDLState = model.StateDeleteReorganization
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+	case model.StateDeleteReorganization:
+		pi := tblInfo.Partition
+		oldIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		newPartInfo := &model.PartitionInfo{Definitions: pi.AddingDefinitions}
+		oldTableID, newTableID := tblInfo.ID, pi.NewTableID
+		resetReorganizePartitionInfo(pi)
+		if job.Type == model.ActionRemovePartitioning {
+			tblInfo.Partition = nil
+		}
+
+		if newTableID != 0 {
+			// The data of the non-partitioned table is stored with the table ID, so the table ID is changed
+			// as well as the partition IDs.
+			if err = t.DropTableOrView(job.SchemaID, oldTableID); err != nil {
+				return ver, errors.Trace(err)
+			}
+			if err = meta.BackupAndRestoreAutoIDs(t, job.SchemaID, oldTableID, job.SchemaID, newTableID); err != nil {
+				return ver, errors.Trace(err)
+			}
+			tblInfo.ID = newTableID
+			if err = t.CreateTableOrView(job.SchemaID, tblInfo); err != nil {
+				return ver, errors.Trace(err)
+			}
+			if job.Type == model.ActionRemovePartitioning {
+				oldIDs = append(oldIDs, oldTableID)
+			}
+			// Used by ApplyDiff in updateSchemaVersion.
+			job.CtxVars = []interface{}{newTableID}
+		}
+		updateTiFlashReplicaAfterReorganize(tblInfo, oldIDs)
+
+		var bundles []*placement.Bundle
+		tblBundle, err := newBundleFromTblInfo(t, job, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		if tblBundle != nil {
+			bundles = append(bundles, tblBundle)
+		}
+		if pi := tblInfo.GetPartitionInfo(); pi != nil {
+			partitionBundles, err := newBundlesFromPartitionDefs(t, job, pi.Definitions)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			bundles = append(bundles, partitionBundles...)
+		}
+		if err = infosync.PutRuleBundles(context.TODO(), bundles); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		if err = dropRuleBundles(d, oldIDs); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		asyncNotifyEvent(d, &ddlutil.Event{Tp: job.Type, TableInfo: tblInfo, PartInfo: newPartInfo})
+		// A background job will be created to delete the data of the old partitions.
+		job.Args = []interface{}{oldIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// onRollbackReorganizePartition removes the new partitions, the table is read from the old partitions until
+// the data is reorganized, so it can be rolled back before that.
+func onRollbackReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	var addingIDs []int64
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && pi.DDLState != model.StateNone {
+		addingIDs = getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+		if pi.DDLAction == model.ActionAlterTablePartitioning && pi.NewTableID != 0 {
+			// The table was not partitioned.
+			tblInfo.Partition = nil
+			if tblInfo.TiFlashReplica != nil {
+				tblInfo.TiFlashReplica.AvailablePartitionIDs = nil
+			}
+		} else {
+			resetReorganizePartitionInfo(pi)
+			if tblInfo.TiFlashReplica != nil {
+				tblInfo.TiFlashReplica.AvailablePartitionIDs = removeIDs(tblInfo.TiFlashReplica.AvailablePartitionIDs, addingIDs)
+			}
+		}
+	}
+	if err = dropRuleBundles(d, addingIDs); err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// A background job will be created to delete the data of the new partitions.
+	job.Args = []interface{}{addingIDs}
+	return ver, nil
+}
+
+// getReorganizedPartitionDefs returns the consecutive partitions to be reorganized by their names, all the
+// partitions are returned if partNames is empty.
+func getReorganizedPartitionDefs(pi *model.PartitionInfo, partNames []string) ([]model.PartitionDefinition, error) {
+	if len(partNames) == 0 {
+		return pi.Definitions, nil
+	}
+	first := -1
+	for i := range pi.Definitions {
+		if pi.Definitions[i].Name.L == partNames[0] {
+			first = i
+			break
+		}
+	}
+	if first < 0 || first+len(partNames) > len(pi.Definitions) {
+		return nil, errors.Trace(ErrReorgPartitionNotExist)
+	}
+	defs := pi.Definitions[first : first+len(partNames)]
+	for i, name := range partNames {
+		if defs[i].Name.L != name {
+			return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+	}
+	return append([]model.PartitionDefinition(nil), defs...), nil
+}
+
+// updateTiFlashReplicaAfterReorganize removes the reorganized partitions from the available TiFlash replicas, the
+// replicas of the new partitions are available when TiFlash reports them.
+func updateTiFlashReplicaAfterReorganize(tblInfo *model.TableInfo, oldIDs []int64) {
+	replica := tblInfo.TiFlashReplica
+	if replica == nil {
+		return
+	}
+	replica.AvailablePartitionIDs = removeIDs(replica.AvailablePartitionIDs, oldIDs)
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		// The partition of the new table has the same ID as the table.
+		replica.Available = replica.IsPartitionAvailable(tblInfo.ID)
+		replica.AvailablePartitionIDs = nil
+		return
+	}
+	replica.Available = true
+	for _, def := range pi.Definitions {
+		if !replica.IsPartitionAvailable(def.ID) {
+			replica.Available = false
+			break
+		}
+	}
+}
+
+// removeIDs returns the IDs that are not in removed.
+func removeIDs(ids, removed []int64) []int64 {
+	ret := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		found := false
+		for _, r := range removed {
+			if id == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+// resetReorganizePartitionInfo clears the information of the partitioning change.
+func resetReorganizePartitionInfo(pi *model.PartitionInfo) {
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.DDLState = model.StateNone
+	pi.DDLAction = model.ActionNone
+	pi.DDLType, pi.DDLExpr, pi.DDLColumns, pi.DDLLinear = 0, "", nil, false
+	pi.NewTableID = 0
+}
+
+// runReorgPartitionJob copies the rows of the old partitions to the new partitions. It returns true when all
+// the rows are copied.
+func (w *worker) runReorgPartitionJob(d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table) (bool, error) {
+	// Build elements for compatible with modify column type. elements will not be used when reorganizing.
+	tblInfo := tbl.Meta()
+	elements := []*meta.Element{{ID: tblInfo.Columns[0].ID, TypeKey: meta.ColumnElementKey}}
+	// Only the rows of the reorganized partitions are copied.
+	partitionIDs := getPartitionIDsFromDefinitions(tblInfo.Partition.DroppingDefinitions)
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, partitionIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+	err = w.runReorgJob(t, reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+			func() {
+				reorgErr = errCancelledDDLJob.GenWithStack("reorganize partition of table `%v` panic", tblInfo.Name)
+			}, false)
+		return w.reorgPartitionData(tbl.(table.PartitionedTable), reorgInfo, partitionIDs)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		if kv.IsTxnRetryableError(err) {
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return false, errors.Trace(err)
+		}
+		if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+		job.State = model.JobStateRollingback
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, nil
+}
+
+// reorgPartitionData copies the rows of the old partitions in partitionIDs one by one.
+func (w *worker) reorgPartitionData(t table.PartitionedTable, reorgInfo *reorgInfo, partitionIDs []int64) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := t.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(t, reorgInfo, partitionIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+type reorgPartitionRecord struct {
+	key    kv.Key // It's the record key in the old partition, used to lock the record.
+	newKey kv.Key // It's the record key in the new partition.
+	vals   []byte // It's the record, which is the same in the new partition.
+	handle kv.Handle
+	row    []types.Datum
+	part   table.PhysicalTable // It's the new partition.
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	metricCounter prometheus.Counter
+
+	// reorgedTbl is the table of the new partitioning scheme.
+	reorgedTbl table.PartitionedTable
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*reorgPartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+
+	// For SQL Mode and warnings.
+	sqlMode mysql.SQLMode
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo) (*reorgPartitionWorker, error) {
+	tbl, err := getTable(reorgInfo.d.store, reorgInfo.Job.SchemaID, t.Meta())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	reorgedTbl, err := tables.GetReorganizedPartitionedTable(tbl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		reorgedTbl:     reorgedTbl,
+		rowDecoder:     rowDecoder,
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+		sqlMode:        reorgInfo.ReorgMeta.SQLMode,
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+// getNextKey gets next handle of entry that we are going to process.
+func (w *reorgPartitionWorker) getNextKey(taskRange reorgBackfillTask,
+	taskDone bool, lastAccessedHandle kv.Key) (nextHandle kv.Key) {
+	if !taskDone {
+		// The task is not done. So we need to pick the last processed entry's handle and add one.
+		return lastAccessedHandle.Next()
+	}
+
+	return taskRange.endKey.Next()
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the reorged handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	return w.rowRecords, w.getNextKey(taskRange, taskDone, lastAccessedHandle), taskDone, errors.Trace(err)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[i] = val
+	}
+	w.cleanRowMap()
+
+	part, err := w.reorgedTbl.GetPartitionByRow(w.sessCtx, row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowRecords = append(w.rowRecords, &reorgPartitionRecord{
+		key:    recordKey,
+		newKey: tablecodec.EncodeRecordKey(part.RecordPrefix(), handle),
+		vals:   append([]byte(nil), rawRow...),
+		handle: handle,
+		row:    row,
+		part:   part,
+	})
+	return nil
+}
+
+func (w *reorgPartitionWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+// BackfillDataInTxn will backfill the records of the new partitions in a transaction. The records which are
+// already written by the DML statements are skipped, and the transaction conflicts with the DML statements
+// which change the records after the snapshot.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		newKeys := make([]kv.Key, 0, len(rowRecords))
+		for _, record := range rowRecords {
+			newKeys = append(newKeys, record.newKey)
+		}
+		existed, err := txn.BatchGet(ctx, newKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+			if _, ok := existed[string(record.newKey)]; ok {
+				// The record is already written by the DML statements, skip it.
+				continue
+			}
+			// Lock the row key to notify us that someone delete or update the row.
+			err = txn.LockKeys(context.Background(), new(kv.LockCtx), record.key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err = txn.Set(record.newKey, record.vals); err != nil {
+				return errors.Trace(err)
+			}
+			if err = w.createIndexes(txn, record); err != nil {
+				return errors.Trace(err)
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ReorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
+func (w *reorgPartitionWorker) createIndexes(txn kv.Transaction, record *reorgPartitionRecord) error {
+	tblInfo := record.part.Meta()
+	for _, idx := range record.part.Indices() {
+		if !tables.IsIndexWritable(idx) || (tblInfo.IsCommonHandle && idx.Meta().Primary) {
+			continue
+		}
+		vals, err := idx.FetchValues(record.row, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rsData := tables.TryGetHandleRestoredDataWrapper(record.part, record.row, nil, idx.Meta())
+		handle, err := idx.Create(w.sessCtx, txn, vals, record.handle, rsData)
+		if err != nil {
+			if kv.ErrKeyExists.Equal(err) && record.handle.Equal(handle) {
+				// Index already exists, skip it.
+				continue
+			}
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -109,6 +109,30 @@ func convertNotStartAddIdxJob2RollbackJob(t *meta.Meta, job *model.Job, occuredE
 // Since modifying column job has two types: normal-type and reorg-type, we should handle it respectively.
 // normal-type has only two states:    None -> Public
 // reorg-type has five states:         None -> Delete-only -> Write-only -> Write-org -> Public
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateDeleteReorganization:
+		// The table is read from the new partitions, it can't be rolled back.
+		job.State = model.JobStateRunning
+		return ver, nil
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
+		if job.SnapshotVer != 0 {
+			// Reorganize partition workers are started. we have to ask them to exit.
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			// Give the this kind of ddl one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+			return w.onReorganizePartition(d, t, job)
+		}
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
 	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
@@ -122,7 +146,7 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	if err != nil {
 		return ver, err
 	}
-	if !needChangeColumnData(oldCol, jp.newCol) {
+	if !needRewriteColumnData(tblInfo, oldCol, jp.newCol) {
 		// Normal-type rolling back
 		if job.SchemaState == model.StateNone {
 			// When change null to not null, although state is unchanged with none, the oldCol flag's has been changed to preNullInsertFlag.
@@ -150,8 +174,15 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, errCancelledDDLJob
 }
 
-func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
+func rollingbackAddColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// If the value of SnapshotVer isn't zero, it means the work is backfilling the column.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// Backfill workers are started. need to ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		return w.onAddColumn(d, t, job)
+	}
+	tblInfo, columnInfo, _, _, _, idxInfo, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -160,18 +191,30 @@ func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
 		return ver, errCancelledDDLJob
 	}
 
+	var idxInfos []*model.IndexInfo
+	if idxInfo != nil {
+		idxInfos = []*model.IndexInfo{idxInfo}
+	}
+	return convertAddColumnJob2RollbackJob(t, job, tblInfo, columnInfo, idxInfos, errCancelledDDLJob)
+}
+
+// convertAddColumnJob2RollbackJob converts the add column job to a rollback job, which drops the adding column
+// and the indexes created along with it.
+func convertAddColumnJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, columnInfo *model.ColumnInfo,
+	idxInfos []*model.IndexInfo, err error) (ver int64, _ error) {
 	originalState := columnInfo.State
-	columnInfo.State = model.StateDeleteOnly
+	updateChangingInfo(columnInfo, idxInfos, model.StateDeleteOnly)
 	job.SchemaState = model.StateDeleteOnly
 
-	job.Args = []interface{}{col.Name}
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
-	if err != nil {
-		return ver, errors.Trace(err)
+	// The args will be used in onDropColumn.
+	job.Args = []interface{}{columnInfo.Name}
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
 	}
 
 	job.State = model.JobStateRollingback
-	return ver, errCancelledDDLJob
+	return ver, errors.Trace(err)
 }
 
 func rollingbackAddColumns(t *meta.Meta, job *model.Job) (ver int64, err error) {
@@ -442,7 +485,7 @@ func rollingbackTruncateTable(t *meta.Meta, job *model.Job) (ver int64, err erro
 func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	switch job.Type {
 	case model.ActionAddColumn:
-		ver, err = rollingbackAddColumn(t, job)
+		ver, err = rollingbackAddColumn(w, d, t, job)
 	case model.ActionAddColumns:
 		ver, err = rollingbackAddColumns(t, job)
 	case model.ActionAddIndex:
@@ -471,12 +514,15 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition, model.ActionModifySchemaDefaultPlacement:
+		model.ActionExchangeTablePartition, model.ActionModifySchemaDefaultPlacement,
+		model.ActionAddCheckConstraint, model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
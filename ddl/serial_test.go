@@ -1549,10 +1549,13 @@ func (s *testSerialSuite) TestModifyingColumn4NewCollations(c *C) {
 
 	tk.MustExec("alter table t add index b_idx(b)")
 	tk.MustExec("alter table t add index c_idx(c)")
-	tk.MustGetErrMsg("alter table t modify b varchar(10) collate utf8_general_ci", "[ddl:8200]Unsupported modifying collation of column 'b' from 'utf8_bin' to 'utf8_general_ci' when index is defined on it.")
-	tk.MustGetErrMsg("alter table t modify c varchar(10) collate utf8_bin", "[ddl:8200]Unsupported modifying collation of column 'c' from 'utf8_general_ci' to 'utf8_bin' when index is defined on it.")
-	tk.MustGetErrMsg("alter table t modify c varchar(10) collate utf8_unicode_ci", "[ddl:8200]Unsupported modifying collation of column 'c' from 'utf8_general_ci' to 'utf8_unicode_ci' when index is defined on it.")
 	tk.MustGetErrMsg("alter table t convert to charset utf8 collate utf8_general_ci", "[ddl:8200]Unsupported converting collation of column 'b' from 'utf8_bin' to 'utf8_general_ci' when index is defined on it.")
+	// Column collation of an indexed column is changed by rewriting the index data.
+	tk.MustExec("alter table t modify b varchar(10) collate utf8_general_ci")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_bin")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_unicode_ci")
+	tk.MustExec("alter table t modify c varchar(10) collate utf8_general_ci")
+	tk.MustExec("alter table t modify b varchar(10) collate utf8_bin")
 	// Change to a compatible collation is allowed.
 	tk.MustExec("alter table t modify c varchar(10) collate utf8mb4_general_ci")
 	// Change the default collation of table is allowed.
@@ -1564,6 +1567,55 @@ func (s *testSerialSuite) TestModifyingColumn4NewCollations(c *C) {
 	tk.MustExec("alter database dct charset utf8mb4 collate utf8mb4_general_ci")
 }
 
+func (s *testSerialSuite) TestModifyColumnCollationWithReorg(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database if not exists dct_reorg")
+	defer tk.MustExec("drop database dct_reorg")
+	tk.MustExec("use dct_reorg")
+	tk.MustExec("create table t(a int, b varchar(10) collate utf8mb4_bin, index idx_b(b), index idx_ab(a, b))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'B'), (3, 'c')")
+	tk.MustExec("alter table t modify b varchar(10) collate utf8mb4_general_ci")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t use index(idx_b) where b = 'b'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t use index(idx_ab) where a = 1 and b = 'A'").Check(testkit.Rows("1"))
+	tk.MustQuery("select collation_name from information_schema.columns where table_schema = 'dct_reorg' and table_name = 't' and column_name = 'b'").Check(testkit.Rows("utf8mb4_general_ci"))
+
+	// The case-insensitive collation makes the unique index duplicated, the job is rolled back.
+	tk.MustExec("create table t1(a int, b varchar(10) collate utf8mb4_bin, unique key uk_b(b))")
+	tk.MustExec("insert into t1 values (1, 'a'), (2, 'A')")
+	tk.MustGetErrCode("alter table t1 modify b varchar(10) collate utf8mb4_general_ci", errno.ErrDupEntry)
+	tk.MustQuery("select collation_name from information_schema.columns where table_schema = 'dct_reorg' and table_name = 't1' and column_name = 'b'").Check(testkit.Rows("utf8mb4_bin"))
+	tk.MustExec("admin check table t1")
+	tk.MustQuery("select a from t1 where b = 'A'").Check(testkit.Rows("2"))
+
+	// Change the charset of an indexed column.
+	tk.MustExec("create table t2(a int, b varchar(10) charset latin1, index idx_b(b))")
+	tk.MustExec("insert into t2 values (1, 'abc'), (2, 'ABC')")
+	tk.MustExec("alter table t2 modify b varchar(10) charset utf8mb4 collate utf8mb4_general_ci")
+	tk.MustExec("admin check table t2")
+	tk.MustQuery("select a from t2 use index(idx_b) where b = 'abc' order by a").Check(testkit.Rows("1", "2"))
+	tk.MustGetErrCode("alter table t2 modify b varchar(10) charset ascii", errno.ErrUnsupportedDDLOperation)
+
+	// The non-ASCII latin1 data is kept if it's a valid utf8mb4 string.
+	tk.MustExec("create table t3(a int, b varchar(10) charset latin1, index idx_b(b))")
+	tk.MustExec("insert into t3 values (1, 'é'), (2, 'É'), (3, 'e')")
+	tk.MustExec("alter table t3 modify b varchar(10) charset utf8mb4 collate utf8mb4_general_ci")
+	tk.MustExec("admin check table t3")
+	tk.MustQuery("select a, hex(b) from t3 order by a").Check(testkit.Rows("1 C3A9", "2 C389", "3 65"))
+	tk.MustQuery("select a from t3 use index(idx_b) where b = 'é' order by a").Check(testkit.Rows("1", "2", "3"))
+
+	// The invalid utf8mb4 string fails the job in the strict mode, and the job is rolled back.
+	tk.MustExec("create table t4(a int, b varchar(10) charset latin1, index idx_b(b))")
+	tk.MustExec("insert into t4 values (1, 'a'), (2, x'e9')")
+	tk.MustGetErrCode("alter table t4 modify b varchar(10) charset utf8mb4", errno.ErrTruncatedWrongValueForField)
+	tk.MustQuery("select character_set_name from information_schema.columns where table_schema = 'dct_reorg' and table_name = 't4' and column_name = 'b'").Check(testkit.Rows("latin1"))
+	tk.MustExec("admin check table t4")
+	tk.MustQuery("select a, hex(b) from t4 order by a").Check(testkit.Rows("1 61", "2 E9"))
+}
+
 func (s *testSerialSuite) TestForbidUnsupportedCollations(c *C) {
 	collate.SetNewCollationEnabledForTest(true)
 	defer collate.SetNewCollationEnabledForTest(false)
@@ -1091,6 +1091,14 @@ func onUpdateFlashReplicaStatus(t *meta.Meta, job *model.Job) (ver int64, _ erro
 				}
 				allAvailable = allAvailable && tblInfo.TiFlashReplica.IsPartitionAvailable(p.ID)
 			}
+			// The partitions being reorganized become available before they are public.
+			if pi.DDLState != model.StateNone && pi.DDLState != model.StateDeleteReorganization {
+				for _, p := range pi.AddingDefinitions {
+					if p.ID == physicalID {
+						tblInfo.TiFlashReplica.AvailablePartitionIDs = append(tblInfo.TiFlashReplica.AvailablePartitionIDs, physicalID)
+					}
+				}
+			}
 			tblInfo.TiFlashReplica.Available = allAvailable
 		} else {
 			// Partition replica become unavailable.
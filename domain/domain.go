@@ -1080,6 +1080,9 @@ func (do *Domain) globalBindHandleWorkerLoop(owner owner.Manager) {
 				if variable.TiDBOptOn(variable.CapturePlanBaseline.GetVal()) {
 					do.bindHandle.CaptureBaselines()
 				}
+				if variable.EnablePlanRegressionDetection.Load() {
+					do.bindHandle.HandlePlanRegressions(variable.PlanRegressionAutoBind.Load())
+				}
 				do.bindHandle.SaveEvolveTasksToStore()
 			case <-gcBindTicker.C:
 				if !owner.IsOwner() {
@@ -201,6 +201,10 @@ func (do *Domain) checkEnableServerGlobalVar(name, sVal string) {
 		err = stmtsummary.StmtSummaryByDigestMap.SetMaxSQLLength(sVal, false)
 	case variable.TiDBCapturePlanBaseline:
 		variable.CapturePlanBaseline.Set(sVal, false)
+	case variable.TiDBEnablePlanRegressionDetection:
+		variable.EnablePlanRegressionDetection.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBPlanRegressionAutoBind:
+		variable.PlanRegressionAutoBind.Store(variable.TiDBOptOn(sVal))
 	case variable.TiDBEnableTopSQL:
 		variable.TopSQLVariable.Enable.Store(variable.TiDBOptOn(sVal))
 	case variable.TiDBTopSQLPrecisionSeconds:
@@ -71,6 +71,7 @@ const (
 	flagReadTimeout              = "read-timeout"
 	flagTransactionalConsistency = "transactional-consistency"
 	flagCompress                 = "compress"
+	flagIncrementalFrom          = "incremental-from"
 
 	// FlagHelp represents the help flag
 	FlagHelp = "help"
@@ -134,6 +135,11 @@ type Config struct {
 	SessionParams      map[string]interface{}
 	Labels             prometheus.Labels `json:"-"`
 	Tables             DatabaseTables
+	IncrementalFrom    string
+	IncrementalBaseTS  uint64
+	// IncrementalTimeZone is the time zone of the server, which the DATETIME
+	// update timestamp columns are written in.
+	IncrementalTimeZone *time.Location `json:"-"`
 }
 
 // DefaultConfig returns the default export Config for dumpling
@@ -226,7 +232,7 @@ func (conf *Config) DefineFlags(flags *pflag.FlagSet) {
 		"If not specified, dumpling will dump table without inner-concurrency which could be relatively slow. default unlimited")
 	flags.String(flagWhere, "", "Dump only selected records")
 	flags.Bool(flagEscapeBackslash, true, "use backslash to escape special characters")
-	flags.String(flagFiletype, "", "The type of export file (sql/csv)")
+	flags.String(flagFiletype, "", "The type of export file (sql/csv/parquet)")
 	flags.Bool(flagNoHeader, false, "whether not to dump CSV table header")
 	flags.BoolP(flagNoSchemas, "m", false, "Do not dump table schemas with the data")
 	flags.BoolP(flagNoData, "d", false, "Do not dump table data")
@@ -250,7 +256,8 @@ func (conf *Config) DefineFlags(flags *pflag.FlagSet) {
 	_ = flags.MarkHidden(flagReadTimeout)
 	flags.Bool(flagTransactionalConsistency, true, "Only support transactional consistency")
 	_ = flags.MarkHidden(flagTransactionalConsistency)
-	flags.StringP(flagCompress, "c", "", "Compress output file type, support 'gzip', 'no-compression' now")
+	flags.StringP(flagCompress, "c", "", "Compress output file type, support 'gzip', 'snappy', 'zstd', 'no-compression' now")
+	flags.String(flagIncrementalFrom, "", "The output directory of a previous dump of TiDB. If specified, only the rows updated since the snapshot of that dump are dumped")
 }
 
 // ParseFromFlags parses dumpling's export.Config from flags
@@ -397,6 +404,10 @@ func (conf *Config) ParseFromFlags(flags *pflag.FlagSet) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	conf.IncrementalFrom, err = flags.GetString(flagIncrementalFrom)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	if conf.Threads <= 0 {
 		return errors.Errorf("--threads is set to %d. It should be greater than 0", conf.Threads)
@@ -521,6 +532,10 @@ func ParseCompressType(compressType string) (storage.CompressType, error) {
 		return storage.NoCompression, nil
 	case "gzip", "gz":
 		return storage.Gzip, nil
+	case "snappy":
+		return storage.Snappy, nil
+	case "zstd", "zst":
+		return storage.Zstd, nil
 	default:
 		return storage.NoCompression, errors.Errorf("unknown compress type %s", compressType)
 	}
@@ -714,6 +729,18 @@ func validateSpecifiedSQL(conf *Config) error {
 	return nil
 }
 
+func validateIncremental(conf *Config) error {
+	if conf.SQL != "" && (conf.IncrementalFrom != "" || conf.IncrementalBaseTS != 0) {
+		return errors.New("can't specify both --sql and --incremental-from at the same time")
+	}
+	// the deleted rows are written as DELETE statements, which can't be expressed in the other formats
+	if (conf.IncrementalFrom != "" || conf.IncrementalBaseTS != 0) &&
+		conf.FileType != "" && !strings.EqualFold(conf.FileType, FileFormatSQLTextString) {
+		return errors.Errorf("unsupported config.FileType '%s' when we specify --incremental-from, please unset --filetype or set it to 'sql'", conf.FileType)
+	}
+	return nil
+}
+
 func adjustFileFormat(conf *Config) error {
 	conf.FileType = strings.ToLower(conf.FileType)
 	switch conf.FileType {
@@ -725,9 +752,9 @@ func adjustFileFormat(conf *Config) error {
 		}
 	case FileFormatSQLTextString:
 		if conf.SQL != "" {
-			return errors.Errorf("unsupported config.FileType '%s' when we specify --sql, please unset --filetype or set it to 'csv'", conf.FileType)
+			return errors.Errorf("unsupported config.FileType '%s' when we specify --sql, please unset --filetype or set it to 'csv' or 'parquet'", conf.FileType)
 		}
-	case FileFormatCSVString:
+	case FileFormatCSVString, FileFormatParquetString:
 	default:
 		return errors.Errorf("unknown config.FileType '%s'", conf.FileType)
 	}
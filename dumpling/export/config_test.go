@@ -5,6 +5,7 @@ package export
 import (
 	"testing"
 
+	"github.com/pingcap/tidb/br/pkg/storage"
 	tcontext "github.com/pingcap/tidb/dumpling/context"
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +34,45 @@ func TestMatchMysqlBugVersion(t *testing.T) {
 		require.Equalf(t, x.expected, matchMysqlBugversion(x.serverInfo), "server info: %s", x.serverInfo)
 	}
 }
+
+func TestParseCompressType(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		compressType string
+		expected     storage.CompressType
+		suffix       string
+	}{
+		{"", storage.NoCompression, ""},
+		{"no-compression", storage.NoCompression, ""},
+		{"gzip", storage.Gzip, ".gz"},
+		{"gz", storage.Gzip, ".gz"},
+		{"snappy", storage.Snappy, ".snappy"},
+		{"zstd", storage.Zstd, ".zst"},
+		{"zst", storage.Zstd, ".zst"},
+	}
+	for _, x := range cases {
+		compressType, err := ParseCompressType(x.compressType)
+		require.NoError(t, err)
+		require.Equalf(t, x.expected, compressType, "compress type: %s", x.compressType)
+		require.Equal(t, x.suffix, compressFileSuffix(compressType))
+	}
+	_, err := ParseCompressType("lz4")
+	require.Error(t, err)
+}
+
+func TestValidateIncremental(t *testing.T) {
+	t.Parallel()
+	conf := defaultConfigForTest(t)
+	conf.IncrementalFrom = "/tmp/previous"
+	require.NoError(t, validateIncremental(conf))
+
+	conf.FileType = "SQL"
+	require.NoError(t, validateIncremental(conf))
+	// the deleted rows can't be written in csv or parquet files
+	conf.FileType = FileFormatCSVString
+	require.EqualError(t, validateIncremental(conf), "unsupported config.FileType 'csv' when we specify --incremental-from, please unset --filetype or set it to 'sql'")
+
+	conf.FileType = ""
+	conf.SQL = "SELECT * FROM t"
+	require.EqualError(t, validateIncremental(conf), "can't specify both --sql and --incremental-from at the same time")
+}
@@ -42,6 +42,8 @@ type Dumper struct {
 
 	extStore storage.ExternalStorage
 	dbHandle *sql.DB
+	// incrementalBaseDB reads the snapshot of the previous dump in incremental dumps.
+	incrementalBaseDB *sql.DB
 
 	tidbPDClientForGC         pd.Client
 	selectTiDBTableRegionFunc func(tctx *tcontext.Context, conn *sql.Conn, meta TableMeta) (pkFields []string, pkVals [][]string, err error)
@@ -59,6 +61,7 @@ func NewDumper(ctx context.Context, conf *Config) (*Dumper, error) {
 	err := adjustConfig(conf,
 		registerTLSConfig,
 		validateSpecifiedSQL,
+		validateIncremental,
 		adjustFileFormat)
 	if err != nil {
 		return nil, err
@@ -73,9 +76,11 @@ func NewDumper(ctx context.Context, conf *Config) (*Dumper, error) {
 
 		tidbSetPDClientForGC,
 		tidbGetSnapshot,
+		tidbReadIncrementalBase,
 		tidbStartGCSavepointUpdateService,
 
-		setSessionParam)
+		setSessionParam,
+		openIncrementalBaseDB)
 	return d, err
 }
 
@@ -132,6 +137,9 @@ func (d *Dumper) Dump() (dumpErr error) {
 	}
 	defer metaConn.Close()
 	m.recordStartTime(time.Now())
+	if conf.IncrementalBaseTS != 0 {
+		m.recordIncrementalBase(conf.IncrementalBaseTS)
+	}
 	// for consistency lock, we can write snapshot info after all tables are locked.
 	// the binlog pos may changed because there is still possible write between we lock tables and write master status.
 	// but for the locked tables doing replication that starts from metadata is safe.
@@ -330,10 +338,20 @@ func (d *Dumper) dumpDatabases(tctx *tcontext.Context, metaConn *sql.Conn, taskC
 				}
 			}
 			if table.Type == TableTypeBase {
+				if conf.IncrementalBaseTS != 0 && meta.IncrementalCondition() == "" {
+					tctx.L().Warn("no update timestamp column found, will dump the whole table in incremental dump",
+						zap.String("database", dbName), zap.String("table", table.Name))
+				}
 				err = d.dumpTableData(tctx, metaConn, meta, taskChan)
 				if err != nil {
 					return err
 				}
+				if conf.IncrementalBaseTS != 0 {
+					err = d.dumpDeletedRows(tctx, metaConn, meta)
+					if err != nil {
+						return err
+					}
+				}
 			}
 		}
 	}
@@ -358,6 +376,36 @@ func (d *Dumper) dumpTableData(tctx *tcontext.Context, conn *sql.Conn, meta Tabl
 	return d.concurrentDumpTable(tctx, conn, meta, taskChan)
 }
 
+// dumpDeletedRows writes the rows deleted since the previous dump of an
+// incremental dump to the deleted file of the table.
+func (d *Dumper) dumpDeletedRows(tctx *tcontext.Context, conn *sql.Conn, meta TableMeta) error {
+	conf := d.conf
+	if conf.NoData {
+		return nil
+	}
+	fileName, err := (&outputFileNamer{DB: meta.DatabaseName(), Table: meta.TableName()}).render(conf.OutputFileTemplate, outputFileTemplateDeleted)
+	if err != nil {
+		return err
+	}
+	baseConn, err := d.incrementalBaseDB.Conn(tctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer baseConn.Close()
+
+	w, tearDown := buildInterceptFileWriter(tctx, d.extStore, fileName+".sql", conf.CompressType)
+	n, err := writeDeletedRows(tctx, conf, meta, baseConn, conn, w)
+	tearDown(tctx)
+	if err != nil {
+		return err
+	}
+	tctx.L().Debug("finish dumping deleted rows",
+		zap.String("database", meta.DatabaseName()),
+		zap.String("table", meta.TableName()),
+		zap.Uint64("deleted rows", n))
+	return nil
+}
+
 func (d *Dumper) buildConcatTask(tctx *tcontext.Context, conn *sql.Conn, meta TableMeta) (*TaskTableData, error) {
 	tableChan := make(chan Task, 128)
 	errCh := make(chan error, 1)
@@ -529,7 +577,7 @@ func (d *Dumper) concurrentDumpTable(tctx *tcontext.Context, conn *sql.Conn, met
 	for max.Cmp(cutoff) >= 0 {
 		nextCutOff := new(big.Int).Add(cutoff, bigEstimatedStep)
 		where := fmt.Sprintf("%s(`%s` >= %d AND `%s` < %d)", nullValueCondition, escapeString(field), cutoff, escapeString(field), nextCutOff)
-		query := buildSelectQuery(db, tbl, selectField, "", buildWhereCondition(conf, where), orderByClause)
+		query := buildSelectQuery(db, tbl, selectField, "", buildWhereCondition(conf, buildIncrementalWhere(meta, where)), orderByClause)
 		if len(nullValueCondition) > 0 {
 			nullValueCondition = ""
 		}
@@ -676,7 +724,7 @@ func (d *Dumper) sendConcurrentDumpTiDBTasks(tctx *tcontext.Context,
 	orderByClause := buildOrderByClauseString(handleColNames)
 
 	for i, w := range where {
-		query := buildSelectQuery(db, tbl, selectField, partition, buildWhereCondition(conf, w), orderByClause)
+		query := buildSelectQuery(db, tbl, selectField, partition, buildWhereCondition(conf, buildIncrementalWhere(meta, w)), orderByClause)
 		task := NewTaskTableData(meta, newTableData(query, selectLen, false), i+startChunkIdx, totalChunk)
 		ctxDone := d.sendTaskToChan(tctx, task, taskChan)
 		if ctxDone {
@@ -923,6 +971,42 @@ func dumpTableMeta(conf *Config, conn *sql.Conn, db string, table *TableInfo) (T
 		},
 	}
 
+	if conf.FileType == FileFormatParquetString && table.Type == TableTypeBase {
+		meta.unsignedColumns, err = selectUnsignedFields(conn, db, tbl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.IncrementalBaseTS != 0 && table.Type == TableTypeBase {
+		// the deleted rows are found by the primary key
+		meta.incrementalKeys, err = GetPrimaryKeyColumns(conn, db, tbl)
+		if err != nil {
+			return nil, err
+		}
+		if len(meta.incrementalKeys) == 0 {
+			return nil, errors.Errorf("can't dump table %s.%s incrementally because it has no primary key, so its deleted rows can't be found",
+				wrapBackTicks(escapeString(db)), wrapBackTicks(escapeString(tbl)))
+		}
+		var (
+			updateTimeField string
+			isTimestamp     bool
+		)
+		updateTimeField, isTimestamp, err = selectUpdateTimestampField(conn, db, tbl)
+		if err != nil {
+			return nil, err
+		}
+		if updateTimeField != "" {
+			// TIMESTAMP values are read in UTC, the session time zone of incremental dumps
+			loc := time.UTC
+			if !isTimestamp && conf.IncrementalTimeZone != nil {
+				loc = conf.IncrementalTimeZone
+			}
+			meta.incrementalCondition = buildIncrementalCondition(updateTimeField, conf.IncrementalBaseTS, loc)
+		}
+		meta.specCmts = append(meta.specCmts, "/*!40103 SET TIME_ZONE='+00:00' */;")
+	}
+
 	if conf.NoSchemas {
 		return meta, nil
 	}
@@ -966,6 +1050,9 @@ func canRebuildConn(consistency string, trxConsistencyOnly bool) bool {
 // Close closes a Dumper and stop dumping immediately
 func (d *Dumper) Close() error {
 	d.cancelCtx()
+	if d.incrementalBaseDB != nil {
+		_ = d.incrementalBaseDB.Close()
+	}
 	if d.dbHandle != nil {
 		return d.dbHandle.Close()
 	}
@@ -1133,6 +1220,10 @@ func tidbStartGCSavepointUpdateService(d *Dumper) error {
 		if err != nil {
 			return err
 		}
+		// the snapshot of the previous dump is read to find the deleted rows
+		if conf.IncrementalBaseTS != 0 && conf.IncrementalBaseTS < snapshotTS {
+			snapshotTS = conf.IncrementalBaseTS
+		}
 		go updateServiceSafePoint(tctx, d.tidbPDClientForGC, defaultDumpGCSafePointTTL, snapshotTS)
 	} else if si.ServerType == ServerTypeTiDB {
 		tctx.L().Warn("If the amount of data to dump is large, criteria: (data more than 60GB or dumped time more than 10 minutes)\n" +
@@ -1143,6 +1234,53 @@ func tidbStartGCSavepointUpdateService(d *Dumper) error {
 	return nil
 }
 
+// tidbReadIncrementalBase is an initialization step of Dumper.
+func tidbReadIncrementalBase(d *Dumper) error {
+	tctx, conf, pool := d.tctx, d.conf, d.dbHandle
+	if conf.IncrementalFrom == "" && conf.IncrementalBaseTS == 0 {
+		return nil
+	}
+	if conf.ServerInfo.ServerType != ServerTypeTiDB {
+		return errors.New("incremental dump is not supported for this server")
+	}
+	if conf.Consistency != consistencyTypeSnapshot || conf.Snapshot == "" {
+		return errors.New("incremental dump is only supported with snapshot consistency")
+	}
+	if conf.IncrementalBaseTS == 0 {
+		b, err := storage.ParseBackend(conf.IncrementalFrom, &conf.BackendOptions)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s, err := storage.New(tctx, b, &storage.ExternalStorageOptions{})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		content, err := s.ReadFile(tctx, metadataPath)
+		if err != nil {
+			return errors.Annotatef(err, "fail to read the metadata of the previous dump %s", conf.IncrementalFrom)
+		}
+		conf.IncrementalBaseTS, err = parseSnapshotTSFromMetadata(string(content))
+		if err != nil {
+			return errors.Annotatef(err, "fail to parse the metadata of the previous dump %s", conf.IncrementalFrom)
+		}
+	}
+	snapshotTS, err := parseSnapshotToTSO(pool, conf.Snapshot)
+	if err != nil {
+		return err
+	}
+	if snapshotTS <= conf.IncrementalBaseTS {
+		return errors.Errorf("snapshot %d is not later than the snapshot %d of the previous dump", snapshotTS, conf.IncrementalBaseTS)
+	}
+	conf.IncrementalTimeZone, err = detectServerTimeZone(pool)
+	if err != nil {
+		return errors.Annotate(err, "fail to detect the time zone of the server")
+	}
+	tctx.L().Info("dump the rows updated since the previous dump",
+		zap.Uint64("previous snapshot", conf.IncrementalBaseTS),
+		zap.Uint64("snapshot", snapshotTS))
+	return nil
+}
+
 func updateServiceSafePoint(tctx *tcontext.Context, pdClient pd.Client, ttl int64, snapshotTS uint64) {
 	updateInterval := time.Duration(ttl/2) * time.Second
 	tick := time.NewTicker(updateInterval)
@@ -1197,12 +1335,38 @@ func setSessionParam(d *Dumper) error {
 			}
 		}
 	}
+	// the update timestamp columns are compared in UTC in incremental dumps
+	if conf.IncrementalBaseTS != 0 {
+		sessionParam["time_zone"] = "+00:00"
+	}
 	if d.dbHandle, err = resetDBWithSessionParams(d.tctx, pool, conf.GetDSN(""), conf.SessionParams); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
 }
 
+// openIncrementalBaseDB is an initialization step of Dumper.
+func openIncrementalBaseDB(d *Dumper) error {
+	conf := d.conf
+	if conf.IncrementalBaseTS == 0 {
+		return nil
+	}
+	sessionParam := make(map[string]interface{}, len(conf.SessionParams))
+	for k, v := range conf.SessionParams {
+		sessionParam[k] = v
+	}
+	sessionParam["tidb_snapshot"] = strconv.FormatUint(conf.IncrementalBaseTS, 10)
+	pool, err := openDBFunc("mysql", conf.GetDSN(""))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if d.incrementalBaseDB, err = resetDBWithSessionParams(d.tctx, pool, conf.GetDSN(""), sessionParam); err != nil {
+		pool.Close()
+		return errors.Annotatef(err, "fail to read the snapshot %d of the previous dump, it may have been garbage collected", conf.IncrementalBaseTS)
+	}
+	return nil
+}
+
 func (d *Dumper) renewSelectTableRegionFuncForLowerTiDB(tctx *tcontext.Context) error {
 	conf := d.conf
 	if !(conf.ServerInfo.ServerType == ServerTypeTiDB && conf.ServerInfo.ServerVersion != nil && conf.ServerInfo.HasTiKV &&
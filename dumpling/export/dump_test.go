@@ -5,6 +5,8 @@ package export
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -98,6 +100,168 @@ func TestDumpTableMeta(t *testing.T) {
 	}
 }
 
+func TestDumpTableMetaIncremental(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	tctx, cancel := tcontext.Background().WithLogger(appLogger).WithCancel()
+	defer cancel()
+	conn, err := db.Conn(tctx)
+	require.NoError(t, err)
+
+	conf := DefaultConfig()
+	conf.NoSchemas = true
+	conf.ServerInfo.ServerType = ServerTypeTiDB
+	conf.IncrementalBaseTS = 428646334496243717
+	// the server isn't in UTC
+	conf.IncrementalTimeZone = time.FixedZone("-05:00", -5*3600)
+
+	expectMeta := func(updatedType string, primaryKey bool) {
+		columns := func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+				AddRow("id", "int(11)", "NO", "PRI", nil, "").
+				AddRow("updated", updatedType, "NO", "", "CURRENT_TIMESTAMP(3)", "on update CURRENT_TIMESTAMP(3)")
+		}
+		mock.ExpectQuery("SHOW COLUMNS FROM").WillReturnRows(columns())
+		mock.ExpectExec("SELECT _tidb_rowid from").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM `%s`.`%s`", database, table)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "updated"}).AddRow(1, "2021-10-25 10:00:00"))
+		indexes := sqlmock.NewRows(showIndexHeaders)
+		if primaryKey {
+			indexes.AddRow(table, 0, "PRIMARY", 1, "id", "A", 0, nil, nil, "", "BTREE", "", "")
+		}
+		mock.ExpectQuery(fmt.Sprintf("SHOW INDEX FROM `%s`.`%s`", database, table)).WillReturnRows(indexes)
+		if primaryKey {
+			mock.ExpectQuery("SHOW COLUMNS FROM").WillReturnRows(columns())
+		}
+	}
+
+	// the TIMESTAMP values are compared in UTC, the session time zone
+	expectMeta("timestamp(3)", true)
+	meta, err := dumpTableMeta(conf, conn, database, &TableInfo{Type: TableTypeBase, Name: table})
+	require.NoError(t, err)
+	require.Equal(t, "`updated` >= '2021-10-25 09:00:00.123'", meta.IncrementalCondition())
+	require.Equal(t, []string{"id"}, meta.IncrementalKeys())
+	require.Equal(t, []string{"/*!40101 SET NAMES binary*/;", "/*!40103 SET TIME_ZONE='+00:00' */;"},
+		meta.(*tableMeta).specCmts)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	tableIR := SelectAllFromTable(conf, meta, "", "ORDER BY `id`")
+	require.Regexp(t, fmt.Sprintf("^SELECT \\* FROM `%s`.`%s` WHERE `updated` >= '2021-10-25 09:00:00.123' +ORDER BY `id`$", database, table),
+		tableIR.(*tableData).query)
+
+	// the DATETIME values are compared in the time zone of the server
+	expectMeta("datetime(3)", true)
+	meta, err = dumpTableMeta(conf, conn, database, &TableInfo{Type: TableTypeBase, Name: table})
+	require.NoError(t, err)
+	require.Equal(t, "`updated` >= '2021-10-25 04:00:00.123'", meta.IncrementalCondition())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// the deleted rows of the tables without primary key can't be found
+	expectMeta("timestamp(3)", false)
+	_, err = dumpTableMeta(conf, conn, database, &TableInfo{Type: TableTypeBase, Name: table})
+	require.EqualError(t, err, fmt.Sprintf("can't dump table `%s`.`%s` incrementally because it has no primary key, so its deleted rows can't be found", database, table))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDumpDeletedRows(t *testing.T) {
+	t.Parallel()
+
+	baseDB, baseMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = baseDB.Close()
+	}()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	tctx, cancel := tcontext.Background().WithLogger(appLogger).WithCancel()
+	defer cancel()
+	conn, err := db.Conn(tctx)
+	require.NoError(t, err)
+
+	conf := defaultConfigForTest(t)
+	conf.OutputDirPath = t.TempDir()
+	conf.IncrementalBaseTS = 428646334496243717
+	mu.Lock()
+	extStore, err := conf.createExternalStorage(tctx)
+	mu.Unlock()
+	require.NoError(t, err)
+	d := &Dumper{
+		tctx:              tctx,
+		conf:              conf,
+		cancelCtx:         cancel,
+		extStore:          extStore,
+		incrementalBaseDB: baseDB,
+	}
+
+	meta := &tableMeta{
+		database:        database,
+		table:           table,
+		specCmts:        []string{"/*!40101 SET NAMES binary*/;", "/*!40103 SET TIME_ZONE='+00:00' */;"},
+		incrementalKeys: []string{"id"},
+	}
+	baseMock.ExpectQuery(fmt.Sprintf("SELECT `id` FROM `%s`.`%s` ORDER BY `id`", database, table)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(fmt.Sprintf("SELECT `id` FROM `%s`.`%s` WHERE \\(`id`\\) IN", database, table)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	require.NoError(t, d.dumpDeletedRows(tctx, conn, meta))
+	require.NoError(t, baseMock.ExpectationsWereMet())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	content, err := os.ReadFile(filepath.Join(conf.OutputDirPath, fmt.Sprintf("%s.%s-deleted.sql", database, table)))
+	require.NoError(t, err)
+	require.Equal(t, "/*!40101 SET NAMES binary*/;\n"+
+		"/*!40103 SET TIME_ZONE='+00:00' */;\n"+
+		fmt.Sprintf("DELETE FROM `%s` WHERE (`id`) IN (\n", table)+
+		"('2'));\n", string(content))
+}
+
+func TestDumpTableMetaParquetUnsigned(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	tctx, cancel := tcontext.Background().WithLogger(appLogger).WithCancel()
+	defer cancel()
+	conn, err := db.Conn(tctx)
+	require.NoError(t, err)
+
+	conf := DefaultConfig()
+	conf.NoSchemas = true
+	conf.FileType = FileFormatParquetString
+
+	columns := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int(11)", "NO", "PRI", nil, "").
+			AddRow("u", "bigint(20) unsigned", "YES", "", nil, "").
+			AddRow("name", "varchar(20)", "YES", "", nil, "")
+	}
+	mock.ExpectQuery("SHOW COLUMNS FROM").WillReturnRows(columns())
+	mock.ExpectQuery(fmt.Sprintf("SELECT \\* FROM `%s`.`%s`", database, table)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "u", "name"}).AddRow(1, nil, "a"))
+	mock.ExpectQuery("SHOW COLUMNS FROM").WillReturnRows(columns())
+	meta, err := dumpTableMeta(conf, conn, database, &TableInfo{Type: TableTypeBase, Name: table})
+	require.NoError(t, err)
+	require.False(t, meta.IsUnsignedColumn("id"))
+	require.True(t, meta.IsUnsignedColumn("u"))
+	require.False(t, meta.IsUnsignedColumn("name"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetListTableTypeByConf(t *testing.T) {
 	t.Parallel()
 
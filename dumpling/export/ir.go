@@ -27,6 +27,7 @@ type TableMeta interface {
 	ColumnCount() uint
 	ColumnTypes() []string
 	ColumnNames() []string
+	SQLColumnTypes() []*sql.ColumnType
 	SelectedField() string
 	SelectedLen() int
 	SpecialComments() StringIter
@@ -34,6 +35,9 @@ type TableMeta interface {
 	ShowCreateView() string
 	AvgRowLength() uint64
 	HasImplicitRowID() bool
+	IncrementalCondition() string
+	IncrementalKeys() []string
+	IsUnsignedColumn(name string) bool
 }
 
 // SQLRowIter is the iterator on a collection of sql.Row.
@@ -258,6 +258,14 @@ type tableMeta struct {
 	showCreateView   string
 	avgRowLength     uint64
 	hasImplicitRowID bool
+	// incrementalCondition filters the rows updated since the previous dump.
+	incrementalCondition string
+	// incrementalKeys are the primary key columns which identify the deleted
+	// rows in incremental dumps.
+	incrementalKeys []string
+	// unsignedColumns is the set of the unsigned integer columns, it's only
+	// collected when dumping parquet files.
+	unsignedColumns map[string]struct{}
 }
 
 func (tm *tableMeta) ColumnTypes() []string {
@@ -268,6 +276,10 @@ func (tm *tableMeta) ColumnTypes() []string {
 	return colTypes
 }
 
+func (tm *tableMeta) SQLColumnTypes() []*sql.ColumnType {
+	return tm.colTypes
+}
+
 func (tm *tableMeta) ColumnNames() []string {
 	colNames := make([]string, len(tm.colTypes))
 	for i, ct := range tm.colTypes {
@@ -316,6 +328,19 @@ func (tm *tableMeta) HasImplicitRowID() bool {
 	return tm.hasImplicitRowID
 }
 
+func (tm *tableMeta) IncrementalCondition() string {
+	return tm.incrementalCondition
+}
+
+func (tm *tableMeta) IncrementalKeys() []string {
+	return tm.incrementalKeys
+}
+
+func (tm *tableMeta) IsUnsignedColumn(name string) bool {
+	_, ok := tm.unsignedColumns[name]
+	return ok
+}
+
 type metaData struct {
 	target   string
 	metaSQL  string
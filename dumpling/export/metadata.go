@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +33,8 @@ const (
 	fileFieldIndex    = 0
 	posFieldIndex     = 1
 	gtidSetFieldIndex = 4
+
+	tidbBinlogLogFile = "tidb-binlog"
 )
 
 func newGlobalMetadata(tctx *tcontext.Context, s storage.ExternalStorage, snapshot string) *globalMetadata {
@@ -51,6 +54,10 @@ func (m *globalMetadata) recordStartTime(t time.Time) {
 	m.buffer.WriteString("Started dump at: " + t.Format(metadataTimeLayout) + "\n")
 }
 
+func (m *globalMetadata) recordIncrementalBase(ts uint64) {
+	m.buffer.WriteString("Incremental since: " + strconv.FormatUint(ts, 10) + "\n")
+}
+
 func (m *globalMetadata) recordFinishTime(t time.Time) {
 	m.buffer.Write(m.afterConnBuffer.Bytes())
 	m.buffer.WriteString("Finished dump at: " + t.Format(metadataTimeLayout) + "\n")
@@ -223,6 +230,38 @@ func (m *globalMetadata) writeGlobalMetaData() error {
 	return write(m.tctx, fileWriter, m.String())
 }
 
+// parseSnapshotTSFromMetadata parses the snapshot TSO of a TiDB dump from the
+// content of its metadata file, which is the position of the first
+// "SHOW MASTER STATUS" section.
+func parseSnapshotTSFromMetadata(content string) (uint64, error) {
+	var (
+		inMasterStatus bool
+		logFile        string
+	)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "SHOW MASTER STATUS:"):
+			inMasterStatus = true
+		case line == "":
+			inMasterStatus = false
+		case inMasterStatus && strings.HasPrefix(line, "Log:"):
+			logFile = strings.TrimSpace(strings.TrimPrefix(line, "Log:"))
+		case inMasterStatus && strings.HasPrefix(line, "Pos:"):
+			if logFile != tidbBinlogLogFile {
+				return 0, errors.Errorf("the metadata is not dumped from TiDB, binlog file '%s'", logFile)
+			}
+			pos := strings.TrimSpace(strings.TrimPrefix(line, "Pos:"))
+			ts, err := strconv.ParseUint(pos, 10, 64)
+			if err != nil {
+				return 0, errors.Annotatef(err, "invalid snapshot TSO '%s' in the metadata", pos)
+			}
+			return ts, nil
+		}
+	}
+	return 0, errors.New("can't find the snapshot TSO in the metadata")
+}
+
 func getValidStr(str []string, idx int) string {
 	if idx < len(str) {
 		return str[idx]
@@ -305,6 +305,45 @@ func TestTiDBSnapshotMetaData(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestParseSnapshotTSFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata := "Started dump at: 2021-10-25 10:00:00\n" +
+		"SHOW MASTER STATUS:\n" +
+		"\tLog: tidb-binlog\n" +
+		"\tPos: 428463226540720129\n" +
+		"\tGTID:\n\n" +
+		"SHOW MASTER STATUS: /* AFTER CONNECTION POOL ESTABLISHED */\n" +
+		"\tLog: tidb-binlog\n" +
+		"\tPos: 428463226737852417\n" +
+		"\tGTID:\n\n" +
+		"Finished dump at: 2021-10-25 10:00:10\n"
+	ts, err := parseSnapshotTSFromMetadata(metadata)
+	require.NoError(t, err)
+	require.Equal(t, uint64(428463226540720129), ts)
+
+	metadata = "Started dump at: 2021-10-25 10:00:00\n" +
+		"SHOW MASTER STATUS:\n" +
+		"\tLog: ON.000001\n" +
+		"\tPos: 7502\n" +
+		"\tGTID:6ce40be3-e359-11e9-87e0-36933cb0ca5a:1-29\n\n" +
+		"Finished dump at: 2021-10-25 10:00:10\n"
+	_, err = parseSnapshotTSFromMetadata(metadata)
+	require.EqualError(t, err, "the metadata is not dumped from TiDB, binlog file 'ON.000001'")
+
+	metadata = "Started dump at: 2021-10-25 10:00:00\n" +
+		"SHOW MASTER STATUS:\n" +
+		"\tLog: tidb-binlog\n" +
+		"\tPos: 2021-10-25 10:00:00\n" +
+		"\tGTID:\n\n"
+	_, err = parseSnapshotTSFromMetadata(metadata)
+	require.Error(t, err)
+	require.Regexp(t, "^invalid snapshot TSO '2021-10-25 10:00:00' in the metadata", err.Error())
+
+	_, err = parseSnapshotTSFromMetadata("Started dump at: 2021-10-25 10:00:00\nFinished dump at: 2021-10-25 10:00:10\n")
+	require.EqualError(t, err, "can't find the snapshot TSO in the metadata")
+}
+
 func TestNoPrivilege(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,324 @@
+// Copyright 2021 PingCAP, Inc. Licensed under Apache-2.0.
+
+package export
+
+import (
+	"database/sql"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/br/pkg/storage"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const (
+	parquetDateFormat     = "2006-01-02"
+	parquetDatetimeFormat = "2006-01-02 15:04:05.999999"
+	// parquetMaxInt64Precision is the max precision of the decimal stored as INT64.
+	parquetMaxInt64Precision = 18
+	secondsPerDay            = 24 * 60 * 60
+)
+
+// parquetConverter converts the text value of a column to the value of the
+// parquet physical type.
+type parquetConverter func(raw []byte) (interface{}, error)
+
+// parquetColumn is a column of the parquet file.
+type parquetColumn struct {
+	schema  *parquet.SchemaElement
+	convert parquetConverter
+}
+
+// newParquetSchema builds the parquet schema of the table. All columns are
+// OPTIONAL so that NULL could be represented.
+//
+// The column types are mapped as follows.
+//
+//	integers           -> INT64 (UINT_64 for the unsigned integers)
+//	FLOAT/DOUBLE       -> FLOAT/DOUBLE
+//	DECIMAL(p,s)       -> INT64 DECIMAL(p,s) if p <= 18, BYTE_ARRAY DECIMAL(p,s) otherwise
+//	DATE               -> INT32 DATE
+//	DATETIME/TIMESTAMP -> INT64 TIMESTAMP(MICROS), not adjusted to UTC
+//	JSON               -> BYTE_ARRAY JSON
+//	ENUM               -> BYTE_ARRAY ENUM
+//	binary types       -> BYTE_ARRAY
+//	others             -> BYTE_ARRAY STRING
+//
+// The DATETIME and TIMESTAMP values are the local time of the session time
+// zone, which is the same as the values in the SQL and CSV files.
+func newParquetSchema(meta TableMeta) ([]*parquet.SchemaElement, []parquetColumn) {
+	colNames, colTypes, sqlColTypes := meta.ColumnNames(), meta.ColumnTypes(), meta.SQLColumnTypes()
+	numChildren := int32(len(colTypes))
+	elements := make([]*parquet.SchemaElement, 0, len(colTypes)+1)
+	elements = append(elements, &parquet.SchemaElement{
+		Name:           "schema",
+		RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED),
+		NumChildren:    &numChildren,
+	})
+	columns := make([]parquetColumn, 0, len(colTypes))
+	for i, colType := range colTypes {
+		var sqlColType *sql.ColumnType
+		if i < len(sqlColTypes) {
+			sqlColType = sqlColTypes[i]
+		}
+		column := newParquetColumn(strings.ToUpper(colType), sqlColType, meta.IsUnsignedColumn(colNames[i]))
+		column.schema.Name = colNames[i]
+		column.schema.RepetitionType = parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL)
+		elements = append(elements, column.schema)
+		columns = append(columns, column)
+	}
+	return elements, columns
+}
+
+// newParquetColumn builds the parquet column of the column type. The driver
+// doesn't report whether a nullable column is unsigned, so it's taken from
+// the column definitions of the table and passed in separately.
+func newParquetColumn(colType string, sqlColType *sql.ColumnType, unsigned bool) parquetColumn { // revive:disable-line:flag-parameter
+	switch colType {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		if unsigned {
+			return parquetColumn{
+				schema: &parquet.SchemaElement{
+					Type:          parquet.TypePtr(parquet.Type_INT64),
+					ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_UINT_64),
+					LogicalType:   &parquet.LogicalType{INTEGER: &parquet.IntType{BitWidth: 64, IsSigned: false}},
+				},
+				convert: convertParquetUint64,
+			}
+		}
+		return parquetColumn{
+			schema: &parquet.SchemaElement{
+				Type:          parquet.TypePtr(parquet.Type_INT64),
+				ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_INT_64),
+				LogicalType:   &parquet.LogicalType{INTEGER: &parquet.IntType{BitWidth: 64, IsSigned: true}},
+			},
+			convert: convertParquetInt64,
+		}
+	case "FLOAT":
+		return parquetColumn{
+			schema:  &parquet.SchemaElement{Type: parquet.TypePtr(parquet.Type_FLOAT)},
+			convert: convertParquetFloat,
+		}
+	case "DOUBLE":
+		return parquetColumn{
+			schema:  &parquet.SchemaElement{Type: parquet.TypePtr(parquet.Type_DOUBLE)},
+			convert: convertParquetDouble,
+		}
+	case "DECIMAL":
+		if sqlColType != nil {
+			if precision, scale, ok := sqlColType.DecimalSize(); ok && precision > 0 && scale >= 0 && scale <= precision {
+				return newParquetDecimalColumn(int32(precision), int32(scale))
+			}
+		}
+	case "DATE":
+		return parquetColumn{
+			schema: &parquet.SchemaElement{
+				Type:          parquet.TypePtr(parquet.Type_INT32),
+				ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_DATE),
+				LogicalType:   &parquet.LogicalType{DATE: parquet.NewDateType()},
+			},
+			convert: convertParquetDate,
+		}
+	case "DATETIME", "TIMESTAMP":
+		// TIMESTAMP_MICROS implies the values are adjusted to UTC, so only the
+		// logical type is set here.
+		return parquetColumn{
+			schema: &parquet.SchemaElement{
+				Type: parquet.TypePtr(parquet.Type_INT64),
+				LogicalType: &parquet.LogicalType{TIMESTAMP: &parquet.TimestampType{
+					IsAdjustedToUTC: false,
+					Unit:            &parquet.TimeUnit{MICROS: parquet.NewMicroSeconds()},
+				}},
+			},
+			convert: convertParquetDatetime,
+		}
+	case "JSON":
+		return parquetColumn{
+			schema: &parquet.SchemaElement{
+				Type:          parquet.TypePtr(parquet.Type_BYTE_ARRAY),
+				ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_JSON),
+				LogicalType:   &parquet.LogicalType{JSON: parquet.NewJsonType()},
+			},
+			convert: convertParquetBytes,
+		}
+	case "ENUM":
+		// the ENUM converted type is not supported by the parquet writer, so
+		// only the logical type is set here.
+		return parquetColumn{
+			schema: &parquet.SchemaElement{
+				Type:        parquet.TypePtr(parquet.Type_BYTE_ARRAY),
+				LogicalType: &parquet.LogicalType{ENUM: parquet.NewEnumType()},
+			},
+			convert: convertParquetBytes,
+		}
+	}
+	if _, ok := dataTypeBin[colType]; ok {
+		return parquetColumn{
+			schema:  &parquet.SchemaElement{Type: parquet.TypePtr(parquet.Type_BYTE_ARRAY)},
+			convert: convertParquetBytes,
+		}
+	}
+	// the DECIMAL of the unknown precision is also dumped as a string.
+	return parquetColumn{
+		schema: &parquet.SchemaElement{
+			Type:          parquet.TypePtr(parquet.Type_BYTE_ARRAY),
+			ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			LogicalType:   &parquet.LogicalType{STRING: parquet.NewStringType()},
+		},
+		convert: convertParquetBytes,
+	}
+}
+
+func newParquetDecimalColumn(precision, scale int32) parquetColumn {
+	schema := &parquet.SchemaElement{
+		Type:          parquet.TypePtr(parquet.Type_BYTE_ARRAY),
+		ConvertedType: parquet.ConvertedTypePtr(parquet.ConvertedType_DECIMAL),
+		LogicalType:   &parquet.LogicalType{DECIMAL: &parquet.DecimalType{Scale: scale, Precision: precision}},
+		Scale:         &scale,
+		Precision:     &precision,
+	}
+	if precision <= parquetMaxInt64Precision {
+		schema.Type = parquet.TypePtr(parquet.Type_INT64)
+		return parquetColumn{
+			schema: schema,
+			convert: func(raw []byte) (interface{}, error) {
+				unscaled, err := parseUnscaledDecimal(raw, int(scale))
+				if err != nil {
+					return nil, err
+				}
+				if !unscaled.IsInt64() {
+					return nil, errors.Errorf("decimal '%s' overflows DECIMAL(%d,%d)", raw, precision, scale)
+				}
+				return unscaled.Int64(), nil
+			},
+		}
+	}
+	return parquetColumn{
+		schema: schema,
+		convert: func(raw []byte) (interface{}, error) {
+			unscaled, err := parseUnscaledDecimal(raw, int(scale))
+			if err != nil {
+				return nil, err
+			}
+			return string(bigIntToTwosComplement(unscaled)), nil
+		},
+	}
+}
+
+func convertParquetInt64(raw []byte) (interface{}, error) {
+	v, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return nil, errors.Annotatef(err, "value '%s' can't be dumped as parquet INT64", raw)
+	}
+	return v, nil
+}
+
+func convertParquetUint64(raw []byte) (interface{}, error) {
+	v, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// UINT_64 is stored as INT64 with the same bits.
+	return int64(v), nil
+}
+
+func convertParquetFloat(raw []byte) (interface{}, error) {
+	v, err := strconv.ParseFloat(string(raw), 32)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return float32(v), nil
+}
+
+func convertParquetDouble(raw []byte) (interface{}, error) {
+	v, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v, nil
+}
+
+func convertParquetBytes(raw []byte) (interface{}, error) {
+	// the raw bytes are reused by the row receiver, so they must be copied.
+	return string(raw), nil
+}
+
+func convertParquetDate(raw []byte) (interface{}, error) {
+	t, err := time.ParseInLocation(parquetDateFormat, string(raw), time.UTC)
+	if err != nil {
+		return nil, errors.Annotatef(err, "date '%s' can't be dumped as parquet DATE", raw)
+	}
+	days := t.Unix() / secondsPerDay
+	if days < math.MinInt32 || days > math.MaxInt32 {
+		return nil, errors.Errorf("date '%s' overflows parquet DATE", raw)
+	}
+	return int32(days), nil
+}
+
+func convertParquetDatetime(raw []byte) (interface{}, error) {
+	t, err := time.ParseInLocation(parquetDatetimeFormat, string(raw), time.UTC)
+	if err != nil {
+		return nil, errors.Annotatef(err, "datetime '%s' can't be dumped as parquet TIMESTAMP", raw)
+	}
+	return t.Unix()*int64(time.Second/time.Microsecond) + int64(t.Nanosecond())/int64(time.Microsecond), nil
+}
+
+// parseUnscaledDecimal parses the decimal string like "-12.30" to the unscaled
+// integer with the given scale, e.g. -1230 when scale is 2.
+func parseUnscaledDecimal(raw []byte, scale int) (*big.Int, error) {
+	s := string(raw)
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot+1:]
+	}
+	if len(fracPart) > scale {
+		return nil, errors.Errorf("decimal '%s' has more than %d fractional digits", s, scale)
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid decimal '%s'", s)
+	}
+	return unscaled, nil
+}
+
+// bigIntToTwosComplement encodes the integer in the big-endian two's
+// complement representation with the minimum number of bytes.
+func bigIntToTwosComplement(v *big.Int) []byte {
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b
+	}
+	// -v-1 is non-negative, and its bitwise complement is v.
+	b := new(big.Int).Sub(new(big.Int).Neg(v), big.NewInt(1)).Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	for i := range b {
+		b[i] = ^b[i]
+	}
+	return b
+}
+
+// parquetCompressionCodec returns the parquet compression codec of the
+// compress type. The parquet file compresses the pages by itself, so the
+// file is never compressed as a whole.
+func parquetCompressionCodec(compressType storage.CompressType) parquet.CompressionCodec {
+	switch compressType {
+	case storage.Gzip:
+		return parquet.CompressionCodec_GZIP
+	case storage.Snappy:
+		return parquet.CompressionCodec_SNAPPY
+	case storage.Zstd:
+		return parquet.CompressionCodec_ZSTD
+	default:
+		return parquet.CompressionCodec_UNCOMPRESSED
+	}
+}
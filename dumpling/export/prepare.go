@@ -18,6 +18,8 @@ const (
 	outputFileTemplateTable  = "table"
 	outputFileTemplateView   = "view"
 	outputFileTemplateData   = "data"
+	// outputFileTemplateDeleted is the file of the rows deleted since the previous dump in incremental dumps
+	outputFileTemplateDeleted = "deleted"
 
 	defaultOutputFileTemplateBase = `
 		{{- define "objectName" -}}
@@ -50,6 +52,9 @@ const (
 		{{- define "data" -}}
 			{{template "objectName" .}}.{{.Index}}
 		{{- end -}}
+		{{- define "deleted" -}}
+			{{template "objectName" .}}-deleted
+		{{- end -}}
 	`
 
 	// DefaultAnonymousOutputFileTemplateText is the default anonymous output file templateText for dumpling's table data file name
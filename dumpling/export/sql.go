@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 
@@ -292,7 +293,7 @@ func SelectVersion(db *sql.DB) (string, error) {
 func SelectAllFromTable(conf *Config, meta TableMeta, partition, orderByClause string) TableDataIR {
 	database, table := meta.DatabaseName(), meta.TableName()
 	selectedField, selectLen := meta.SelectedField(), meta.SelectedLen()
-	query := buildSelectQuery(database, table, selectedField, partition, buildWhereCondition(conf, ""), orderByClause)
+	query := buildSelectQuery(database, table, selectedField, partition, buildWhereCondition(conf, buildIncrementalWhere(meta, "")), orderByClause)
 
 	return &tableData{
 		query:  query,
@@ -1070,6 +1071,208 @@ func parseSnapshotToTSO(pool *sql.DB, snapshot string) (uint64, error) {
 	return (uint64(tso.Int64) << 18) * 1000, nil
 }
 
+// incrementalSafeMargin is subtracted from the physical time of the previous
+// snapshot, because the update timestamp of a row is set when the statement is
+// executed, while the row becomes visible when the transaction commits. It's
+// the default max-txn-ttl of TiDB.
+const incrementalSafeMargin = time.Hour
+
+// selectUpdateTimestampField returns the TIMESTAMP or DATETIME column which is
+// updated to the current timestamp automatically and whether it's a TIMESTAMP
+// column, or "" if there isn't one.
+func selectUpdateTimestampField(db *sql.Conn, dbName, tableName string) (string, bool, error) {
+	query := fmt.Sprintf("SHOW COLUMNS FROM `%s`.`%s`", escapeString(dbName), escapeString(tableName))
+	rows, err := db.QueryContext(context.Background(), query)
+	if err != nil {
+		return "", false, errors.Annotatef(err, "sql: %s", query)
+	}
+	results, err := GetSpecifiedColumnValuesAndClose(rows, "FIELD", "TYPE", "EXTRA")
+	if err != nil {
+		return "", false, errors.Annotatef(err, "sql: %s", query)
+	}
+	for _, oneRow := range results {
+		fieldName, tp, extra := oneRow[0], strings.ToLower(oneRow[1]), strings.ToLower(oneRow[2])
+		isTimestamp := strings.HasPrefix(tp, "timestamp")
+		if (isTimestamp || strings.HasPrefix(tp, "datetime")) &&
+			strings.Contains(extra, "on update current_timestamp") {
+			return fieldName, isTimestamp, nil
+		}
+	}
+	return "", false, nil
+}
+
+// detectServerTimeZone returns the global time zone of the server, which the
+// current timestamp is converted to when it's stored in a DATETIME column.
+func detectServerTimeZone(db *sql.DB) (*time.Location, error) {
+	const query = "SELECT @@global.time_zone, @@system_time_zone"
+	var tz, systemTZ string
+	if err := db.QueryRow(query).Scan(&tz, &systemTZ); err != nil {
+		return nil, errors.Annotatef(err, "sql: %s", query)
+	}
+	return parseTimeZone(tz, systemTZ)
+}
+
+// parseTimeZone parses the value of the time_zone variable, which is either an
+// offset like +08:00 or the name of a time zone. SYSTEM stands for systemTZ.
+func parseTimeZone(tz, systemTZ string) (*time.Location, error) {
+	if strings.EqualFold(tz, "SYSTEM") {
+		tz = systemTZ
+	}
+	if strings.HasPrefix(tz, "+") || strings.HasPrefix(tz, "-") {
+		parts := strings.Split(tz[1:], ":")
+		if len(parts) == 2 {
+			hour, err1 := strconv.Atoi(parts[0])
+			minute, err2 := strconv.Atoi(parts[1])
+			if err1 == nil && err2 == nil {
+				offset := hour*3600 + minute*60
+				if tz[0] == '-' {
+					offset = -offset
+				}
+				return time.FixedZone(tz, offset), nil
+			}
+		}
+		return nil, errors.Errorf("invalid time zone '%s'", tz)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid time zone '%s'", tz)
+	}
+	return loc, nil
+}
+
+// selectUnsignedFields returns the set of the unsigned integer columns.
+func selectUnsignedFields(db *sql.Conn, dbName, tableName string) (map[string]struct{}, error) {
+	query := fmt.Sprintf("SHOW COLUMNS FROM `%s`.`%s`", escapeString(dbName), escapeString(tableName))
+	rows, err := db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, errors.Annotatef(err, "sql: %s", query)
+	}
+	results, err := GetSpecifiedColumnValuesAndClose(rows, "FIELD", "TYPE")
+	if err != nil {
+		return nil, errors.Annotatef(err, "sql: %s", query)
+	}
+	fields := make(map[string]struct{})
+	for _, oneRow := range results {
+		fieldName, tp := oneRow[0], strings.ToLower(oneRow[1])
+		if strings.Contains(tp, "int") && strings.Contains(tp, "unsigned") {
+			fields[fieldName] = struct{}{}
+		}
+	}
+	return fields, nil
+}
+
+// buildIncrementalCondition builds the condition of the rows updated since the
+// snapshot ts, by the update timestamp field whose values are in loc. The
+// session time zone of the dump is UTC, so loc is UTC for a TIMESTAMP field.
+func buildIncrementalCondition(field string, ts uint64, loc *time.Location) string {
+	physical := time.Duration(ts>>18)*time.Millisecond - incrementalSafeMargin
+	if physical < 0 {
+		physical = 0
+	}
+	since := time.Unix(0, int64(physical)).In(loc)
+	return fmt.Sprintf("`%s` >= '%s'", escapeString(field), since.Format("2006-01-02 15:04:05.000"))
+}
+
+// selectIncrementalKeys reads the keys of the table by the key columns in
+// selectedField. It returns nil rows if the table doesn't exist, which means
+// the table is created after the previous dump when reading its snapshot.
+func selectIncrementalKeys(ctx context.Context, conn *sql.Conn, dbName, tableName, selectedField string) (*sql.Rows, error) {
+	query := fmt.Sprintf("SELECT %s FROM `%s`.`%s` ORDER BY %s", selectedField, escapeString(dbName), escapeString(tableName), selectedField)
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		if mysqlErr, ok := errors.Cause(err).(*mysql.MySQLError); ok && mysqlErr.Number == ErrNoSuchTable {
+			return nil, nil
+		}
+		return nil, errors.Annotatef(err, "sql: %s", query)
+	}
+	return rows, nil
+}
+
+// scanIncrementalKey scans the key of the current row.
+func scanIncrementalKey(rows *sql.Rows, keyLen int) ([][]byte, error) {
+	key := make([][]byte, keyLen)
+	dest := make([]interface{}, keyLen)
+	for i := range key {
+		dest[i] = &key[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return key, nil
+}
+
+// incrementalKeyString encodes the key to a string that can be compared.
+func incrementalKeyString(key [][]byte) string {
+	var sb strings.Builder
+	for _, v := range key {
+		sb.WriteString(strconv.Itoa(len(v)))
+		sb.WriteByte(':')
+		sb.Write(v)
+	}
+	return sb.String()
+}
+
+// selectDeletedKeys returns the keys that no row of the table has. The values
+// of the keys are compared in binary.
+func selectDeletedKeys(ctx context.Context, conn *sql.Conn, dbName, tableName, selectedField string, keys [][][]byte) ([][][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?,", len(keys[0])), ",") + ")"
+	var query strings.Builder
+	args := make([]interface{}, 0, len(keys)*len(keys[0]))
+	fmt.Fprintf(&query, "SELECT %s FROM `%s`.`%s` WHERE (%s) IN (", selectedField, escapeString(dbName), escapeString(tableName), selectedField)
+	for i, key := range keys {
+		if i > 0 {
+			query.WriteByte(',')
+		}
+		query.WriteString(placeholder)
+		for _, v := range key {
+			args = append(args, v)
+		}
+	}
+	query.WriteByte(')')
+
+	rows, err := conn.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, errors.Annotatef(err, "sql: %s", query.String())
+	}
+	defer rows.Close()
+	existing := make(map[string]struct{}, len(keys))
+	for rows.Next() {
+		key, err := scanIncrementalKey(rows, len(keys[0]))
+		if err != nil {
+			return nil, err
+		}
+		existing[incrementalKeyString(key)] = struct{}{}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Annotatef(err, "sql: %s", query.String())
+	}
+
+	var deleted [][][]byte
+	for _, key := range keys {
+		if _, ok := existing[incrementalKeyString(key)]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted, nil
+}
+
+// buildIncrementalWhere combines the incremental condition of the table with
+// the where condition of the chunk.
+func buildIncrementalWhere(meta TableMeta, where string) string {
+	cond := meta.IncrementalCondition()
+	switch {
+	case cond == "":
+		return where
+	case where == "":
+		return cond
+	default:
+		return fmt.Sprintf("(%s) AND (%s)", cond, where)
+	}
+}
+
 func buildWhereCondition(conf *Config, where string) string {
 	var query strings.Builder
 	separator := "WHERE"
@@ -15,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -865,6 +866,119 @@ func TestBuildWhereCondition(t *testing.T) {
 	}
 }
 
+func TestSelectUpdateTimestampField(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SHOW COLUMNS FROM `test`.`t`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int(11)", "NO", "PRI", nil, "").
+			AddRow("created", "timestamp", "YES", "", "CURRENT_TIMESTAMP", "DEFAULT_GENERATED").
+			AddRow("name", "varchar(12)", "NO", "", nil, "").
+			AddRow("up`dated", "datetime(3)", "YES", "", "CURRENT_TIMESTAMP(3)", "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(3)"))
+	field, isTimestamp, err := selectUpdateTimestampField(conn, "test", "t")
+	require.NoError(t, err)
+	require.Equal(t, "up`dated", field)
+	require.False(t, isTimestamp)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery("SHOW COLUMNS FROM `test`.`t`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int(11)", "NO", "PRI", nil, "").
+			AddRow("updated", "timestamp", "NO", "", "CURRENT_TIMESTAMP", "on update CURRENT_TIMESTAMP"))
+	field, isTimestamp, err = selectUpdateTimestampField(conn, "test", "t")
+	require.NoError(t, err)
+	require.Equal(t, "updated", field)
+	require.True(t, isTimestamp)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery("SHOW COLUMNS FROM `test`.`t`").
+		WillReturnRows(sqlmock.NewRows([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}).
+			AddRow("id", "int(11)", "NO", "PRI", nil, "").
+			AddRow("created", "timestamp", "YES", "", "CURRENT_TIMESTAMP", ""))
+	field, _, err = selectUpdateTimestampField(conn, "test", "t")
+	require.NoError(t, err)
+	require.Equal(t, "", field)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParseTimeZone(t *testing.T) {
+	t.Parallel()
+
+	// the physical time of the ts of TestBuildIncrementalWhere
+	ts := time.Date(2021, 10, 25, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		tz       string
+		systemTZ string
+		expected string
+	}{
+		{"+00:00", "UTC", "2021-10-25 10:00:00"},
+		{"-05:00", "UTC", "2021-10-25 05:00:00"},
+		{"+05:30", "UTC", "2021-10-25 15:30:00"},
+		{"Asia/Shanghai", "UTC", "2021-10-25 18:00:00"},
+		{"SYSTEM", "America/New_York", "2021-10-25 06:00:00"},
+		{"system", "UTC", "2021-10-25 10:00:00"},
+	}
+	for _, ca := range cases {
+		loc, err := parseTimeZone(ca.tz, ca.systemTZ)
+		require.NoError(t, err, ca.tz)
+		require.Equal(t, ca.expected, ts.In(loc).Format("2006-01-02 15:04:05"), ca.tz)
+	}
+
+	_, err := parseTimeZone("+8", "UTC")
+	require.EqualError(t, err, "invalid time zone '+8'")
+	_, err = parseTimeZone("SYSTEM", "Not/Exist")
+	require.Error(t, err)
+}
+
+func TestDetectServerTimeZone(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	mock.ExpectQuery("SELECT @@global.time_zone, @@system_time_zone").
+		WillReturnRows(sqlmock.NewRows([]string{"@@global.time_zone", "@@system_time_zone"}).AddRow("SYSTEM", "Asia/Shanghai"))
+	loc, err := detectServerTimeZone(db)
+	require.NoError(t, err)
+	require.Equal(t, "Asia/Shanghai", loc.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBuildIncrementalWhere(t *testing.T) {
+	t.Parallel()
+
+	// the physical time of the ts is 2021-10-25 10:00:00.123 UTC, an hour is subtracted for the long transactions
+	cond := buildIncrementalCondition("up`dated", 428646334496243717, time.UTC)
+	require.Equal(t, "`up``dated` >= '2021-10-25 09:00:00.123'", cond)
+	require.Equal(t, "`t` >= '1970-01-01 00:00:00.000'", buildIncrementalCondition("t", 1, time.UTC))
+	// the DATETIME values are in the time zone of the server
+	require.Equal(t, "`t` >= '2021-10-25 04:00:00.123'",
+		buildIncrementalCondition("t", 428646334496243717, time.FixedZone("-05:00", -5*3600)))
+
+	meta := &tableMeta{incrementalCondition: cond}
+	require.Equal(t, cond, buildIncrementalWhere(meta, ""))
+	require.Equal(t, "(`up``dated` >= '2021-10-25 09:00:00.123') AND (`id` IS NULL OR (`id` >= 1 AND `id` < 10))",
+		buildIncrementalWhere(meta, "`id` IS NULL OR (`id` >= 1 AND `id` < 10)"))
+	require.Equal(t, "`id` >= 1", buildIncrementalWhere(&tableMeta{}, "`id` >= 1"))
+
+	conf := DefaultConfig()
+	conf.Where = "a > 1"
+	require.Equal(t, "WHERE (a > 1) AND (`up``dated` >= '2021-10-25 09:00:00.123') ",
+		buildWhereCondition(conf, buildIncrementalWhere(meta, "")))
+}
+
 func TestBuildRegionQueriesWithoutPartition(t *testing.T) {
 	t.Parallel()
 
@@ -224,6 +224,20 @@ func (r RowReceiverArr) WriteToBufferInCsv(bf *bytes.Buffer, escapeBackslash boo
 	}
 }
 
+// rawBytes returns the raw bytes of the i-th column, which is nil for NULL
+func (r RowReceiverArr) rawBytes(i int) sql.RawBytes {
+	switch receiver := r.receivers[i].(type) {
+	case *SQLTypeNumber:
+		return receiver.RawBytes
+	case *SQLTypeString:
+		return receiver.RawBytes
+	case *SQLTypeBytes:
+		return receiver.RawBytes
+	default:
+		return nil
+	}
+}
+
 // SQLTypeNumber implements RowReceiverStringer which represents numeric type columns in database
 type SQLTypeNumber struct {
 	SQLTypeString
@@ -66,6 +66,9 @@ type mockTableIR struct {
 	specCmt          []string
 	colTypes         []string
 	colNames         []string
+	sqlColTypes      []*sql.ColumnType
+	unsignedCols     map[string]struct{}
+	incrementalKeys  []string
 	escapeBackSlash  bool
 	hasImplicitRowID bool
 	rowErr           error
@@ -93,6 +96,19 @@ func (m *mockTableIR) HasImplicitRowID() bool {
 	return m.hasImplicitRowID
 }
 
+func (m *mockTableIR) IncrementalCondition() string {
+	return ""
+}
+
+func (m *mockTableIR) IncrementalKeys() []string {
+	return m.incrementalKeys
+}
+
+func (m *mockTableIR) IsUnsignedColumn(name string) bool {
+	_, ok := m.unsignedCols[name]
+	return ok
+}
+
 func (m *mockTableIR) Start(_ *tcontext.Context, conn *sql.Conn) error {
 	return nil
 }
@@ -117,6 +133,10 @@ func (m *mockTableIR) ColumnTypes() []string {
 	return m.colTypes
 }
 
+func (m *mockTableIR) SQLColumnTypes() []*sql.ColumnType {
+	return m.sqlColTypes
+}
+
 func (m *mockTableIR) ColumnNames() []string {
 	return m.colNames
 }
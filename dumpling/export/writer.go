@@ -49,6 +49,8 @@ func NewWriter(tctx *tcontext.Context, id int64, config *Config, conn *sql.Conn,
 		sw.fileFmt = FileFormatSQLText
 	case FileFormatCSVString:
 		sw.fileFmt = FileFormatCSV
+	case FileFormatParquetString:
+		sw.fileFmt = FileFormatParquet
 	}
 	return sw
 }
@@ -199,7 +201,7 @@ func (w *Writer) tryToWriteTableData(tctx *tcontext.Context, meta TableMeta, ir
 
 	somethingIsWritten := false
 	for {
-		fileWriter, tearDown := buildInterceptFileWriter(tctx, w.extStorage, fileName, conf.CompressType)
+		fileWriter, tearDown := buildInterceptFileWriter(tctx, w.extStorage, fileName, format.CompressType(conf.CompressType))
 		n, err := format.WriteInsert(tctx, conf, meta, ir, fileWriter)
 		tearDown(tctx)
 		if err != nil {
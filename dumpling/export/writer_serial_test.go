@@ -3,12 +3,19 @@
 package export
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/parquet"
+	preader "github.com/xitongsys/parquet-go/reader"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/br/pkg/storage"
@@ -68,6 +75,103 @@ func TestWriteInsert(t *testing.T) {
 	require.Equal(t, ReadGauge(finishedSizeGauge, conf.Labels), float64(len(expected)))
 }
 
+func TestWriteInsertIncremental(t *testing.T) {
+	cfg, clean := createMockConfig(t)
+	defer clean()
+
+	data := [][]driver.Value{
+		{"1", "male", "bob@mail.com", "020-1234", nil},
+		{"2", "female", "sarah@mail.com", "020-1253", "healthy"},
+	}
+	colTypes := []string{"INT", "SET", "VARCHAR", "VARCHAR", "TEXT"}
+	specCmts := []string{
+		"/*!40101 SET NAMES binary*/;",
+	}
+	tableIR := newMockTableIR("test", "employee", data, specCmts, colTypes)
+	bf := storage.NewBufferWriter()
+
+	conf := configForWriteSQL(cfg, UnspecifiedSize, UnspecifiedSize)
+	conf.IncrementalBaseTS = 428646334496243717
+	n, err := WriteInsert(tcontext.Background(), conf, tableIR, tableIR, bf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), n)
+
+	// the rows are upserted by REPLACE INTO
+	expected := "/*!40101 SET NAMES binary*/;\n" +
+		"REPLACE INTO `employee` VALUES\n" +
+		"(1,'male','bob@mail.com','020-1234',NULL),\n" +
+		"(2,'female','sarah@mail.com','020-1253','healthy');\n"
+	require.Equal(t, expected, bf.String())
+	require.Equal(t, ReadGauge(finishedRowsGauge, conf.Labels), float64(len(data)))
+	require.Equal(t, ReadGauge(finishedSizeGauge, conf.Labels), float64(len(expected)))
+}
+
+func TestWriteDeletedRows(t *testing.T) {
+	cfg, clean := createMockConfig(t)
+	defer clean()
+
+	baseDB, baseMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer baseDB.Close()
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	tctx := tcontext.Background().WithLogger(appLogger)
+	baseConn, err := baseDB.Conn(tctx)
+	require.NoError(t, err)
+	conn, err := db.Conn(tctx)
+	require.NoError(t, err)
+
+	specCmts := []string{
+		"/*!40101 SET NAMES binary*/;",
+		"/*!40103 SET TIME_ZONE='+00:00' */;",
+	}
+	tableIR := newMockTableIR("test", "employee", nil, specCmts, []string{"INT", "VARCHAR"})
+	tableIR.incrementalKeys = []string{"id", "name"}
+
+	// the rows 2 and 3 exist in the snapshot of the previous dump but not in the current snapshot
+	baseMock.ExpectQuery("SELECT `id`,`name` FROM `test`.`employee` ORDER BY `id`,`name`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c'd"))
+	mock.ExpectQuery("SELECT `id`,`name` FROM `test`.`employee` WHERE \\(`id`,`name`\\) IN \\(\\(\\?,\\?\\),\\(\\?,\\?\\),\\(\\?,\\?\\)\\)").
+		WithArgs([]byte("1"), []byte("a"), []byte("2"), []byte("b"), []byte("3"), []byte("c'd")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	bf := storage.NewBufferWriter()
+	conf := configForWriteSQL(cfg, UnspecifiedSize, UnspecifiedSize)
+	conf.IncrementalBaseTS = 428646334496243717
+	n, err := writeDeletedRows(tctx, conf, tableIR, baseConn, conn, bf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), n)
+	expected := "/*!40101 SET NAMES binary*/;\n" +
+		"/*!40103 SET TIME_ZONE='+00:00' */;\n" +
+		"DELETE FROM `employee` WHERE (`id`,`name`) IN (\n" +
+		"('2','b'),\n" +
+		"('3','c''d'));\n"
+	require.Equal(t, expected, bf.String())
+	require.NoError(t, baseMock.ExpectationsWereMet())
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// nothing is written if no row is deleted
+	bf.Reset()
+	baseMock.ExpectQuery("SELECT `id`,`name` FROM `test`.`employee` ORDER BY `id`,`name`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	mock.ExpectQuery("SELECT `id`,`name` FROM `test`.`employee` WHERE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	n, err = writeDeletedRows(tctx, conf, tableIR, baseConn, conn, bf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), n)
+	require.Equal(t, "", bf.String())
+
+	// the table is created after the previous dump
+	baseMock.ExpectQuery("SELECT `id`,`name` FROM `test`.`employee` ORDER BY `id`,`name`").
+		WillReturnError(&mysql.MySQLError{Number: ErrNoSuchTable, Message: "Table 'test.employee' doesn't exist"})
+	n, err = writeDeletedRows(tctx, conf, tableIR, baseConn, conn, bf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), n)
+	require.Equal(t, "", bf.String())
+	require.NoError(t, baseMock.ExpectationsWereMet())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestWriteInsertReturnsError(t *testing.T) {
 	cfg, clean := createMockConfig(t)
 	defer clean()
@@ -233,6 +337,111 @@ func TestWriteInsertInCsvReturnsError(t *testing.T) {
 	RemoveLabelValuesWithTaskInMetrics(conf.Labels)
 }
 
+func TestWriteInsertInParquet(t *testing.T) {
+	cfg, clean := createMockConfig(t)
+	defer clean()
+
+	data := [][]driver.Value{
+		{"1", "18446744073709551615", "-12.30", "-123456789012345678901234567.891", "2021-01-02 03:04:05.123456", "2021-01-02", `{"a": 1}`, "male", "bob", []byte{0, 0xff}, "1.5"},
+		{"2", "0", "0.05", "0.000", "1969-12-31 23:59:59", "1969-12-31", "[]", "female", "", []byte{}, "-1e10"},
+		{"3", "3", nil, nil, nil, nil, nil, nil, nil, nil, nil},
+	}
+	colTypes := []string{"INT", "BIGINT", "DECIMAL", "DECIMAL", "DATETIME", "DATE", "JSON", "ENUM", "VARCHAR", "BLOB", "DOUBLE"}
+	tableIR := newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "u", "d1", "d2", "dt", "d", "j", "gender", "name", "b", "f"}
+	tableIR.unsignedCols = map[string]struct{}{"u": {}}
+	tableIR.sqlColTypes = mockSQLColumnTypes(t,
+		sqlmock.NewColumn("id").OfType("INT", int32(0)),
+		sqlmock.NewColumn("u").OfType("BIGINT", uint64(0)),
+		sqlmock.NewColumn("d1").OfType("DECIMAL", "").WithPrecisionAndScale(10, 2),
+		sqlmock.NewColumn("d2").OfType("DECIMAL", "").WithPrecisionAndScale(30, 3),
+	)
+	bf := storage.NewBufferWriter()
+
+	conf := cloneConfigForTest(cfg)
+	conf.CompressType = storage.Zstd
+	n, err := WriteInsertInParquet(tcontext.Background(), conf, tableIR, tableIR, bf)
+	require.Equal(t, uint64(3), n)
+	require.NoError(t, err)
+	require.Equal(t, float64(len(data)), ReadGauge(finishedRowsGauge, conf.Labels))
+	require.Equal(t, float64(len(bf.Bytes())), ReadGauge(finishedSizeGauge, conf.Labels))
+
+	file, err := buffer.NewBufferFile(bf.Bytes())
+	require.NoError(t, err)
+	reader, err := preader.NewParquetReader(file, nil, 1)
+	require.NoError(t, err)
+	defer reader.ReadStop()
+	require.Equal(t, int64(3), reader.GetNumRows())
+
+	schema := reader.Footer.Schema[1:]
+	require.Len(t, schema, len(colTypes))
+	for i, name := range tableIR.colNames {
+		// the reader replaces the names in the footer with the Go style ones
+		require.Equal(t, name, reader.SchemaHandler.Infos[i+1].ExName)
+		require.Equal(t, parquet.FieldRepetitionType_OPTIONAL, schema[i].GetRepetitionType())
+	}
+	require.Equal(t, parquet.ConvertedType_UINT_64, schema[1].GetConvertedType())
+	require.Equal(t, &parquet.DecimalType{Scale: 2, Precision: 10}, schema[2].LogicalType.DECIMAL)
+	require.Equal(t, parquet.Type_INT64, schema[2].GetType())
+	require.Equal(t, &parquet.DecimalType{Scale: 3, Precision: 30}, schema[3].LogicalType.DECIMAL)
+	require.Equal(t, parquet.Type_BYTE_ARRAY, schema[3].GetType())
+	require.False(t, schema[4].LogicalType.TIMESTAMP.IsAdjustedToUTC)
+	require.NotNil(t, schema[5].LogicalType.DATE)
+	require.NotNil(t, schema[6].LogicalType.JSON)
+	require.NotNil(t, schema[7].LogicalType.ENUM)
+	require.NotNil(t, schema[8].LogicalType.STRING)
+	require.Nil(t, schema[9].LogicalType)
+	require.Equal(t, parquet.Type_DOUBLE, schema[10].GetType())
+
+	rows, err := reader.ReadByNumber(3)
+	require.NoError(t, err)
+	expected := [][]interface{}{
+		{
+			int64(1), uint64(18446744073709551615), int64(-1230), string([]byte{0xfe, 0x71, 0x16, 0xf0, 0x09, 0x3c, 0x8c, 0x1f, 0x11, 0xb1, 0xc0, 0xf5, 0x2d}),
+			int64(1609556645123456), int32(18629), `{"a": 1}`, "male", "bob", "\x00\xff", 1.5,
+		},
+		{int64(2), uint64(0), int64(5), "\x00", int64(-1000000), int32(-1), "[]", "female", "", "", -1e10},
+		{int64(3), uint64(3), nil, nil, nil, nil, nil, nil, nil, nil, nil},
+	}
+	for i, row := range rows {
+		v := reflect.ValueOf(row)
+		values := make([]interface{}, v.NumField())
+		for j := range values {
+			if f := v.Field(j); !f.IsNil() {
+				values[j] = f.Elem().Interface()
+			}
+		}
+		require.Equal(t, expected[i], values, "row %d", i)
+	}
+
+	RemoveLabelValuesWithTaskInMetrics(conf.Labels)
+
+	// test file size
+	bf.Reset()
+	tableIR = newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "u", "d1", "d2", "dt", "d", "j", "gender", "name", "b", "f"}
+	tableIR.unsignedCols = map[string]struct{}{"u": {}}
+	conf.FileSize = 1
+	n, err = WriteInsertInParquet(tcontext.Background(), conf, tableIR, tableIR, bf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), n)
+	require.Equal(t, float64(1), ReadGauge(finishedRowsGauge, conf.Labels))
+
+	RemoveLabelValuesWithTaskInMetrics(conf.Labels)
+
+	// test invalid value
+	bf.Reset()
+	data = [][]driver.Value{{"1", "1", "1.00", "1.000", "0000-00-00 00:00:00", nil, nil, nil, nil, nil, nil}}
+	tableIR = newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "u", "d1", "d2", "dt", "d", "j", "gender", "name", "b", "f"}
+	tableIR.unsignedCols = map[string]struct{}{"u": {}}
+	n, err = WriteInsertInParquet(tcontext.Background(), conf, tableIR, tableIR, bf)
+	require.Equal(t, uint64(0), n)
+	require.Regexp(t, "column `dt`: datetime '0000-00-00 00:00:00' can't be dumped as parquet TIMESTAMP.*", err.Error())
+
+	RemoveLabelValuesWithTaskInMetrics(conf.Labels)
+}
+
 func TestSQLDataTypes(t *testing.T) {
 	cfg, clean := createMockConfig(t)
 	defer clean()
@@ -307,6 +516,69 @@ func configForWriteCSV(config *Config, noHeader bool, opt *csvOption) *Config {
 	return cfg
 }
 
+func TestWriteInsertInParquetNullableUnsigned(t *testing.T) {
+	cfg, clean := createMockConfig(t)
+	defer clean()
+
+	data := [][]driver.Value{
+		{"1", "18446744073709551615"},
+		{"2", nil},
+	}
+	colTypes := []string{"INT", "BIGINT"}
+	tableIR := newMockTableIR("test", "employee", data, nil, colTypes)
+	tableIR.colNames = []string{"id", "u"}
+	// the driver scans the nullable unsigned BIGINT as sql.NullInt64
+	tableIR.sqlColTypes = mockSQLColumnTypes(t,
+		sqlmock.NewColumn("id").OfType("INT", int32(0)),
+		sqlmock.NewColumn("u").OfType("BIGINT", sql.NullInt64{}).Nullable(true),
+	)
+	tableIR.unsignedCols = map[string]struct{}{"u": {}}
+	bf := storage.NewBufferWriter()
+
+	conf := cloneConfigForTest(cfg)
+	n, err := WriteInsertInParquet(tcontext.Background(), conf, tableIR, tableIR, bf)
+	require.Equal(t, uint64(2), n)
+	require.NoError(t, err)
+
+	file, err := buffer.NewBufferFile(bf.Bytes())
+	require.NoError(t, err)
+	reader, err := preader.NewParquetReader(file, nil, 1)
+	require.NoError(t, err)
+	defer reader.ReadStop()
+
+	schema := reader.Footer.Schema[1:]
+	require.Equal(t, parquet.ConvertedType_UINT_64, schema[1].GetConvertedType())
+	require.Equal(t, parquet.FieldRepetitionType_OPTIONAL, schema[1].GetRepetitionType())
+
+	rows, err := reader.ReadByNumber(2)
+	require.NoError(t, err)
+	expected := []interface{}{uint64(18446744073709551615), nil}
+	for i, row := range rows {
+		f := reflect.ValueOf(row).Field(1)
+		var value interface{}
+		if !f.IsNil() {
+			value = f.Elem().Interface()
+		}
+		require.Equal(t, expected[i], value, "row %d", i)
+	}
+
+	RemoveLabelValuesWithTaskInMetrics(conf.Labels)
+}
+
+func mockSQLColumnTypes(t *testing.T, columns ...*sqlmock.Column) []*sql.ColumnType {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(columns...))
+	rows, err := db.Query("SELECT")
+	require.NoError(t, err)
+	defer rows.Close()
+	colTypes, err := rows.ColumnTypes()
+	require.NoError(t, err)
+	return colTypes
+}
+
 func createMockConfig(t *testing.T) (cfg *Config, clean func()) {
 	cfg = &Config{
 		FileSize: UnspecifiedSize,
@@ -5,6 +5,7 @@ package export
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"path"
@@ -19,6 +20,8 @@ import (
 	tcontext "github.com/pingcap/tidb/dumpling/context"
 	"github.com/pingcap/tidb/dumpling/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xitongsys/parquet-go/marshal"
+	pwriter "github.com/xitongsys/parquet-go/writer"
 	"go.uber.org/zap"
 )
 
@@ -197,13 +200,18 @@ func WriteInsert(pCtx *tcontext.Context, cfg *Config, meta TableMeta, tblIR Tabl
 	}()
 
 	selectedField := meta.SelectedField()
+	// the rows of an incremental dump may already exist, so they are upserted
+	insertStatement := "INSERT INTO"
+	if cfg.IncrementalBaseTS != 0 {
+		insertStatement = "REPLACE INTO"
+	}
 
 	// if has generated column
 	if selectedField != "" && selectedField != "*" {
-		insertStatementPrefix = fmt.Sprintf("INSERT INTO %s (%s) VALUES\n",
+		insertStatementPrefix = fmt.Sprintf("%s %s (%s) VALUES\n", insertStatement,
 			wrapBackTicks(escapeString(meta.TableName())), selectedField)
 	} else {
-		insertStatementPrefix = fmt.Sprintf("INSERT INTO %s VALUES\n",
+		insertStatementPrefix = fmt.Sprintf("%s %s VALUES\n", insertStatement,
 			wrapBackTicks(escapeString(meta.TableName())))
 	}
 	insertStatementPrefixLen := uint64(len(insertStatementPrefix))
@@ -394,6 +402,215 @@ func WriteInsertInCsv(pCtx *tcontext.Context, cfg *Config, meta TableMeta, tblIR
 	return counter, wp.Error()
 }
 
+// parquetFileBuffer is the output of the parquet writer. Its buffer is sent to
+// the writerPipe once it's large enough.
+type parquetFileBuffer struct {
+	*bytes.Buffer
+}
+
+// WriteInsertInParquet writes TableDataIR to a storage.ExternalFileWriter in parquet type.
+// The file size is estimated by the size of the text values, because the parquet
+// writer buffers a whole row group before writing it out.
+func WriteInsertInParquet(pCtx *tcontext.Context, cfg *Config, meta TableMeta, tblIR TableDataIR, w storage.ExternalFileWriter) (n uint64, err error) {
+	fileRowIter := tblIR.Rows()
+	if !fileRowIter.HasNext() {
+		return 0, fileRowIter.Error()
+	}
+	if meta.SelectedField() == "" {
+		return 0, errors.Errorf("can't dump table %s.%s in parquet type because all of its columns are generated columns",
+			wrapBackTicks(escapeString(meta.DatabaseName())), wrapBackTicks(escapeString(meta.TableName())))
+	}
+
+	out := &parquetFileBuffer{Buffer: pool.Get().(*bytes.Buffer)}
+	if bfCap := out.Cap(); bfCap < lengthLimit {
+		out.Grow(lengthLimit - bfCap)
+	}
+	schema, columns := newParquetSchema(meta)
+	pw, err := pwriter.NewParquetWriterFromWriter(out, schema, 1)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	pw.MarshalFunc = marshal.MarshalCSV
+	pw.CompressionType = parquetCompressionCodec(cfg.CompressType)
+
+	wp := newWriterPipe(w, cfg.FileSize, UnspecifiedSize, cfg.Labels)
+
+	// use context.Background here to make sure writerPipe can deplete all the chunks in pipeline
+	ctx, cancel := tcontext.Background().WithLogger(pCtx.L()).WithCancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		wp.Run(ctx)
+		wg.Done()
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	var (
+		row         = MakeRowReceiver(meta.ColumnTypes())
+		counter     uint64
+		lastCounter uint64
+	)
+
+	defer func() {
+		if err != nil {
+			pCtx.L().Warn("fail to dumping table(chunk), will revert some metrics and start a retry if possible",
+				zap.String("database", meta.DatabaseName()),
+				zap.String("table", meta.TableName()),
+				zap.Uint64("finished rows", lastCounter),
+				zap.Uint64("finished size", wp.finishedFileSize),
+				log.ShortError(err))
+			SubGauge(finishedRowsGauge, cfg.Labels, float64(lastCounter))
+			SubGauge(finishedSizeGauge, cfg.Labels, float64(wp.finishedFileSize))
+		} else {
+			pCtx.L().Debug("finish dumping table(chunk)",
+				zap.String("database", meta.DatabaseName()),
+				zap.String("table", meta.TableName()),
+				zap.Uint64("finished rows", counter),
+				zap.Uint64("finished size", wp.finishedFileSize))
+			summary.CollectSuccessUnit(summary.TotalBytes, 1, wp.finishedFileSize)
+			summary.CollectSuccessUnit("total rows", 1, counter)
+		}
+	}()
+
+	for fileRowIter.HasNext() {
+		if err = fileRowIter.Decode(row); err != nil {
+			return counter, errors.Trace(err)
+		}
+		record := make([]interface{}, len(columns))
+		for i, column := range columns {
+			raw := row.rawBytes(i)
+			if raw == nil {
+				continue
+			}
+			if record[i], err = column.convert(raw); err != nil {
+				return counter, errors.Annotatef(err, "column %s", wrapBackTicks(escapeString(column.schema.Name)))
+			}
+			wp.currentFileSize += uint64(len(raw))
+		}
+		if err = pw.Write(record); err != nil {
+			return counter, errors.Trace(err)
+		}
+		counter++
+
+		if out.Len() >= lengthLimit {
+			select {
+			case <-pCtx.Done():
+				return counter, pCtx.Err()
+			case err = <-wp.errCh:
+				return counter, err
+			case wp.input <- out.Buffer:
+				out.Buffer = pool.Get().(*bytes.Buffer)
+				if bfCap := out.Cap(); bfCap < lengthLimit {
+					out.Grow(lengthLimit - bfCap)
+				}
+				AddGauge(finishedRowsGauge, cfg.Labels, float64(counter-lastCounter))
+				lastCounter = counter
+			}
+		}
+
+		fileRowIter.Next()
+		if wp.ShouldSwitchFile() {
+			break
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		return counter, errors.Trace(err)
+	}
+	wp.input <- out.Buffer
+	close(wp.input)
+	<-wp.closed
+	AddGauge(finishedRowsGauge, cfg.Labels, float64(counter-lastCounter))
+	lastCounter = counter
+	if err = fileRowIter.Error(); err != nil {
+		return counter, errors.Trace(err)
+	}
+	return counter, wp.Error()
+}
+
+// deletedRowsBatchSize is the number of the keys checked by one query when
+// finding the deleted rows.
+const deletedRowsBatchSize = 1024
+
+// writeDeletedRows writes the DELETE statements of the rows which exist in the
+// snapshot of the previous dump but not in the current snapshot. The keys of
+// the previous snapshot are read by baseConn, and checked by conn.
+func writeDeletedRows(pCtx *tcontext.Context, cfg *Config, meta TableMeta, baseConn, conn *sql.Conn, w storage.ExternalFileWriter) (n uint64, err error) {
+	keys := meta.IncrementalKeys()
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = wrapBackTicks(escapeString(key))
+	}
+	selectedField := strings.Join(fields, ",")
+	rows, err := selectIncrementalKeys(pCtx, baseConn, meta.DatabaseName(), meta.TableName(), selectedField)
+	if err != nil || rows == nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	bf := pool.Get().(*bytes.Buffer)
+	defer func() {
+		bf.Reset()
+		pool.Put(bf)
+	}()
+	batch := make([][][]byte, 0, deletedRowsBatchSize)
+	flush := func() error {
+		deleted, err := selectDeletedKeys(pCtx, conn, meta.DatabaseName(), meta.TableName(), selectedField, batch)
+		batch = batch[:0]
+		if err != nil || len(deleted) == 0 {
+			return err
+		}
+		if n == 0 {
+			specCmtIter := meta.SpecialComments()
+			for specCmtIter.HasNext() {
+				bf.WriteString(specCmtIter.Next())
+				bf.WriteByte('\n')
+			}
+		}
+		fmt.Fprintf(bf, "DELETE FROM %s WHERE (%s) IN (\n", wrapBackTicks(escapeString(meta.TableName())), selectedField)
+		for i, key := range deleted {
+			bf.WriteByte('(')
+			for j, v := range key {
+				if j > 0 {
+					bf.WriteByte(',')
+				}
+				bf.WriteByte('\'')
+				escapeSQL(v, bf, cfg.EscapeBackslash)
+				bf.WriteByte('\'')
+			}
+			if i < len(deleted)-1 {
+				bf.WriteString("),\n")
+			} else {
+				bf.WriteString("));\n")
+			}
+		}
+		n += uint64(len(deleted))
+		err = write(pCtx, w, bf.String())
+		bf.Reset()
+		return err
+	}
+
+	for rows.Next() {
+		key, err := scanIncrementalKey(rows, len(keys))
+		if err != nil {
+			return n, err
+		}
+		batch = append(batch, key)
+		if len(batch) == deletedRowsBatchSize {
+			if err = flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return n, errors.Trace(err)
+	}
+	return n, flush()
+}
+
 func write(tctx *tcontext.Context, writer storage.ExternalFileWriter, str string) error {
 	_, err := writer.Write(tctx, []byte(str))
 	if err != nil {
@@ -568,12 +785,16 @@ func compressFileSuffix(compressType storage.CompressType) string {
 		return ""
 	case storage.Gzip:
 		return ".gz"
+	case storage.Snappy:
+		return ".snappy"
+	case storage.Zstd:
+		return ".zst"
 	default:
 		return ""
 	}
 }
 
-// FileFormat is the format that output to file. Currently we support SQL text and CSV file format.
+// FileFormat is the format that output to file. Currently we support SQL text, CSV and Parquet file format.
 type FileFormat int32
 
 const (
@@ -583,6 +804,8 @@ const (
 	FileFormatSQLText
 	// FileFormatCSV indicates the given file type is csv type
 	FileFormatCSV
+	// FileFormatParquet indicates the given file type is parquet type
+	FileFormatParquet
 )
 
 const (
@@ -590,6 +813,8 @@ const (
 	FileFormatSQLTextString = "sql"
 	// FileFormatCSVString indicates the string/suffix of csv type file
 	FileFormatCSVString = "csv"
+	// FileFormatParquetString indicates the string/suffix of parquet type file
+	FileFormatParquetString = "parquet"
 )
 
 // String implement Stringer.String method.
@@ -599,32 +824,48 @@ func (f FileFormat) String() string {
 		return strings.ToUpper(FileFormatSQLTextString)
 	case FileFormatCSV:
 		return strings.ToUpper(FileFormatCSVString)
+	case FileFormatParquet:
+		return strings.ToUpper(FileFormatParquetString)
 	default:
 		return "unknown"
 	}
 }
 
 // Extension returns the extension for specific format.
-//  text -> "sql"
-//  csv  -> "csv"
+//  text    -> "sql"
+//  csv     -> "csv"
+//  parquet -> "parquet"
 func (f FileFormat) Extension() string {
 	switch f {
 	case FileFormatSQLText:
 		return FileFormatSQLTextString
 	case FileFormatCSV:
 		return FileFormatCSVString
+	case FileFormatParquet:
+		return FileFormatParquetString
 	default:
 		return "unknown_format"
 	}
 }
 
-// WriteInsert writes TableDataIR to a storage.ExternalFileWriter in sql/csv type
+// CompressType returns the compress type of the whole file. The parquet file
+// compresses its pages by itself, so it is never compressed as a whole.
+func (f FileFormat) CompressType(compressType storage.CompressType) storage.CompressType {
+	if f == FileFormatParquet {
+		return storage.NoCompression
+	}
+	return compressType
+}
+
+// WriteInsert writes TableDataIR to a storage.ExternalFileWriter in sql/csv/parquet type
 func (f FileFormat) WriteInsert(pCtx *tcontext.Context, cfg *Config, meta TableMeta, tblIR TableDataIR, w storage.ExternalFileWriter) (uint64, error) {
 	switch f {
 	case FileFormatSQLText:
 		return WriteInsert(pCtx, cfg, meta, tblIR, w)
 	case FileFormatCSV:
 		return WriteInsertInCsv(pCtx, cfg, meta, tblIR, w)
+	case FileFormatParquet:
+		return WriteInsertInParquet(pCtx, cfg, meta, tblIR, w)
 	default:
 		return 0, errors.Errorf("unknown file format")
 	}
@@ -1510,3 +1510,41 @@ func (s *testSerialSuite) TestAggInDisk(c *C) {
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t;").Check(testkit.Rows("0"))
 	tk.MustQuery("select /*+ HASH_AGG() */ count(c) from t group by c1;").Check(testkit.Rows())
 }
+
+func (s *testSuiteAgg) TestGroupByWithRollup(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (1, 2, 20), (2, 1, 30), (2, 1, 40), (null, 1, 50)")
+
+	c.Assert(tk.HasPlan("select a, b, sum(c) from t group by a, b with rollup", "Expand"), IsTrue)
+	tk.MustQuery("select a, b, sum(c) from t group by a, b with rollup").Sort().Check(testkit.Rows(
+		"1 1 10", "1 2 20", "1 <nil> 30", "2 1 70", "2 <nil> 70", "<nil> 1 50", "<nil> <nil> 150", "<nil> <nil> 50"))
+	// GROUPING() tells the super-aggregate rows from the NULL values.
+	tk.MustQuery("select a, b, sum(c), grouping(a), grouping(b), grouping(a, b) from t group by a, b with rollup").Sort().Check(testkit.Rows(
+		"1 1 10 0 0 0", "1 2 20 0 0 0", "1 <nil> 30 0 1 1", "2 1 70 0 0 0", "2 <nil> 70 0 1 1",
+		"<nil> 1 50 0 0 0", "<nil> <nil> 150 1 1 3", "<nil> <nil> 50 0 1 1"))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup having grouping(a) = 1").Check(testkit.Rows("<nil> 150"))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup order by grouping(a), a").Check(testkit.Rows(
+		"<nil> 50", "1 30", "2 70", "<nil> 150"))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup having a is null").Sort().Check(testkit.Rows("<nil> 150", "<nil> 50"))
+	// The aggregate functions see the original values of the group by columns.
+	tk.MustQuery("select a, sum(a), count(a) from t group by a with rollup").Sort().Check(testkit.Rows(
+		"1 2 2", "2 4 2", "<nil> 6 4", "<nil> <nil> 0"))
+	tk.MustQuery("select count(*) from (select a, b, count(*) from t group by a, b with rollup) tt").Check(testkit.Rows("8"))
+	tk.MustQuery("select a, b, count(*) from t where a = 1 group by a, b with rollup").Sort().Check(testkit.Rows(
+		"1 1 1", "1 2 1", "1 <nil> 2", "<nil> <nil> 2"))
+	tk.MustQuery("select a, b, count(*) from t where a > 10 group by a, b with rollup").Check(testkit.Rows())
+
+	tk.MustExec("set @@sql_mode = ''")
+	tk.MustQuery("select a + 1, count(*) from t group by a + 1 with rollup").Sort().Check(testkit.Rows(
+		"2 2", "3 2", "<nil> 1", "<nil> 5"))
+	tk.MustQuery("select a + 1 as x, count(*), grouping(a + 1) from t group by x with rollup").Sort().Check(testkit.Rows(
+		"2 2 0", "3 2 0", "<nil> 1 0", "<nil> 5 1"))
+
+	_, err := tk.Exec("select a, grouping(a) from t group by a")
+	c.Assert(plannercore.ErrInvalidGroupFuncUse.Equal(err), IsTrue)
+	_, err = tk.Exec("select a from t where grouping(a) = 0 group by a with rollup")
+	c.Assert(plannercore.ErrInvalidGroupFuncUse.Equal(err), IsTrue)
+}
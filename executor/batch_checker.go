@@ -158,6 +158,11 @@ func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.D
 		if t.Meta().IsCommonHandle && v.Meta().Primary {
 			continue
 		}
+		// The value of the column being added is filled when the row is written,
+		// so the unique index created along with it is checked at that time.
+		if tables.FindAddingCol(t.WritableCols(), v.Meta()) != nil {
+			continue
+		}
 		if len(row) < len(t.WritableCols()) && addChangingColTimes == 0 {
 			if col := tables.FindChangingCol(t.WritableCols(), v.Meta()); col != nil {
 				row = append(row, row[col.DependencyColumnOffset])
@@ -242,6 +242,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildIndexLookUpReader(v)
 	case *plannercore.PhysicalWindow:
 		return b.buildWindow(v)
+	case *plannercore.PhysicalExpand:
+		return b.buildExpand(v)
 	case *plannercore.PhysicalShuffle:
 		return b.buildShuffle(v)
 	case *plannercore.PhysicalShuffleReceiverStub:
@@ -822,6 +824,8 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		fkTrigger:                 newFKTriggerExec(v.FKChecks, v.FKCascades, 0),
+		viewChecks:                v.ViewChecks,
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -1599,6 +1603,7 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableTiDBHotRegions),
 			strings.ToLower(infoschema.TableSessionVar),
 			strings.ToLower(infoschema.TableConstraints),
+			strings.ToLower(infoschema.TableCheckConstraints),
 			strings.ToLower(infoschema.TableTiFlashReplica),
 			strings.ToLower(infoschema.TableTiDBServersInfo),
 			strings.ToLower(infoschema.TableTiKVStoreStatus),
@@ -1849,6 +1854,30 @@ func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Execu
 	return e
 }
 
+func (b *executorBuilder) buildExpand(v *plannercore.PhysicalExpand) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	groupingIDs := make([]int64, 0, len(v.GroupingSets))
+	for _, set := range v.GroupingSets {
+		groupingIDs = append(groupingIDs, plannercore.GroupingID(set, len(v.GroupByExprs)))
+	}
+	childLen := v.Schema().Len() - len(v.GroupByCols) - 1
+	childColIdxs := make([]int, 0, childLen)
+	for _, col := range v.Schema().Columns[:childLen] {
+		childColIdxs = append(childColIdxs, col.Index)
+	}
+	e := &ExpandExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
+		childColIdxs: childColIdxs,
+		groupByExprs: v.GroupByExprs,
+		groupingSets: v.GroupingSets,
+		groupingIDs:  groupingIDs,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executor {
 	childExecs := make([]Executor, len(v.Children()))
 	for i, child := range v.Children() {
@@ -1972,6 +2001,8 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		fkTriggers:                buildTblID2FKTriggerExec(v.FKChecks, v.FKCascades),
+		viewChecks:                v.ViewChecks,
 	}
 	return updateExec
 }
@@ -2014,6 +2045,7 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 		tblID2Table:    tblID2table,
 		IsMultiTable:   v.IsMultiTable,
 		tblColPosInfos: v.TblColPosInfos,
+		fkTriggers:     buildTblID2FKTriggerExec(v.FKChecks, v.FKCascades),
 	}
 	return deleteExec
 }
@@ -4488,12 +4520,15 @@ func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *
 
 	tblInfo := v.TableInfo.Meta()
 	if tblInfo.TempTableType != model.TempTableNone {
-		if tblInfo.TempTableType == model.TempTableGlobal {
-			e.sampler = &emptySampler{}
-		} else {
+		if tblInfo.TempTableType == model.TempTableLocal {
 			b.err = errors.New("TABLESAMPLE clause can not be applied to local temporary tables")
 			return nil
 		}
+		if tblInfo.TempTablePreserveRows {
+			b.err = errors.New("TABLESAMPLE clause can not be applied to global temporary tables with ON COMMIT PRESERVE ROWS")
+			return nil
+		}
+		e.sampler = &emptySampler{}
 	} else if v.TableSampleInfo.AstNode.SampleMethod == ast.SampleMethodTypeTiDBRegion {
 		e.sampler = newTableRegionSampler(
 			b.ctx, v.TableInfo, startTS, v.TableSampleInfo.Partitions, v.Schema(),
@@ -4641,6 +4676,10 @@ func (b *executorBuilder) validCanReadTemporaryTable(tbl *model.TableInfo) error
 		return errors.New("can not read local temporary table when 'tidb_snapshot' is set")
 	}
 
+	if tbl.PreserveRowsOnCommit() && sessionVars.SnapshotTS != 0 {
+		return errors.New("can not read global temporary table with ON COMMIT PRESERVE ROWS when 'tidb_snapshot' is set")
+	}
+
 	if sessionVars.TxnCtx.IsStaleness || b.isStaleness {
 		return errors.New("can not stale read temporary table")
 	}
@@ -997,7 +997,8 @@ func newLockCtx(seVars *variable.SessionVars, lockWaitTime int64) *tikvstore.Loc
 func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *tikvstore.LockCtx, keys ...kv.Key) error {
 	sessVars := se.GetSessionVars()
 	sctx := sessVars.StmtCtx
-	if !sctx.InUpdateStmt && !sctx.InDeleteStmt {
+	// The rows locked by the DML statements aren't returned to the client, the statements can be retried.
+	if !sctx.InUpdateStmt && !sctx.InDeleteStmt && !sctx.InInsertStmt {
 		atomic.StoreUint32(&se.GetSessionVars().TxnCtx.ForUpdate, 1)
 	}
 	// Lock keys only once when finished fetching all results.
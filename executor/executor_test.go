@@ -1276,6 +1276,43 @@ func (s *testSuiteWithData) TestSetOperationOnDiffColType(c *C) {
 	}
 }
 
+func (s *testSuiteP1) TestSetOperationAll(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test`)
+	tk.MustExec(`drop table if exists t1, t2, t3`)
+	tk.MustExec(`create table t1(a int)`)
+	tk.MustExec(`create table t2 like t1`)
+	tk.MustExec(`create table t3 like t1`)
+	tk.MustExec(`insert into t1 values (1),(1),(1),(2),(2),(3),(null),(null)`)
+	tk.MustExec(`insert into t2 values (1),(1),(2),(4),(null)`)
+	tk.MustExec(`insert into t3 values (1),(2),(2)`)
+
+	tk.MustQuery(`select * from t1 intersect all select * from t2`).Sort().Check(testkit.Rows("1", "1", "2", "<nil>"))
+	tk.MustQuery(`select * from t1 except all select * from t2`).Sort().Check(testkit.Rows("1", "2", "3", "<nil>"))
+	tk.MustQuery(`select * from t2 except all select * from t1`).Sort().Check(testkit.Rows("4"))
+	tk.MustQuery(`select * from t1 intersect select * from t2`).Sort().Check(testkit.Rows("1", "2", "<nil>"))
+	tk.MustQuery(`select * from t1 except select * from t2`).Sort().Check(testkit.Rows("3"))
+	// INTERSECT has higher precedence than UNION and EXCEPT.
+	tk.MustQuery(`select * from t1 except all select * from t2 intersect all select * from t3`).Sort().Check(testkit.Rows("1", "1", "2", "3", "<nil>", "<nil>"))
+	tk.MustQuery(`select * from t1 intersect all select * from t2 intersect all select * from t3`).Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery(`select * from t1 union all select * from t3 except all select * from t2`).Sort().Check(testkit.Rows("1", "1", "2", "2", "2", "3", "<nil>"))
+	// Nested set operations.
+	tk.MustQuery(`(select * from t1 except all select * from t2) intersect all (select * from t2 union all select * from t3)`).Sort().Check(testkit.Rows("1", "2", "<nil>"))
+	tk.MustQuery(`select * from t1 except all (select * from t2 union all select * from t3)`).Sort().Check(testkit.Rows("3", "<nil>"))
+	tk.MustQuery(`(select * from t1 intersect all select * from t2) order by a desc limit 3`).Check(testkit.Rows("2", "1", "1"))
+	tk.MustQuery(`select count(*) from (select * from t1 except all select * from t3) t`).Check(testkit.Rows("5"))
+
+	tk.MustExec(`drop table if exists t1, t2`)
+	tk.MustExec(`create table t1(a int, b varchar(10))`)
+	tk.MustExec(`create table t2(a int, b varchar(10))`)
+	tk.MustExec(`insert into t1 values (1,'a'),(1,'a'),(1,'b'),(null,null),(null,null)`)
+	tk.MustExec(`insert into t2 values (1,'a'),(1,'b'),(1,'b'),(null,null)`)
+	tk.MustQuery(`select * from t1 intersect all select * from t2`).Sort().Check(testkit.Rows("1 a", "1 b", "<nil> <nil>"))
+	tk.MustQuery(`select * from t1 except all select * from t2`).Sort().Check(testkit.Rows("1 a", "<nil> <nil>"))
+	tk.MustQuery(`select * from t2 except all select * from t1`).Sort().Check(testkit.Rows("1 b"))
+	tk.MustGetErrCode(`select * from t1 intersect all select a from t2`, mysql.ErrWrongNumberOfColumnsInSelect)
+}
+
 // issue-23038: wrong key range of index scan for year column
 func (s *testSuiteWithData) TestIndexScanWithYearCol(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
@@ -0,0 +1,161 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// ExpandExec replicates every row of its child once for each grouping set. In the replica of a grouping set, the
+// group by columns that are not in the set are filled with NULL, and the last column is the grouping id.
+// It is used to compute the super-aggregate rows of `GROUP BY ... WITH ROLLUP`.
+type ExpandExec struct {
+	baseExecutor
+
+	// childColIdxs are the columns of the child copied to the output.
+	childColIdxs []int
+	groupByExprs []expression.Expression
+	// groupingSets contains the offsets of the group by expressions kept by each replica.
+	groupingSets [][]int
+	groupingIDs  []int64
+	// keptColIdxs[i] are the columns of groupByResult copied in the replica of the i-th grouping set.
+	keptColIdxs [][]int
+	// nulledColIdxs[i] are the group by columns filled with NULL in the replica of the i-th grouping set.
+	nulledColIdxs [][]int
+	// singleColIdxs[i] is []int{i}, which is used to copy a single cell of groupByResult.
+	singleColIdxs [][]int
+
+	childResult   *chunk.Chunk
+	groupByResult *chunk.Chunk
+	// setIdx and rowIdx point to the next row to output.
+	setIdx int
+	rowIdx int
+
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *ExpandExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+
+	e.childResult = newFirstChunk(e.children[0])
+	fieldTypes := make([]*types.FieldType, 0, len(e.groupByExprs))
+	for _, expr := range e.groupByExprs {
+		fieldTypes = append(fieldTypes, expr.GetType())
+	}
+	e.groupByResult = chunk.New(fieldTypes, e.initCap, e.maxChunkSize)
+	e.memTracker.Consume(e.childResult.MemoryUsage() + e.groupByResult.MemoryUsage())
+
+	e.singleColIdxs = make([][]int, 0, len(e.groupByExprs))
+	for i := range e.groupByExprs {
+		e.singleColIdxs = append(e.singleColIdxs, []int{i})
+	}
+	e.keptColIdxs = make([][]int, 0, len(e.groupingSets))
+	e.nulledColIdxs = make([][]int, 0, len(e.groupingSets))
+	for _, set := range e.groupingSets {
+		kept := make([]bool, len(e.groupByExprs))
+		for _, offset := range set {
+			kept[offset] = true
+		}
+		keptIdxs := make([]int, 0, len(set))
+		nulledIdxs := make([]int, 0, len(e.groupByExprs)-len(set))
+		for i := range e.groupByExprs {
+			if kept[i] {
+				keptIdxs = append(keptIdxs, i)
+			} else {
+				nulledIdxs = append(nulledIdxs, i)
+			}
+		}
+		e.keptColIdxs = append(e.keptColIdxs, keptIdxs)
+		e.nulledColIdxs = append(e.nulledColIdxs, nulledIdxs)
+	}
+	// Mark the current child chunk as consumed, so the first call of Next fetches data from the child.
+	e.setIdx = len(e.groupingSets)
+	e.rowIdx = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *ExpandExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	childLen := len(e.childColIdxs)
+	for !req.IsFull() {
+		if e.setIdx >= len(e.groupingSets) {
+			if err := e.fetchChild(ctx); err != nil {
+				return err
+			}
+			if e.childResult.NumRows() == 0 {
+				return nil
+			}
+		}
+		keptIdxs, nulledIdxs := e.keptColIdxs[e.setIdx], e.nulledColIdxs[e.setIdx]
+		groupingID := e.groupingIDs[e.setIdx]
+		numRows := e.childResult.NumRows()
+		for ; e.rowIdx < numRows && !req.IsFull(); e.rowIdx++ {
+			req.AppendPartialRowByColIdxs(0, e.childResult.GetRow(e.rowIdx), e.childColIdxs)
+			groupByRow := e.groupByResult.GetRow(e.rowIdx)
+			for _, idx := range keptIdxs {
+				req.AppendPartialRowByColIdxs(childLen+idx, groupByRow, e.singleColIdxs[idx])
+			}
+			for _, idx := range nulledIdxs {
+				req.AppendNull(childLen + idx)
+			}
+			req.AppendInt64(childLen+len(e.groupByExprs), groupingID)
+		}
+		if e.rowIdx >= numRows {
+			e.setIdx++
+			e.rowIdx = 0
+		}
+	}
+	return nil
+}
+
+func (e *ExpandExec) fetchChild(ctx context.Context) error {
+	mSize := e.childResult.MemoryUsage() + e.groupByResult.MemoryUsage()
+	err := Next(ctx, e.children[0], e.childResult)
+	if err != nil {
+		return err
+	}
+	e.groupByResult.Reset()
+	if e.childResult.NumRows() > 0 {
+		err = expression.VectorizedExecute(e.ctx, e.groupByExprs, chunk.NewIterator4Chunk(e.childResult), e.groupByResult)
+		if err != nil {
+			return err
+		}
+	}
+	e.memTracker.Consume(e.childResult.MemoryUsage() + e.groupByResult.MemoryUsage() - mSize)
+	e.setIdx = 0
+	e.rowIdx = 0
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *ExpandExec) Close() error {
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.childResult.MemoryUsage() - e.groupByResult.MemoryUsage())
+	}
+	e.childResult = nil
+	e.groupByResult = nil
+	return e.baseExecutor.Close()
+}
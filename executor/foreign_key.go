@@ -0,0 +1,511 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// maxForeignKeyCascadeDepth is the maximum depth of the foreign key cascades, it's the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// fkTriggerExec executes the foreign key checks and cascades of a written table.
+type fkTriggerExec struct {
+	checks   []*plannercore.FKCheck
+	cascades []*fkCascadeExec
+	// depth is the depth of the cascade which writes the table, it's 0 for the table written by the statement.
+	depth int
+}
+
+// fkCascadeExec executes a foreign key cascade on the child table.
+type fkCascadeExec struct {
+	*plannercore.FKCascade
+	// genExprs are the expressions of the generated columns of the child table.
+	genExprs []expression.Expression
+	// childTrigger is the trigger of the child table when its rows are deleted or updated.
+	childTrigger *fkTriggerExec
+	// inited indicates genExprs and childTrigger are built, they're built on demand.
+	inited bool
+}
+
+func newFKTriggerExec(checks []*plannercore.FKCheck, cascades []*plannercore.FKCascade, depth int) *fkTriggerExec {
+	if len(checks) == 0 && len(cascades) == 0 {
+		return nil
+	}
+	e := &fkTriggerExec{checks: checks, depth: depth}
+	for _, cascade := range cascades {
+		e.cascades = append(e.cascades, &fkCascadeExec{FKCascade: cascade})
+	}
+	return e
+}
+
+func buildTblID2FKTriggerExec(checks map[int64][]*plannercore.FKCheck, cascades map[int64][]*plannercore.FKCascade) map[int64]*fkTriggerExec {
+	if len(checks) == 0 && len(cascades) == 0 {
+		return nil
+	}
+	triggers := make(map[int64]*fkTriggerExec, len(checks)+len(cascades))
+	for id, tblChecks := range checks {
+		triggers[id] = newFKTriggerExec(tblChecks, cascades[id], 0)
+	}
+	for id, tblCascades := range cascades {
+		if _, ok := triggers[id]; !ok {
+			triggers[id] = newFKTriggerExec(nil, tblCascades, 0)
+		}
+	}
+	return triggers
+}
+
+// checkInsertedRow checks the referred parent rows exist for the inserted row.
+func (e *fkTriggerExec) checkInsertedRow(ctx context.Context, sctx sessionctx.Context, row []types.Datum) error {
+	if e == nil {
+		return nil
+	}
+	for _, check := range e.checks {
+		if !check.CheckExist {
+			continue
+		}
+		if err := checkFKParentExists(ctx, sctx, check, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUpdatedRow checks the referred parent rows exist for the updated row whose foreign key values are changed.
+func (e *fkTriggerExec) checkUpdatedRow(ctx context.Context, sctx sessionctx.Context, oldRow, newRow []types.Datum) error {
+	if e == nil {
+		return nil
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	for _, check := range e.checks {
+		if !check.CheckExist {
+			continue
+		}
+		changed, err := fkValuesChanged(sc, oldRow, check.ValueOffsets, newRow, check.ValueOffsets)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err = checkFKParentExists(ctx, sctx, check, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onParentWrite checks and cascades the child rows which refer to the written parent row.
+// The newRow is nil if the parent row is deleted.
+func (e *fkTriggerExec) onParentWrite(ctx context.Context, sctx sessionctx.Context, oldRow, newRow []types.Datum) error {
+	if e == nil {
+		return nil
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	for _, check := range e.checks {
+		if check.CheckExist {
+			continue
+		}
+		vals, hasNull, skip, err := getReferredValues(sc, oldRow, newRow, check.ValueOffsets)
+		if err != nil {
+			return err
+		}
+		if hasNull || skip {
+			continue
+		}
+		handles, err := lookupFKRows(ctx, sctx, &check.FKLookup, vals, 1)
+		if err != nil {
+			return err
+		}
+		if len(handles) > 0 {
+			return check.FailedErr
+		}
+	}
+	for _, cascade := range e.cascades {
+		vals, hasNull, skip, err := getReferredValues(sc, oldRow, newRow, cascade.ValueOffsets)
+		if err != nil {
+			return err
+		}
+		if hasNull || skip {
+			continue
+		}
+		if err = e.doCascade(ctx, sctx, cascade, vals, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *fkTriggerExec) doCascade(ctx context.Context, sctx sessionctx.Context, cascade *fkCascadeExec, vals, parentRow []types.Datum) error {
+	handles, err := lookupFKRows(ctx, sctx, &cascade.FKLookup, vals, 0)
+	if err != nil || len(handles) == 0 {
+		return err
+	}
+	if e.depth >= maxForeignKeyCascadeDepth {
+		return table.ErrFkDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+	}
+	if err = cascade.init(sctx, e.depth+1); err != nil {
+		return err
+	}
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	child := cascade.Tbl
+	for _, h := range handles {
+		oldRow, err := getOldRow(ctx, sctx, txn, child, h, cascade.genExprs)
+		if err != nil {
+			return err
+		}
+		if cascade.Tp == plannercore.FKCascadeOnDelete && cascade.ReferOpt == ast.ReferOptionCascade {
+			if err = child.RemoveRecord(sctx, h, oldRow); err != nil {
+				return err
+			}
+			if err = cascade.childTrigger.onParentWrite(ctx, sctx, oldRow, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		newRow, err := cascade.buildChildRow(sctx, oldRow, parentRow)
+		if err != nil {
+			return err
+		}
+		if err = updateCascadedRow(ctx, sctx, child, h, oldRow, newRow, cascade.Cols); err != nil {
+			return err
+		}
+		if err = cascade.childTrigger.checkUpdatedRow(ctx, sctx, oldRow, newRow); err != nil {
+			return err
+		}
+		if err = cascade.childTrigger.onParentWrite(ctx, sctx, oldRow, newRow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// init builds the generated column expressions and the trigger of the child table.
+func (e *fkCascadeExec) init(sctx sessionctx.Context, depth int) error {
+	if e.inited {
+		return nil
+	}
+	child := e.Tbl
+	for _, col := range child.WritableCols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(sctx, col.GeneratedExprString, child.Meta())
+		if err != nil {
+			return err
+		}
+		e.genExprs = append(e.genExprs, expr)
+	}
+	is := sctx.GetInfoSchema().(infoschema.InfoSchema)
+	var (
+		checks   []*plannercore.FKCheck
+		cascades []*plannercore.FKCascade
+		err      error
+	)
+	if e.Tp == plannercore.FKCascadeOnDelete && e.ReferOpt == ast.ReferOptionCascade {
+		checks, cascades, err = plannercore.BuildOnDeleteFKTriggers(sctx, is, e.DBName, child)
+	} else {
+		updatedCols := make(map[string]struct{}, len(e.Cols))
+		for _, col := range e.Cols {
+			updatedCols[col.Name.L] = struct{}{}
+		}
+		checks, cascades, err = plannercore.BuildOnUpdateFKTriggers(sctx, is, e.DBName, child, updatedCols)
+	}
+	if err != nil {
+		return err
+	}
+	e.childTrigger = newFKTriggerExec(checks, cascades, depth)
+	e.inited = true
+	return nil
+}
+
+// buildChildRow builds the new child row for the SET NULL action or the CASCADE action on update.
+func (e *fkCascadeExec) buildChildRow(sctx sessionctx.Context, oldRow, parentRow []types.Datum) ([]types.Datum, error) {
+	newRow := make([]types.Datum, len(oldRow))
+	copy(newRow, oldRow)
+	for i, col := range e.Cols {
+		if e.ReferOpt == ast.ReferOptionSetNull {
+			newRow[col.Offset].SetNull()
+			continue
+		}
+		v, err := table.CastValue(sctx, parentRow[e.ValueOffsets[i]], col.ToInfo(), false, false)
+		if err != nil {
+			return nil, err
+		}
+		newRow[col.Offset] = v
+	}
+	if err := table.CheckNotNull(e.Cols, newRow); err != nil {
+		return nil, err
+	}
+	// Refill the virtual generated columns since their base columns may be changed.
+	gIdx := 0
+	for _, col := range e.Tbl.WritableCols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		if !col.GeneratedStored {
+			val, err := e.genExprs[gIdx].Eval(chunk.MutRowFromDatums(newRow).ToRow())
+			if err != nil {
+				return nil, err
+			}
+			newRow[col.Offset], err = table.CastValue(sctx, val, col.ToInfo(), false, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		gIdx++
+	}
+	if err := table.CheckRowConstraint(sctx, table.WritableConstraints(e.Tbl), newRow); err != nil {
+		return nil, err
+	}
+	return newRow, nil
+}
+
+// updateCascadedRow writes the child row changed by the foreign key cascade.
+// The cascaded rows are not counted in the affected rows of the statement.
+func updateCascadedRow(ctx context.Context, sctx sessionctx.Context, t table.Table, h kv.Handle, oldRow, newRow []types.Datum, cols []*table.Column) error {
+	touched := make([]bool, len(newRow))
+	handleChanged := false
+	for _, col := range cols {
+		touched[col.Offset] = true
+		if col.IsPKHandleColumn(t.Meta()) || col.IsCommonHandleColumn(t.Meta()) {
+			handleChanged = true
+		}
+	}
+	for _, col := range t.WritableCols() {
+		if col.IsGenerated() && !col.GeneratedStored {
+			touched[col.Offset] = true
+		}
+	}
+	if !handleChanged {
+		return t.UpdateRecord(ctx, sctx, h, oldRow, newRow, touched)
+	}
+	if err := t.RemoveRecord(sctx, h, oldRow); err != nil {
+		return err
+	}
+	_, err := t.AddRecord(sctx, newRow, table.IsUpdate, table.WithCtx(ctx))
+	return err
+}
+
+// checkFKParentExists checks the parent row referred by the child row exists,
+// the found parent row is locked so that it can't be deleted or updated by the
+// other transactions. In the optimistic transaction, the locked keys are checked
+// for write conflicts when committing.
+func checkFKParentExists(ctx context.Context, sctx sessionctx.Context, check *plannercore.FKCheck, row []types.Datum) error {
+	vals, hasNull := getFKValues(row, check.ValueOffsets)
+	if hasNull {
+		return nil
+	}
+	if check.Tbl == nil {
+		return check.FailedErr
+	}
+	// The row which refers to itself is always valid.
+	if check.Tbl.Meta().ID == check.FKTable.ID {
+		refOffsets := make([]int, 0, len(check.Cols))
+		for _, col := range check.Cols {
+			refOffsets = append(refOffsets, col.Offset)
+		}
+		changed, err := fkValuesChanged(sctx.GetSessionVars().StmtCtx, row, refOffsets, row, check.ValueOffsets)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+	}
+	keys, err := lookupFKRowKeys(ctx, sctx, &check.FKLookup, vals, 1)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return check.FailedErr
+	}
+	// Like SELECT ... FOR UPDATE on the parent table, the transaction can't be retried
+	// since the locked parent row may be changed before retrying.
+	atomic.StoreUint32(&sctx.GetSessionVars().TxnCtx.ForUpdate, 1)
+	return LockKeys(ctx, sctx, sctx.GetSessionVars().LockWaitTimeout, keys...)
+}
+
+func getFKValues(row []types.Datum, offsets []int) ([]types.Datum, bool) {
+	vals := make([]types.Datum, 0, len(offsets))
+	for _, offset := range offsets {
+		if row[offset].IsNull() {
+			return nil, true
+		}
+		vals = append(vals, row[offset])
+	}
+	return vals, false
+}
+
+// getReferredValues gets the referred values from the old parent row, skip is true if the values are not changed by the update.
+func getReferredValues(sc *stmtctx.StatementContext, oldRow, newRow []types.Datum, offsets []int) (vals []types.Datum, hasNull, skip bool, err error) {
+	if newRow != nil {
+		changed, err := fkValuesChanged(sc, oldRow, offsets, newRow, offsets)
+		if err != nil || !changed {
+			return nil, false, true, err
+		}
+	}
+	vals, hasNull = getFKValues(oldRow, offsets)
+	return vals, hasNull, false, nil
+}
+
+// fkValuesChanged compares the values at the oldOffsets of the old row with the values at the newOffsets of the new row.
+func fkValuesChanged(sc *stmtctx.StatementContext, oldRow []types.Datum, oldOffsets []int, newRow []types.Datum, newOffsets []int) (bool, error) {
+	for i, offset := range oldOffsets {
+		oldVal, newVal := oldRow[offset], newRow[newOffsets[i]]
+		// We should use binary collation to compare datum, otherwise the result will be incorrect.
+		oldVal.SetCollation(charset.CollationBin)
+		cmp, err := oldVal.CompareDatum(sc, &newVal)
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lookupFKRows finds the handles of the rows whose looked up columns equal to the values.
+// At most limit rows are returned if limit is positive.
+func lookupFKRows(ctx context.Context, sctx sessionctx.Context, lookup *plannercore.FKLookup, vals []types.Datum, limit int) ([]kv.Handle, error) {
+	handles, _, err := lookupFKRowsAndKeys(ctx, sctx, lookup, vals, limit)
+	return handles, err
+}
+
+// lookupFKRowKeys is like lookupFKRows, but it returns the found row or index keys.
+func lookupFKRowKeys(ctx context.Context, sctx sessionctx.Context, lookup *plannercore.FKLookup, vals []types.Datum, limit int) ([]kv.Key, error) {
+	_, keys, err := lookupFKRowsAndKeys(ctx, sctx, lookup, vals, limit)
+	return keys, err
+}
+
+func lookupFKRowsAndKeys(ctx context.Context, sctx sessionctx.Context, lookup *plannercore.FKLookup, vals []types.Datum, limit int) ([]kv.Handle, []kv.Key, error) {
+	sc := sctx.GetSessionVars().StmtCtx
+	converted := make([]types.Datum, 0, len(vals))
+	for i, val := range vals {
+		v, err := val.ConvertTo(sc, &lookup.Cols[i].FieldType)
+		if err != nil {
+			// The value can't be stored in the looked up column, so no row matches it.
+			return nil, nil, nil
+		}
+		converted = append(converted, v)
+	}
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	tblInfo := lookup.Tbl.Meta()
+	var (
+		prefix       kv.Key
+		decodeHandle func(key, value []byte) (kv.Handle, error)
+	)
+	switch {
+	case lookup.IsHandle:
+		h := kv.IntHandle(converted[0].GetInt64())
+		key := tablecodec.EncodeRecordKey(lookup.Tbl.RecordPrefix(), h)
+		_, err = txn.Get(ctx, key)
+		if kv.IsErrNotFound(err) {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return []kv.Handle{h}, []kv.Key{key}, nil
+	case lookup.Idx != nil && lookup.Idx.Meta().Primary && tblInfo.IsCommonHandle:
+		encoded, err := codec.EncodeKey(sc, nil, converted...)
+		if err != nil {
+			return nil, nil, err
+		}
+		prefix = tablecodec.EncodeRowKey(tblInfo.ID, encoded)
+		decodeHandle = func(key, _ []byte) (kv.Handle, error) {
+			return tablecodec.DecodeRowKey(key)
+		}
+	case lookup.Idx != nil:
+		prefix, _, err = lookup.Idx.GenIndexKey(sc, converted, nil, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		colsLen := len(lookup.Idx.Meta().Columns)
+		decodeHandle = func(key, value []byte) (kv.Handle, error) {
+			return tablecodec.DecodeIndexHandle(key, value, colsLen)
+		}
+	default:
+		prefix = tablecodec.GenTableRecordPrefix(tblInfo.ID)
+		decodeHandle = func(key, value []byte) (kv.Handle, error) {
+			h, err := tablecodec.DecodeRowKey(key)
+			if err != nil {
+				return nil, err
+			}
+			// The default values are indexed by the column offsets, so the whole row is decoded.
+			row, _, err := tables.DecodeRawRowData(sctx, tblInfo, h, lookup.Tbl.DeletableCols(), value)
+			if err != nil {
+				return nil, err
+			}
+			for i, col := range lookup.Cols {
+				cmp, err := row[col.Offset].CompareDatum(sc, &converted[i])
+				if err != nil || cmp != 0 {
+					return nil, err
+				}
+			}
+			return h, nil
+		}
+	}
+
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+	var (
+		handles []kv.Handle
+		keys    []kv.Key
+	)
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		h, err := decodeHandle(it.Key(), it.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		if h != nil {
+			handles = append(handles, h)
+			keys = append(keys, it.Key().Clone())
+			if limit > 0 && len(handles) >= limit {
+				break
+			}
+		}
+		if err = it.Next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return handles, keys, nil
+}
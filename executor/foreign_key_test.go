@@ -0,0 +1,179 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"sync/atomic"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite4) TestForeignKeyCheckOnWrite(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists child, parent")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	defer tk.MustExec("set @@foreign_key_checks = 0")
+
+	tk.MustExec("create table parent (id int primary key, name varchar(10), unique key(name))")
+	tk.MustExec("create table child (id int primary key, pid int, pname varchar(10), " +
+		"constraint fk_1 foreign key (pid) references parent (id), " +
+		"constraint fk_2 foreign key (pname) references parent (name))")
+	tk.MustExec("insert into parent values (1, 'a'), (2, 'b')")
+
+	// The child row must refer to an existing parent row.
+	tk.MustExec("insert into child values (1, 1, 'a'), (2, null, null)")
+	tk.MustGetErrCode("insert into child values (3, 3, null)", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("insert into child values (3, null, 'c')", errno.ErrNoReferencedRow2)
+	tk.MustExec("insert ignore into child values (3, 3, null)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`child`, CONSTRAINT `fk_1` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`))"))
+	tk.MustGetErrCode("update child set pid = 3 where id = 1", errno.ErrNoReferencedRow2)
+	tk.MustExec("update child set pid = 2, pname = 'b' where id = 1")
+
+	// The parent row which is referred can't be deleted or updated by default.
+	tk.MustGetErrCode("delete from parent where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("update parent set name = 'c' where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustExec("delete from parent where id = 1")
+	tk.MustExec("update parent set id = 3 where id = 3")
+	tk.MustQuery("select * from parent").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("1 2 b", "2 <nil> <nil>"))
+
+	// The parent table must exist and have an index on the referred columns.
+	tk.MustGetErrCode("create table t (a int, foreign key (a) references t_not_exist (id))", errno.ErrFkCannotOpenParent)
+	tk.MustGetErrCode("create table t (a int, b int, foreign key (a, b) references child (pid, pname))", errno.ErrFkNoIndexParent)
+
+	// The foreign keys are not checked when foreign_key_checks is off.
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("insert into child values (3, 3, null)")
+	tk.MustExec("delete from parent")
+}
+
+func (s *testSuite4) TestForeignKeyCheckInOptimisticTxn(c *C) {
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustExec("drop table if exists child, parent")
+	tk1.MustExec("set @@foreign_key_checks = 1")
+	defer tk1.MustExec("set @@foreign_key_checks = 0")
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+	defer tk2.MustExec("set @@foreign_key_checks = 0")
+
+	tk1.MustExec("create table parent (id int primary key)")
+	tk1.MustExec("create table child (id int primary key, pid int, foreign key (pid) references parent (id))")
+	tk1.MustExec("insert into parent values (1), (2)")
+
+	// The parent row is deleted by another transaction before the child row is committed.
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("insert into child values (1, 1)")
+	tk2.MustExec("delete from parent where id = 1")
+	_, err := tk1.Exec("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("error: %s", err))
+
+	// The referred value of the parent row is updated by another transaction.
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("insert into child values (2, 2)")
+	tk2.MustExec("update parent set id = 3 where id = 2")
+	_, err = tk1.Exec("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("error: %s", err))
+
+	tk1.MustQuery("select * from parent").Check(testkit.Rows("3"))
+	tk1.MustQuery("select * from child").Check(testkit.Rows())
+
+	// The parent row which isn't changed by other transactions doesn't conflict.
+	// Only the lock of the parent row makes the transaction unable to retry.
+	tk1.MustExec("begin optimistic")
+	tk1.MustExec("insert into parent select id + 1 from parent for update")
+	c.Assert(atomic.LoadUint32(&tk1.Se.GetSessionVars().TxnCtx.ForUpdate), Equals, uint32(0))
+	tk1.MustExec("insert into child values (3, 3)")
+	c.Assert(atomic.LoadUint32(&tk1.Se.GetSessionVars().TxnCtx.ForUpdate), Equals, uint32(1))
+	tk1.MustExec("commit")
+	tk1.MustQuery("select * from child").Check(testkit.Rows("3 3"))
+}
+
+func (s *testSuite4) TestForeignKeyCascade(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3, t4")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	defer tk.MustExec("set @@foreign_key_checks = 0")
+
+	tk.MustExec("create table t1 (id int primary key, a int, key(a))")
+	tk.MustExec("create table t2 (id int primary key, pid int, key(pid), " +
+		"foreign key (pid) references t1 (id) on delete cascade on update cascade)")
+	tk.MustExec("create table t3 (id int primary key, pid int, " +
+		"foreign key (pid) references t2 (id) on delete set null on update restrict)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 1), (3, 2)")
+	tk.MustExec("insert into t3 values (1, 1), (2, 3)")
+
+	// ON UPDATE CASCADE updates the child rows, the cascaded rows are not counted in the affected rows.
+	tk.MustExec("update t1 set id = 10 where id = 1")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("1 10", "2 10", "3 2"))
+
+	// ON DELETE CASCADE deletes the child rows, then ON DELETE SET NULL is triggered on the grandchild rows.
+	tk.MustExec("delete from t1 where id = 10")
+	tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("3 2"))
+	tk.MustQuery("select * from t3 order by id").Check(testkit.Rows("1 <nil>", "2 3"))
+
+	// ON UPDATE RESTRICT on the grandchild rows rejects the cascaded update.
+	tk.MustGetErrCode("update t2 set id = 4 where id = 3", errno.ErrRowIsReferenced2)
+	tk.MustExec("delete from t3 where id = 2")
+	tk.MustExec("update t2 set id = 4 where id = 3")
+
+	// The self-referencing foreign key cascades recursively until the max depth.
+	tk.MustExec("create table t4 (id int primary key, pid int, key(pid), foreign key (pid) references t4 (id) on delete cascade)")
+	tk.MustExec("insert into t4 values (1, 1), (2, 1), (3, 2)")
+	tk.MustExec("delete from t4 where id = 1")
+	tk.MustQuery("select count(*) from t4").Check(testkit.Rows("0"))
+	tk.MustExec("insert into t4 values (1, 1)")
+	for i := 1; i <= 16; i++ {
+		tk.MustExec("insert into t4 values (?, ?)", i+1, i)
+	}
+	tk.MustGetErrCode("delete from t4 where id = 1", errno.ErrFkDepthExceeded)
+	tk.MustExec("drop table t4")
+}
+
+func (s *testSuite4) TestForeignKeyExplain(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists child, parent")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	defer tk.MustExec("set @@foreign_key_checks = 0")
+
+	tk.MustExec("create table parent (id int primary key)")
+	tk.MustExec("create table child (id int primary key, pid int, key idx_pid(pid), " +
+		"constraint fk_1 foreign key (pid) references parent (id) on delete cascade)")
+	tk.MustQuery("explain format = 'brief' insert into child values (1, 1)").Check(testkit.Rows(
+		"Insert N/A root  N/A",
+		"└─Foreign_Key_Check N/A root  foreign_key:fk_1, table:parent, handle:id, check_exist"))
+	tk.MustQuery("explain format = 'brief' delete from parent where id = 1").Check(testkit.Rows(
+		"Delete N/A root  N/A",
+		"├─Point_Get 1.00 root table:parent handle:1",
+		"└─Foreign_Key_Cascade N/A root  foreign_key:fk_1, table:child, index:idx_pid, on_delete:CASCADE"))
+
+	// The child rows are looked up by the index created along with the foreign key.
+	tk.MustExec("drop table child")
+	tk.MustExec("create table child (id int primary key, pid int, constraint fk_1 foreign key (pid) references parent (id) on delete cascade)")
+	tk.MustQuery("explain format = 'brief' delete from parent where id = 1").Check(testkit.Rows(
+		"Delete N/A root  N/A",
+		"├─Point_Get 1.00 root table:parent handle:1",
+		"└─Foreign_Key_Cascade N/A root  foreign_key:fk_1, table:child, index:fk_1, on_delete:CASCADE"))
+}
@@ -147,6 +147,7 @@ func (e *IndexNestedLoopHashJoin) Open(ctx context.Context) error {
 	}
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.initInnerResultSpill()
 	e.innerPtrBytes = make([][]byte, 0, 8)
 	if e.runtimeStats != nil {
 		e.stats = &indexLookUpJoinRuntimeStats{}
@@ -319,7 +320,14 @@ func (e *IndexNestedLoopHashJoin) Close() error {
 		close(e.joinChkResourceCh[i])
 	}
 	e.joinChkResourceCh = nil
-	return e.baseExecutor.Close()
+	var firstErr error
+	if e.innerResultSpillAction != nil {
+		firstErr = e.innerResultSpillAction.closeAll()
+	}
+	if err := e.baseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
 }
 
 func (ow *indexHashJoinOuterWorker) run(ctx context.Context) {
@@ -432,6 +440,8 @@ func (e *IndexNestedLoopHashJoin) newInnerWorker(taskCh chan *indexHashJoinTask,
 			indexRanges:   copiedRanges,
 			keyOff2IdxOff: e.keyOff2IdxOff,
 			stats:         innerStats,
+			diskTracker:   e.diskTracker,
+			spillAction:   e.innerResultSpillAction,
 		},
 		taskCh:            taskCh,
 		joiner:            e.joiners[workerID],
@@ -482,6 +492,9 @@ func (iw *indexHashJoinInnerWorker) run(ctx context.Context, cancelFunc context.
 			break
 		}
 		err := iw.handleTask(ctx, task, joinResult, h, resultCh)
+		if closeErr := iw.closeInnerResult(task.lookUpJoinTask); err == nil {
+			err = closeErr
+		}
 		if err != nil {
 			joinResult.err = err
 			break
@@ -576,6 +589,17 @@ func (iw *indexHashJoinInnerWorker) fetchInnerResults(ctx context.Context, task
 	return iw.innerWorker.fetchInnerResults(ctx, task, lookUpContents)
 }
 
+// closeInnerResult closes the inner result of a task after the task is handled, the disk files are removed if it has been spilled.
+func (iw *indexHashJoinInnerWorker) closeInnerResult(task *lookUpJoinTask) error {
+	if task.innerResult == nil {
+		return nil
+	}
+	if iw.spillAction != nil {
+		iw.spillAction.unregister(task.innerResult)
+	}
+	return task.innerResult.Close()
+}
+
 func (iw *indexHashJoinInnerWorker) handleHashJoinInnerWorkerPanic(r interface{}) {
 	if r != nil {
 		iw.resultCh <- &indexHashJoinResult{err: errors.Errorf("%v", r)}
@@ -613,13 +637,16 @@ func (iw *indexHashJoinInnerWorker) handleTask(ctx context.Context, task *indexH
 
 func (iw *indexHashJoinInnerWorker) doJoinUnordered(ctx context.Context, task *indexHashJoinTask, joinResult *indexHashJoinResult, h hash.Hash64, resultCh chan *indexHashJoinResult) error {
 	var ok bool
-	iter := chunk.NewIterator4List(task.innerResult)
+	iter := chunk.NewIterator4RowContainer(task.innerResult)
 	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
 		ok, joinResult = iw.joinMatchedInnerRow2Chunk(ctx, row, task, joinResult, h, iw.joinKeyBuf)
 		if !ok {
 			return errors.New("indexHashJoinInnerWorker.doJoinUnordered failed")
 		}
 	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
 	for chkIdx, outerRowStatus := range task.outerRowStatus {
 		chk := task.outerResult.GetChunk(chkIdx)
 		for rowIdx, val := range outerRowStatus {
@@ -749,7 +776,12 @@ func (iw *indexHashJoinInnerWorker) doJoinInOrder(ctx context.Context, task *ind
 		close(resultCh)
 	}()
 	for i, numChunks := 0, task.innerResult.NumChunks(); i < numChunks; i++ {
-		for j, chk := 0, task.innerResult.GetChunk(i); j < chk.NumRows(); j++ {
+		var chk *chunk.Chunk
+		chk, err = task.innerResult.GetChunk(i)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < chk.NumRows(); j++ {
 			row := chk.GetRow(j)
 			ptr := chunk.RowPtr{ChkIdx: uint32(i), RowIdx: uint32(j)}
 			err = iw.collectMatchedInnerPtrs4OuterRows(ctx, row, ptr, task, h, iw.joinKeyBuf)
@@ -766,7 +798,12 @@ func (iw *indexHashJoinInnerWorker) doJoinInOrder(ctx context.Context, task *ind
 			matchedInnerRows, hasMatched, hasNull = matchedInnerRows[:0], false, false
 			outerRow := task.outerResult.GetChunk(chkIdx).GetRow(outerRowIdx)
 			for _, ptr := range innerRowPtrs {
-				matchedInnerRows = append(matchedInnerRows, task.innerResult.GetRow(ptr))
+				var innerRow chunk.Row
+				innerRow, err = task.innerResult.GetRow(ptr)
+				if err != nil {
+					return err
+				}
+				matchedInnerRows = append(matchedInnerRows, innerRow)
 			}
 			iter := chunk.NewIterator4Slice(matchedInnerRows)
 			for iter.Begin(); iter.Current() != iter.End(); {
@@ -27,6 +27,8 @@ import (
 	"unsafe"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -36,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
@@ -80,7 +83,11 @@ type IndexLookUpJoin struct {
 	// lastColHelper store the information for last col if there's complicated filter like col > x_col and col < x_col + 100.
 	lastColHelper *plannercore.ColWithCmpFuncManager
 
-	memTracker *memory.Tracker // track memory usage.
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *disk.Tracker   // track disk usage.
+	// innerResultSpillAction spills the inner results of the tasks to disk when the memory quota is exceeded,
+	// it is nil if spilling to disk is disabled.
+	innerResultSpillAction *innerResultSpillAction
 
 	stats *indexLookUpJoinRuntimeStats
 }
@@ -106,7 +113,7 @@ type lookUpJoinTask struct {
 	outerResult *chunk.List
 	outerMatch  [][]bool
 
-	innerResult       *chunk.List
+	innerResult       *chunk.RowContainer
 	encodedLookUpKeys []*chunk.Chunk
 	lookupMap         *mvmap.MVMap
 	matchedInners     []chunk.Row
@@ -148,6 +155,9 @@ type innerWorker struct {
 	nextColCompareFilters *plannercore.ColWithCmpFuncManager
 	keyOff2IdxOff         []int
 	stats                 *innerWorkerRuntimeStats
+
+	diskTracker *disk.Tracker
+	spillAction *innerResultSpillAction
 }
 
 // Open implements the Executor interface.
@@ -158,6 +168,7 @@ func (e *IndexLookUpJoin) Open(ctx context.Context) error {
 	}
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.initInnerResultSpill()
 	e.innerPtrBytes = make([][]byte, 0, 8)
 	if e.runtimeStats != nil {
 		e.stats = &indexLookUpJoinRuntimeStats{}
@@ -167,6 +178,18 @@ func (e *IndexLookUpJoin) Open(ctx context.Context) error {
 	return nil
 }
 
+// initInnerResultSpill initializes the disk tracker, and registers the action to spill
+// the inner results of the tasks to disk if it is enabled.
+func (e *IndexLookUpJoin) initInnerResultSpill() {
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.innerResultSpillAction = nil
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		e.innerResultSpillAction = newInnerResultSpillAction()
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.innerResultSpillAction)
+	}
+}
+
 func (e *IndexLookUpJoin) startWorkers(ctx context.Context) {
 	concurrency := e.ctx.GetSessionVars().IndexLookupJoinConcurrency()
 	if e.stats != nil {
@@ -220,6 +243,8 @@ func (e *IndexLookUpJoin) newInnerWorker(taskCh chan *lookUpJoinTask) *innerWork
 		indexRanges:   copiedRanges,
 		keyOff2IdxOff: e.keyOff2IdxOff,
 		stats:         innerStats,
+		diskTracker:   e.diskTracker,
+		spillAction:   e.innerResultSpillAction,
 	}
 	if e.lastColHelper != nil {
 		// nextCwf.TmpConstant needs to be reset for every individual
@@ -252,7 +277,9 @@ func (e *IndexLookUpJoin) Next(ctx context.Context, req *chunk.Chunk) error {
 		}
 		startTime := time.Now()
 		if e.innerIter == nil || e.innerIter.Current() == e.innerIter.End() {
-			e.lookUpMatchedInners(task, task.cursor)
+			if err := e.lookUpMatchedInners(task, task.cursor); err != nil {
+				return err
+			}
 			e.innerIter = chunk.NewIterator4Slice(task.matchedInners)
 			e.innerIter.Begin()
 		}
@@ -292,6 +319,14 @@ func (e *IndexLookUpJoin) getFinishedTask(ctx context.Context) (*lookUpJoinTask,
 	if task != nil && int(task.cursor.ChkIdx) < task.outerResult.NumChunks() {
 		return task, nil
 	}
+	if task != nil {
+		// The previous task is finished, release the memory and disk used by it.
+		e.task = nil
+		task.memTracker.Detach()
+		if err := e.closeInnerResult(task); err != nil {
+			return nil, err
+		}
+	}
 
 	select {
 	case task = <-e.resultCh:
@@ -315,16 +350,31 @@ func (e *IndexLookUpJoin) getFinishedTask(ctx context.Context) (*lookUpJoinTask,
 	return task, nil
 }
 
-func (e *IndexLookUpJoin) lookUpMatchedInners(task *lookUpJoinTask, rowPtr chunk.RowPtr) {
+func (e *IndexLookUpJoin) lookUpMatchedInners(task *lookUpJoinTask, rowPtr chunk.RowPtr) error {
 	outerKey := task.encodedLookUpKeys[rowPtr.ChkIdx].GetRow(int(rowPtr.RowIdx)).GetBytes(0)
 	e.innerPtrBytes = task.lookupMap.Get(outerKey, e.innerPtrBytes[:0])
 	task.matchedInners = task.matchedInners[:0]
 
 	for _, b := range e.innerPtrBytes {
 		ptr := *(*chunk.RowPtr)(unsafe.Pointer(&b[0]))
-		matchedInner := task.innerResult.GetRow(ptr)
+		matchedInner, err := task.innerResult.GetRow(ptr)
+		if err != nil {
+			return err
+		}
 		task.matchedInners = append(task.matchedInners, matchedInner)
 	}
+	return nil
+}
+
+// closeInnerResult closes the inner result of a task, the disk files are removed if it has been spilled.
+func (e *IndexLookUpJoin) closeInnerResult(task *lookUpJoinTask) error {
+	if task.innerResult == nil {
+		return nil
+	}
+	if e.innerResultSpillAction != nil {
+		e.innerResultSpillAction.unregister(task.innerResult)
+	}
+	return task.innerResult.Close()
 }
 
 func (ow *outerWorker) run(ctx context.Context, wg *sync.WaitGroup) {
@@ -657,9 +707,19 @@ func (iw *innerWorker) fetchInnerResults(ctx context.Context, task *lookUpJoinTa
 		return err
 	}
 
-	innerResult := chunk.NewList(retTypes(innerExec), iw.ctx.GetSessionVars().MaxChunkSize, iw.ctx.GetSessionVars().MaxChunkSize)
+	innerResult := chunk.NewRowContainer(retTypes(innerExec), iw.ctx.GetSessionVars().MaxChunkSize)
 	innerResult.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
 	innerResult.GetMemTracker().AttachTo(task.memTracker)
+	innerResult.GetDiskTracker().AttachTo(iw.diskTracker)
+	task.innerResult = innerResult
+	if iw.spillAction != nil {
+		iw.spillAction.register(innerResult)
+		failpoint.Inject("testIndexLookUpJoinInnerResultSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				innerResult.SpillToDisk()
+			}
+		})
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -673,10 +733,12 @@ func (iw *innerWorker) fetchInnerResults(ctx context.Context, task *lookUpJoinTa
 		if iw.executorChk.NumRows() == 0 {
 			break
 		}
-		innerResult.Add(iw.executorChk)
+		err = innerResult.Add(iw.executorChk)
+		if err != nil {
+			return err
+		}
 		iw.executorChk = newFirstChunk(innerExec)
 	}
-	task.innerResult = innerResult
 	return nil
 }
 
@@ -690,7 +752,10 @@ func (iw *innerWorker) buildLookUpMap(task *lookUpJoinTask) error {
 	keyBuf := make([]byte, 0, 64)
 	valBuf := make([]byte, 8)
 	for i := 0; i < task.innerResult.NumChunks(); i++ {
-		chk := task.innerResult.GetChunk(i)
+		chk, err := task.innerResult.GetChunk(i)
+		if err != nil {
+			return err
+		}
 		for j := 0; j < chk.NumRows(); j++ {
 			innerRow := chk.GetRow(j)
 			if iw.hasNullInJoinKey(innerRow) {
@@ -731,9 +796,88 @@ func (e *IndexLookUpJoin) Close() error {
 	e.workerWg.Wait()
 	e.memTracker = nil
 	e.task = nil
-	return e.baseExecutor.Close()
+	var firstErr error
+	if e.innerResultSpillAction != nil {
+		firstErr = e.innerResultSpillAction.closeAll()
+	}
+	if err := e.baseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// innerResultSpillAction implements memory.ActionOnExceed for the index lookup joins.
+// If the memory quota of a query is exceeded, the inner results of the tasks kept in memory are spilled to disk.
+type innerResultSpillAction struct {
+	memory.BaseOOMAction
+	mu struct {
+		sync.Mutex
+		innerResults map[*chunk.RowContainer]struct{}
+	}
+}
+
+func newInnerResultSpillAction() *innerResultSpillAction {
+	a := &innerResultSpillAction{}
+	a.mu.innerResults = make(map[*chunk.RowContainer]struct{})
+	return a
+}
+
+// register registers the inner result of a task, which can be spilled to disk until it is unregistered.
+func (a *innerResultSpillAction) register(rc *chunk.RowContainer) {
+	// The SpillDiskAction of rc is created here, before rc is shared with other goroutines.
+	rc.ActionSpill()
+	a.mu.Lock()
+	a.mu.innerResults[rc] = struct{}{}
+	a.mu.Unlock()
+}
+
+func (a *innerResultSpillAction) unregister(rc *chunk.RowContainer) {
+	a.mu.Lock()
+	delete(a.mu.innerResults, rc)
+	a.mu.Unlock()
+}
+
+// closeAll closes all the inner results which are not unregistered, it is called after all the workers exit.
+func (a *innerResultSpillAction) closeAll() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var firstErr error
+	for rc := range a.mu.innerResults {
+		if err := rc.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(a.mu.innerResults, rc)
+	}
+	return firstErr
 }
 
+// Action spills the inner results kept in memory to disk, and if there is none, calls its fallbackAction.
+func (a *innerResultSpillAction) Action(t *memory.Tracker) {
+	a.mu.Lock()
+	spilled := false
+	for rc := range a.mu.innerResults {
+		if rc.GetMemTracker().BytesConsumed() > 0 {
+			rc.ActionSpill().Action(t)
+			spilled = true
+		}
+	}
+	a.mu.Unlock()
+	if spilled {
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority get the priority of the Action
+func (a *innerResultSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (a *innerResultSpillAction) SetLogHook(hook func(uint64)) {}
+
 type indexLookUpJoinRuntimeStats struct {
 	concurrency int
 	probe       int64
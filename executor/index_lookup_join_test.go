@@ -21,6 +21,9 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -393,3 +396,63 @@ func (s *testSuite5) TestPartitionTableIndexJoinAndIndexReader(c *C) {
 		tk.MustQuery("select /*+ TIDB_INLJ(t1, t2) */ t1.a from t t1, t t2 where t1.a=t2.b and " + cond).Sort().Check(result)
 	}
 }
+
+func (s *testSerialSuite1) TestIndexLookupJoinInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testIndexLookUpJoinInnerResultSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testIndexLookUpJoinInnerResultSpill"), IsNil)
+	}()
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	sm := &mockSessionManager1{
+		PS: make([]*util.ProcessInfo, 0),
+	}
+	tk.Se.SetSessionManager(sm)
+	s.domain.ExpensiveQueryHandle().SetSessionManager(sm)
+
+	tk.MustExec("set @@tidb_mem_quota_query=1;")
+	tk.MustExec("set @@tidb_max_chunk_size=32;")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	values1 := make([]string, 0, 200)
+	values2 := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		if i < 200 {
+			values1 = append(values1, fmt.Sprintf("(%v, %v)", i, i))
+		}
+		values2 = append(values2, fmt.Sprintf("(%v, %v)", i%100, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(values1, ", "))
+	tk.MustExec("insert into t2 values " + strings.Join(values2, ", "))
+
+	expected := make([]string, 0, 400)
+	for i := 0; i < 200; i++ {
+		if i < 100 {
+			expected = append(expected, fmt.Sprintf("%v %v", i, i), fmt.Sprintf("%v %v", i, i+100), fmt.Sprintf("%v %v", i, i+200))
+		} else {
+			expected = append(expected, fmt.Sprintf("%v <nil>", i))
+		}
+	}
+	for _, hint := range []string{"INL_JOIN", "INL_HASH_JOIN"} {
+		sql := fmt.Sprintf("select /*+ %s(t2) */ t1.a, t2.b from t1 left join t2 on t1.a = t2.a order by t1.a, t2.b", hint)
+		tk.MustQuery(sql).Check(testkit.Rows(expected...))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+
+		rows := tk.MustQuery("explain analyze " + sql).Rows()
+		for _, row := range rows {
+			line := fmt.Sprintf("%v", row)
+			disk := fmt.Sprintf("%v", row[len(row)-1])
+			if strings.Contains(line, "IndexJoin") || strings.Contains(line, "IndexHashJoin") {
+				c.Assert(strings.Contains(disk, "0 Bytes") || strings.Contains(disk, "N/A"), IsFalse, Commentf("%v", line))
+			}
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -170,3 +171,33 @@ func (s *testSuite1) TestPartitionTableRandomIndexMerge(c *C) {
 		tk.MustQuery("select /*+ USE_INDEX_MERGE(tpk, a, b) */ * from tpk where " + cond).Sort().Check(result)
 	}
 }
+
+func (s *testSuite1) TestMultiValuedIndex(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, j json, index mvi((cast(j->'$.a' as signed array))))")
+	tk.MustExec(`insert into t values (1, '{"a": [1, 2, 2]}'), (2, '{"a": [2, 3]}'), (3, '{"a": []}'), (4, '{"a": 4}'), (5, '{}')`)
+	tk.MustExec("admin check table t")
+
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where 2 member of (j->'$.a') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where 4 member of (j->'$.a') order by id").Check(testkit.Rows("4"))
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where json_contains(j->'$.a', '[2, 3]') order by id").Check(testkit.Rows("2"))
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where json_overlaps(j->'$.a', '[1, 3]') order by id").Check(testkit.Rows("1", "2"))
+	c.Assert(tk.HasPlan("select /*+ use_index_merge(t, mvi) */ id from t where json_overlaps(j->'$.a', '[1, 3]')", "IndexMerge"), IsTrue)
+
+	tk.MustExec("update t set j = '{\"a\": [5]}' where id = 1")
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where 2 member of (j->'$.a')").Check(testkit.Rows())
+	tk.MustQuery("select /*+ use_index_merge(t, mvi) */ id from t where 5 member of (j->'$.a')").Check(testkit.Rows("1"))
+
+	// Add the multi-valued index to a table with data.
+	tk.MustExec("alter table t add index mvi2((cast(j->'$.a' as char(10) array)))")
+	tk.MustExec("admin check table t")
+
+	tk.MustGetErrCode("create table t1(j json, unique index((cast(j as signed array))))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create table t1(j json, index((cast(j as signed array) + 1)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create table t1(j json, a int as (cast(j as signed array)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("select cast(1 as signed array)", errno.ErrNotSupportedYet)
+}
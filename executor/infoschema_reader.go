@@ -147,6 +147,8 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForTiDBHotRegions(sctx)
 		case infoschema.TableConstraints:
 			e.setDataFromTableConstraints(sctx, dbs)
+		case infoschema.TableCheckConstraints:
+			e.setDataFromCheckConstraints(sctx, dbs)
 		case infoschema.TableSessionVar:
 			err = e.setDataFromSessionVar(sctx)
 		case infoschema.TableTiDBServersInfo:
@@ -883,6 +885,18 @@ func (e *memtableRetriever) setDataFromPartitions(ctx context.Context, sctx sess
 							buf.WriteString(col.String())
 						}
 						partitionExpr = buf.String()
+					} else if table.Partition.Type == model.PartitionTypeKey {
+						if table.Partition.Linear {
+							partitionMethod = "LINEAR KEY"
+						}
+						buf := bytes.NewBuffer(nil)
+						for i, col := range table.Partition.Columns {
+							if i > 0 {
+								buf.WriteString(",")
+							}
+							buf.WriteString(col.String())
+						}
+						partitionExpr = buf.String()
 					}
 
 					var policyName, directPlacement interface{}
@@ -1034,17 +1048,18 @@ func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []*
 			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
 				continue
 			}
+			checkOption := table.View.GetCheckOption()
 			record := types.MakeDatums(
-				infoschema.CatalogVal,           // TABLE_CATALOG
-				schema.Name.O,                   // TABLE_SCHEMA
-				table.Name.O,                    // TABLE_NAME
-				table.View.SelectStmt,           // VIEW_DEFINITION
-				table.View.CheckOption.String(), // CHECK_OPTION
-				"NO",                            // IS_UPDATABLE
-				table.View.Definer.String(),     // DEFINER
-				table.View.Security.String(),    // SECURITY_TYPE
-				charset,                         // CHARACTER_SET_CLIENT
-				collation,                       // COLLATION_CONNECTION
+				infoschema.CatalogVal,        // TABLE_CATALOG
+				schema.Name.O,                // TABLE_SCHEMA
+				table.Name.O,                 // TABLE_NAME
+				table.View.SelectStmt,        // VIEW_DEFINITION
+				checkOption.String(),         // CHECK_OPTION
+				"NO",                         // IS_UPDATABLE
+				table.View.Definer.String(),  // DEFINER
+				table.View.Security.String(), // SECURITY_TYPE
+				charset,                      // CHARACTER_SET_CLIENT
+				collation,                    // COLLATION_CONNECTION
 			)
 			rows = append(rows, record)
 		}
@@ -1645,6 +1660,51 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 				)
 				rows = append(rows, record)
 			}
+
+			for _, constraint := range tbl.Constraints {
+				if constraint.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,          // CONSTRAINT_CATALOG
+					schema.Name.O,                  // CONSTRAINT_SCHEMA
+					constraint.Name.O,              // CONSTRAINT_NAME
+					schema.Name.O,                  // TABLE_SCHEMA
+					tbl.Name.O,                     // TABLE_NAME
+					infoschema.CheckConstraintType, // CONSTRAINT_TYPE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataFromCheckConstraints constructs data for table information_schema.check_constraints.
+// See https://dev.mysql.com/doc/refman/8.0/en/information-schema-check-constraints-table.html
+func (e *memtableRetriever) setDataFromCheckConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if len(tbl.Constraints) == 0 {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, constraint := range tbl.Constraints {
+				if constraint.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal, // CONSTRAINT_CATALOG
+					schema.Name.O,         // CONSTRAINT_SCHEMA
+					constraint.Name.O,     // CONSTRAINT_NAME
+					fmt.Sprintf("(%s)", constraint.ExprString), // CHECK_CLAUSE
+				)
+				rows = append(rows, record)
+			}
 		}
 	}
 	e.rows = rows
@@ -2105,7 +2165,20 @@ func (e *stmtSummaryTableRetriever) retrieve(ctx context.Context, sctx sessionct
 		rows = reader.GetStmtSummaryCurrentRows()
 	case infoschema.TableStatementsSummaryHistory,
 		infoschema.ClusterTableStatementsSummaryHistory:
-		rows = reader.GetStmtSummaryHistoryRows()
+		if timeRange := e.extractor.CoarseTimeRange; timeRange != nil {
+			var startTime, endTime int64
+			if !timeRange.StartTime.IsZero() {
+				startTime = timeRange.StartTime.Unix()
+			}
+			if !timeRange.EndTime.IsZero() {
+				endTime = timeRange.EndTime.Unix()
+			}
+			reader.SetPersistedTimeRange(startTime, endTime)
+		}
+		rows, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return rows, nil
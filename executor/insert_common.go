@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
@@ -85,6 +86,11 @@ type InsertValues struct {
 
 	rowLen int
 
+	// fkTrigger executes the foreign key checks and cascades of the written rows.
+	fkTrigger *fkTriggerExec
+	// viewChecks are the WITH CHECK OPTION conditions of the view which the rows are inserted through.
+	viewChecks []*plannercore.ViewCheck
+
 	stats *InsertRuntimeStat
 
 	// isLoadData indicates whatever current goroutine is use for generating batch data. LoadData use two goroutines. One for generate batch data,
@@ -1116,6 +1122,30 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
+	if err = table.CheckRowConstraint(e.ctx, table.WritableConstraints(e.Table), row); err != nil {
+		// INSERT IGNORE skips the row which violates the check constraint.
+		if vars.StmtCtx.DupKeyAsWarning && table.ErrCheckConstraintViolated.Equal(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
+	if err = checkViewCheckOptions(e.ctx, e.viewChecks, row); err != nil {
+		// INSERT IGNORE skips the row which is out of the view.
+		if vars.StmtCtx.DupKeyAsWarning && ErrViewCheckFailed.Equal(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
+	if err = e.fkTrigger.checkInsertedRow(ctx, e.ctx, row); err != nil {
+		// INSERT IGNORE skips the row which refers to a nonexistent parent row.
+		if vars.StmtCtx.DupKeyAsWarning && table.ErrNoReferencedRow2.Equal(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
@@ -20,6 +20,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/collate"
@@ -613,3 +614,32 @@ func (s *testSuite) TestIssue24930(c *C) {
     then (select t2.a from t2 where t2.a = t1.a limit 1) else t1.a end a
 	from t1 where t1.a=1 order by a limit 1`).Check(testkit.Rows()) // can return an empty result instead of hanging forever
 }
+
+func (s *testSuite) TestLateralDerivedTable(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values (1), (2), (3), (4)")
+	tk.MustExec("insert into t2 values (1, 10), (1, 20), (1, 30), (1, 40), (2, 5), (2, 15), (3, 7)")
+
+	// top-N per group
+	q1 := "select t1.a, d.b from t1, lateral (select b from t2 where t2.a = t1.a order by b desc limit 2) d"
+	checkApplyPlan(c, tk, q1, 0)
+	tk.MustQuery(q1).Sort().Check(testkit.Rows("1 30", "1 40", "2 15", "2 5", "3 7"))
+	tk.MustExec("set tidb_enable_parallel_apply=true")
+	checkApplyPlan(c, tk, q1, 1)
+	tk.MustQuery(q1).Sort().Check(testkit.Rows("1 30", "1 40", "2 15", "2 5", "3 7"))
+
+	q2 := "select t1.a, d.b from t1 left join lateral (select b from t2 where t2.a = t1.a order by b limit 1) d on true"
+	checkApplyPlan(c, tk, q2, 1)
+	tk.MustQuery(q2).Sort().Check(testkit.Rows("1 10", "2 5", "3 7", "4 <nil>"))
+	tk.MustQuery("select t1.a, d.s from t1 join lateral (select sum(b) s from t2 where t2.a = t1.a) d").Sort().Check(
+		testkit.Rows("1 100", "2 20", "3 7", "4 <nil>"))
+	tk.MustQuery("select t1.a, d.c from t1, lateral (select t1.a * 2 as c) d where d.c > 4").Sort().Check(testkit.Rows("3 6", "4 8"))
+
+	// uncorrelated lateral derived table is built as a normal join
+	tk.MustQuery("select count(*) from t1, lateral (select b from t2) d").Check(testkit.Rows("28"))
+	// the lateral derived table can't refer to the left side of a right join
+	tk.MustGetErrCode("select * from t1 right join lateral (select t1.a) d on true", mysql.ErrBadField)
+}
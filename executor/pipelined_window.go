@@ -25,14 +25,10 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
-type dataInfo struct {
-	chk         *chunk.Chunk
-	remaining   uint64
-	accumulated uint64
-}
-
 // PipelinedWindowExec is the executor for window functions.
 type PipelinedWindowExec struct {
 	baseExecutor
@@ -44,15 +40,15 @@ type PipelinedWindowExec struct {
 	end                *core.FrameBound
 	groupChecker       *vecGroupChecker
 
-	// childResult stores the child chunk. Note that even if remaining is 0, e.rows might still references rows in data[0].chk after returned it to upper executor, since there is no guarantee what the upper executor will do to the returned chunk, it might destroy the data (as in the benchmark test, it reused the chunk to pull data, and it will be chk.Reset(), causing panicking). So dataIdx, accumulated and dropped are added to ensure that chunk will only be returned if there is no row reference.
-	childResult *chunk.Chunk
-	data        []dataInfo
-	dataIdx     int
+	// childResult stores the child chunk. The rows [consumeBegin, consumeBegin+rowToConsume) of it are fetched
+	// by getRowsInPartition but not consumed yet.
+	childResult  *chunk.Chunk
+	consumeBegin int
+	// childColIdxs are the columns of the child copied to the result.
+	childColIdxs []int
 
 	// done indicates the child executor is drained or something unexpected happened.
 	done         bool
-	accumulated  uint64
-	dropped      uint64
 	rowToConsume uint64
 	newPartition bool
 
@@ -62,56 +58,80 @@ type PipelinedWindowExec struct {
 	lastEndRow     uint64
 	stagedStartRow uint64
 	stagedEndRow   uint64
-	rowStart       uint64
 	orderByCols    []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
 
-	// rows keeps rows starting from curStartRow
-	rows                     []chunk.Row
+	// rowBuffer keeps the rows of the current partition starting from min(curRowIdx, lastStartRow, lastEndRow),
+	// which may be spilled to disk. The first row of the current partition is at partitionOffset in rowBuffer.
+	rowBuffer       *windowRowBuffer
+	partitionOffset uint64
+	rowsBuf         []chunk.Row
+	slidingRows     slidingRows
+
 	rowCnt                   uint64
 	whole                    bool
 	isRangeFrame             bool
 	emptyFrame               bool
 	initializedSlidingWindow bool
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
 }
 
 // Close implements the Executor Close interface.
 func (e *PipelinedWindowExec) Close() error {
-	return errors.Trace(e.baseExecutor.Close())
+	var firstErr error
+	if e.rowBuffer != nil {
+		firstErr = e.rowBuffer.close()
+		e.rowBuffer = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+	}
+	e.childResult = nil
+	e.rowsBuf = nil
+	e.groupChecker.reset()
+	if err := e.baseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return errors.Trace(firstErr)
 }
 
 // Open implements the Executor Open interface
 func (e *PipelinedWindowExec) Open(ctx context.Context) (err error) {
+	err = e.baseExecutor.Open(ctx)
+	if err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+
+	e.childResult = newFirstChunk(e.children[0])
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+	e.rowBuffer = newWindowRowBuffer(e.ctx, retTypes(e.children[0]), e.maxChunkSize, e.memTracker, e.diskTracker)
+	e.childColIdxs = windowChildColIdxs(e.Schema(), e.numWindowFuncs)
 	e.rowToConsume = 0
 	e.done = false
-	e.accumulated = 0
-	e.dropped = 0
-	e.data = make([]dataInfo, 0)
-	e.dataIdx = 0
+	e.newPartition = false
+	e.partitionOffset = 0
+	e.rowCnt = 0
 	e.slidingWindowFuncs = make([]aggfuncs.SlidingWindowAggFunc, len(e.windowFuncs))
 	for i, windowFunc := range e.windowFuncs {
 		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
 			e.slidingWindowFuncs[i] = slidingWindowAggFunc
 		}
 	}
-	e.rows = make([]chunk.Row, 0)
-	return e.baseExecutor.Open(ctx)
-}
-
-func (e *PipelinedWindowExec) firstResultChunkNotReady() bool {
-	if !e.done && len(e.data) == 0 {
-		return true
-	}
-	// chunk can't be ready unless, 1. all of the rows in the chunk is filled, 2. e.rows doesn't contain rows in the chunk
-	return len(e.data) > 0 && (e.data[0].remaining != 0 || e.data[0].accumulated > e.dropped)
+	return e.reset()
 }
 
 // Next implements the Executor Next interface.
 func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err error) {
 	chk.Reset()
 
-	for e.firstResultChunkNotReady() {
+	for !chk.IsFull() {
 		// we firstly gathering enough rows and consume them, until we are able to produce.
 		// for unbounded frame, it needs consume the whole partition before being able to produce, in this case
 		// e.p.enoughToProduce will be false until so.
@@ -138,39 +158,33 @@ func (e *PipelinedWindowExec) Next(ctx context.Context, chk *chunk.Chunk) (err e
 					continue
 				}
 				e.newPartition = false
-				e.reset()
+				err = e.reset()
+				if err != nil {
+					return err
+				}
 				if e.rowToConsume == 0 {
 					// no more data
 					break
 				}
 			}
-			e.rowCnt += e.rowToConsume
-			e.rowToConsume = 0
-		}
-
-		// e.p is ready to produce data
-		if len(e.data) > e.dataIdx && e.data[e.dataIdx].remaining != 0 {
-			produced, err := e.produce(e.ctx, e.data[e.dataIdx].chk, e.data[e.dataIdx].remaining)
+			err = e.consumeRows()
 			if err != nil {
 				return err
 			}
-			e.data[e.dataIdx].remaining -= produced
-			if e.data[e.dataIdx].remaining == 0 {
-				e.dataIdx++
-			}
 		}
-	}
-	if len(e.data) > 0 {
-		chk.SwapColumns(e.data[0].chk)
-		e.data = e.data[1:]
-		e.dataIdx--
+
+		// e.p is ready to produce data
+		_, err = e.produce(e.ctx, chk, uint64(chk.RequiredRows()-chk.NumRows()))
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error) {
 	e.newPartition = true
-	if len(e.rows) == 0 {
+	if e.rowBuffer.numRows == 0 {
 		// if getRowsInPartition is called for the first time, we ignore it as a new partition
 		e.newPartition = false
 	}
@@ -179,7 +193,7 @@ func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error
 		var drained, samePartition bool
 		drained, err = e.fetchChild(ctx)
 		if err != nil {
-			err = errors.Trace(err)
+			return errors.Trace(err)
 		}
 		// we return immediately to use a combination of true newPartition but 0 in e.rowToConsume to indicate the data source is drained,
 		if drained {
@@ -196,55 +210,41 @@ func (e *PipelinedWindowExec) getRowsInPartition(ctx context.Context) (err error
 		}
 	}
 	begin, end := e.groupChecker.getNextGroup()
+	e.consumeBegin = begin
 	e.rowToConsume += uint64(end - begin)
-	for i := begin; i < end; i++ {
-		e.rows = append(e.rows, e.childResult.GetRow(i))
-	}
 	return
 }
 
+// consumeRows appends the rows fetched by getRowsInPartition to the current partition.
+func (e *PipelinedWindowExec) consumeRows() error {
+	if e.rowToConsume > 0 {
+		err := e.rowBuffer.append(e.childResult, e.consumeBegin, e.consumeBegin+int(e.rowToConsume))
+		if err != nil {
+			return err
+		}
+	}
+	e.rowCnt += e.rowToConsume
+	e.rowToConsume = 0
+	return nil
+}
+
 func (e *PipelinedWindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	// TODO: reuse chunks
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	mSize := e.childResult.MemoryUsage()
+	err = Next(ctx, e.children[0], e.childResult)
+	e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
-	}
-
-	// TODO: reuse chunks
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
-	}
-	e.accumulated += uint64(numRows)
-	e.data = append(e.data, dataInfo{chk: resultChk, remaining: uint64(numRows), accumulated: e.accumulated})
-
-	e.childResult = childResult
-	return false, nil
+	return e.childResult.NumRows() == 0, nil
 }
 
-func (e *PipelinedWindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
-		}
-	}
-	return nil
+func (e *PipelinedWindowExec) getRow(i uint64) (chunk.Row, error) {
+	return e.rowBuffer.getRow(e.partitionOffset + i)
 }
 
-func (e *PipelinedWindowExec) getRow(i uint64) chunk.Row {
-	return e.rows[i-e.rowStart]
-}
-
-func (e *PipelinedWindowExec) getRows(start, end uint64) []chunk.Row {
-	return e.rows[start-e.rowStart : end-e.rowStart]
+func (e *PipelinedWindowExec) appendRows(dst []chunk.Row, start, end uint64) ([]chunk.Row, error) {
+	return e.rowBuffer.appendRows(dst, e.partitionOffset+start, e.partitionOffset+end)
 }
 
 // finish is called upon a whole partition is consumed
@@ -257,12 +257,19 @@ func (e *PipelinedWindowExec) getStart(ctx sessionctx.Context) (uint64, error) {
 		return 0, nil
 	}
 	if e.isRangeFrame {
+		curRow, err := e.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var start uint64
 		for start = mathutil.MaxUint64(e.lastStartRow, e.stagedStartRow); start < e.rowCnt; start++ {
+			row, err := e.getRow(start)
+			if err != nil {
+				return 0, err
+			}
 			var res int64
-			var err error
 			for i := range e.orderByCols {
-				res, _, err = e.start.CmpFuncs[i](ctx, e.orderByCols[i], e.start.CalcFuncs[i], e.getRow(start), e.getRow(e.curRowIdx))
+				res, _, err = e.start.CmpFuncs[i](ctx, e.orderByCols[i], e.start.CalcFuncs[i], row, curRow)
 				if err != nil {
 					return 0, err
 				}
@@ -297,12 +304,19 @@ func (e *PipelinedWindowExec) getEnd(ctx sessionctx.Context) (uint64, error) {
 		return e.rowCnt, nil
 	}
 	if e.isRangeFrame {
+		curRow, err := e.getRow(e.curRowIdx)
+		if err != nil {
+			return 0, err
+		}
 		var end uint64
 		for end = mathutil.MaxUint64(e.lastEndRow, e.stagedEndRow); end < e.rowCnt; end++ {
+			row, err := e.getRow(end)
+			if err != nil {
+				return 0, err
+			}
 			var res int64
-			var err error
 			for i := range e.orderByCols {
-				res, _, err = e.end.CmpFuncs[i](ctx, e.end.CalcFuncs[i], e.orderByCols[i], e.getRow(e.curRowIdx), e.getRow(end))
+				res, _, err = e.end.CmpFuncs[i](ctx, e.end.CalcFuncs[i], e.orderByCols[i], curRow, row)
 				if err != nil {
 					return 0, err
 				}
@@ -362,6 +376,12 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		if start >= e.rowCnt {
 			start = e.rowCnt
 		}
+		var row chunk.Row
+		row, err = e.getRow(e.curRowIdx)
+		if err != nil {
+			return
+		}
+		chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
 		// if start >= end, we should return a default value, and we reset the frame to empty.
 		if start >= end {
 			for i, wf := range e.windowFuncs {
@@ -379,11 +399,19 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 			}
 		} else {
 			e.emptyFrame = false
+			slidingRowsFetched := false
 			for i, wf := range e.windowFuncs {
 				slidingWindowAggFunc := e.slidingWindowFuncs[i]
 				if e.lastStartRow != start || e.lastEndRow != end {
 					if slidingWindowAggFunc != nil && e.initializedSlidingWindow {
-						err = slidingWindowAggFunc.Slide(ctx, e.getRow, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
+						if !slidingRowsFetched {
+							err = e.slidingRows.fetch(e.appendRows, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow)
+							if err != nil {
+								return
+							}
+							slidingRowsFetched = true
+						}
+						err = slidingWindowAggFunc.Slide(ctx, e.slidingRows.getRow, e.lastStartRow, e.lastEndRow, start-e.lastStartRow, end-e.lastEndRow, e.partialResults[i])
 					} else {
 						// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
 						// whether elements inside deque are out of current window.
@@ -393,7 +421,11 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 						}
 						// TODO(zhifeng): track memory usage here
 						wf.ResetPartialResult(e.partialResults[i])
-						_, err = wf.UpdatePartialResult(ctx, e.getRows(start, end), e.partialResults[i])
+						e.rowsBuf, err = e.appendRows(e.rowsBuf[:0], start, end)
+						if err != nil {
+							return
+						}
+						_, err = wf.UpdatePartialResult(ctx, e.rowsBuf, e.partialResults[i])
 					}
 				}
 				if err != nil {
@@ -413,12 +445,7 @@ func (e *PipelinedWindowExec) produce(ctx sessionctx.Context, chk *chunk.Chunk,
 		remained--
 	}
 	extend := mathutil.MinUint64Val(e.curRowIdx, e.lastEndRow, e.lastStartRow)
-	if extend > e.rowStart {
-		numDrop := extend - e.rowStart
-		e.dropped += numDrop
-		e.rows = e.rows[numDrop:]
-		e.rowStart = extend
-	}
+	err = e.rowBuffer.release(e.partitionOffset + extend)
 	return
 }
 
@@ -441,7 +468,7 @@ func (e *PipelinedWindowExec) enoughToProduce(ctx sessionctx.Context) (enough bo
 }
 
 // reset resets the processor
-func (e *PipelinedWindowExec) reset() {
+func (e *PipelinedWindowExec) reset() error {
 	e.lastStartRow = 0
 	e.lastEndRow = 0
 	e.stagedStartRow = 0
@@ -449,13 +476,11 @@ func (e *PipelinedWindowExec) reset() {
 	e.emptyFrame = false
 	e.curRowIdx = 0
 	e.whole = false
-	numDrop := e.rowCnt - e.rowStart
-	e.dropped += numDrop
-	e.rows = e.rows[numDrop:]
-	e.rowStart = 0
+	e.partitionOffset += e.rowCnt
 	e.rowCnt = 0
 	e.initializedSlidingWindow = false
 	for i, windowFunc := range e.windowFuncs {
 		windowFunc.ResetPartialResult(e.partialResults[i])
 	}
+	return e.rowBuffer.release(e.partitionOffset)
 }
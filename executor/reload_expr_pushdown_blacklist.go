@@ -232,6 +232,10 @@ var funcName2Alias = map[string]string{
 	"ord":                        ast.Ord,
 	"position":                   ast.Position,
 	"quote":                      ast.Quote,
+	"regexp_instr":               ast.RegexpInStr,
+	"regexp_like":                ast.RegexpLike,
+	"regexp_replace":             ast.RegexpReplace,
+	"regexp_substr":              ast.RegexpSubstr,
 	"repeat":                     ast.Repeat,
 	"replace":                    ast.Replace,
 	"reverse":                    ast.Reverse,
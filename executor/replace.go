@@ -89,6 +89,10 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 	if err != nil {
 		return false, err
 	}
+	err = e.fkTrigger.onParentWrite(ctx, e.ctx, oldRow, nil)
+	if err != nil {
+		return false, err
+	}
 	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return false, nil
 }
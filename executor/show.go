@@ -1006,6 +1006,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constraint := range tableInfo.Constraints {
+		if constraint.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constraint.Name.O, sqlMode), constraint.ExprString))
+		if !constraint.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	buf.WriteString(") ENGINE=InnoDB")
@@ -1067,7 +1077,11 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	}
 
 	if tableInfo.TempTableType == model.TempTableGlobal {
-		fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
+		if tableInfo.TempTablePreserveRows {
+			fmt.Fprintf(buf, " ON COMMIT PRESERVE ROWS")
+		} else {
+			fmt.Fprintf(buf, " ON COMMIT DELETE ROWS")
+		}
 	}
 
 	if tableInfo.PlacementPolicyRef != nil {
@@ -1205,6 +1219,9 @@ func fetchShowCreateTable4View(ctx sessionctx.Context, tb *model.TableInfo, buf
 		}
 	}
 	fmt.Fprintf(buf, ") AS %s", tb.View.SelectStmt)
+	if checkOption := tb.View.GetCheckOption(); checkOption != model.CheckOptionNone {
+		fmt.Fprintf(buf, " WITH %s CHECK OPTION", checkOption.String())
+	}
 }
 
 func appendDirectPlacementInfo(directPlacementOpts *model.PlacementSettings, buf *bytes.Buffer) {
@@ -1237,6 +1254,22 @@ func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer,
 		fmt.Fprintf(buf, "\nPARTITIONS %d", partitionInfo.Num)
 		return
 	}
+	if partitionInfo.Type == model.PartitionTypeKey {
+		buf.WriteString("\nPARTITION BY ")
+		if partitionInfo.Linear {
+			buf.WriteString("LINEAR ")
+		}
+		buf.WriteString("KEY (")
+		for i, col := range partitionInfo.Columns {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(stringutil.Escape(col.O, sqlMode))
+		}
+		buf.WriteString(")")
+		fmt.Fprintf(buf, "\nPARTITIONS %d", partitionInfo.Num)
+		return
+	}
 	// this if statement takes care of range columns case
 	if partitionInfo.Columns != nil && partitionInfo.Type == model.PartitionTypeRange {
 		buf.WriteString("\nPARTITION BY RANGE COLUMNS(")
@@ -87,6 +87,9 @@ func (e *ShowExec) appendTableForStatsExtended(dbName string, tbl *model.TableIn
 		case ast.StatsTypeCardinality:
 			statsType = "cardinality"
 			statsVal = item.StringVals
+		case ast.StatsTypeMCV:
+			statsType = "mcv"
+			statsVal = item.StringVals
 		}
 		e.appendRow([]interface{}{
 			dbName,
@@ -60,6 +60,10 @@ type UpdateExec struct {
 	virtualAssignmentsOffset  int
 	drained                   bool
 	memTracker                *memory.Tracker
+	// fkTriggers execute the foreign key checks and cascades of the updated tables, keyed by the table ID.
+	fkTriggers map[int64]*fkTriggerExec
+	// viewChecks are the WITH CHECK OPTION conditions of the views which the tables are updated through, keyed by the table ID.
+	viewChecks map[int64][]*plannercore.ViewCheck
 
 	stats *updateRuntimeStats
 
@@ -189,7 +193,7 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		flags := bAssignFlag[content.Start:content.End]
 
 		// Update row
-		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
+		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker, e.fkTriggers[content.TblID], e.viewChecks[content.TblID])
 		if err1 == nil {
 			e.updatedRowKeys[content.Start].Set(handle, changed)
 			continue
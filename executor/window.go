@@ -25,6 +25,8 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
@@ -36,45 +38,96 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// rowBuffer stores the rows of the current partition, which may be spilled to disk.
+	rowBuffer *windowRowBuffer
+	// partition is the current partition in rowBuffer.
+	partition windowPartition
+	// emitted is the number of rows of the current partition which have been returned.
+	emitted uint64
+	// childColIdxs are the columns of the child copied to the result.
+	childColIdxs []int
+	rowsBuf      []chunk.Row
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+
+	e.childResult = newFirstChunk(e.children[0])
+	e.memTracker.Consume(e.childResult.MemoryUsage())
+	e.rowBuffer = newWindowRowBuffer(e.ctx, retTypes(e.children[0]), e.maxChunkSize, e.memTracker, e.diskTracker)
+	e.partition = windowPartition{buffer: e.rowBuffer}
+	e.emitted = 0
+	e.executed = false
+	e.childColIdxs = windowChildColIdxs(e.Schema(), e.numWindowFuncs)
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
-	return errors.Trace(e.baseExecutor.Close())
+	var firstErr error
+	if e.rowBuffer != nil {
+		firstErr = e.rowBuffer.close()
+		e.rowBuffer = nil
+		e.partition = windowPartition{}
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.childResult.MemoryUsage())
+	}
+	e.childResult = nil
+	e.rowsBuf = nil
+	e.groupChecker.reset()
+	e.processor.resetPartialResult()
+	if err := e.baseExecutor.Close(); firstErr == nil {
+		firstErr = err
+	}
+	return errors.Trace(firstErr)
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
+	for !chk.IsFull() {
+		if e.emitted == e.partition.numRows {
+			if e.executed {
+				return nil
+			}
+			if err := e.fetchNextPartition(ctx); err != nil {
+				e.executed = true
+				return err
+			}
+			if e.partition.numRows == 0 {
+				return nil
+			}
+		}
+		if err := e.appendResult(chk); err != nil {
 			e.executed = true
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
-	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
-}
-
-func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
+// fetchNextPartition reads the rows of the next partition into rowBuffer, and releases the rows of the previous one.
+func (e *WindowExec) fetchNextPartition(ctx context.Context) error {
+	e.partition.offset += e.partition.numRows
+	e.partition.numRows = 0
+	e.emitted = 0
+	if err := e.rowBuffer.release(e.partition.offset); err != nil {
+		return err
+	}
 	if e.groupChecker.isExhausted() {
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
@@ -82,116 +135,82 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return nil
 		}
 		_, err = e.groupChecker.splitIntoGroups(e.childResult)
 		if err != nil {
 			return errors.Trace(err)
 		}
 	}
-	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
-	}
-
-	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
-		meetLastGroup = false
+	for {
+		begin, end := e.groupChecker.getNextGroup()
+		if err := e.rowBuffer.append(e.childResult, begin, end); err != nil {
+			return err
+		}
+		e.partition.numRows += uint64(end - begin)
+		if end < e.childResult.NumRows() {
+			break
+		}
+		// The partition may continue in the next child chunk.
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			break
 		}
-
 		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
 		if err != nil {
 			return errors.Trace(err)
 		}
-
-		if isFirstGroupSameAsPrev {
-			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
-			}
-			meetLastGroup = end == e.childResult.NumRows()
+		if !isFirstGroupSameAsPrev {
+			break
 		}
 	}
-	return e.consumeGroupRows(groupRows)
+	return e.processor.consumeGroupRows(e.ctx, &e.partition)
 }
 
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
-		return nil
+// appendResult appends the next rows of the current partition and their window function results to chk.
+func (e *WindowExec) appendResult(chk *chunk.Chunk) (err error) {
+	numRows := mathutil.Min(int(e.partition.numRows-e.emitted), chk.RequiredRows()-chk.NumRows())
+	e.rowsBuf, err = e.partition.appendRows(e.rowsBuf[:0], e.emitted, e.emitted+uint64(numRows))
+	if err != nil {
+		return err
 	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
-
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
-		}
+	for _, row := range e.rowsBuf {
+		chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
+	}
+	err = e.processor.appendResult2Chunk(e.ctx, &e.partition, chk, numRows)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.emitted += uint64(numRows)
+	if e.emitted == e.partition.numRows {
+		e.processor.resetPartialResult()
 	}
 	return nil
 }
 
 func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	mSize := e.childResult.MemoryUsage()
+	err = Next(ctx, e.children[0], e.childResult)
+	e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
-	}
-
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
-	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
-
-	e.childResult = childResult
-	return false, nil
-}
-
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
-		}
-	}
-	return nil
+	return e.childResult.NumRows() == 0, nil
 }
 
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
-	// appendResult2Chunk appends the final results to chunk.
-	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error
+	// appendResult2Chunk appends the final results of the next `remained` rows of the partition to chunk.
+	// It is called when all the rows of current partition are consumed.
+	appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -199,32 +218,40 @@ type windowProcessor interface {
 type aggWindowProcessor struct {
 	windowFuncs    []aggfuncs.AggFunc
 	partialResults []aggfuncs.PartialResult
+	rowsBuf        []chunk.Row
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) (err error) {
+	// Consume the rows batch by batch, since the partition may be too large to be kept in memory.
+	batchSize := uint64(ctx.GetSessionVars().MaxChunkSize)
+	for start := uint64(0); start < rows.numRows; start += batchSize {
+		p.rowsBuf, err = rows.appendRows(p.rowsBuf[:0], start, mathutil.MinUint64(start+batchSize, rows.numRows))
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for i, windowFunc := range p.windowFuncs {
+			// @todo Add memory trace
+			_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuf, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -239,6 +266,8 @@ type rowFrameWindowProcessor struct {
 	start          *core.FrameBound
 	end            *core.FrameBound
 	curRowIdx      uint64
+	rowsBuf        []chunk.Row
+	slidingRows    slidingRows
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
@@ -287,12 +316,12 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
+	numRows := rows.numRows
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -316,20 +345,26 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		remained--
 		shiftStart = start - lastStart
 		shiftEnd = end - lastEnd
+		slidingRowsFetched := false
 		if start >= end {
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					if !slidingRowsFetched {
+						err = p.slidingRows.fetch(rows.appendRows, lastStart, lastEnd, shiftStart, shiftEnd)
+						if err != nil {
+							return err
+						}
+						slidingRowsFetched = true
+					}
+					err = slidingWindowAggFunc.Slide(ctx, p.slidingRows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -338,9 +373,14 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				if !slidingRowsFetched {
+					err = p.slidingRows.fetch(rows.appendRows, lastStart, lastEnd, shiftStart, shiftEnd)
+					if err != nil {
+						return err
+					}
+					slidingRowsFetched = true
+				}
+				err = slidingWindowAggFunc.Slide(ctx, p.slidingRows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
 				// whether elements inside deque are out of current window.
@@ -348,14 +388,18 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				p.rowsBuf, err = rows.appendRows(p.rowsBuf[:0], start, end)
+				if err != nil {
+					return err
+				}
+				_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuf, p.partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -368,7 +412,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -386,18 +430,30 @@ type rangeFrameWindowProcessor struct {
 	orderByCols     []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
+	rowsBuf           []chunk.Row
+	slidingRows       slidingRows
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	numRows := rows.numRows
+	if p.lastStartOffset >= numRows {
+		return p.lastStartOffset, nil
+	}
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
+		row, err := rows.getRow(p.lastStartOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], row, curRow)
 			if err != nil {
 				return 0, err
 			}
@@ -414,16 +470,26 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows *windowPartition) (uint64, error) {
+	numRows := rows.numRows
 	if p.end.UnBounded {
 		return numRows, nil
 	}
+	if p.lastEndOffset >= numRows {
+		return p.lastEndOffset, nil
+	}
+	curRow, err := rows.getRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
+		row, err := rows.getRow(p.lastEndOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], curRow, row)
 			if err != nil {
 				return 0, err
 			}
@@ -440,7 +506,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *windowPartition, chk *chunk.Chunk, remained int) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -460,30 +526,36 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
 		shiftEnd = end - lastEnd
+		slidingRowsFetched := false
 		if start >= end {
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					if !slidingRowsFetched {
+						err = p.slidingRows.fetch(rows.appendRows, lastStart, lastEnd, shiftStart, shiftEnd)
+						if err != nil {
+							return err
+						}
+						slidingRowsFetched = true
+					}
+					err = slidingWindowAggFunc.Slide(ctx, p.slidingRows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -492,21 +564,30 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				if !slidingRowsFetched {
+					err = p.slidingRows.fetch(rows.appendRows, lastStart, lastEnd, shiftStart, shiftEnd)
+					if err != nil {
+						return err
+					}
+					slidingRowsFetched = true
+				}
+				err = slidingWindowAggFunc.Slide(ctx, p.slidingRows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				p.rowsBuf, err = rows.appendRows(p.rowsBuf[:0], start, end)
+				if err != nil {
+					return err
+				}
+				_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuf, p.partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -519,11 +600,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *windowPartition) error {
+	return nil
 }
 
 func (p *rangeFrameWindowProcessor) resetPartialResult() {
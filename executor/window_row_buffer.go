@@ -0,0 +1,282 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// windowRowBuffer buffers the rows of the partitions processed by the window executors.
+// A row is addressed by its offset, which is the number of rows appended before it.
+// The leading rows are released once they are no longer referenced by any frame.
+// If the memory quota of the query is exceeded, windowSpillDiskAction sets the buffer to spill mode,
+// then the buffered rows and the rows appended later are written into a chunk.ListInDisk,
+// until all the rows in the buffer are released.
+type windowRowBuffer struct {
+	fieldTypes []*types.FieldType
+	chunkSize  int
+
+	// memChunks are the chunks kept in memory, the offset of the first row of memChunks[i] is memOffsets[i].
+	memChunks  []*chunk.Chunk
+	memOffsets []uint64
+	// listInDisk stores the spilled rows, which are in front of all the rows in memChunks.
+	// The offset of the first row of the i-th chunk in listInDisk is diskOffsets[i].
+	listInDisk  *chunk.ListInDisk
+	diskOffsets []uint64
+
+	// numRows is the number of rows appended into the buffer.
+	numRows uint64
+	// released is the offset before which all the rows have been released.
+	released uint64
+
+	// inSpillMode is set by windowSpillDiskAction, and the rows are spilled in the next call of the buffer,
+	// so the buffer is only accessed by the goroutine of the executor.
+	inSpillMode uint32
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+func newWindowRowBuffer(sctx sessionctx.Context, fieldTypes []*types.FieldType, chunkSize int, memTracker *memory.Tracker, diskTracker *disk.Tracker) *windowRowBuffer {
+	b := &windowRowBuffer{
+		fieldTypes:  fieldTypes,
+		chunkSize:   chunkSize,
+		memTracker:  memory.NewTracker(memory.LabelForRowChunks, -1),
+		diskTracker: diskTracker,
+	}
+	b.memTracker.AttachTo(memTracker)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		failpoint.Inject("testWindowRowBufferSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				// Spill all the rows except the last chunk to disk.
+				b.inSpillMode = 1
+			}
+		})
+		sctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(&windowSpillDiskAction{buffer: b})
+	}
+	return b
+}
+
+// append appends the rows [begin, end) of src into the buffer.
+func (b *windowRowBuffer) append(src *chunk.Chunk, begin, end int) error {
+	for begin < end {
+		var last *chunk.Chunk
+		if n := len(b.memChunks); n > 0 && b.memChunks[n-1].NumRows() < b.chunkSize {
+			last = b.memChunks[n-1]
+		} else {
+			last = chunk.New(b.fieldTypes, b.chunkSize, b.chunkSize)
+			b.memChunks = append(b.memChunks, last)
+			b.memOffsets = append(b.memOffsets, b.numRows)
+			b.memTracker.Consume(last.MemoryUsage())
+		}
+		num := mathutil.Min(end-begin, b.chunkSize-last.NumRows())
+		oldMemUsage := last.MemoryUsage()
+		last.Append(src, begin, begin+num)
+		b.memTracker.Consume(last.MemoryUsage() - oldMemUsage)
+		begin += num
+		b.numRows += uint64(num)
+	}
+	return b.spillIfNeeded()
+}
+
+// spillIfNeeded writes the chunks in memory into disk if the buffer is in spill mode.
+// The last chunk is kept in memory until it is full, to avoid writing small chunks.
+func (b *windowRowBuffer) spillIfNeeded() error {
+	if atomic.LoadUint32(&b.inSpillMode) == 0 {
+		return nil
+	}
+	numSpilled := len(b.memChunks)
+	if numSpilled > 0 && b.memChunks[numSpilled-1].NumRows() < b.chunkSize {
+		numSpilled--
+	}
+	if numSpilled == 0 {
+		return nil
+	}
+	if b.listInDisk == nil {
+		b.listInDisk = chunk.NewListInDisk(b.fieldTypes)
+		b.listInDisk.GetDiskTracker().AttachTo(b.diskTracker)
+	}
+	for i := 0; i < numSpilled; i++ {
+		if err := b.listInDisk.Add(b.memChunks[i]); err != nil {
+			return err
+		}
+		b.diskOffsets = append(b.diskOffsets, b.memOffsets[i])
+		b.memTracker.Consume(-b.memChunks[i].MemoryUsage())
+		b.memChunks[i] = nil
+	}
+	b.memChunks = b.memChunks[numSpilled:]
+	b.memOffsets = b.memOffsets[numSpilled:]
+	return nil
+}
+
+// getRow gets the row at the offset idx, which must not be released.
+func (b *windowRowBuffer) getRow(idx uint64) (chunk.Row, error) {
+	if err := b.spillIfNeeded(); err != nil {
+		return chunk.Row{}, err
+	}
+	if len(b.memOffsets) > 0 && idx >= b.memOffsets[0] {
+		i := sort.Search(len(b.memOffsets), func(i int) bool { return b.memOffsets[i] > idx }) - 1
+		return b.memChunks[i].GetRow(int(idx - b.memOffsets[i])), nil
+	}
+	i := sort.Search(len(b.diskOffsets), func(i int) bool { return b.diskOffsets[i] > idx }) - 1
+	return b.listInDisk.GetRow(chunk.RowPtr{ChkIdx: uint32(i), RowIdx: uint32(idx - b.diskOffsets[i])})
+}
+
+// appendRows appends the rows in [start, end) to dst.
+func (b *windowRowBuffer) appendRows(dst []chunk.Row, start, end uint64) ([]chunk.Row, error) {
+	for idx := start; idx < end; idx++ {
+		row, err := b.getRow(idx)
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, row)
+	}
+	return dst, nil
+}
+
+// release releases the rows before the offset idx.
+func (b *windowRowBuffer) release(idx uint64) error {
+	if idx <= b.released {
+		return nil
+	}
+	b.released = idx
+	numReleased := 0
+	for ; numReleased < len(b.memChunks); numReleased++ {
+		chk := b.memChunks[numReleased]
+		if b.memOffsets[numReleased]+uint64(chk.NumRows()) > idx {
+			break
+		}
+		b.memTracker.Consume(-chk.MemoryUsage())
+		b.memChunks[numReleased] = nil
+	}
+	b.memChunks = b.memChunks[numReleased:]
+	b.memOffsets = b.memOffsets[numReleased:]
+	if b.released < b.numRows || b.listInDisk == nil {
+		return nil
+	}
+	// All the spilled rows are released, so the following rows can be kept in memory again.
+	err := b.listInDisk.Close()
+	b.listInDisk = nil
+	b.diskOffsets = b.diskOffsets[:0]
+	atomic.StoreUint32(&b.inSpillMode, 0)
+	return err
+}
+
+// close releases all the resources of the buffer.
+func (b *windowRowBuffer) close() error {
+	b.memTracker.Consume(-b.memTracker.BytesConsumed())
+	b.memChunks, b.memOffsets = nil, nil
+	if b.listInDisk == nil {
+		return nil
+	}
+	err := b.listInDisk.Close()
+	b.listInDisk, b.diskOffsets = nil, nil
+	return err
+}
+
+// windowSpillDiskAction implements memory.ActionOnExceed for the window executors.
+// If the memory quota of a query is exceeded, windowSpillDiskAction.Action is triggered.
+type windowSpillDiskAction struct {
+	memory.BaseOOMAction
+	buffer *windowRowBuffer
+}
+
+// Action sets the windowRowBuffer to spill mode, and if it is already in spill mode, calls its fallbackAction.
+func (a *windowSpillDiskAction) Action(t *memory.Tracker) {
+	if atomic.LoadUint32(&a.buffer.inSpillMode) == 0 && a.buffer.memTracker.BytesConsumed() > 0 {
+		logutil.BgLogger().Info("memory exceeds quota, set window row buffer to spill mode",
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		atomic.StoreUint32(&a.buffer.inSpillMode, 1)
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority get the priority of the Action
+func (a *windowSpillDiskAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (a *windowSpillDiskAction) SetLogHook(hook func(uint64)) {}
+
+// windowPartition is a partition stored in a windowRowBuffer, the i-th row of the partition is at offset+i.
+type windowPartition struct {
+	buffer  *windowRowBuffer
+	offset  uint64
+	numRows uint64
+}
+
+func (p *windowPartition) getRow(idx uint64) (chunk.Row, error) {
+	return p.buffer.getRow(p.offset + idx)
+}
+
+func (p *windowPartition) appendRows(dst []chunk.Row, start, end uint64) ([]chunk.Row, error) {
+	return p.buffer.appendRows(dst, p.offset+start, p.offset+end)
+}
+
+// slidingRows holds the rows sliding out of and into the frame for SlidingWindowAggFunc.Slide.
+// They are fetched in advance because the rows may be read from disk, which can fail.
+type slidingRows struct {
+	lastStart uint64
+	lastEnd   uint64
+	shiftEnd  uint64
+	outRows   []chunk.Row
+	inRows    []chunk.Row
+}
+
+// fetch fetches the rows in [lastStart, lastStart+shiftStart) and [lastEnd, lastEnd+shiftEnd).
+func (s *slidingRows) fetch(appendRows func([]chunk.Row, uint64, uint64) ([]chunk.Row, error), lastStart, lastEnd, shiftStart, shiftEnd uint64) (err error) {
+	s.lastStart, s.lastEnd, s.shiftEnd = lastStart, lastEnd, shiftEnd
+	s.outRows, err = appendRows(s.outRows[:0], lastStart, lastStart+shiftStart)
+	if err != nil {
+		return err
+	}
+	s.inRows, err = appendRows(s.inRows[:0], lastEnd, lastEnd+shiftEnd)
+	return err
+}
+
+// getRow is passed to SlidingWindowAggFunc.Slide to get the fetched rows.
+func (s *slidingRows) getRow(idx uint64) chunk.Row {
+	if idx >= s.lastEnd && idx < s.lastEnd+s.shiftEnd {
+		return s.inRows[idx-s.lastEnd]
+	}
+	return s.outRows[idx-s.lastStart]
+}
+
+// windowChildColIdxs returns the offsets in the child of the columns copied to the output of the window executors.
+func windowChildColIdxs(schema *expression.Schema, numWindowFuncs int) []int {
+	columns := schema.Columns[:schema.Len()-numWindowFuncs]
+	colIdxs := make([]int, 0, len(columns))
+	for _, col := range columns {
+		colIdxs = append(colIdxs, col.Index)
+	}
+	return colIdxs
+}
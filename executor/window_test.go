@@ -15,9 +15,14 @@
 package executor_test
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -471,3 +476,80 @@ func (s *testSuite7) TestIssue24264(c *C) {
 		"8297270320597030697",
 		"<nil>"))
 }
+
+func (s *testSerialSuite1) TestWindowInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testWindowRowBufferSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testWindowRowBufferSpill"), IsNil)
+	}()
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	sm := &mockSessionManager1{
+		PS: make([]*util.ProcessInfo, 0),
+	}
+	tk.Se.SetSessionManager(sm)
+	s.domain.ExpensiveQueryHandle().SetSessionManager(sm)
+
+	tk.MustExec("set @@tidb_mem_quota_query=1;")
+	tk.MustExec("set @@tidb_max_chunk_size=32;")
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	defer tk.MustExec("set @@tidb_enable_pipelined_window_function=1;")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(p int, o int, v int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	for p := 0; p < 3; p++ {
+		for o := 0; o < 200; o++ {
+			if p > 0 || o > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(fmt.Sprintf("(%v, %v, %v)", p, o, o))
+		}
+	}
+	tk.MustExec(buf.String())
+
+	sumRange := func(begin, end int) int {
+		if begin < 0 {
+			begin = 0
+		}
+		if end > 199 {
+			end = 199
+		}
+		return (begin + end) * (end - begin + 1) / 2
+	}
+	frameRows := make([]string, 0, 600)
+	wholeRows := make([]string, 0, 600)
+	for p := 0; p < 3; p++ {
+		for o := 0; o < 200; o++ {
+			frameRows = append(frameRows, fmt.Sprintf("%v %v %v %v %v", p, o, o+1, sumRange(o-2, o+2), sumRange(o-3, o)))
+			wholeRows = append(wholeRows, fmt.Sprintf("%v %v 200 19900", p, o))
+		}
+	}
+	frameSQL := "select p, o, row_number() over w, sum(v) over (w rows between 2 preceding and 2 following), " +
+		"sum(v) over (w range between 3 preceding and current row) from t window w as (partition by p order by o) order by p, o"
+	wholeSQL := "select p, o, count(*) over (partition by p), sum(v) over (partition by p) from t order by p, o"
+	for _, pipelined := range []int{0, 1} {
+		tk.MustExec(fmt.Sprintf("set @@tidb_enable_pipelined_window_function = %v", pipelined))
+		tk.MustQuery(frameSQL).Check(testkit.Rows(frameRows...))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+		tk.MustQuery(wholeSQL).Check(testkit.Rows(wholeRows...))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+
+		rows := tk.MustQuery("explain analyze " + frameSQL).Rows()
+		for _, row := range rows {
+			line := fmt.Sprintf("%v", row)
+			disk := fmt.Sprintf("%v", row[len(row)-1])
+			if strings.Contains(line, "Window") {
+				c.Assert(strings.Contains(disk, "0 Bytes") || strings.Contains(disk, "N/A"), IsFalse, Commentf("%v", line))
+			}
+		}
+	}
+}
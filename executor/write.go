@@ -28,10 +28,12 @@ import (
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/memory"
 )
 
@@ -50,7 +52,7 @@ var (
 //     1. changed (bool) : does the update really change the row values. e.g. update set i = 1 where i = 1;
 //     2. err (error) : error in the update.
 func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, modified []bool, t table.Table,
-	onDup bool, memTracker *memory.Tracker) (bool, error) {
+	onDup bool, memTracker *memory.Tracker, fkTrigger *fkTriggerExec, viewChecks []*plannercore.ViewCheck) (bool, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("executor.updateRecord", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -171,7 +173,35 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 	}
 
-	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
+	// 5. Check the new row against the check constraints.
+	if err = table.CheckRowConstraint(sctx, table.WritableConstraints(t), newData); err != nil {
+		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`, the row violates the constraint is skipped.
+		if sc.DupKeyAsWarning && table.ErrCheckConstraintViolated.Equal(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+	if err = checkViewCheckOptions(sctx, viewChecks, newData); err != nil {
+		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`, the row out of the view is skipped.
+		if sc.DupKeyAsWarning && ErrViewCheckFailed.Equal(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// 6. Check the parent rows referred by the new row exist.
+	if err = fkTrigger.checkUpdatedRow(ctx, sctx, oldData, newData); err != nil {
+		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`, the row refers to a nonexistent parent row is skipped.
+		if sc.DupKeyAsWarning && table.ErrNoReferencedRow2.Equal(err) {
+			sc.AppendWarning(err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	// 7. If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
 		// we use the staging buffer so that we don't need to precheck the existence of handle or unique keys by sending
@@ -211,6 +241,11 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 
 	}
+
+	// 8. Check and cascade the child rows which refer to the old row.
+	if err = fkTrigger.onParentWrite(ctx, sctx, oldData, newData); err != nil {
+		return false, err
+	}
 	if onDup {
 		sc.AddAffectedRows(2)
 	} else {
@@ -222,6 +257,25 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 	return true, nil
 }
 
+// checkViewCheckOptions checks the row written through a view satisfies the WITH CHECK OPTION conditions, that is,
+// the row is still visible through the view.
+func checkViewCheckOptions(sctx sessionctx.Context, checks []*plannercore.ViewCheck, row []types.Datum) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, check := range checks {
+		ok, isNull, err := check.Expr.EvalInt(sctx, r)
+		if err != nil {
+			return err
+		}
+		if ok == 0 || isNull {
+			return ErrViewCheckFailed.FastGenByArgs(check.DBName.O, check.ViewName.O)
+		}
+	}
+	return nil
+}
+
 func rebaseAutoRandomValue(ctx context.Context, sctx sessionctx.Context, t table.Table, newData *types.Datum, col *table.Column) error {
 	tableInfo := t.Meta()
 	if !tableInfo.ContainsAutoRandomBits() {
@@ -25,9 +25,10 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
-	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -305,11 +306,12 @@ func (s *testSuite) TestInsert(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
 
 	tk.MustExec("create view v as select * from t")
-	_, err = tk.Exec("insert into v values(1,2)")
-	c.Assert(err.Error(), Equals, "insert into view v is not supported now.")
-	_, err = tk.Exec("replace into v values(1,2)")
-	c.Assert(err.Error(), Equals, "replace into view v is not supported now.")
-	tk.MustExec("drop view v")
+	tk.MustExec("insert into v values(1,2)")
+	tk.MustExec("replace into v values(3,4)")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("1 1", "1 2", "3 4"))
+	tk.MustExec("create view v1 as select a + 1 as a, b from t")
+	tk.MustGetErrCode("insert into v1 values(1,2)", mysql.ErrNonInsertableTable)
+	tk.MustExec("drop view v, v1")
 
 	tk.MustExec("create sequence seq")
 	_, err = tk.Exec("insert into seq values()")
@@ -1600,8 +1602,8 @@ func (s *testSuite8) TestUpdate(c *C) {
 	tk.MustExec("set @@sql_mode=@orig_sql_mode;")
 
 	tk.MustExec("create view v as select * from t")
-	_, err = tk.Exec("update v set a = '2000-11-11'")
-	c.Assert(err.Error(), Equals, core.ErrViewInvalid.GenWithStackByArgs("test", "v").Error())
+	tk.MustExec("update v set a = '2000-11-11'")
+	tk.MustQuery("select a from t").Check(testkit.Rows("2000-11-11 00:00:00"))
 	tk.MustExec("drop view v")
 
 	tk.MustExec("create sequence seq")
@@ -1905,8 +1907,9 @@ func (s *testSuite) TestDelete(c *C) {
 	tk.CheckExecResult(1, 0)
 
 	tk.MustExec("create view v as select * from delete_test")
-	_, err = tk.Exec("delete from v where name = 'aaa'")
-	c.Assert(err.Error(), Equals, core.ErrViewInvalid.GenWithStackByArgs("test", "v").Error())
+	tk.MustExec("insert into delete_test values (3, 'aaa')")
+	tk.MustExec("delete from v where name = 'aaa'")
+	tk.CheckExecResult(1, 0)
 	tk.MustExec("drop view v")
 
 	tk.MustExec("create sequence seq")
@@ -4087,6 +4090,124 @@ func (s *testSuite) TestIssue21232(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("a", "b"))
 }
 
+func (s *testSuite) TestWriteThroughView(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	// The views are defined by the current user, who must have the privileges on the base table.
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("drop view if exists v, v1, v2, v3, v4, v5, v6")
+	tk.MustExec("create table t(a int primary key, b int, c int default 10)")
+	tk.MustExec("create view v(x, y) as select a, b from t where b > 0")
+
+	// The columns which are not in the view are filled with the default values.
+	tk.MustExec("insert into v values(1, 1), (2, 2)")
+	tk.MustExec("insert into v(y, x) values(-3, 3)")
+	tk.MustExec("insert into v set x = 4, y = 4")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1 10", "2 2 10", "3 -3 10", "4 4 10"))
+	tk.MustGetErrCode("insert into v(c) values(1)", mysql.ErrBadField)
+	tk.MustGetErrCode("update v set c = 1", mysql.ErrBadField)
+
+	// Only the rows visible through the view are updated and deleted.
+	tk.MustExec("update v set y = y + 10 where x < 4")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 11 10", "2 12 10", "3 -3 10", "4 4 10"))
+	tk.MustExec("delete from v where y > 10 order by x limit 1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 12 10", "3 -3 10", "4 4 10"))
+	tk.MustExec("delete from v where x = 3")
+	tk.CheckExecResult(0, 0)
+	tk.MustExec("insert into v values(2, 1) on duplicate key update y = values(y) + 1")
+	tk.MustQuery("select b from t where a = 2").Check(testkit.Rows("2"))
+	tk.MustExec("update v as vv set vv.y = 5 where exists (select 1 from t where t.a = vv.x + 1)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 5 10", "3 -3 10", "4 4 10"))
+
+	// The derived columns can't be written.
+	tk.MustExec("create view v1 as select a, b + 1 as b from t")
+	tk.MustGetErrCode("update v1 set b = 1", mysql.ErrNonupdateableColumn)
+	tk.MustGetErrCode("insert into v1(a) values(1)", mysql.ErrNonInsertableTable)
+	tk.MustExec("update v1 set a = a + 10 where b = 6")
+	tk.MustQuery("select * from t").Check(testkit.Rows("3 -3 10", "4 4 10", "12 5 10"))
+	tk.MustExec("delete from v1 where b = 5")
+	tk.MustQuery("select * from t").Check(testkit.Rows("3 -3 10", "12 5 10"))
+	tk.MustExec("create view v2 as select count(*) as cnt from t")
+	tk.MustGetErrCode("update v2 set cnt = 1", mysql.ErrNonUpdatableTable)
+	tk.MustGetErrCode("delete from v2", mysql.ErrNonUpdatableTable)
+	tk.MustGetErrCode("insert into v2 values(1)", mysql.ErrNonInsertableTable)
+	tk.MustGetErrCode("create view v3 as select distinct a from t with check option", mysql.ErrViewNonupdCheck)
+
+	// WITH LOCAL CHECK OPTION checks the conditions of the view and the underlying views with check options.
+	tk.MustExec("truncate table t")
+	tk.MustExec("create view v3 as select * from t where b < 10 with local check option")
+	tk.MustExec("create view v4 as select * from v3 where b > -10")
+	tk.MustExec("create view v5 as select * from v3 where b > -10 with local check option")
+	tk.MustExec("insert into v3 values(1, 1, 1)")
+	tk.MustGetErrMsg("insert into v3 values(2, 20, 1)", "[executor:1369]CHECK OPTION failed 'test.v3'")
+	tk.MustGetErrMsg("update v3 set b = 20", "[executor:1369]CHECK OPTION failed 'test.v3'")
+	tk.MustExec("insert ignore into v3 values(2, 20, 1)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1369 CHECK OPTION failed 'test.v3'"))
+	tk.MustExec("update ignore v3 set b = 20")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1369 CHECK OPTION failed 'test.v3'"))
+	tk.MustExec("insert into v4(a, b) values(3, -1)")
+	tk.MustExec("insert into v4(a, b) values(4, -20)")
+	tk.MustGetErrMsg("insert into v5 values(5, 20, 1)", "[executor:1369]CHECK OPTION failed 'test.v5'")
+	tk.MustGetErrMsg("insert into v5 values(5, -20, 1)", "[executor:1369]CHECK OPTION failed 'test.v5'")
+	tk.MustQuery("select a, b from t").Check(testkit.Rows("1 1", "3 -1", "4 -20"))
+
+	// WITH CASCADED CHECK OPTION also checks the conditions of the underlying views without check options.
+	tk.MustExec("create view v6 as select * from v where y < 10 with cascaded check option")
+	tk.MustExec("insert into v6 values(5, 5)")
+	tk.MustGetErrMsg("insert into v6 values(6, -1)", "[executor:1369]CHECK OPTION failed 'test.v6'")
+	tk.MustGetErrMsg("update v6 set y = 20", "[executor:1369]CHECK OPTION failed 'test.v6'")
+	tk.MustExec("drop view v, v1, v2, v3, v4, v5, v6")
+}
+
+func (s *testSuite) TestWriteThroughLegacyView(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	// The views are defined by the current user, who must have the privileges on the base table.
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("drop view if exists v, v1")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("create view v as select * from t where b > 0")
+	tk.MustExec("create view v1 as select * from t where b > 0 with cascaded check option")
+
+	// The view without WITH CHECK OPTION is persisted with CheckOptionCascaded as the older versions do.
+	tbl, err := s.domain.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("v"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().View.CheckOption, Equals, model.CheckOptionCascaded)
+	c.Assert(tbl.Meta().View.CheckOptionSpecified, IsFalse)
+
+	// Hack the view with check option into the format of the older versions, which doesn't
+	// record whether the check option is specified.
+	db, ok := s.domain.InfoSchema().SchemaByName(model.NewCIStr("test"))
+	c.Assert(ok, IsTrue)
+	tbl, err = s.domain.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("v1"))
+	c.Assert(err, IsNil)
+	tblInfo := tbl.Meta().Clone()
+	tblInfo.View.CheckOptionSpecified = false
+	err = kv.RunInNewTxn(context.Background(), s.store, false, func(ctx context.Context, txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		_, err := m.GenSchemaVersion()
+		c.Assert(err, IsNil)
+		c.Assert(m.UpdateTable(db.ID, tblInfo), IsNil)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(s.domain.Reload(), IsNil)
+
+	// The views in the old format are not checked.
+	for _, view := range []string{"v", "v1"} {
+		tk.MustExec(fmt.Sprintf("insert into %s values(1, -1)", view))
+		tk.MustExec(fmt.Sprintf("update %s set b = -2 where a = 2", view))
+		tk.MustQuery(fmt.Sprintf("show create view %s", view)).Check(testkit.Rows(
+			fmt.Sprintf("%s CREATE ALGORITHM=UNDEFINED DEFINER=`root`@`%%` SQL SECURITY DEFINER VIEW `%s` (`a`, `b`) AS SELECT `test`.`t`.`a` AS `a`,`test`.`t`.`b` AS `b` FROM `test`.`t` WHERE `b`>0 utf8mb4 utf8mb4_bin", view, view)))
+		tk.MustQuery(fmt.Sprintf("select check_option from information_schema.views where table_name = '%s'", view)).Check(testkit.Rows("NONE"))
+		tk.MustExec("delete from t")
+	}
+	tk.MustExec("drop view v, v1")
+}
+
 func testEqualDatumsAsBinary(c *C, a []interface{}, b []interface{}, same bool) {
 	sc := new(stmtctx.StatementContext)
 	re := new(executor.ReplaceExec)
@@ -106,6 +106,7 @@ var (
 	_ builtinFunc = &builtinCastJSONAsTimeSig{}
 	_ builtinFunc = &builtinCastJSONAsDurationSig{}
 	_ builtinFunc = &builtinCastJSONAsJSONSig{}
+	_ builtinFunc = &builtinCastJSONAsArraySig{}
 )
 
 type castAsIntFunctionClass struct {
@@ -422,6 +423,15 @@ func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 	}
 	bf.tp = c.tp
 	argTp := args[0].GetType().EvalType()
+	if c.tp.Array && !args[0].GetType().Array {
+		if argTp != types.ETJson {
+			return nil, ErrNotSupportedCastAsArray.GenWithStackByArgs("Non-JSON Array type")
+		}
+		if c.tp.Tp == mysql.TypeJSON {
+			return nil, ErrNotSupportedCastAsArray.GenWithStackByArgs("JSON")
+		}
+		return &builtinCastJSONAsArraySig{bf}, nil
+	}
 	switch argTp {
 	case types.ETInt:
 		sig = &builtinCastIntAsJSONSig{bf}
@@ -1625,6 +1635,56 @@ func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON,
 	return b.args[0].EvalJSON(b.ctx, row)
 }
 
+// builtinCastJSONAsArraySig casts every element of a JSON array to the element type, and returns the results as a JSON array.
+// A JSON scalar or object is treated as an array containing only itself.
+type builtinCastJSONAsArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsArraySig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	elemTp := b.tp.Clone()
+	elemTp.Array = false
+	sc := b.ctx.GetSessionVars().StmtCtx
+	elems := []json.BinaryJSON{val}
+	if val.TypeCode == json.TypeCodeArray {
+		elems = make([]json.BinaryJSON, 0, val.GetElemCount())
+		for i := 0; i < val.GetElemCount(); i++ {
+			elems = append(elems, val.ArrayGetElem(i))
+		}
+	}
+	results := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		d := types.NewDatumFromJSONScalar(elem, elemTp.Collate)
+		if d.Kind() == types.KindMysqlJSON {
+			return res, true, ErrNotSupportedCastAsArray.GenWithStackByArgs("JSON " + elem.Type())
+		}
+		d, err = d.ConvertTo(sc, elemTp)
+		if err != nil {
+			return res, true, err
+		}
+		if d.Kind() == types.KindString || d.Kind() == types.KindBytes {
+			results = append(results, d.GetString())
+			continue
+		}
+		d, err = d.ConvertTo(sc, types.NewFieldType(mysql.TypeJSON))
+		if err != nil {
+			return res, true, err
+		}
+		results = append(results, d.GetMysqlJSON())
+	}
+	return json.CreateBinary(results), false, nil
+}
+
 type builtinCastJSONAsIntSig struct {
 	baseBuiltinCastFunc
 }
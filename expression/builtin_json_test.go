@@ -477,6 +477,74 @@ func TestJSONContains(t *testing.T) {
 	}
 }
 
+func TestJSONMemberOf(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	fc := funcs[ast.JSONMemberOf]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+	}{
+		{[]interface{}{nil, `[1, 2]`}, nil},
+		{[]interface{}{1, nil}, nil},
+		{[]interface{}{1, `[1, 2]`}, 1},
+		{[]interface{}{3, `[1, 2]`}, 0},
+		{[]interface{}{"1", `[1, 2]`}, 0},
+		{[]interface{}{"a", `["a", "b"]`}, 1},
+		{[]interface{}{1.5, `[1.5]`}, 1},
+		{[]interface{}{1, `1`}, 1},
+	}
+	for _, tt := range tbl {
+		args := types.MakeDatums(tt.input...)
+		f, err := fc.getFunction(ctx, datumsToConstants(args))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		if tt.expected == nil {
+			require.True(t, d.IsNull())
+		} else {
+			require.Equal(t, int64(tt.expected.(int)), d.GetInt64())
+		}
+	}
+}
+
+func TestJSONOverlaps(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	fc := funcs[ast.JSONOverlaps]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{nil, `[1]`}, nil, nil},
+		{[]interface{}{`[1]`, nil}, nil, nil},
+		{[]interface{}{`[1, 2]`, `[2, 3]`}, 1, nil},
+		{[]interface{}{`[1, 2]`, `[3, 4]`}, 0, nil},
+		{[]interface{}{`[1, 2]`, `2`}, 1, nil},
+		{[]interface{}{`{"a": 1, "b": 2}`, `{"b": 2}`}, 1, nil},
+		{[]interface{}{`{"a": 1, "b": 2}`, `{"b": 3}`}, 0, nil},
+		{[]interface{}{`1`, `1`}, 1, nil},
+		{[]interface{}{`a:1`, `1`}, nil, json.ErrInvalidJSONText},
+	}
+	for _, tt := range tbl {
+		args := types.MakeDatums(tt.input...)
+		f, err := fc.getFunction(ctx, datumsToConstants(args))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err == nil {
+			require.NoError(t, err)
+			if tt.expected == nil {
+				require.True(t, d.IsNull())
+			} else {
+				require.Equal(t, int64(tt.expected.(int)), d.GetInt64())
+			}
+		} else {
+			require.True(t, tt.err.(*terror.Error).Equal(err))
+		}
+	}
+}
+
 func TestJSONContainsPath(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
@@ -0,0 +1,524 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeSig{}
+	_ builtinFunc = &builtinRegexpInStrSig{}
+	_ builtinFunc = &builtinRegexpSubstrSig{}
+	_ builtinFunc = &builtinRegexpReplaceSig{}
+)
+
+// The roles of the arguments of the regular expression functions.
+const (
+	regexpArgExpr = iota
+	regexpArgPattern
+	regexpArgReplacement
+	regexpArgPosition
+	regexpArgOccurrence
+	regexpArgReturnOption
+	regexpArgMatchType
+)
+
+var (
+	regexpLikeArgRoles    = []int{regexpArgExpr, regexpArgPattern, regexpArgMatchType}
+	regexpInStrArgRoles   = []int{regexpArgExpr, regexpArgPattern, regexpArgPosition, regexpArgOccurrence, regexpArgReturnOption, regexpArgMatchType}
+	regexpSubstrArgRoles  = []int{regexpArgExpr, regexpArgPattern, regexpArgPosition, regexpArgOccurrence, regexpArgMatchType}
+	regexpReplaceArgRoles = []int{regexpArgExpr, regexpArgPattern, regexpArgReplacement, regexpArgPosition, regexpArgOccurrence, regexpArgMatchType}
+)
+
+// getRegexpArgTypes returns the eval types of the first `argCnt` arguments with the roles.
+func getRegexpArgTypes(roles []int, argCnt int) []types.EvalType {
+	argTps := make([]types.EvalType, 0, argCnt)
+	for _, role := range roles[:argCnt] {
+		switch role {
+		case regexpArgPosition, regexpArgOccurrence, regexpArgReturnOption:
+			argTps = append(argTps, types.ETInt)
+		default:
+			argTps = append(argTps, types.ETString)
+		}
+	}
+	return argTps
+}
+
+// regexpArgs is the evaluated arguments of a regular expression function for one row.
+// The arguments which are not specified keep the default values.
+type regexpArgs struct {
+	expr         string
+	pattern      string
+	replacement  string
+	matchType    string
+	position     int64
+	occurrence   int64
+	returnOption int64
+}
+
+func (a *regexpArgs) setString(role int, val string) {
+	switch role {
+	case regexpArgExpr:
+		a.expr = val
+	case regexpArgPattern:
+		a.pattern = val
+	case regexpArgReplacement:
+		a.replacement = val
+	case regexpArgMatchType:
+		a.matchType = val
+	}
+}
+
+func (a *regexpArgs) setInt(role int, val int64) {
+	switch role {
+	case regexpArgPosition:
+		a.position = val
+	case regexpArgOccurrence:
+		a.occurrence = val
+	case regexpArgReturnOption:
+		a.returnOption = val
+	}
+}
+
+func isRegexpIntArg(role int) bool {
+	return role == regexpArgPosition || role == regexpArgOccurrence || role == regexpArgReturnOption
+}
+
+// builtinRegexpBaseSig is the base of the REGEXP_XXX functions.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#regexp-syntax
+type builtinRegexpBaseSig struct {
+	baseBuiltinFunc
+	name     string
+	argRoles []int
+	// memorizedRegexp and memorizedErr are not serialized, they are a cache of the compiled
+	// regexp when both the pattern and the match type are constant.
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+	once            sync.Once
+}
+
+func newBuiltinRegexpBaseSig(bf baseBuiltinFunc, name string, roles []int) builtinRegexpBaseSig {
+	return builtinRegexpBaseSig{baseBuiltinFunc: bf, name: name, argRoles: roles[:len(bf.args)]}
+}
+
+func (b *builtinRegexpBaseSig) clone(from *builtinRegexpBaseSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.name = from.name
+	b.argRoles = from.argRoles
+}
+
+// evalArgs evaluates the arguments of the row, isNull is true if any of the arguments is null.
+func (b *builtinRegexpBaseSig) evalArgs(row chunk.Row) (args regexpArgs, isNull bool, err error) {
+	args.position = 1
+	for i, role := range b.argRoles {
+		if isRegexpIntArg(role) {
+			var val int64
+			val, isNull, err = b.args[i].EvalInt(b.ctx, row)
+			if isNull || err != nil {
+				return args, true, err
+			}
+			args.setInt(role, val)
+			continue
+		}
+		var val string
+		val, isNull, err = b.args[i].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return args, true, err
+		}
+		args.setString(role, val)
+	}
+	return args, false, nil
+}
+
+// getRegexp returns the compiled regexp for the pattern and the match type.
+func (b *builtinRegexpBaseSig) getRegexp(pattern, matchType string) (*regexp.Regexp, error) {
+	if !b.canMemorize() {
+		return b.compile(pattern, matchType)
+	}
+	// Only be executed once to achieve thread-safe
+	b.once.Do(func() {
+		b.memorizedRegexp, b.memorizedErr = b.compile(pattern, matchType)
+	})
+	return b.memorizedRegexp, b.memorizedErr
+}
+
+// canMemorize returns whether the pattern and the match type are the same for all the rows.
+func (b *builtinRegexpBaseSig) canMemorize() bool {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i, role := range b.argRoles {
+		if (role == regexpArgPattern || role == regexpArgMatchType) && !b.args[i].ConstItem(sc) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *builtinRegexpBaseSig) compile(pattern, matchType string) (*regexp.Regexp, error) {
+	// The case sensitivity follows the collation by default, it can be overridden by the match type.
+	caseInsensitive := collate.IsCICollation(b.collation)
+	multiLine, dotAll := false, false
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			caseInsensitive = false
+		case 'i':
+			caseInsensitive = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Unix-only line endings, '\n' is the only line terminator recognized by the regexp package.
+		default:
+			return nil, errIncorrectArgs.GenWithStackByArgs(b.name)
+		}
+	}
+	var flags strings.Builder
+	if caseInsensitive {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	if flags.Len() > 0 {
+		pattern = "(?" + flags.String() + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+func (b *builtinRegexpBaseSig) isBinaryCollation() bool {
+	return b.collation == charset.CollationBin
+}
+
+// getStartOffset converts the 1-based position of `expr` to the byte offset where the search starts.
+// The position is counted in bytes for the binary collation, otherwise in characters.
+func (b *builtinRegexpBaseSig) getStartOffset(expr string, position int64) (int, error) {
+	offset, ok := 0, position >= 1
+	if ok && b.isBinaryCollation() {
+		ok = position <= int64(len(expr))+1
+		offset = int(position - 1)
+	} else {
+		for i := int64(1); ok && i < position; i++ {
+			if offset >= len(expr) {
+				ok = false
+				break
+			}
+			_, size := utf8.DecodeRuneInString(expr[offset:])
+			offset += size
+		}
+	}
+	if !ok {
+		return 0, ErrRegexp.GenWithStackByArgs("Index out of bounds in regular expression search.")
+	}
+	return offset, nil
+}
+
+// getPosition converts the byte offset of `expr` to the 1-based position.
+func (b *builtinRegexpBaseSig) getPosition(expr string, offset int) int64 {
+	if b.isBinaryCollation() {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+// findMatch returns the byte offsets of the `occurrence`-th match of the pattern in `expr`,
+// it returns nil if there is no such match.
+func (b *builtinRegexpBaseSig) findMatch(args *regexpArgs) ([]int, error) {
+	re, err := b.getRegexp(args.pattern, args.matchType)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := b.getStartOffset(args.expr, args.position)
+	if err != nil {
+		return nil, err
+	}
+	occurrence := 1
+	if args.occurrence > 1 {
+		occurrence = int(args.occurrence)
+	}
+	matches := re.FindAllStringIndex(args.expr[offset:], occurrence)
+	if len(matches) < occurrence {
+		return nil, nil
+	}
+	match := matches[occurrence-1]
+	return []int{match[0] + offset, match[1] + offset}, nil
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, getRegexpArgTypes(regexpLikeArgRoles, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := newBuiltinRegexpLikeSig(bf)
+	sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	return sig, nil
+}
+
+type builtinRegexpLikeSig struct {
+	builtinRegexpBaseSig
+}
+
+func newBuiltinRegexpLikeSig(bf baseBuiltinFunc) *builtinRegexpLikeSig {
+	return &builtinRegexpLikeSig{newBuiltinRegexpBaseSig(bf, ast.RegexpLike, regexpLikeArgRoles)}
+}
+
+func (b *builtinRegexpLikeSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeSig{}
+	newSig.clone(&b.builtinRegexpBaseSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	args, isNull, err := b.evalArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.regexpLike(&args)
+	return res, err != nil, err
+}
+
+func (b *builtinRegexpLikeSig) regexpLike(args *regexpArgs) (int64, error) {
+	re, err := b.getRegexp(args.pattern, args.matchType)
+	if err != nil {
+		return 0, err
+	}
+	return boolToInt64(re.MatchString(args.expr)), nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, getRegexpArgTypes(regexpInStrArgRoles, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxIntWidth
+	sig := newBuiltinRegexpInStrSig(bf)
+	sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	return sig, nil
+}
+
+type builtinRegexpInStrSig struct {
+	builtinRegexpBaseSig
+}
+
+func newBuiltinRegexpInStrSig(bf baseBuiltinFunc) *builtinRegexpInStrSig {
+	return &builtinRegexpInStrSig{newBuiltinRegexpBaseSig(bf, ast.RegexpInStr, regexpInStrArgRoles)}
+}
+
+func (b *builtinRegexpInStrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrSig{}
+	newSig.clone(&b.builtinRegexpBaseSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	args, isNull, err := b.evalArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.regexpInStr(&args)
+	return res, err != nil, err
+}
+
+func (b *builtinRegexpInStrSig) regexpInStr(args *regexpArgs) (int64, error) {
+	if args.returnOption != 0 && args.returnOption != 1 {
+		return 0, errIncorrectArgs.GenWithStackByArgs(b.name)
+	}
+	match, err := b.findMatch(args)
+	if err != nil || match == nil {
+		return 0, err
+	}
+	// The return_option is 0 for the position of the match, or 1 for the position following the match.
+	return b.getPosition(args.expr, match[args.returnOption]), nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, getRegexpArgTypes(regexpSubstrArgRoles, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := newBuiltinRegexpSubstrSig(bf)
+	sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	return sig, nil
+}
+
+type builtinRegexpSubstrSig struct {
+	builtinRegexpBaseSig
+}
+
+func newBuiltinRegexpSubstrSig(bf baseBuiltinFunc) *builtinRegexpSubstrSig {
+	return &builtinRegexpSubstrSig{newBuiltinRegexpBaseSig(bf, ast.RegexpSubstr, regexpSubstrArgRoles)}
+}
+
+func (b *builtinRegexpSubstrSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrSig{}
+	newSig.clone(&b.builtinRegexpBaseSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrSig) evalString(row chunk.Row) (string, bool, error) {
+	args, isNull, err := b.evalArgs(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.regexpSubstr(&args)
+}
+
+// regexpSubstr returns the matched substring, isNull is true if there is no match.
+func (b *builtinRegexpSubstrSig) regexpSubstr(args *regexpArgs) (string, bool, error) {
+	match, err := b.findMatch(args)
+	if err != nil || match == nil {
+		return "", true, err
+	}
+	return args.expr[match[0]:match[1]], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, getRegexpArgTypes(regexpReplaceArgRoles, len(args))...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	for _, a := range args[:3] {
+		SetBinFlagOrBinStr(a.GetType(), bf.tp)
+	}
+	sig := newBuiltinRegexpReplaceSig(bf)
+	sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	return sig, nil
+}
+
+type builtinRegexpReplaceSig struct {
+	builtinRegexpBaseSig
+}
+
+func newBuiltinRegexpReplaceSig(bf baseBuiltinFunc) *builtinRegexpReplaceSig {
+	return &builtinRegexpReplaceSig{newBuiltinRegexpBaseSig(bf, ast.RegexpReplace, regexpReplaceArgRoles)}
+}
+
+func (b *builtinRegexpReplaceSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceSig{}
+	newSig.clone(&b.builtinRegexpBaseSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	args, isNull, err := b.evalArgs(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, err := b.regexpReplace(&args)
+	return res, err != nil, err
+}
+
+// regexpReplace replaces the `occurrence`-th match of the pattern with the replacement,
+// all the matches are replaced if the occurrence is 0. `$n` in the replacement refers to the n-th captured group.
+func (b *builtinRegexpReplaceSig) regexpReplace(args *regexpArgs) (string, error) {
+	re, err := b.getRegexp(args.pattern, args.matchType)
+	if err != nil {
+		return "", err
+	}
+	offset, err := b.getStartOffset(args.expr, args.position)
+	if err != nil {
+		return "", err
+	}
+	src := args.expr[offset:]
+	limit := -1
+	if args.occurrence > 0 {
+		limit = int(args.occurrence)
+	}
+	matches := re.FindAllStringSubmatchIndex(src, limit)
+	if args.occurrence > 0 {
+		if len(matches) < limit {
+			return args.expr, nil
+		}
+		matches = matches[limit-1:]
+	}
+	res := make([]byte, 0, len(args.expr))
+	res = append(res, args.expr[:offset]...)
+	last := 0
+	for _, match := range matches {
+		res = append(res, src[last:match[0]]...)
+		res = re.ExpandString(res, args.replacement, src, match)
+		last = match[1]
+	}
+	res = append(res, src[last:]...)
+	return string(res), nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit/trequire"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpLike(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		args  []interface{}
+		match interface{}
+		err   error
+	}{
+		{[]interface{}{"abc", "b"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "B"}, 0, nil},
+		{[]interface{}{"abc", "B", "i"}, 1, nil},
+		{[]interface{}{"abc", "B", "ic"}, 0, nil},
+		{[]interface{}{"a\nb", "^b"}, 0, nil},
+		{[]interface{}{"a\nb", "^b", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b"}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", "("}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, errIncorrectArgs},
+	}
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%v", tt.args)
+		f, err := funcs[ast.RegexpLike].getFunction(ctx, datumsToConstants(types.MakeDatums(tt.args...)))
+		require.NoError(t, err, comment)
+		match, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), comment)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.match), match, comment)
+	}
+}
+
+func TestRegexpInStr(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		args []interface{}
+		pos  interface{}
+		err  error
+	}{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 0}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"你好世界", "世"}, 3, nil},
+		{[]interface{}{"你好世界", "世", 1, 1, 1}, 4, nil},
+		{[]interface{}{"abc", "c", 4}, 0, nil},
+		{[]interface{}{"abc", "c", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 0}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 1, 1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "c", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%v", tt.args)
+		f, err := funcs[ast.RegexpInStr].getFunction(ctx, datumsToConstants(types.MakeDatums(tt.args...)))
+		require.NoError(t, err, comment)
+		pos, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), comment)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.pos), pos, comment)
+	}
+}
+
+func TestRegexpSubstr(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", 1, 1, "i"}, "abc", nil},
+		{[]interface{}{"你好世界", ".", 3}, "世", nil},
+		{[]interface{}{"abc", "b", 5}, nil, ErrRegexp},
+		{[]interface{}{nil, "b"}, nil, nil},
+	}
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%v", tt.args)
+		f, err := funcs[ast.RegexpSubstr].getFunction(ctx, datumsToConstants(types.MakeDatums(tt.args...)))
+		require.NoError(t, err, comment)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), comment)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.res), res, comment)
+	}
+}
+
+func TestRegexpReplace(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	tests := []struct {
+		args []interface{}
+		res  interface{}
+		err  error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 0}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 2}, "abc X ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5}, "abc X X", nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", "X", 1, 1, "i"}, "X def ghi", nil},
+		{[]interface{}{"abc def", "([a-z]+) ([a-z]+)", "$2 $1"}, "def abc", nil},
+		{[]interface{}{"你好世界", "好", "坏"}, "你坏世界", nil},
+		{[]interface{}{"你好世界", ".", "X", 3}, "你好XX", nil},
+		{[]interface{}{"abc", "b", "X", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		comment := fmt.Sprintf("%v", tt.args)
+		f, err := funcs[ast.RegexpReplace].getFunction(ctx, datumsToConstants(types.MakeDatums(tt.args...)))
+		require.NoError(t, err, comment)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			require.True(t, terror.ErrorEqual(err, tt.err), comment)
+			continue
+		}
+		require.NoError(t, err, comment)
+		trequire.DatumEqual(t, types.NewDatum(tt.res), res, comment)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalArgs evaluates all the arguments into the buffers, the buffers should be released by putArgBufs.
+func (b *builtinRegexpBaseSig) vecEvalArgs(input *chunk.Chunk) ([]*chunk.Column, error) {
+	bufs := make([]*chunk.Column, 0, len(b.argRoles))
+	for i, role := range b.argRoles {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			b.putArgBufs(bufs)
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		if isRegexpIntArg(role) {
+			err = b.args[i].VecEvalInt(b.ctx, input, buf)
+		} else {
+			err = b.args[i].VecEvalString(b.ctx, input, buf)
+		}
+		if err != nil {
+			b.putArgBufs(bufs)
+			return nil, err
+		}
+	}
+	return bufs, nil
+}
+
+func (b *builtinRegexpBaseSig) putArgBufs(bufs []*chunk.Column) {
+	for _, buf := range bufs {
+		b.bufAllocator.put(buf)
+	}
+}
+
+// getArgsOfRow gets the arguments of the i-th row from the buffers, isNull is true if any of the arguments is null.
+func (b *builtinRegexpBaseSig) getArgsOfRow(bufs []*chunk.Column, i int) (args regexpArgs, isNull bool) {
+	args.position = 1
+	for j, role := range b.argRoles {
+		if bufs[j].IsNull(i) {
+			return args, true
+		}
+		if isRegexpIntArg(role) {
+			args.setInt(role, bufs[j].GetInt64(i))
+		} else {
+			args.setString(role, bufs[j].GetString(i))
+		}
+	}
+	return args, false
+}
+
+func (b *builtinRegexpLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		args, isNull := b.getArgsOfRow(bufs, i)
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		if i64s[i], err = b.regexpLike(&args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpInStrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		args, isNull := b.getArgsOfRow(bufs, i)
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		if i64s[i], err = b.regexpInStr(&args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpSubstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		args, isNull := b.getArgsOfRow(bufs, i)
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.regexpSubstr(&args)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		args, isNull := b.getArgsOfRow(bufs, i)
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		res, err := b.regexpReplace(&args)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -206,6 +206,12 @@ func deriveCollation(ctx sessionctx.Context, funcName string, args []Expression,
 		return CheckAndDeriveCollationFromExprs(ctx, funcName, retType, args[1:]...)
 	case ast.FindInSet, ast.Regexp:
 		return CheckAndDeriveCollationFromExprs(ctx, funcName, types.ETInt, args...)
+	case ast.RegexpLike, ast.RegexpInStr:
+		return CheckAndDeriveCollationFromExprs(ctx, funcName, types.ETInt, args[0], args[1])
+	case ast.RegexpSubstr:
+		return CheckAndDeriveCollationFromExprs(ctx, funcName, retType, args[0], args[1])
+	case ast.RegexpReplace:
+		return CheckAndDeriveCollationFromExprs(ctx, funcName, retType, args[0], args[1], args[2])
 	case ast.Field:
 		if argTps[0] == types.ETString {
 			return CheckAndDeriveCollationFromExprs(ctx, funcName, retType, args...)
@@ -654,6 +654,14 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 	// 	f = &builtinRegexpSig{base}
 	// case tipb.ScalarFuncSig_RegexpUTF8Sig:
 	// 	f = &builtinRegexpUTF8Sig{base}
+	case tipb.ScalarFuncSig_RegexpLikeSig:
+		f = newBuiltinRegexpLikeSig(base)
+	case tipb.ScalarFuncSig_RegexpInStrSig:
+		f = newBuiltinRegexpInStrSig(base)
+	case tipb.ScalarFuncSig_RegexpSubstrSig:
+		f = newBuiltinRegexpSubstrSig(base)
+	case tipb.ScalarFuncSig_RegexpReplaceSig:
+		f = newBuiltinRegexpReplaceSig(base)
 	case tipb.ScalarFuncSig_JsonExtractSig:
 		f = &builtinJSONExtractSig{base}
 	case tipb.ScalarFuncSig_JsonUnquoteSig:
@@ -56,6 +56,9 @@ var (
 	errSequenceAccessDenied      = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
 	errUnsupportedJSONComparison = dbterror.ClassExpression.NewStdErr(mysql.ErrNotSupportedYet,
 		pmysql.Message("comparison of JSON in the LEAST and GREATEST operators", nil))
+	// ErrNotSupportedCastAsArray is returned when the argument or the element type of `CAST(... AS ... ARRAY)` is unsupported.
+	ErrNotSupportedCastAsArray = dbterror.ClassExpression.NewStdErr(mysql.ErrNotSupportedYet,
+		pmysql.Message("CAST-ing %s to array", nil))
 )
 
 // handleInvalidTimeError reports error or warning depend on the context.
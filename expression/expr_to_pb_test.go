@@ -1141,6 +1141,43 @@ func TestExprOnlyPushDownToTiKV(t *testing.T) {
 	require.Len(t, remained, 0)
 }
 
+func TestRegexpFuncPushDownToTiKV(t *testing.T) {
+	t.Parallel()
+	sc := new(stmtctx.StatementContext)
+	client := new(mock.Client)
+
+	stringColumn := genColumn(mysql.TypeString, 1)
+	intColumn := genColumn(mysql.TypeLonglong, 2)
+	exprs := make([]Expression, 0)
+
+	function, err := NewFunction(mock.NewContext(), ast.RegexpLike, types.NewFieldType(mysql.TypeLonglong), stringColumn, stringColumn, stringColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpInStr, types.NewFieldType(mysql.TypeLonglong), stringColumn, stringColumn, intColumn, intColumn, intColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpSubstr, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn, intColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	function, err = NewFunction(mock.NewContext(), ast.RegexpReplace, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn, stringColumn, intColumn, intColumn)
+	require.NoError(t, err)
+	exprs = append(exprs, function)
+
+	pushed, remained := PushDownExprs(sc, exprs, client, kv.TiKV)
+	require.Len(t, pushed, len(exprs))
+	require.Len(t, remained, 0)
+
+	pbExprs, err := ExpressionsToPBList(sc, exprs, client)
+	require.NoError(t, err)
+	sigs := []tipb.ScalarFuncSig{tipb.ScalarFuncSig_RegexpLikeSig, tipb.ScalarFuncSig_RegexpInStrSig, tipb.ScalarFuncSig_RegexpSubstrSig, tipb.ScalarFuncSig_RegexpReplaceSig}
+	for i, pbExpr := range pbExprs {
+		require.Equal(t, sigs[i], pbExpr.Sig)
+	}
+}
+
 func TestGroupByItem2Pb(t *testing.T) {
 	t.Parallel()
 	sc := new(stmtctx.StatementContext)
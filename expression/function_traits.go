@@ -253,6 +253,7 @@ var booleanFunctions = map[string]struct{}{
 	ast.IsNull:             {},
 	ast.Like:               {},
 	ast.Regexp:             {},
+	ast.RegexpLike:         {},
 	ast.IsIPv4:             {},
 	ast.IsIPv4Compat:       {},
 	ast.IsIPv4Mapped:       {},
@@ -5179,7 +5179,9 @@ func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
 	tk.MustExec("SET FOREIGN_KEY_CHECKS=1")
-	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8047 variable 'foreign_key_checks' does not yet support value: 1"))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows())
+	tk.MustQuery("select @@foreign_key_checks").Check(testkit.Rows("1"))
+	tk.MustExec("SET FOREIGN_KEY_CHECKS=0")
 }
 
 func (s *testIntegrationSuite) TestUserVarMockWindFunc(c *C) {
@@ -10432,3 +10434,30 @@ func (s *testIntegrationSuite) TestIssue28643(c *C) {
 	tk.MustExec("set tidb_enable_vectorized_expression = off;")
 	tk.MustQuery("select hour(a) from t;").Check(testkit.Rows("838", "838"))
 }
+
+func (s *testIntegrationSerialSuite) TestRegexpFunctions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a varchar(20) collate utf8mb4_general_ci, b varchar(20) collate utf8mb4_bin, c varbinary(20))")
+	tk.MustExec("insert into t values ('Dog cat dog', 'Dog cat dog', 'Dog cat dog'), ('你好世界', '你好世界', '你好世界'), (null, null, null)")
+	for _, vec := range []string{"on", "off"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vec)
+		// The case sensitivity follows the collation unless it's specified by the match type.
+		tk.MustQuery("select regexp_like(a, 'dog cat'), regexp_like(b, 'dog cat'), regexp_like(c, 'dog cat'), regexp_like(b, 'dog cat', 'i') from t").
+			Check(testkit.Rows("1 0 0 1", "0 0 0 0", "<nil> <nil> <nil> <nil>"))
+		// The positions are counted in characters, or in bytes for the binary strings.
+		tk.MustQuery("select regexp_instr(a, 'dog', 1, 2), regexp_instr(b, 'dog', 1, 2), regexp_instr(b, '世'), regexp_instr(c, '世') from t").
+			Check(testkit.Rows("9 0 0 0", "0 0 3 7", "<nil> <nil> <nil> <nil>"))
+		tk.MustQuery("select regexp_substr(a, '[a-z]+', 1, 2), regexp_substr(b, '.', 3) from t").
+			Check(testkit.Rows("cat g", "<nil> 世", "<nil> <nil>"))
+		tk.MustQuery("select regexp_replace(a, 'dog', 'fox'), regexp_replace(b, 'dog', 'fox'), regexp_replace(b, '(\\\\w+) (\\\\w+)', '$2 $1', 1, 1) from t").
+			Check(testkit.Rows("fox cat fox Dog cat fox cat Dog dog", "你好世界 你好世界 你好世界", "<nil> <nil> <nil>"))
+		tk.MustQuery("select count(*) from t where regexp_like(a, '^dog')").Check(testkit.Rows("1"))
+	}
+	tk.MustGetErrCode("select regexp_like('a', 'a', 'x')", mysql.ErrWrongArguments)
+	tk.MustGetErrCode("select regexp_instr('a', 'a', 3)", mysql.ErrRegexp)
+	tk.MustGetErrCode("select regexp_substr('a', '(')", mysql.ErrRegexp)
+}
@@ -78,6 +78,13 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		// The table ID is changed when the table becomes partitioned or non-partitioned.
+		oldTableID = diff.TableID
+		if diff.OldTableID != 0 {
+			oldTableID = diff.OldTableID
+		}
+		newTableID = diff.TableID
 	default:
 		oldTableID = diff.TableID
 		newTableID = diff.TableID
@@ -103,6 +110,13 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
 			return nil, errors.Trace(err)
 		}
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		if oldTableID != newTableID {
+			b.applyPlacementDelete(placement.GroupID(oldTableID))
+		}
+		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	dbInfo := b.copySchemaTables(roDBInfo.Name.L)
 	b.copySortedTables(oldTableID, newTableID)
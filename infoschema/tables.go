@@ -178,6 +178,8 @@ const (
 	TableAttributes = "ATTRIBUTES"
 	// TablePlacementRules is the string constant of placement rules table.
 	TablePlacementRules = "PLACEMENT_RULES"
+	// TableCheckConstraints is the string constant of CHECK_CONSTRAINTS.
+	TableCheckConstraints = "CHECK_CONSTRAINTS"
 )
 
 const (
@@ -276,6 +278,7 @@ var tableIDMap = map[string]int64{
 	TableAttributes:                      autoid.InformationSchemaDBID + 77,
 	TableTiDBHotRegionsHistory:           autoid.InformationSchemaDBID + 78,
 	TablePlacementRules:                  autoid.InformationSchemaDBID + 79,
+	TableCheckConstraints:                autoid.InformationSchemaDBID + 80,
 }
 
 type columnInfo struct {
@@ -572,6 +575,13 @@ var tableConstraintsCols = []columnInfo{
 	{name: "CONSTRAINT_TYPE", tp: mysql.TypeVarchar, size: 64},
 }
 
+var tableCheckConstraintsCols = []columnInfo{
+	{name: "CONSTRAINT_CATALOG", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CHECK_CLAUSE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag},
+}
+
 var tableTriggersCols = []columnInfo{
 	{name: "TRIGGER_CATALOG", tp: mysql.TypeVarchar, size: 512},
 	{name: "TRIGGER_SCHEMA", tp: mysql.TypeVarchar, size: 64},
@@ -1497,6 +1507,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// CheckConstraintType is the string constant of CHECK.
+	CheckConstraintType = "CHECK"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1835,6 +1847,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableAttributes:                         tableAttributesCols,
 	TablePlacementRules:                     tablePlacementRulesCols,
+	TableCheckConstraints:                   tableCheckConstraintsCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -41,4 +41,12 @@ var (
 			Name:      "bind_memory_usage",
 			Help:      "Memory usage of sql bind",
 		}, []string{LabelScope, LblType})
+
+	PlanRegressionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "bindinfo",
+			Name:      "plan_regression_counter",
+			Help:      "Counter of detected plan regressions and the bindings created for them",
+		}, []string{LblType})
 )
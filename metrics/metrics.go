@@ -73,6 +73,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(BindUsageCounter)
 	prometheus.MustRegister(BindTotalGauge)
 	prometheus.MustRegister(BindMemoryUsage)
+	prometheus.MustRegister(PlanRegressionCounter)
 	prometheus.MustRegister(CampaignOwnerCounter)
 	prometheus.MustRegister(ConnGauge)
 	prometheus.MustRegister(DisconnectionCounter)
@@ -107,6 +108,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(OwnerHandleSyncerHistogram)
 	prometheus.MustRegister(PanicCounter)
 	prometheus.MustRegister(PlanCacheCounter)
+	prometheus.MustRegister(PlanCacheMissCounter)
 	prometheus.MustRegister(PseudoEstimation)
 	prometheus.MustRegister(PacketIOHistogram)
 	prometheus.MustRegister(QueryDurationHistogram)
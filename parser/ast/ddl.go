@@ -1403,7 +1403,7 @@ func (n *CreateViewStmt) Restore(ctx *format.RestoreCtx) error {
 		return errors.Annotate(err, "An error occurred while create CreateViewStmt.Select")
 	}
 
-	if n.CheckOption != model.CheckOptionCascaded {
+	if n.CheckOption != model.CheckOptionNone {
 		ctx.WriteKeyWord(" WITH ")
 		ctx.WriteKeyWord(n.CheckOption.String())
 		ctx.WriteKeyWord(" CHECK OPTION")
@@ -2677,6 +2677,8 @@ func (n *AlterTableSpec) Restore(ctx *format.RestoreCtx) error {
 			ctx.WriteKeyWord(" DEPENDENCY(")
 		case StatsTypeCorrelation:
 			ctx.WriteKeyWord(" CORRELATION(")
+		case StatsTypeMCV:
+			ctx.WriteKeyWord(" MCV(")
 		}
 		for i, col := range n.Statistics.Columns {
 			if i != 0 {
@@ -518,6 +518,10 @@ type TableSource struct {
 
 	// AsName is the alias name of the table source.
 	AsName model.CIStr
+
+	// Lateral indicates the table source is a LATERAL derived table,
+	// which can refer to the columns of the preceding table sources.
+	Lateral bool
 }
 
 func (*TableSource) resultSet() {}
@@ -564,6 +568,9 @@ func (n *TableSource) Restore(ctx *format.RestoreCtx) error {
 			ctx.WritePlain(")")
 		}
 	} else {
+		if n.Lateral {
+			ctx.WriteKeyWord("LATERAL ")
+		}
 		if needParen {
 			ctx.WritePlain("(")
 		}
@@ -834,7 +841,8 @@ func (n *ByItem) Accept(v Visitor) (Node, bool) {
 // GroupByClause represents group by clause.
 type GroupByClause struct {
 	node
-	Items []*ByItem
+	Items  []*ByItem
+	Rollup bool
 }
 
 // Restore implements Node interface.
@@ -848,6 +856,9 @@ func (n *GroupByClause) Restore(ctx *format.RestoreCtx) error {
 			return errors.Annotatef(err, "An error occurred while restore GroupByClause.Items[%d]", i)
 		}
 	}
+	if n.Rollup {
+		ctx.WriteKeyWord(" WITH ROLLUP")
+	}
 	return nil
 }
 
@@ -211,6 +211,10 @@ const (
 	Ord             = "ord"
 	Position        = "position"
 	Quote           = "quote"
+	RegexpInStr     = "regexp_instr"
+	RegexpLike      = "regexp_like"
+	RegexpReplace   = "regexp_replace"
+	RegexpSubstr    = "regexp_substr"
 	Repeat          = "repeat"
 	Replace         = "replace"
 	Reverse         = "reverse"
@@ -288,6 +292,7 @@ const (
 	UUIDToBin       = "uuid_to_bin"
 	BinToUUID       = "bin_to_uuid"
 	VitessHash      = "vitess_hash"
+	Grouping        = "grouping"
 	// get_lock() and release_lock() is parsed but do nothing.
 	// It is used for preventing error in Ruby's activerecord migrations.
 	GetLock     = "get_lock"
@@ -338,6 +343,8 @@ const (
 	JSONDepth         = "json_depth"
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
+	JSONMemberOf      = "json_memberof"
+	JSONOverlaps      = "json_overlaps"
 
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
@@ -389,6 +396,19 @@ func (n *FuncCallExpr) Restore(ctx *format.RestoreCtx) error {
 		return nil
 	}
 
+	if n.FnName.L == JSONMemberOf {
+		if err := n.Args[0].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.(MEMBER OF).Args[0]")
+		}
+		ctx.WriteKeyWord(" MEMBER OF ")
+		ctx.WritePlain("(")
+		if err := n.Args[1].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.(MEMBER OF).Args[1]")
+		}
+		ctx.WritePlain(")")
+		return nil
+	}
+
 	if len(n.Schema.String()) != 0 {
 		ctx.WriteName(n.Schema.O)
 		ctx.WritePlain(".")
@@ -1674,6 +1674,8 @@ const (
 	StatsTypeCardinality uint8 = iota
 	StatsTypeDependency
 	StatsTypeCorrelation
+	// StatsTypeMCV is the list of the most common value combinations of the columns.
+	StatsTypeMCV
 )
 
 // StatisticsSpec is the specification for ADD /DROP STATISTICS.
@@ -1712,6 +1714,8 @@ func (n *CreateStatisticsStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord(" (dependency) ")
 	case StatsTypeCorrelation:
 		ctx.WriteKeyWord(" (correlation) ")
+	case StatsTypeMCV:
+		ctx.WriteKeyWord(" (mcv) ")
 	}
 	ctx.WriteKeyWord("ON ")
 	if err := n.Table.Restore(ctx); err != nil {
@@ -3337,7 +3341,7 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 		ctx.WritePlainf("%d", n.HintData.(uint64))
 	case "nth_plan":
 		ctx.WritePlainf("%d", n.HintData.(int64))
-	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "inl_hash_join", "inl_merge_join":
+	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "inl_hash_join", "inl_merge_join", "leading":
 		for i, table := range n.Tables {
 			if i != 0 {
 				ctx.WritePlain(", ")
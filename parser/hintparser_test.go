@@ -146,6 +146,23 @@ func TestParseHint(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "LEADING(t1, `db`.t2@qb1) leading(@qb2 t3)",
+			output: []*ast.TableOptimizerHint{
+				{
+					HintName: model.NewCIStr("LEADING"),
+					Tables: []ast.HintTable{
+						{TableName: model.NewCIStr("t1")},
+						{DBName: model.NewCIStr("db"), TableName: model.NewCIStr("t2"), QBName: model.NewCIStr("qb1")},
+					},
+				},
+				{
+					HintName: model.NewCIStr("leading"),
+					QBName:   model.NewCIStr("qb2"),
+					Tables:   []ast.HintTable{{TableName: model.NewCIStr("t3")}},
+				},
+			},
+		},
 		{
 			input: "USE_INDEX_MERGE(@qb1 tbl1 x, y, z) IGNORE_INDEX(tbl2@qb2) USE_INDEX(tbl3 PRIMARY) FORCE_INDEX(tbl4@qb3 c1)",
 			output: []*ast.TableOptimizerHint{
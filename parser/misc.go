@@ -153,6 +153,7 @@ var tokenMap = map[string]int{
 	"ANY":                      any,
 	"APPROX_COUNT_DISTINCT":    approxCountDistinct,
 	"APPROX_PERCENTILE":        approxPercentile,
+	"ARRAY":                    array,
 	"AS":                       as,
 	"ASC":                      asc,
 	"ASCII":                    ascii,
@@ -420,6 +421,7 @@ var tokenMap = map[string]int{
 	"LAST_BACKUP":              lastBackup,
 	"LAST":                     last,
 	"LASTVAL":                  lastval,
+	"LATERAL":                  lateral,
 	"LEADER":                   leader,
 	"LEADER_CONSTRAINTS":       leaderConstraints,
 	"LEADING":                  leading,
@@ -458,9 +460,11 @@ var tokenMap = map[string]int{
 	"MAX":                      max,
 	"MAXVALUE":                 maxValue,
 	"MB":                       mb,
+	"MCV":                      mcv,
 	"MEDIUMBLOB":               mediumblobType,
 	"MEDIUMINT":                mediumIntType,
 	"MEDIUMTEXT":               mediumtextType,
+	"MEMBER":                   member,
 	"MEMORY":                   memory,
 	"MERGE":                    merge,
 	"MICROSECOND":              microsecond,
@@ -595,6 +599,7 @@ var tokenMap = map[string]int{
 	"RIGHT":                    right,
 	"RLIKE":                    rlike,
 	"ROLE":                     role,
+	"ROLLUP":                   rollup,
 	"ROLLBACK":                 rollback,
 	"ROUTINE":                  routine,
 	"ROW_COUNT":                rowCount,
@@ -928,6 +933,7 @@ var hintTokenMap = map[string]int{
 	"USE_CASCADES":            hintUseCascades,
 	"NTH_PLAN":                hintNthPlan,
 	"FORCE_INDEX":             hintForceIndex,
+	"LEADING":                 hintLeading,
 
 	// TiDB hint aliases
 	"TIDB_HJ":   hintHashJoin,
@@ -89,6 +89,9 @@ const (
 	ActionAlterTablePlacement           ActionType = 56
 	ActionAlterCacheTable               ActionType = 57
 	ActionAlterTableStatsOptions        ActionType = 58
+	ActionAlterTablePartitioning        ActionType = 59
+	ActionRemovePartitioning            ActionType = 60
+	ActionReorganizePartition           ActionType = 61
 )
 
 var actionMap = map[ActionType]string{
@@ -147,6 +150,9 @@ var actionMap = map[ActionType]string{
 	ActionModifySchemaDefaultPlacement:  "modify schema default placement",
 	ActionAlterCacheTable:               "alter cache table",
 	ActionAlterTableStatsOptions:        "alter table statistics options",
+	ActionAlterTablePartitioning:        "alter table partition by",
+	ActionRemovePartitioning:            "remove partitioning",
+	ActionReorganizePartition:           "reorganize partition",
 }
 
 // String return current ddl action in string
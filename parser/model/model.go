@@ -342,6 +342,10 @@ type TableInfo struct {
 	PlacementPolicyRef   *PolicyRefInfo     `json:"policy_ref_info"`
 	DirectPlacementOpts  *PlacementSettings `json:"placement_settings"`
 
+	// TempTablePreserveRows is true when the global temporary table is created with ON COMMIT PRESERVE ROWS,
+	// its rows are kept in the session after the transaction commits.
+	TempTablePreserveRows bool `json:"temp_table_preserve_rows,omitempty"`
+
 	// StatsOptions is used when do analyze/auto-analyze for each table
 	StatsOptions *StatsOptions `json:"stats_options"`
 }
@@ -521,6 +525,9 @@ func (t *TableInfo) Clone() *TableInfo {
 	nt.Columns = make([]*ColumnInfo, len(t.Columns))
 	nt.Indices = make([]*IndexInfo, len(t.Indices))
 	nt.ForeignKeys = make([]*FKInfo, len(t.ForeignKeys))
+	if t.Constraints != nil {
+		nt.Constraints = make([]*ConstraintInfo, len(t.Constraints))
+	}
 
 	for i := range t.Columns {
 		nt.Columns[i] = t.Columns[i].Clone()
@@ -534,6 +541,10 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	for i := range t.Constraints {
+		nt.Constraints[i] = t.Constraints[i].Clone()
+	}
+
 	return &nt
 }
 
@@ -659,6 +670,12 @@ func (t *TableInfo) IsView() bool {
 	return t.View != nil
 }
 
+// PreserveRowsOnCommit checks whether the TableInfo is a temporary table whose rows are kept in the session
+// after the transaction commits, that is, a local temporary table or a global temporary table with ON COMMIT PRESERVE ROWS.
+func (t *TableInfo) PreserveRowsOnCommit() bool {
+	return t.TempTableType == TempTableLocal || (t.TempTableType == TempTableGlobal && t.TempTablePreserveRows)
+}
+
 // IsSequence checks if TableInfo is a sequence.
 func (t *TableInfo) IsSequence() bool {
 	return t.Sequence != nil
@@ -719,6 +736,8 @@ type ViewCheckOption int
 const (
 	CheckOptionLocal ViewCheckOption = iota
 	CheckOptionCascaded
+	// CheckOptionNone means the view is defined without WITH CHECK OPTION.
+	CheckOptionNone
 )
 
 func (v *ViewCheckOption) String() string {
@@ -727,6 +746,8 @@ func (v *ViewCheckOption) String() string {
 		return "LOCAL"
 	case CheckOptionCascaded:
 		return "CASCADED"
+	case CheckOptionNone:
+		return "NONE"
 	default:
 		return "CASCADED"
 	}
@@ -740,6 +761,19 @@ type ViewInfo struct {
 	SelectStmt  string             `json:"view_select"`
 	CheckOption ViewCheckOption    `json:"view_checkoption"`
 	Cols        []CIStr            `json:"view_cols"`
+	// CheckOptionSpecified indicates whether the view is defined with WITH CHECK OPTION.
+	// The views created by the older versions always carry CheckOptionCascaded even
+	// if the clause is absent, so the CheckOption is ignored unless this is set.
+	CheckOptionSpecified bool `json:"view_checkoption_specified,omitempty"`
+}
+
+// GetCheckOption returns the check option of the view, which is CheckOptionNone
+// if the view is defined without WITH CHECK OPTION.
+func (v *ViewInfo) GetCheckOption() ViewCheckOption {
+	if !v.CheckOptionSpecified {
+		return CheckOptionNone
+	}
+	return v.CheckOption
 }
 
 const (
@@ -803,6 +837,8 @@ type PartitionInfo struct {
 	Type    PartitionType `json:"type"`
 	Expr    string        `json:"expr"`
 	Columns []CIStr       `json:"columns"`
+	// Linear is true for the LINEAR HASH and LINEAR KEY partitions.
+	Linear bool `json:"linear,omitempty"`
 
 	// User may already creates table with partition but table partition is not
 	// yet supported back then. When Enable is true, write/read need use tid
@@ -816,6 +852,23 @@ type PartitionInfo struct {
 	DroppingDefinitions []PartitionDefinition `json:"dropping_definitions"`
 	States              []PartitionState      `json:"states"`
 	Num                 uint64                `json:"num"`
+
+	// DDLState is the state of the partitioning change by ALTER TABLE ... PARTITION BY, REMOVE PARTITIONING etc.
+	// The rows are written to both the current and the other partitioning scheme when it is not StateNone.
+	DDLState SchemaState `json:"ddl_state,omitempty"`
+	// DDLAction is the action of the partitioning change.
+	DDLAction ActionType `json:"ddl_action,omitempty"`
+	// DDLType, DDLExpr, DDLColumns and DDLLinear describe the other partitioning scheme of the change, i.e. the
+	// new one before the data is reorganized, and the old one after that. The partitions in DroppingDefinitions
+	// are reorganized into the ones in AddingDefinitions, see ReorganizedDefinitions.
+	DDLType    PartitionType `json:"ddl_type,omitempty"`
+	DDLExpr    string        `json:"ddl_expr,omitempty"`
+	DDLColumns []CIStr       `json:"ddl_columns,omitempty"`
+	DDLLinear  bool          `json:"ddl_linear,omitempty"`
+	// NewTableID is the new table ID when a non-partitioned table is changed to a partitioned one or the
+	// other way around, it is 0 if the table ID is unchanged. The old table ID is dropped along with its data,
+	// so the tools tracking the tables by their IDs, e.g. BR and TiCDC, see it as a new table.
+	NewTableID int64 `json:"new_table_id,omitempty"`
 }
 
 // GetNameByID gets the partition name by ID.
@@ -842,6 +895,34 @@ func (pi *PartitionInfo) GetPlacementByID(id int64) (*PolicyRefInfo, *PlacementS
 	return nil, nil
 }
 
+// ReorganizedDefinitions returns the partitions of the other partitioning scheme when the partitioning is being
+// changed. The partitions in DroppingDefinitions are replaced by the ones in AddingDefinitions before the data is
+// reorganized, and the other way around after that. The replaced partitions must be consecutive.
+func (pi *PartitionInfo) ReorganizedDefinitions() []PartitionDefinition {
+	oldDefs, newDefs := pi.DroppingDefinitions, pi.AddingDefinitions
+	if pi.DDLState == StateDeleteReorganization {
+		oldDefs, newDefs = newDefs, oldDefs
+	}
+	if len(oldDefs) == 0 {
+		return pi.Definitions
+	}
+	pos := -1
+	for i := range pi.Definitions {
+		if pi.Definitions[i].ID == oldDefs[0].ID {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 || pos+len(oldDefs) > len(pi.Definitions) {
+		return pi.Definitions
+	}
+	defs := make([]PartitionDefinition, 0, len(pi.Definitions)-len(oldDefs)+len(newDefs))
+	defs = append(defs, pi.Definitions[:pos]...)
+	defs = append(defs, newDefs...)
+	defs = append(defs, pi.Definitions[pos+len(oldDefs):]...)
+	return defs
+}
+
 func (pi *PartitionInfo) GetStateByID(id int64) SchemaState {
 	for _, pstate := range pi.States {
 		if pstate.ID == id {
@@ -998,6 +1079,7 @@ type IndexInfo struct {
 	Primary   bool           `json:"is_primary"`   // Whether the index is primary key.
 	Invisible bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global    bool           `json:"is_global"`    // Whether the index is global.
+	MVIndex   bool           `json:"mv_index"`     // Whether the index is a multi-valued index, which has one entry per array element.
 }
 
 // Clone clones IndexInfo.
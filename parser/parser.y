@@ -52,6 +52,8 @@ import (
 	/*yy:token "%c"     */
 	identifier "identifier"
 	asof       "AS OF"
+	memberof   "MEMBER OF"
+	withRollup "WITH ROLLUP"
 
 	/*yy:token "_%c"    */
 	underscoreCS "UNDERSCORE_CHARSET"
@@ -171,6 +173,7 @@ import (
 	kill              "KILL"
 	lag               "LAG"
 	lastValue         "LAST_VALUE"
+	lateral           "LATERAL"
 	lead              "LEAD"
 	leading           "LEADING"
 	left              "LEFT"
@@ -296,6 +299,7 @@ import (
 	algorithm             "ALGORITHM"
 	always                "ALWAYS"
 	any                   "ANY"
+	array                 "ARRAY"
 	ascii                 "ASCII"
 	attributes            "ATTRIBUTES"
 	statsOptions          "STATS_OPTIONS"
@@ -450,6 +454,7 @@ import (
 	maxUpdatesPerHour     "MAX_UPDATES_PER_HOUR"
 	maxUserConnections    "MAX_USER_CONNECTIONS"
 	mb                    "MB"
+	member                "MEMBER"
 	memory                "MEMORY"
 	merge                 "MERGE"
 	microsecond           "MICROSECOND"
@@ -531,6 +536,7 @@ import (
 	resume                "RESUME"
 	reverse               "REVERSE"
 	role                  "ROLE"
+	rollup                "ROLLUP"
 	rollback              "ROLLBACK"
 	routine               "ROUTINE"
 	rowCount              "ROW_COUNT"
@@ -721,6 +727,7 @@ import (
 	drainer                    "DRAINER"
 	jobs                       "JOBS"
 	job                        "JOB"
+	mcv                        "MCV"
 	nodeID                     "NODE_ID"
 	nodeState                  "NODE_STATE"
 	optimistic                 "OPTIMISTIC"
@@ -954,6 +961,7 @@ import (
 	Boolean                                "Boolean (0, 1, false, true)"
 	OptionalBraces                         "optional braces"
 	CastType                               "Cast function target type"
+	CastArrayOpt                           "Cast function target type ARRAY option"
 	ClearPasswordExpireOptions             "Clear password expire options"
 	ColumnDef                              "table column definition"
 	ColumnDefList                          "table column definition list"
@@ -3543,6 +3551,10 @@ StatsType:
 	{
 		$$ = ast.StatsTypeCorrelation
 	}
+|	"MCV"
+	{
+		$$ = ast.StatsTypeMCV
+	}
 
 CreateStatisticsStmt:
 	"CREATE" "STATISTICS" IfNotExists Identifier '(' StatsType ')' "ON" TableName '(' ColumnNameList ')'
@@ -4315,7 +4327,7 @@ CreateViewStmt:
 			endOffset := parser.startOffset(&yyS[yypt])
 			selStmt.SetText(strings.TrimSpace(parser.src[startOffset:endOffset]))
 		} else {
-			x.CheckOption = model.CheckOptionCascaded
+			x.CheckOption = model.CheckOptionNone
 		}
 		$$ = x
 	}
@@ -4400,6 +4412,10 @@ ViewCheckOption:
 	{
 		$$ = nil
 	}
+|	"WITH" "CHECK" "OPTION"
+	{
+		$$ = model.CheckOptionCascaded
+	}
 |	"WITH" "CASCADED" "CHECK" "OPTION"
 	{
 		$$ = model.CheckOptionCascaded
@@ -5527,6 +5543,10 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
 	}
+|	BitExpr memberof '(' SimpleExpr ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONMemberOf), Args: []ast.ExprNode{$1, $4}}
+	}
 |	BitExpr
 
 RegexpSym:
@@ -5609,6 +5629,10 @@ GroupByClause:
 	{
 		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem)}
 	}
+|	"GROUP" "BY" ByList withRollup
+	{
+		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem), Rollup: true}
+	}
 
 HavingClause:
 	{
@@ -5643,6 +5667,15 @@ IfExists:
 		$$ = true
 	}
 
+CastArrayOpt:
+	{
+		$$ = false
+	}
+|	"ARRAY"
+	{
+		$$ = true
+	}
+
 IfNotExists:
 	{
 		$$ = false
@@ -5828,6 +5861,7 @@ Identifier:
 UnReservedKeyword:
 	"ACTION"
 |	"ADVISE"
+|	"ARRAY"
 |	"ASCII"
 |	"ATTRIBUTES"
 |	"STATS_OPTIONS"
@@ -5911,6 +5945,7 @@ UnReservedKeyword:
 |	"REORGANIZE"
 |	"RESTART"
 |	"ROLE"
+|	"ROLLUP"
 |	"ROLLBACK"
 |	"SESSION"
 |	"SIGNED"
@@ -6040,6 +6075,7 @@ UnReservedKeyword:
 |	"ACCOUNT"
 |	"INCREMENTAL"
 |	"CPU"
+|	"MEMBER"
 |	"MEMORY"
 |	"BLOCK"
 |	"IO"
@@ -6176,6 +6212,7 @@ TiDBKeyword:
 |	"DRAINER"
 |	"JOBS"
 |	"JOB"
+|	"MCV"
 |	"NODE_ID"
 |	"NODE_STATE"
 |	"PUMP"
@@ -6916,7 +6953,7 @@ SimpleExpr:
 			FunctionType: ast.CastBinaryOperator,
 		}
 	}
-|	builtinCast '(' Expression "AS" CastType ')'
+|	builtinCast '(' Expression "AS" CastType CastArrayOpt ')'
 	{
 		/* See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_cast */
 		tp := $5.(*types.FieldType)
@@ -6927,6 +6964,7 @@ SimpleExpr:
 		if tp.Decimal == types.UnspecifiedLength {
 			tp.Decimal = defaultDecimal
 		}
+		tp.Array = $6.(bool)
 		explicitCharset := parser.explicitCharset
 		parser.explicitCharset = false
 		$$ = &ast.FuncCastExpr{
@@ -8823,6 +8861,11 @@ TableFactor:
 		resultNode := $1.(*ast.SubqueryExpr).Query
 		$$ = &ast.TableSource{Source: resultNode, AsName: $2.(model.CIStr)}
 	}
+|	"LATERAL" SubSelect TableAsNameOpt
+	{
+		resultNode := $2.(*ast.SubqueryExpr).Query
+		$$ = &ast.TableSource{Source: resultNode, AsName: $3.(model.CIStr), Lateral: true}
+	}
 |	'(' TableRefs ')'
 	{
 		j := $2.(*ast.Join)
@@ -46,6 +46,8 @@ type FieldType struct {
 	Collate string
 	// Elems is the element list for enum and set type.
 	Elems []string
+	// Array indicates the type is an array of the element type, it is only used by `CAST(... AS ... ARRAY)`.
+	Array bool
 }
 
 // NewFieldType returns a FieldType,
@@ -78,6 +80,7 @@ func (ft *FieldType) Equal(other *FieldType) bool {
 		(ignoreDecimal || ft.Decimal == other.Decimal) &&
 		ft.Charset == other.Charset &&
 		ft.Collate == other.Collate &&
+		ft.Array == other.Array &&
 		flenEqual &&
 		mysql.HasUnsignedFlag(ft.Flag) == mysql.HasUnsignedFlag(other.Flag)
 	if !partialEqual || len(ft.Elems) != len(other.Elems) {
@@ -93,6 +96,9 @@ func (ft *FieldType) Equal(other *FieldType) bool {
 
 // EvalType gets the type in evaluation.
 func (ft *FieldType) EvalType() EvalType {
+	if ft.Array {
+		return ETJson
+	}
 	switch ft.Tp {
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
 		mysql.TypeBit, mysql.TypeYear:
@@ -291,15 +297,14 @@ func (ft *FieldType) RestoreAsCastType(ctx *format.RestoreCtx, explicitCharset b
 		if ft.Flen != UnspecifiedLength {
 			ctx.WritePlainf("(%d)", ft.Flen)
 		}
-		if !explicitCharset {
-			return
-		}
-		if !skipWriteBinary && ft.Flag&mysql.BinaryFlag != 0 {
-			ctx.WriteKeyWord(" BINARY")
-		}
-		if ft.Charset != charset.CharsetBin && ft.Charset != mysql.DefaultCharset {
-			ctx.WriteKeyWord(" CHARSET ")
-			ctx.WriteKeyWord(ft.Charset)
+		if explicitCharset {
+			if !skipWriteBinary && ft.Flag&mysql.BinaryFlag != 0 {
+				ctx.WriteKeyWord(" BINARY")
+			}
+			if ft.Charset != charset.CharsetBin && ft.Charset != mysql.DefaultCharset {
+				ctx.WriteKeyWord(" CHARSET ")
+				ctx.WriteKeyWord(ft.Charset)
+			}
 		}
 	case mysql.TypeDate:
 		ctx.WriteKeyWord("DATE")
@@ -335,6 +340,9 @@ func (ft *FieldType) RestoreAsCastType(ctx *format.RestoreCtx, explicitCharset b
 	case mysql.TypeYear:
 		ctx.WriteKeyWord("YEAR")
 	}
+	if ft.Array {
+		ctx.WriteKeyWord(" ARRAY")
+	}
 }
 
 // FormatAsCastType is used for write AST back to string.
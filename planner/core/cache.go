@@ -74,6 +74,9 @@ type pstmtPlanCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	foreignKeyChecks     bool
+	// sqlDigest is the digest of the parameterized statement, which is only set for the non-prepared plan cache.
+	sqlDigest string
 
 	hash []byte
 }
@@ -83,7 +86,7 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 	if len(key.hash) == 0 {
 		var (
 			dbBytes    = hack.Slice(key.database)
-			bufferSize = len(dbBytes) + 8*6 + 3*8
+			bufferSize = len(dbBytes) + 8*6 + 3*8 + 1 + len(key.sqlDigest)
 		)
 		if key.hash == nil {
 			key.hash = make([]byte, 0, bufferSize)
@@ -104,6 +107,14 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		if key.foreignKeyChecks {
+			key.hash = append(key.hash, 1)
+		} else {
+			key.hash = append(key.hash, 0)
+		}
+		if key.sqlDigest != "" {
+			key.hash = append(key.hash, hack.Slice(key.sqlDigest)...)
+		}
 	}
 	return key.hash
 }
@@ -139,6 +150,7 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 		timezoneOffset:       timezoneOffset,
 		isolationReadEngines: make(map[kv.StoreType]struct{}),
 		selectLimit:          sessionVars.SelectLimit,
+		foreignKeyChecks:     sessionVars.ForeignKeyChecks,
 	}
 	for k, v := range sessionVars.IsolationReadEngines {
 		key.isolationReadEngines[k] = v
@@ -146,6 +158,14 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 	return key
 }
 
+// NewNonPreparedPlanCacheKey creates a new pstmtPlanCacheKey object for the non-prepared plan cache, the sqlDigest is
+// the digest of the parameterized statement.
+func NewNonPreparedPlanCacheKey(sessionVars *variable.SessionVars, sqlDigest string, schemaVersion int64) kvcache.Key {
+	key := NewPSTMTPlanCacheKey(sessionVars, 0, schemaVersion).(*pstmtPlanCacheKey)
+	key.sqlDigest = sqlDigest
+	return key
+}
+
 // FieldSlice is the slice of the types.FieldType
 type FieldSlice []types.FieldType
 
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
@@ -190,3 +191,96 @@ func (checker *cacheableChecker) isPartitionTable(tn *ast.TableName) bool {
 func (checker *cacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
 	return in, checker.cacheable
 }
+
+// NonPreparedPlanCacheableWithCtx checks whether the input ast of a text protocol query can use the non-prepared plan
+// cache, and returns the reason if it can't. Only the single table SELECT, UPDATE and DELETE statements, which are
+// also cacheable for the prepared plan cache, are supported now. The reason is empty if the statement is not one of
+// the supported kinds, since it's meaningless to explain why a DDL can't use the plan cache.
+func NonPreparedPlanCacheableWithCtx(sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (bool, string) {
+	var (
+		tableRefs *ast.TableRefsClause
+		hints     []*ast.TableOptimizerHint
+		hasWith   bool
+	)
+	switch x := node.(type) {
+	case *ast.SelectStmt:
+		if x.Kind != ast.SelectStmtKindSelect {
+			return false, ""
+		}
+		if x.SelectIntoOpt != nil {
+			return false, "query has SELECT INTO"
+		}
+		tableRefs, hints, hasWith = x.From, x.TableHints, x.With != nil
+	case *ast.UpdateStmt:
+		if x.MultipleTable {
+			return false, "query accesses more than one table"
+		}
+		tableRefs, hints, hasWith = x.TableRefs, x.TableHints, x.With != nil
+	case *ast.DeleteStmt:
+		if x.IsMultiTable {
+			return false, "query accesses more than one table"
+		}
+		tableRefs, hints, hasWith = x.TableRefs, x.TableHints, x.With != nil
+	default:
+		return false, ""
+	}
+	if hasWith {
+		return false, "query has CTE"
+	}
+	if len(hints) > 0 {
+		return false, "query has hints"
+	}
+	if tableRefs == nil || tableRefs.TableRefs == nil || tableRefs.TableRefs.Right != nil {
+		return false, "query doesn't access exactly one table"
+	}
+	ts, ok := tableRefs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return false, "query doesn't access exactly one table"
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return false, "query accesses a derived table"
+	}
+	if tn.AsOf != nil {
+		return false, "query reads a stale snapshot"
+	}
+	if util.IsMemOrSysDB(tn.Schema.L) {
+		return false, "query accesses a memory or system table"
+	}
+	checker := nonPreparedCacheableChecker{}
+	node.Accept(&checker)
+	if checker.reason != "" {
+		return false, checker.reason
+	}
+	if !CacheableWithCtx(sctx, node, is) {
+		return false, "query is not cacheable for the prepared plan cache"
+	}
+	return true, ""
+}
+
+// nonPreparedCacheableChecker checks the rules of the non-prepared plan cache which are not covered by
+// cacheableChecker.
+type nonPreparedCacheableChecker struct {
+	reason string
+}
+
+// Enter implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch in.(type) {
+	case *driver.ParamMarkerExpr:
+		checker.reason = "query has parameter markers"
+		return in, true
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr:
+		checker.reason = "query has sub-queries"
+		return in, true
+	case *ast.VariableExpr:
+		checker.reason = "query has variables"
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, checker.reason == ""
+}
@@ -884,6 +884,14 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 
 	RowLen int
+
+	// FKChecks and FKCascades are the foreign key checks and cascades of the inserted rows,
+	// the replaced rows of REPLACE and the updated rows of ON DUPLICATE KEY UPDATE.
+	FKChecks   []*FKCheck
+	FKCascades []*FKCascade
+
+	// ViewChecks are the WITH CHECK OPTION conditions of the view which the rows are inserted through.
+	ViewChecks []*ViewCheck
 }
 
 // Update represents Update plan.
@@ -905,6 +913,13 @@ type Update struct {
 	PartitionedTable []table.PartitionedTable
 
 	tblID2Table map[int64]table.Table
+
+	// FKChecks and FKCascades are the foreign key checks and cascades of the updated tables, keyed by the table ID.
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
+
+	// ViewChecks are the WITH CHECK OPTION conditions of the views which the tables are updated through, keyed by the table ID.
+	ViewChecks map[int64][]*ViewCheck
 }
 
 // Delete represents a delete plan.
@@ -916,6 +931,10 @@ type Delete struct {
 	SelectPlan PhysicalPlan
 
 	TblColPosInfos TblColPosInfoSlice
+
+	// FKChecks and FKCascades are the foreign key checks and cascades of the deleted tables, keyed by the table ID.
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
 }
 
 // AnalyzeInfo is used to store the database name, table name and partition name of analyze task.
@@ -1252,15 +1271,26 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, driverSide, indent st
 		err = e.explainPlanInRowFormat(x.tablePlan, "cop[tikv]", "(Probe)", childIndent, true)
 	case *Insert:
 		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, len(x.FKChecks)+len(x.FKCascades) == 0)
+		}
+		if err == nil {
+			err = e.explainFKPlans(x.FKChecks, x.FKCascades, childIndent)
 		}
 	case *Update:
+		fkChecks, fkCascades := collectFKPlans(x.TblColPosInfos, x.FKChecks, x.FKCascades)
 		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, len(fkChecks)+len(fkCascades) == 0)
+		}
+		if err == nil {
+			err = e.explainFKPlans(fkChecks, fkCascades, childIndent)
 		}
 	case *Delete:
+		fkChecks, fkCascades := collectFKPlans(x.TblColPosInfos, x.FKChecks, x.FKCascades)
 		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, len(fkChecks)+len(fkCascades) == 0)
+		}
+		if err == nil {
+			err = e.explainFKPlans(fkChecks, fkCascades, childIndent)
 		}
 	case *Execute:
 		if x.Plan != nil {
@@ -1314,6 +1344,44 @@ func getRuntimeInfo(ctx sessionctx.Context, p Plan, runtimeStatsColl *execdetail
 
 // prepareOperatorInfo generates the following information for every plan:
 // operator id, estimated rows, task type, access object and other operator info.
+// explainFKPlans explains the foreign key checks and cascades after the select plan of the write statement.
+func (e *Explain) explainFKPlans(fkChecks []*FKCheck, fkCascades []*FKCascade, childIndent string) error {
+	for i, check := range fkChecks {
+		err := e.explainPlanInRowFormat(check, "root", "", childIndent, i == len(fkChecks)-1 && len(fkCascades) == 0)
+		if err != nil {
+			return err
+		}
+	}
+	for i, cascade := range fkCascades {
+		err := e.explainPlanInRowFormat(cascade, "root", "", childIndent, i == len(fkCascades)-1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectFKPlans collects the foreign key plans in the order of the written tables.
+func collectFKPlans(infos TblColPosInfoSlice, checks map[int64][]*FKCheck, cascades map[int64][]*FKCascade) ([]*FKCheck, []*FKCascade) {
+	if len(checks) == 0 && len(cascades) == 0 {
+		return nil, nil
+	}
+	var (
+		fkChecks   []*FKCheck
+		fkCascades []*FKCascade
+	)
+	visited := make(map[int64]struct{}, len(infos))
+	for _, info := range infos {
+		if _, ok := visited[info.TblID]; ok {
+			continue
+		}
+		visited[info.TblID] = struct{}{}
+		fkChecks = append(fkChecks, checks[info.TblID]...)
+		fkCascades = append(fkCascades, cascades[info.TblID]...)
+	}
+	return fkChecks, fkCascades
+}
+
 func (e *Explain) prepareOperatorInfo(p Plan, taskType, driverSide, indent string, isLastChild bool) {
 	if p.ExplainID().String() == "_0" {
 		return
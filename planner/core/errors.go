@@ -50,6 +50,8 @@ var (
 	ErrWrongGroupField                       = dbterror.ClassOptimizer.NewStd(mysql.ErrWrongGroupField)
 	ErrDupFieldName                          = dbterror.ClassOptimizer.NewStd(mysql.ErrDupFieldName)
 	ErrNonUpdatableTable                     = dbterror.ClassOptimizer.NewStd(mysql.ErrNonUpdatableTable)
+	ErrNonInsertableTable                    = dbterror.ClassOptimizer.NewStd(mysql.ErrNonInsertableTable)
+	ErrNonupdateableColumn                   = dbterror.ClassOptimizer.NewStd(mysql.ErrNonupdateableColumn)
 	ErrMultiUpdateKeyConflict                = dbterror.ClassOptimizer.NewStd(mysql.ErrMultiUpdateKeyConflict)
 	ErrInternal                              = dbterror.ClassOptimizer.NewStd(mysql.ErrInternal)
 	ErrNonUniqTable                          = dbterror.ClassOptimizer.NewStd(mysql.ErrNonuniqTable)
@@ -82,6 +84,7 @@ var (
 	ErrViewNoExplain                         = dbterror.ClassOptimizer.NewStd(mysql.ErrViewNoExplain)
 	ErrWrongValueCountOnRow                  = dbterror.ClassOptimizer.NewStd(mysql.ErrWrongValueCountOnRow)
 	ErrViewInvalid                           = dbterror.ClassOptimizer.NewStd(mysql.ErrViewInvalid)
+	ErrViewNonupdCheck                       = dbterror.ClassOptimizer.NewStd(mysql.ErrViewNonupdCheck)
 	ErrNoSuchThread                          = dbterror.ClassOptimizer.NewStd(mysql.ErrNoSuchThread)
 	ErrUnknownColumn                         = dbterror.ClassOptimizer.NewStd(mysql.ErrBadField)
 	ErrCartesianProductUnsupported           = dbterror.ClassOptimizer.NewStd(mysql.ErrCartesianProductUnsupported)
@@ -49,6 +49,8 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrWrongGroupField,
 		ErrDupFieldName,
 		ErrNonUpdatableTable,
+		ErrNonInsertableTable,
+		ErrNonupdateableColumn,
 		ErrInternal,
 		ErrNonUniqTable,
 		ErrWindowInvalidWindowFuncUse,
@@ -79,6 +81,7 @@ func (s testErrorSuite) TestError(c *C) {
 		ErrViewNoExplain,
 		ErrWrongValueCountOnRow,
 		ErrViewInvalid,
+		ErrViewNonupdCheck,
 		ErrNoSuchThread,
 		ErrUnknownColumn,
 		ErrCartesianProductUnsupported,
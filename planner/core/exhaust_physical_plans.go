@@ -2293,6 +2293,23 @@ func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([
 	return []PhysicalPlan{window}, true, nil
 }
 
+func (p *LogicalExpand) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
+	// The Expand is only executed by TiDB, pushing it down to TiFlash in the MPP mode is out of scope until tipb
+	// and TiFlash support it, see docs/design/2026-10-16-group-by-with-rollup.md.
+	if !prop.IsEmpty() || prop.IsFlashProp() {
+		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because operator `Expand` is not supported now.")
+		return nil, true, nil
+	}
+	expand := PhysicalExpand{
+		GroupByExprs:  p.GroupByExprs,
+		GroupByCols:   p.GroupByCols,
+		GroupingSets:  p.GroupingSets,
+		GroupingIDCol: p.GroupingIDCol,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	expand.SetSchema(p.Schema())
+	return []PhysicalPlan{expand}, true, nil
+}
+
 // exhaustPhysicalPlans is only for implementing interface. DataSource and Dual generate task in `findBestTask` directly.
 func (p *baseLogicalPlan) exhaustPhysicalPlans(_ *property.PhysicalProperty) ([]PhysicalPlan, bool, error) {
 	panic("baseLogicalPlan.exhaustPhysicalPlans() should never be called.")
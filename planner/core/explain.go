@@ -775,6 +775,32 @@ func (p *PhysicalWindow) formatFrameBound(buffer *bytes.Buffer, bound *FrameBoun
 	}
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalExpand) ExplainInfo() string {
+	buffer := bytes.NewBufferString("group by:")
+	for i, expr := range p.GroupByExprs {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(expr.ExplainInfo())
+	}
+	buffer.WriteString(", grouping sets:")
+	for i, set := range p.GroupingSets {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString("(")
+		for j, offset := range set {
+			if j > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(p.GroupByExprs[offset].ExplainInfo())
+		}
+		buffer.WriteString(")")
+	}
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalWindow) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
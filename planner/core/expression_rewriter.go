@@ -337,6 +337,14 @@ func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.Subqu
 
 // Enter implements Visitor interface.
 func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
+	if er.b.rollup != nil {
+		if expr, ok := inNode.(ast.ExprNode); ok {
+			if col, name := er.b.rollup.resolveGroupByExpr(expr, er.schema, er.names); col != nil {
+				er.ctxStackAppend(col, name)
+				return inNode, true
+			}
+		}
+	}
 	switch v := inNode.(type) {
 	case *ast.AggregateFuncExpr:
 		index, ok := -1, false
@@ -1112,6 +1120,18 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 			return retNode, false
 		}
 
+		// check the argument and element type of "CAST(AS ... ARRAY)".
+		if v.Tp.Array {
+			if arg.GetType().EvalType() != types.ETJson {
+				er.err = expression.ErrNotSupportedCastAsArray.GenWithStackByArgs("Non-JSON Array type")
+			} else if v.Tp.Tp == mysql.TypeJSON {
+				er.err = expression.ErrNotSupportedCastAsArray.GenWithStackByArgs("JSON")
+			}
+			if er.err != nil {
+				return retNode, false
+			}
+		}
+
 		castFunction := expression.BuildCastFunction(er.sctx, arg, v.Tp)
 		if v.Tp.EvalType() == types.ETString {
 			castFunction.SetCoercibility(expression.CoercibilityImplicit)
@@ -1764,11 +1784,82 @@ func (er *expressionRewriter) rewriteFuncCall(v *ast.FuncCallExpr) bool {
 		er.ctxStackPop(len(v.Args))
 		er.ctxStackAppend(funcIf, types.EmptyName)
 		return true
+	case ast.Grouping:
+		er.rewriteGrouping(v)
+		return true
 	default:
 		return false
 	}
 }
 
+// rewriteGrouping rewrites GROUPING(expr, ...) of `GROUP BY ... WITH ROLLUP` to the bit operations on the grouping id
+// generated by LogicalExpand. GROUPING(expr) is 1 if expr is rolled up in the current row, otherwise 0. For multiple
+// arguments, the result of the first argument is the most significant bit.
+func (er *expressionRewriter) rewriteGrouping(v *ast.FuncCallExpr) {
+	if len(v.Args) == 0 {
+		er.err = expression.ErrIncorrectParameterCount.GenWithStackByArgs(v.FnName.O)
+		return
+	}
+	rollup := er.b.rollup
+	if rollup == nil || rollup.groupingIDCol == nil {
+		er.err = ErrInvalidGroupFuncUse
+		return
+	}
+	idx := er.schema.ColumnIndex(rollup.groupingIDCol)
+	if idx < 0 {
+		er.err = ErrInvalidGroupFuncUse
+		return
+	}
+	groupingID := er.schema.Columns[idx]
+	stackLen := len(er.ctxStack)
+	args := er.ctxStack[stackLen-len(v.Args):]
+	var result expression.Expression
+	for _, arg := range args {
+		offset := -1
+		if col, ok := arg.(*expression.Column); ok {
+			for i, gbyCol := range rollup.gbyCols {
+				if gbyCol.UniqueID == col.UniqueID {
+					offset = i
+					break
+				}
+			}
+		}
+		if offset < 0 {
+			er.err = ErrWrongArguments.GenWithStackByArgs(strings.ToUpper(v.FnName.L))
+			return
+		}
+		// (grouping_id >> offset) & 1
+		offsetConst := &expression.Constant{Value: types.NewIntDatum(int64(offset)), RetType: types.NewFieldType(mysql.TypeLonglong)}
+		bit, err := er.newFunction(ast.RightShift, types.NewFieldType(mysql.TypeLonglong), groupingID, offsetConst)
+		if err != nil {
+			er.err = err
+			return
+		}
+		bit, err = er.newFunction(ast.And, types.NewFieldType(mysql.TypeLonglong), bit, expression.NewOne())
+		if err != nil {
+			er.err = err
+			return
+		}
+		if result == nil {
+			result = bit
+			continue
+		}
+		// (result << 1) | bit
+		result, err = er.newFunction(ast.LeftShift, types.NewFieldType(mysql.TypeLonglong), result, expression.NewOne())
+		if err != nil {
+			er.err = err
+			return
+		}
+		result, err = er.newFunction(ast.Or, types.NewFieldType(mysql.TypeLonglong), result, bit)
+		if err != nil {
+			er.err = err
+			return
+		}
+	}
+	er.ctxStackPop(len(v.Args))
+	er.ctxStackAppend(result, types.EmptyName)
+}
+
 func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 	stackLen := len(er.ctxStack)
 	args := er.ctxStack[stackLen-len(v.Args):]
@@ -0,0 +1,417 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/plancodec"
+)
+
+// FKLookup describes how to find the rows of a table by the values of the foreign key columns.
+type FKLookup struct {
+	// Tbl is the looked up table, it's nil if the table doesn't exist.
+	Tbl table.Table
+	// Idx is the index whose leading columns are Cols, it's nil if the rows are
+	// looked up by the int handle or by scanning the whole table.
+	Idx table.Index
+	// IsHandle indicates the rows are looked up by the int handle.
+	IsHandle bool
+	// Cols are the looked up columns of Tbl.
+	Cols []*table.Column
+	// ValueOffsets are the offsets of the columns in the written row, which supply the values to look up.
+	ValueOffsets []int
+}
+
+func (l *FKLookup) explainInfo(buffer *bytes.Buffer) {
+	if l.Tbl == nil {
+		return
+	}
+	fmt.Fprintf(buffer, ", table:%s", l.Tbl.Meta().Name.O)
+	switch {
+	case l.IsHandle:
+		buffer.WriteString(", handle:" + l.Cols[0].Name.O)
+	case l.Idx != nil:
+		buffer.WriteString(", index:" + l.Idx.Meta().Name.O)
+	default:
+		buffer.WriteString(", full_scan")
+	}
+}
+
+// FKCheck indicates the foreign key constraint checker.
+// When CheckExist is true, it checks the referred parent row exists for the written child row,
+// otherwise it checks no child row refers to the deleted or updated parent row.
+type FKCheck struct {
+	basePhysicalPlan
+	FKLookup
+
+	FK *model.FKInfo
+	// FKTable is the table which defines the foreign key.
+	FKTable    *model.TableInfo
+	CheckExist bool
+	FailedErr  error
+}
+
+// Init initializes FKCheck.
+func (p FKCheck) Init(ctx sessionctx.Context) *FKCheck {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeForeignKeyCheck, &p, 0)
+	return &p
+}
+
+// ExplainInfo implements Plan interface.
+func (p *FKCheck) ExplainInfo() string {
+	buffer := bytes.NewBufferString("foreign_key:" + p.FK.Name.O)
+	p.FKLookup.explainInfo(buffer)
+	if p.CheckExist {
+		buffer.WriteString(", check_exist")
+	} else {
+		buffer.WriteString(", check_not_exist")
+	}
+	return buffer.String()
+}
+
+// FKCascadeType indicates in which case the referential action is triggered.
+type FKCascadeType int8
+
+const (
+	// FKCascadeOnDelete indicates the action is triggered by deleting the parent rows.
+	FKCascadeOnDelete FKCascadeType = 1
+	// FKCascadeOnUpdate indicates the action is triggered by updating the parent rows.
+	FKCascadeOnUpdate FKCascadeType = 2
+)
+
+// FKCascade indicates the CASCADE or SET NULL referential action on the child rows.
+// The child rows are looked up by the old values of the referred columns in the parent row.
+type FKCascade struct {
+	basePhysicalPlan
+	FKLookup
+
+	Tp FKCascadeType
+	FK *model.FKInfo
+	// DBName is the schema of the child table, it's used to build the cascades of the child table.
+	DBName model.CIStr
+	// ReferOpt is the referential action of the foreign key for Tp.
+	ReferOpt ast.ReferOptionType
+}
+
+// Init initializes FKCascade.
+func (p FKCascade) Init(ctx sessionctx.Context) *FKCascade {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeForeignKeyCascade, &p, 0)
+	return &p
+}
+
+// ExplainInfo implements Plan interface.
+func (p *FKCascade) ExplainInfo() string {
+	buffer := bytes.NewBufferString("foreign_key:" + p.FK.Name.O)
+	p.FKLookup.explainInfo(buffer)
+	if p.Tp == FKCascadeOnDelete {
+		buffer.WriteString(", on_delete:")
+	} else {
+		buffer.WriteString(", on_update:")
+	}
+	buffer.WriteString(p.ReferOpt.String())
+	return buffer.String()
+}
+
+// BuildOnInsertFKChecks builds the foreign key checks of the rows written into the child table.
+// If updatedCols isn't nil, only the foreign keys which contain the updated columns are checked.
+func BuildOnInsertFKChecks(ctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr, tbl table.Table, updatedCols map[string]struct{}) ([]*FKCheck, error) {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil, nil
+	}
+	tblInfo := tbl.Meta()
+	checks := make([]*FKCheck, 0, len(tblInfo.ForeignKeys))
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.State != model.StatePublic || !isFKColsUpdated(fk.Cols, updatedCols) {
+			continue
+		}
+		offsets, err := getFKColOffsets(tbl, fk.Cols)
+		if err != nil {
+			return nil, err
+		}
+		check := FKCheck{
+			FK:         fk,
+			FKTable:    tblInfo,
+			CheckExist: true,
+			FailedErr:  table.ErrNoReferencedRow2.FastGenByArgs(fkDescription(dbName, tblInfo, fk)),
+		}.Init(ctx)
+		check.ValueOffsets = offsets
+		parent, err := is.TableByName(dbName, fk.RefTable)
+		if err == nil {
+			if err = buildFKLookup(&check.FKLookup, parent, fk.RefCols); err != nil {
+				return nil, err
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// BuildOnDeleteFKTriggers builds the foreign key checks and cascades triggered by deleting the rows of the parent table.
+func BuildOnDeleteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr, tbl table.Table) ([]*FKCheck, []*FKCascade, error) {
+	return buildOnParentWriteFKTriggers(ctx, is, dbName, tbl, FKCascadeOnDelete, nil)
+}
+
+// BuildOnUpdateFKTriggers builds the foreign key checks and cascades triggered by updating the rows of the table,
+// it includes the checks of the table as a child table and the checks and cascades of the table as a parent table.
+func BuildOnUpdateFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr, tbl table.Table, updatedCols map[string]struct{}) ([]*FKCheck, []*FKCascade, error) {
+	checks, err := BuildOnInsertFKChecks(ctx, is, dbName, tbl, updatedCols)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentChecks, cascades, err := buildOnParentWriteFKTriggers(ctx, is, dbName, tbl, FKCascadeOnUpdate, updatedCols)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(checks, parentChecks...), cascades, nil
+}
+
+func buildOnParentWriteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr, tbl table.Table,
+	tp FKCascadeType, updatedCols map[string]struct{}) ([]*FKCheck, []*FKCascade, error) {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil, nil, nil
+	}
+	var (
+		checks   []*FKCheck
+		cascades []*FKCascade
+	)
+	tblInfo := tbl.Meta()
+	for _, child := range is.SchemaTables(dbName) {
+		for _, fk := range child.Meta().ForeignKeys {
+			if fk.State != model.StatePublic || fk.RefTable.L != tblInfo.Name.L || !isFKColsUpdated(fk.RefCols, updatedCols) {
+				continue
+			}
+			offsets, err := getFKColOffsets(tbl, fk.RefCols)
+			if err != nil {
+				return nil, nil, err
+			}
+			var lookup FKLookup
+			if err = buildFKLookup(&lookup, child, fk.Cols); err != nil {
+				return nil, nil, err
+			}
+			lookup.ValueOffsets = offsets
+			referOpt := ast.ReferOptionType(fk.OnDelete)
+			if tp == FKCascadeOnUpdate {
+				referOpt = ast.ReferOptionType(fk.OnUpdate)
+			}
+			switch referOpt {
+			case ast.ReferOptionCascade, ast.ReferOptionSetNull:
+				cascade := FKCascade{Tp: tp, FK: fk, DBName: dbName, ReferOpt: referOpt}.Init(ctx)
+				cascade.FKLookup = lookup
+				cascades = append(cascades, cascade)
+			default:
+				// RESTRICT, NO ACTION and SET DEFAULT all reject the write of the referred parent row.
+				check := FKCheck{
+					FK:        fk,
+					FKTable:   child.Meta(),
+					FailedErr: table.ErrRowIsReferenced2.FastGenByArgs(fkDescription(dbName, child.Meta(), fk)),
+				}.Init(ctx)
+				check.FKLookup = lookup
+				checks = append(checks, check)
+			}
+		}
+	}
+	return checks, cascades, nil
+}
+
+func (p *Insert) buildOnInsertFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, dbName model.CIStr, onDupColSet map[string]struct{}) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	checks, err := BuildOnInsertFKChecks(ctx, is, dbName, p.Table, nil)
+	if err != nil {
+		return err
+	}
+	var (
+		parentChecks []*FKCheck
+		cascades     []*FKCascade
+	)
+	if p.IsReplace {
+		parentChecks, cascades, err = BuildOnDeleteFKTriggers(ctx, is, dbName, p.Table)
+	} else if len(p.OnDuplicate) > 0 {
+		parentChecks, cascades, err = buildOnParentWriteFKTriggers(ctx, is, dbName, p.Table, FKCascadeOnUpdate, onDupColSet)
+	}
+	if err != nil {
+		return err
+	}
+	p.FKChecks = append(checks, parentChecks...)
+	p.FKCascades = cascades
+	return nil
+}
+
+func (updt *Update) buildOnUpdateFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	tblID2UpdatedCols := make(map[int64]map[string]struct{}, len(tblID2table))
+	for _, assign := range updt.OrderedList {
+		for _, info := range updt.TblColPosInfos {
+			if assign.Col.Index < info.Start || assign.Col.Index >= info.End {
+				continue
+			}
+			cols := tblID2table[info.TblID].WritableCols()
+			if offset := assign.Col.Index - info.Start; offset < len(cols) {
+				if tblID2UpdatedCols[info.TblID] == nil {
+					tblID2UpdatedCols[info.TblID] = make(map[string]struct{})
+				}
+				tblID2UpdatedCols[info.TblID][cols[offset].Name.L] = struct{}{}
+			}
+		}
+	}
+	for tblID, updatedCols := range tblID2UpdatedCols {
+		tbl := tblID2table[tblID]
+		dbInfo, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		checks, cascades, err := BuildOnUpdateFKTriggers(ctx, is, dbInfo.Name, tbl, updatedCols)
+		if err != nil {
+			return err
+		}
+		if len(checks) > 0 {
+			if updt.FKChecks == nil {
+				updt.FKChecks = make(map[int64][]*FKCheck)
+			}
+			updt.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if updt.FKCascades == nil {
+				updt.FKCascades = make(map[int64][]*FKCascade)
+			}
+			updt.FKCascades[tblID] = cascades
+		}
+	}
+	return nil
+}
+
+func (del *Delete) buildOnDeleteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) error {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return nil
+	}
+	for tblID, tbl := range tblID2table {
+		dbInfo, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		checks, cascades, err := BuildOnDeleteFKTriggers(ctx, is, dbInfo.Name, tbl)
+		if err != nil {
+			return err
+		}
+		if len(checks) > 0 {
+			if del.FKChecks == nil {
+				del.FKChecks = make(map[int64][]*FKCheck)
+			}
+			del.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if del.FKCascades == nil {
+				del.FKCascades = make(map[int64][]*FKCascade)
+			}
+			del.FKCascades[tblID] = cascades
+		}
+	}
+	return nil
+}
+
+// buildFKLookup finds the way to look up the rows of tbl by the cols.
+// A public index whose leading columns are the cols is preferred, the int handle is used if
+// the cols is the handle column, otherwise the whole table is scanned.
+func buildFKLookup(lookup *FKLookup, tbl table.Table, cols []model.CIStr) error {
+	tblInfo := tbl.Meta()
+	if tblInfo.GetPartitionInfo() != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("foreign key on the partitioned table")
+	}
+	lookup.Tbl = tbl
+	lookup.Cols = make([]*table.Column, 0, len(cols))
+	for _, name := range cols {
+		col := table.FindColLowerCase(tbl.Cols(), name.L)
+		if col == nil {
+			return ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		lookup.Cols = append(lookup.Cols, col)
+	}
+	if len(cols) == 1 && tblInfo.PKIsHandle && mysql.HasPriKeyFlag(lookup.Cols[0].Flag) {
+		lookup.IsHandle = true
+		return nil
+	}
+	for _, idx := range tbl.Indices() {
+		idxInfo := idx.Meta()
+		if idxInfo.State != model.StatePublic || len(idxInfo.Columns) < len(cols) {
+			continue
+		}
+		match := true
+		for i, name := range cols {
+			if idxInfo.Columns[i].Name.L != name.L || idxInfo.Columns[i].Length != types.UnspecifiedLength {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		// The unique index can locate the row directly, so it's preferred.
+		if lookup.Idx == nil || (!lookup.Idx.Meta().Unique && idxInfo.Unique) {
+			lookup.Idx = idx
+		}
+	}
+	return nil
+}
+
+func getFKColOffsets(tbl table.Table, cols []model.CIStr) ([]int, error) {
+	offsets := make([]int, 0, len(cols))
+	for _, name := range cols {
+		col := table.FindColLowerCase(tbl.Cols(), name.L)
+		if col == nil {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		offsets = append(offsets, col.Offset)
+	}
+	return offsets, nil
+}
+
+func isFKColsUpdated(cols []model.CIStr, updatedCols map[string]struct{}) bool {
+	if updatedCols == nil {
+		return true
+	}
+	for _, col := range cols {
+		if _, ok := updatedCols[col.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fkDescription returns the foreign key description used in the error message, e.g.
+// `test`.`child`, CONSTRAINT `fk_1` FOREIGN KEY (`a`) REFERENCES `parent` (`id`)
+func fkDescription(dbName model.CIStr, tblInfo *model.TableInfo, fk *model.FKInfo) string {
+	quote := func(names []model.CIStr) string {
+		quoted := make([]string, 0, len(names))
+		for _, name := range names {
+			quoted = append(quoted, "`"+name.O+"`")
+		}
+		return strings.Join(quoted, ", ")
+	}
+	return fmt.Sprintf("`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+		dbName.O, tblInfo.Name.O, fk.Name.O, quote(fk.Cols), fk.RefTable.O, quote(fk.RefCols))
+}
@@ -210,6 +210,20 @@ func (p PhysicalWindow) Init(ctx sessionctx.Context, stats *property.StatsInfo,
 	return &p
 }
 
+// Init initializes LogicalExpand.
+func (p LogicalExpand) Init(ctx sessionctx.Context, offset int) *LogicalExpand {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeExpand, &p, offset)
+	return &p
+}
+
+// Init initializes PhysicalExpand.
+func (p PhysicalExpand) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int, props ...*property.PhysicalProperty) *PhysicalExpand {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeExpand, &p, offset)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes PhysicalShuffle.
 func (p PhysicalShuffle) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int, props ...*property.PhysicalProperty) *PhysicalShuffle {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeShuffle, &p, offset)
@@ -111,6 +111,8 @@ const (
 	HintIgnorePlanCache = "ignore_plan_cache"
 	// HintLimitToCop is a hint enforce pushing limit or topn to coprocessor.
 	HintLimitToCop = "limit_to_cop"
+	// HintLeading specifies the set of tables to be used as the prefix in the execution plan.
+	HintLeading = "leading"
 )
 
 const (
@@ -168,6 +170,131 @@ func (a *aggOrderByResolver) Leave(inNode ast.Node) (ast.Node, bool) {
 	return inNode, true
 }
 
+// rollupInfo records the group by items of a `GROUP BY ... WITH ROLLUP` query block and the columns generated for
+// them by LogicalExpand. It is used to resolve the group by items and GROUPING() above the aggregation, which should
+// see NULL in the super-aggregate rows.
+type rollupInfo struct {
+	// gbyExprs are the group by items that are not columns, the columns are resolved by their names.
+	gbyExprs []ast.ExprNode
+	gbyTexts []string
+
+	gbyCols       []*expression.Column
+	groupingIDCol *expression.Column
+}
+
+func newRollupInfo(gby *ast.GroupByClause) *rollupInfo {
+	r := &rollupInfo{
+		gbyExprs: make([]ast.ExprNode, len(gby.Items)),
+		gbyTexts: make([]string, len(gby.Items)),
+	}
+	for i, item := range gby.Items {
+		if _, ok := item.Expr.(*ast.ColumnNameExpr); ok {
+			continue
+		}
+		text, ok := restoreExprNode(item.Expr)
+		if !ok {
+			continue
+		}
+		r.gbyExprs[i], r.gbyTexts[i] = item.Expr, text
+	}
+	return r
+}
+
+func restoreExprNode(expr ast.ExprNode) (string, bool) {
+	var sb strings.Builder
+	if err := expr.Restore(format.NewRestoreCtx(0, &sb)); err != nil {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// resolveGroupByExpr returns the column generated for the group by item if expr is equal to it.
+func (r *rollupInfo) resolveGroupByExpr(expr ast.ExprNode, schema *expression.Schema, names types.NameSlice) (*expression.Column, *types.FieldName) {
+	if r.gbyCols == nil {
+		return nil, nil
+	}
+	var text string
+	for i, gbyExpr := range r.gbyExprs {
+		if gbyExpr == nil {
+			continue
+		}
+		idx := schema.ColumnIndex(r.gbyCols[i])
+		if idx < 0 {
+			continue
+		}
+		if gbyExpr != expr {
+			if text == "" {
+				var ok bool
+				if text, ok = restoreExprNode(expr); !ok {
+					return nil, nil
+				}
+			}
+			if text != r.gbyTexts[i] {
+				continue
+			}
+		}
+		return schema.Columns[idx], names[idx]
+	}
+	return nil, nil
+}
+
+// buildExpand builds the LogicalExpand of `GROUP BY ... WITH ROLLUP` and returns the group by items of the
+// aggregation above it, which are the group by columns generated by the Expand and the grouping id.
+func (b *PlanBuilder) buildExpand(p LogicalPlan, gbyItems []expression.Expression) (LogicalPlan, []expression.Expression) {
+	expand := LogicalExpand{
+		GroupByExprs: gbyItems,
+		GroupByCols:  make([]*expression.Column, 0, len(gbyItems)),
+	}.Init(b.ctx, b.getSelectOffset())
+	schema := p.Schema().Clone()
+	names := make(types.NameSlice, p.Schema().Len(), p.Schema().Len()+len(gbyItems)+1)
+	copy(names, p.OutputNames())
+	for _, item := range gbyItems {
+		tp := item.GetType().Clone()
+		tp.Flag &^= mysql.NotNullFlag
+		col := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  tp,
+		}
+		name := types.EmptyName
+		if c, ok := item.(*expression.Column); ok {
+			// The references to the group by column above the aggregation are resolved to the generated column
+			// by its name, and the original column is only used by the aggregate functions.
+			if idx := p.Schema().ColumnIndex(c); idx >= 0 && names[idx] != types.EmptyName {
+				name, names[idx] = names[idx], types.EmptyName
+			}
+		}
+		expand.GroupByCols = append(expand.GroupByCols, col)
+		schema.Append(col)
+		names = append(names, name)
+	}
+	expand.GroupingIDCol = &expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  types.NewFieldType(mysql.TypeLonglong),
+	}
+	expand.GroupingIDCol.RetType.Flag |= mysql.NotNullFlag
+	schema.Append(expand.GroupingIDCol)
+	names = append(names, types.EmptyName)
+	// WITH ROLLUP rolls up the group by items from right to left.
+	for i := len(gbyItems); i >= 0; i-- {
+		set := make([]int, 0, i)
+		for j := 0; j < i; j++ {
+			set = append(set, j)
+		}
+		expand.GroupingSets = append(expand.GroupingSets, set)
+	}
+	expand.SetChildren(p)
+	expand.SetSchema(schema)
+	expand.names = names
+	b.rollup.gbyCols, b.rollup.groupingIDCol = expand.GroupByCols, expand.GroupingIDCol
+
+	newGbyItems := make([]expression.Expression, 0, len(gbyItems)+1)
+	for _, col := range expand.GroupByCols {
+		newGbyItems = append(newGbyItems, col)
+	}
+	newGbyItems = append(newGbyItems, expand.GroupingIDCol)
+	return expand, newGbyItems
+}
+
 func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression,
 	correlatedAggMap map[*ast.AggregateFuncExpr]int) (LogicalPlan, map[int]int, error) {
 	b.optFlag |= flagBuildKeyInfo
@@ -265,6 +392,9 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 			}
 		}
 	}
+	if b.rollup != nil && len(gbyItems) > 0 {
+		p, gbyItems = b.buildExpand(p, gbyItems)
+	}
 	for i, col := range p.Schema().Columns {
 		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
@@ -593,8 +723,12 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 		p.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
 		p.preferJoinType = 0
 	}
+	// set the join order
+	if len(hintInfo.leadingJoinOrder) > 0 {
+		p.preferJoinOrder = true
+	}
 	// set hintInfo for further usage if this hint info can be used.
-	if p.preferJoinType != 0 {
+	if p.preferJoinType != 0 || p.preferJoinOrder {
 		p.hintInfo = hintInfo
 	}
 }
@@ -683,7 +817,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
-	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	rightPlan, err := b.buildJoinRightSide(ctx, leftPlan, joinNode)
 	if err != nil {
 		return nil, err
 	}
@@ -697,7 +831,20 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	handleMap2 := b.handleHelper.popMap()
 	b.handleHelper.mergeAndPush(handleMap1, handleMap2)
 
-	joinPlan := LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	var (
+		joinPlan   *LogicalJoin
+		resultPlan LogicalPlan
+	)
+	// A LATERAL derived table which refers to the columns of the left side is evaluated
+	// for every row of the left side, so it's built as an Apply instead of a Join.
+	if len(extractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())) > 0 {
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		ap := LogicalApply{LogicalJoin: LogicalJoin{StraightJoin: true}}.Init(b.ctx, b.getSelectOffset())
+		joinPlan, resultPlan = &ap.LogicalJoin, ap
+	} else {
+		joinPlan = LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+		resultPlan = joinPlan
+	}
 	joinPlan.SetChildren(leftPlan, rightPlan)
 	joinPlan.SetSchema(expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema()))
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len()+rightPlan.Schema().Len())
@@ -762,11 +909,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		}
 	} else if joinNode.On != nil {
 		b.curClause = onClause
-		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, joinPlan, nil, false)
+		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, resultPlan, nil, false)
 		if err != nil {
 			return nil, err
 		}
-		if newPlan != joinPlan {
+		if newPlan != resultPlan {
 			return nil, errors.New("ON condition doesn't support subqueries yet")
 		}
 		onCondition := expression.SplitCNFItems(onExpr)
@@ -774,7 +921,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(resultPlan)
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -784,7 +931,26 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return resultPlan, nil
+}
+
+// buildJoinRightSide builds the right side of the join. If it's a LATERAL derived table, the
+// schema of the left side is pushed as an outer schema, so the derived table can refer to the
+// columns of the preceding table sources as correlated columns.
+func (b *PlanBuilder) buildJoinRightSide(ctx context.Context, leftPlan LogicalPlan, joinNode *ast.Join) (LogicalPlan, error) {
+	ts, ok := joinNode.Right.(*ast.TableSource)
+	// The LATERAL derived table can't refer to the columns of the left side of a RIGHT JOIN,
+	// because the right side is the outer table. We just leave the reference unresolved.
+	if !ok || !ts.Lateral || joinNode.Tp == ast.RightJoin {
+		return b.buildResultSetNode(ctx, joinNode.Right)
+	}
+	b.outerSchemas = append(b.outerSchemas, leftPlan.Schema().Clone())
+	b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	defer func() {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}()
+	return b.buildResultSetNode(ctx, joinNode.Right)
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -1529,6 +1695,90 @@ func (b *PlanBuilder) buildSemiJoinForSetOperator(
 	return joinPlan, nil
 }
 
+// buildRowNumberForSetOperator appends a `row_number() over (partition by all columns)` column to the plan, so that
+// the duplicated rows are numbered and can be matched one by one by INTERSECT ALL and EXCEPT ALL.
+func (b *PlanBuilder) buildRowNumberForSetOperator(p LogicalPlan) (LogicalPlan, error) {
+	desc, err := aggregation.NewWindowFuncDesc(b.ctx, ast.WindowFuncRowNumber, []expression.Expression{})
+	if err != nil {
+		return nil, err
+	}
+	if desc == nil {
+		return nil, ErrWrongArguments.GenWithStackByArgs(ast.WindowFuncRowNumber)
+	}
+	partitionBy := make([]property.SortItem, 0, p.Schema().Len())
+	for _, col := range p.Schema().Columns {
+		partitionBy = append(partitionBy, property.SortItem{Col: col})
+	}
+	window := LogicalWindow{
+		WindowFuncDescs: []*aggregation.WindowFuncDesc{desc},
+		PartitionBy:     partitionBy,
+	}.Init(b.ctx, b.getSelectOffset())
+	window.names = make([]*types.FieldName, p.Schema().Len(), p.Schema().Len()+1)
+	copy(window.names, p.OutputNames())
+	window.names = append(window.names, types.EmptyName)
+	schema := p.Schema().Clone()
+	schema.Append(&expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  desc.RetTp,
+	})
+	window.SetChildren(p)
+	window.SetSchema(schema)
+	return window, nil
+}
+
+// buildBagSemiJoinForSetOperator builds the set operators 'intersect all' and 'except all'. Both sides are numbered by
+// buildRowNumberForSetOperator, then the n-th duplicate of a left row only matches the n-th duplicate of the same row on
+// the right side. So a semi join keeps min(m, n) copies of a row and an anti semi join keeps max(m-n, 0) copies, where
+// m and n are the occurrences of the row on the left and right side.
+func (b *PlanBuilder) buildBagSemiJoinForSetOperator(
+	leftOriginPlan LogicalPlan,
+	rightOriginPlan LogicalPlan,
+	joinType JoinType) (LogicalPlan, error) {
+	columnNums := leftOriginPlan.Schema().Len()
+	leftPlan, err := b.buildRowNumberForSetOperator(leftOriginPlan)
+	if err != nil {
+		return nil, err
+	}
+	rightPlan, err := b.buildRowNumberForSetOperator(rightOriginPlan)
+	if err != nil {
+		return nil, err
+	}
+	joinPlan := LogicalJoin{JoinType: joinType}.Init(b.ctx, b.getSelectOffset())
+	joinPlan.SetChildren(leftPlan, rightPlan)
+	joinPlan.SetSchema(leftPlan.Schema())
+	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len())
+	copy(joinPlan.names, leftPlan.OutputNames())
+	for j := 0; j < columnNums; j++ {
+		leftCol, rightCol := leftPlan.Schema().Columns[j], rightPlan.Schema().Columns[j]
+		eqCond, err := expression.NewFunction(b.ctx, ast.NullEQ, types.NewFieldType(mysql.TypeTiny), leftCol, rightCol)
+		if err != nil {
+			return nil, err
+		}
+		if leftCol.RetType.Tp != rightCol.RetType.Tp {
+			joinPlan.OtherConditions = append(joinPlan.OtherConditions, eqCond)
+		} else {
+			joinPlan.EqualConditions = append(joinPlan.EqualConditions, eqCond.(*expression.ScalarFunction))
+		}
+	}
+	leftRowNum, rightRowNum := leftPlan.Schema().Columns[columnNums], rightPlan.Schema().Columns[columnNums]
+	eqCond, err := expression.NewFunction(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), leftRowNum, rightRowNum)
+	if err != nil {
+		return nil, err
+	}
+	joinPlan.EqualConditions = append(joinPlan.EqualConditions, eqCond.(*expression.ScalarFunction))
+
+	// Remove the row number column from the output.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(joinPlan.Schema().Columns[:columnNums])}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(joinPlan)
+	schema := expression.NewSchema(joinPlan.Schema().Clone().Columns[:columnNums]...)
+	for _, col := range schema.Columns {
+		col.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+	}
+	proj.names = joinPlan.OutputNames()[:columnNums]
+	proj.SetSchema(schema)
+	return proj, nil
+}
+
 // buildIntersect build the set operator for 'intersect'. It is called before buildExcept and buildUnion because of its
 // higher precedence.
 func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (LogicalPlan, *ast.SetOprType, error) {
@@ -1553,18 +1803,13 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 	columnNums := leftPlan.Schema().Len()
 	for i := 1; i < len(selects); i++ {
 		var rightPlan LogicalPlan
+		var setOpr ast.SetOprType
 		switch x := selects[i].(type) {
 		case *ast.SelectStmt:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			setOpr = *x.AfterSetOperator
 			rightPlan, err = b.buildSelect(ctx, x)
 		case *ast.SetOprSelectList:
-			if *x.AfterSetOperator == ast.IntersectAll {
-				// TODO: support intersect all
-				return nil, nil, errors.Errorf("TiDB do not support intersect all")
-			}
+			setOpr = *x.AfterSetOperator
 			rightPlan, err = b.buildSetOpr(ctx, &ast.SetOprStmt{SelectList: x})
 		}
 		if err != nil {
@@ -1573,7 +1818,11 @@ func (b *PlanBuilder) buildIntersect(ctx context.Context, selects []ast.Node) (L
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		if setOpr == ast.IntersectAll {
+			leftPlan, err = b.buildBagSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		} else {
+			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, SemiJoin)
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1592,20 +1841,21 @@ func (b *PlanBuilder) buildExcept(ctx context.Context, selects []LogicalPlan, af
 		if rightPlan.Schema().Len() != columnNums {
 			return nil, ErrWrongNumberOfColumnsInSelect.GenWithStackByArgs()
 		}
-		if *afterSetOpts[i] == ast.Except {
+		if *afterSetOpts[i] == ast.Except || *afterSetOpts[i] == ast.ExceptAll {
 			leftPlan, err := b.buildUnion(ctx, unionPlans, tmpAfterSetOpts)
 			if err != nil {
 				return nil, err
 			}
-			leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			if *afterSetOpts[i] == ast.ExceptAll {
+				leftPlan, err = b.buildBagSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			} else {
+				leftPlan, err = b.buildSemiJoinForSetOperator(leftPlan, rightPlan, AntiSemiJoin)
+			}
 			if err != nil {
 				return nil, err
 			}
 			unionPlans = []LogicalPlan{leftPlan}
 			tmpAfterSetOpts = []*ast.SetOprType{nil}
-		} else if *afterSetOpts[i] == ast.ExceptAll {
-			// TODO: support except all.
-			return nil, errors.Errorf("TiDB do not support except all")
 		} else {
 			unionPlans = append(unionPlans, rightPlan)
 			tmpAfterSetOpts = append(tmpAfterSetOpts, afterSetOpts[i])
@@ -1957,7 +2207,7 @@ func (a *havingWindowAndOrderbyExprResolver) popCurClause() {
 
 // Enter implements Visitor interface.
 func (a *havingWindowAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChildren bool) {
-	switch n.(type) {
+	switch v := n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
 	case *ast.WindowFuncExpr:
@@ -1969,6 +2219,12 @@ func (a *havingWindowAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, s
 		// Enter a new context, skip it.
 		// For example: select sum(c) + c + exists(select c from t) from t;
 		return n, true
+	case *ast.FuncCallExpr:
+		a.inExpr = true
+		if v.FnName.L == ast.Grouping && !a.inAggFunc {
+			// GROUPING() is evaluated above the aggregation as an auxiliary select field, see Leave.
+			return n, true
+		}
 	case *ast.PartitionByClause:
 		a.pushCurClause(partitionByClause)
 	case *ast.OrderByClause:
@@ -2053,6 +2309,18 @@ func (a *havingWindowAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, o
 				AsName:    model.NewCIStr(fmt.Sprintf("sel_window_%d", len(a.selectFields))),
 			})
 		}
+	case *ast.FuncCallExpr:
+		if v.FnName.L == ast.Grouping && !a.inAggFunc {
+			asName := model.NewCIStr(fmt.Sprintf("sel_grouping_%d", len(a.selectFields)))
+			a.selectFields = append(a.selectFields, &ast.SelectField{
+				Auxiliary: true,
+				Expr:      v,
+				AsName:    asName,
+			})
+			colExpr := &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: asName}}
+			a.colMapper[colExpr] = len(a.selectFields) - 1
+			return colExpr, true
+		}
 	case *ast.WindowSpec:
 		a.inWindowSpec = false
 	case *ast.PartitionByClause:
@@ -3245,12 +3513,14 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 		aggHints                                                                                              aggHintInfo
 		timeRangeHint                                                                                         ast.HintTimeRange
 		limitHints                                                                                            limitHintInfo
+		leadingJoinOrder                                                                                      []hintTableInfo
+		leadingHintCnt                                                                                        int
 	)
 	for _, hint := range hints {
 		// Set warning for the hint that requires the table name.
 		switch hint.HintName.L {
 		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ,
-			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintIndexMerge:
+			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintIndexMerge, HintLeading:
 			if len(hint.Tables) == 0 {
 				b.pushHintWithoutTableWarning(hint)
 				continue
@@ -3349,10 +3619,20 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 			timeRangeHint = hint.HintData.(ast.HintTimeRange)
 		case HintLimitToCop:
 			limitHints.preferLimitToCop = true
+		case HintLeading:
+			if leadingHintCnt == 0 {
+				leadingJoinOrder = append(leadingJoinOrder, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+			}
+			leadingHintCnt++
 		default:
 			// ignore hints that not implemented
 		}
 	}
+	if leadingHintCnt > 1 {
+		// The join order is ambiguous if there are more than one leading hints, so all of them are ignored.
+		leadingJoinOrder = nil
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("We can only use one leading hint at most, when multiple leading hints are used, all leading hints will be invalid"))
+	}
 	b.tableHintInfo = append(b.tableHintInfo, tableHintInfo{
 		sortMergeJoinTables:         sortMergeTables,
 		broadcastJoinTables:         BCTables,
@@ -3366,6 +3646,7 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 		indexMergeHintList:          indexMergeHintList,
 		timeRangeHint:               timeRangeHint,
 		limitHints:                  limitHints,
+		leadingJoinOrder:            leadingJoinOrder,
 	})
 }
 
@@ -3453,6 +3734,10 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		// table hints are only visible in the current SELECT statement.
 		b.popTableHints()
 	}()
+	// The rollup of the outer query block is invisible in the current one.
+	oldRollup := b.rollup
+	b.rollup = nil
+	defer func() { b.rollup = oldRollup }()
 	if b.buildingRecursivePartForCTE {
 		if sel.Distinct || sel.OrderBy != nil || sel.Limit != nil {
 			return nil, ErrNotSupportedYet.GenWithStackByArgs("ORDER BY / LIMIT / SELECT DISTINCT in recursive query block of Common Table Expression")
@@ -3528,6 +3813,9 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		if err != nil {
 			return nil, err
 		}
+		if sel.GroupBy.Rollup {
+			b.rollup = newRollupInfo(sel.GroupBy)
+		}
 	}
 
 	if b.ctx.GetSessionVars().SQLMode.HasOnlyFullGroupBy() && sel.From != nil {
@@ -3891,7 +4179,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	}
 
 	is := b.is
-	if len(b.buildingViewStack) > 0 {
+	if len(b.buildingViewStack) > 0 || b.isMergedViewBase(tn) {
 		// For tables in view, always ignore local temporary table, considering the below case:
 		// If a user created a normal table `t1` and a view `v1` referring `t1`, and then a local temporary table with a same name `t1` is created.
 		// At this time, executing 'select * from v1' should still return all records from normal table `t1` instead of temporary table `t1`.
@@ -3908,11 +4196,14 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		return nil, ErrViewSelectTemporaryTable.GenWithStackByArgs(tn.Name)
 	}
 
-	var authErr error
-	if sessionVars.User != nil {
-		authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
+	// The privileges on the base table of the merged view are checked with the view.
+	if !b.isMergedViewBase(tn) {
+		var authErr error
+		if sessionVars.User != nil {
+			authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
 
 	if tbl.Type().IsVirtualTable() {
 		if tn.TableSample != nil {
@@ -4137,7 +4428,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 
 	var result LogicalPlan = ds
 	dirty := tableHasDirtyContent(b.ctx, tableInfo)
-	if dirty || tableInfo.TempTableType == model.TempTableLocal {
+	if dirty || tableInfo.PreserveRowsOnCommit() {
 		us := LogicalUnionScan{handleCols: handleCols}.Init(b.ctx, b.getSelectOffset())
 		us.SetChildren(ds)
 		result = us
@@ -4306,10 +4597,7 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 	}
 	defer deferFunc()
 
-	charset, collation := b.ctx.GetSessionVars().GetCharsetInfo()
-	viewParser := parser.New()
-	viewParser.SetParserConfig(b.ctx.GetSessionVars().BuildParserConfig())
-	selectNode, err := viewParser.ParseOneStmt(tableInfo.View.SelectStmt, charset, collation)
+	selectNode, err := b.parseViewSelect(tableInfo)
 	if err != nil {
 		return nil, err
 	}
@@ -4597,6 +4885,22 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		}
 	}
 
+	mv, err := b.mergeTargetView(update.TableRefs.TableRefs, "UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	if mv != nil {
+		defer mv.restore()
+		if err = mv.mergeUpdateStmt(update); err != nil {
+			return nil, err
+		}
+		b.mergedView = mv
+		defer func() { b.mergedView = nil }()
+		if err = b.appendMergedViewVisitInfo(mv.updatableView, mysql.UpdatePriv, mysql.SelectPriv); err != nil {
+			return nil, err
+		}
+	}
+
 	p, err := b.buildResultSetNode(ctx, update.TableRefs.TableRefs)
 	if err != nil {
 		return nil, err
@@ -4605,6 +4909,9 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 	var tableList []*ast.TableName
 	tableList = extractTableList(update.TableRefs.TableRefs, tableList, false)
 	for _, t := range tableList {
+		if b.isMergedViewBase(t) {
+			continue
+		}
 		dbName := t.Schema.L
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
@@ -4613,6 +4920,12 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 	}
 
 	oldSchemaLen := p.Schema().Len()
+	if mv != nil && mv.where != nil {
+		p, err = b.buildSelection(ctx, p, mv.where, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if update.Where != nil {
 		p, err = b.buildSelection(ctx, p, update.Where, nil)
 		if err != nil {
@@ -4692,8 +5005,21 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
 	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	if err != nil {
+		return nil, err
+	}
 	updt.PartitionedTable = b.partitionedTable
 	updt.tblID2Table = tblID2table
+	if mv != nil {
+		checks, err := b.buildViewChecks(ctx, mv.updatableView)
+		if err != nil {
+			return nil, err
+		}
+		if len(checks) > 0 {
+			updt.ViewChecks = map[int64][]*ViewCheck{mv.base.TableInfo.ID: checks}
+		}
+	}
+	err = updt.buildOnUpdateFKTriggers(b.ctx, b.is, tblID2table)
 	return updt, err
 }
 
@@ -4814,7 +5140,9 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		if !found {
 			return nil, nil, false, infoschema.ErrTableNotExists.GenWithStackByArgs(tn.DBInfo.Name.O, tableInfo.Name.O)
 		}
-		for i, colInfo := range tableInfo.Columns {
+		// The stored generated columns being added are not public, they are evaluated by the table when the rows are written.
+		for _, col := range tableVal.Cols() {
+			colInfo := col.ColumnInfo
 			if !colInfo.IsGenerated() {
 				continue
 			}
@@ -4830,7 +5158,7 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 			}
 			virtualAssignments = append(virtualAssignments, &ast.Assignment{
 				Column: &ast.ColumnName{Schema: tn.Schema, Table: tn.Name, Name: colInfo.Name},
-				Expr:   tableVal.Cols()[i].GeneratedExpr,
+				Expr:   col.GeneratedExpr,
 			})
 		}
 	}
@@ -4921,7 +5249,10 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, "", nil)
+		// The privileges on the base table of the merged view are checked with the view.
+		if b.mergedView == nil {
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, "", nil)
+		}
 	}
 	return newList, p, allAssignmentsAreConstant, nil
 }
@@ -4959,6 +5290,29 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 		}
 	}
 
+	var mv *mergedView
+	if !delete.IsMultiTable {
+		var err error
+		if mv, err = b.mergeTargetView(delete.TableRefs.TableRefs, "DELETE"); err != nil {
+			return nil, err
+		}
+	}
+	if mv != nil {
+		defer mv.restore()
+		if err := mv.mergeDeleteStmt(delete); err != nil {
+			return nil, err
+		}
+		b.mergedView = mv
+		defer func() { b.mergedView = nil }()
+		privs := []mysql.PrivilegeType{mysql.DeletePriv}
+		if delete.Where != nil || delete.Order != nil {
+			privs = append(privs, mysql.SelectPriv)
+		}
+		if err := b.appendMergedViewVisitInfo(mv.updatableView, privs...); err != nil {
+			return nil, err
+		}
+	}
+
 	p, err := b.buildResultSetNode(ctx, delete.TableRefs.TableRefs)
 	if err != nil {
 		return nil, err
@@ -4966,6 +5320,12 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	oldSchema := p.Schema()
 	oldLen := oldSchema.Len()
 
+	if mv != nil && mv.where != nil {
+		p, err = b.buildSelection(ctx, p, mv.where, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// For explicit column usage, should use the all-public columns.
 	if delete.Where != nil {
 		p, err = b.buildSelection(ctx, p, delete.Where, nil)
@@ -4999,7 +5359,7 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	}
 
 	// If the delete is non-qualified it does not require Select Priv
-	if delete.Where == nil && delete.Order == nil {
+	if delete.Where == nil && delete.Order == nil && mv == nil {
 		b.popVisitInfo()
 	}
 	var authErr error
@@ -5089,6 +5449,9 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 		var tableList []*ast.TableName
 		tableList = extractTableList(delete.TableRefs.TableRefs, tableList, false)
 		for _, v := range tableList {
+			if b.isMergedViewBase(v) {
+				continue
+			}
 			if isCTE(v) {
 				return nil, ErrNonUpdatableTable.GenWithStackByArgs(v.Name.O, "DELETE")
 			}
@@ -5124,6 +5487,10 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
 	del.TblColPosInfos, err = buildColumns2Handle(del.names, tblID2Handle, tblID2table, false)
+	if err != nil {
+		return nil, err
+	}
+	err = del.buildOnDeleteFKTriggers(b.ctx, b.is, tblID2table)
 	return del, err
 }
 
@@ -541,6 +541,47 @@ func (s *testPlanSuite) TestJoinReOrder(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestOuterJoinReorderAndLeadingHint(c *C) {
+	defer testleak.AfterTest(c)()
+	var input []string
+	var output []struct {
+		SQL      string
+		Plan     string
+		Warnings []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+
+	sessVars := s.ctx.GetSessionVars()
+	sessVars.EnableOuterJoinReorder = true
+	defer func() {
+		sessVars.EnableOuterJoinReorder = false
+	}()
+	ctx := context.Background()
+	for i, tt := range input {
+		comment := Commentf("for %s", tt)
+		sessVars.StmtCtx.SetWarnings(nil)
+		stmt, err := s.ParseOneStmt(tt, "", "")
+		c.Assert(err, IsNil, comment)
+
+		p, _, err := BuildLogicalPlanForTest(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(context.TODO(), flagPredicatePushDown|flagJoinReOrder, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		planString := ToString(p)
+		var warnings []string
+		for _, warn := range sessVars.StmtCtx.GetWarnings() {
+			warnings = append(warnings, warn.Err.Error())
+		}
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = planString
+			output[i].Warnings = warnings
+		})
+		c.Assert(planString, Equals, output[i].Plan, comment)
+		c.Assert(warnings, DeepEquals, output[i].Warnings, comment)
+	}
+}
+
 func (s *testPlanSuite) TestEagerAggregation(c *C) {
 	defer testleak.AfterTest(c)()
 	var input []string
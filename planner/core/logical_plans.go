@@ -52,6 +52,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalExpand{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -129,8 +130,9 @@ type LogicalJoin struct {
 	StraightJoin  bool
 
 	// hintInfo stores the join algorithm hint information specified by client.
-	hintInfo       *tableHintInfo
-	preferJoinType uint
+	hintInfo        *tableHintInfo
+	preferJoinType  uint
+	preferJoinOrder bool
 
 	EqualConditions []*expression.ScalarFunction
 	LeftConditions  expression.CNFExprs
@@ -1174,6 +1176,46 @@ func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
 	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
 }
 
+// LogicalExpand replicates every input row once for each grouping set. It is placed below the aggregation of
+// `GROUP BY ... WITH ROLLUP` to produce the super-aggregate rows. In the replica of a grouping set, the group by
+// columns that are not in the set are NULL, and the grouping id column records which of them are rolled up.
+type LogicalExpand struct {
+	logicalSchemaProducer
+
+	// GroupByExprs are the group by items evaluated on the child.
+	GroupByExprs []expression.Expression
+	// GroupByCols are the output columns of GroupByExprs, which are NULL when rolled up.
+	GroupByCols []*expression.Column
+	// GroupingSets contains the offsets of the GroupByExprs kept by each replica.
+	GroupingSets [][]int
+	// GroupingIDCol is the output column of the grouping id.
+	GroupingIDCol *expression.Column
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalExpand) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.GroupByExprs))
+	for _, expr := range p.GroupByExprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
+// GetExpandResultColumns returns the columns generated by the Expand, i.e. the group by columns and the grouping id.
+func (p *LogicalExpand) GetExpandResultColumns() []*expression.Column {
+	return p.schema.Columns[p.schema.Len()-len(p.GroupByCols)-1:]
+}
+
+// GroupingID returns the grouping id of the grouping set, the i-th bit of which is set when the i-th group by item
+// is rolled up in the set.
+func GroupingID(groupingSet []int, numGroupByItems int) int64 {
+	var id int64 = 1<<uint(numGroupByItems) - 1
+	for _, offset := range groupingSet {
+		id &^= 1 << uint(offset)
+	}
+	return id
+}
+
 // ExtractCorColumnsBySchema only extracts the correlated columns that match the specified schema.
 // e.g. If the correlated columns from plan are [t1.a, t2.a, t3.a] and specified schema is [t2.a, t2.b, t2.c],
 // only [t2.a] is returned.
@@ -1350,11 +1350,15 @@ type StatementsSummaryExtractor struct {
 	// Enable is true means the executor should use digest to locate statement summary.
 	// Enable is false, means the executor should keep the behavior compatible with before.
 	Enable bool
+	// CoarseTimeRange is extracted from `summary_begin_time` and `summary_end_time`, the summaries overlapping with it
+	// are read from the persisted history. A zero StartTime or EndTime means unbounded, and it is nil if no time range
+	// is specified. The predicates are still kept to filter the summaries exactly.
+	CoarseTimeRange *TimeRange
 }
 
 // Extract implements the MemTablePredicateExtractor Extract interface
 func (e *StatementsSummaryExtractor) Extract(
-	_ sessionctx.Context,
+	ctx sessionctx.Context,
 	schema *expression.Schema,
 	names []*types.FieldName,
 	predicates []expression.Expression,
@@ -1369,6 +1373,25 @@ func (e *StatementsSummaryExtractor) Extract(
 		e.Enable = true
 		e.Digests = digests
 	}
+
+	timezone := ctx.GetSessionVars().StmtCtx.TimeZone
+	_, beginStart, beginEnd := e.extractTimeRange(ctx, schema, names, remained, "summary_begin_time", timezone)
+	_, endStart, endEnd := e.extractTimeRange(ctx, schema, names, remained, "summary_end_time", timezone)
+	// A summary overlaps with the time range if it ends after the start time and begins before the end time.
+	startTime := mathutil.MaxInt64(beginStart, endStart)
+	endTime := beginEnd
+	if endTime == 0 || (endEnd != 0 && endEnd < endTime) {
+		endTime = endEnd
+	}
+	if startTime != 0 || endTime != 0 {
+		e.CoarseTimeRange = &TimeRange{}
+		if startTime != 0 {
+			e.CoarseTimeRange.StartTime = time.Unix(0, startTime)
+		}
+		if endTime != 0 {
+			e.CoarseTimeRange.EndTime = time.Unix(0, endTime)
+		}
+	}
 	return remained
 }
 
@@ -1376,8 +1399,24 @@ func (e *StatementsSummaryExtractor) explainInfo(p *PhysicalMemTable) string {
 	if e.SkipRequest {
 		return "skip_request: true"
 	}
-	if !e.Enable {
-		return ""
+	r := new(bytes.Buffer)
+	if e.Enable {
+		r.WriteString(fmt.Sprintf("digests: [%s], ", extractStringFromStringSet(e.Digests)))
 	}
-	return fmt.Sprintf("digests: [%s]", extractStringFromStringSet(e.Digests))
+	if e.CoarseTimeRange != nil {
+		formatTime := func(t time.Time) string {
+			if t.IsZero() {
+				return "unbounded"
+			}
+			t = t.In(p.ctx.GetSessionVars().StmtCtx.TimeZone)
+			return types.NewTime(types.FromGoTime(t), mysql.TypeDatetime, types.MaxFsp).String()
+		}
+		r.WriteString(fmt.Sprintf("start_time:%v, end_time:%v, ", formatTime(e.CoarseTimeRange.StartTime), formatTime(e.CoarseTimeRange.EndTime)))
+	}
+	// remove the last ", " in the message info
+	s := r.String()
+	if len(s) > 2 {
+		return s[:len(s)-2]
+	}
+	return s
 }
@@ -0,0 +1,266 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var (
+	nonPreparedPlanCacheCounter     = metrics.PlanCacheCounter.WithLabelValues("non-prepare")
+	nonPreparedPlanCacheMissCounter = metrics.PlanCacheMissCounter.WithLabelValues("non-prepare")
+)
+
+// nonPreparedPlanCacheValue is the value of the non-prepared plan cache.
+type nonPreparedPlanCacheValue struct {
+	*PSTMTPlanCacheValue
+	// visitInfos are used to check the privileges when the plan is reused.
+	visitInfos     []visitInfo
+	normalizedPlan string
+	planDigest     *parser.Digest
+}
+
+// GetPlanFromNonPreparedPlanCache tries to get the plan of a text protocol query from the plan cache. The constants
+// in the WHERE clause of the query are replaced with parameter markers first, so the queries only differing in these
+// constants can share the same plan, just like executing a prepared statement with different parameters.
+// If ok is false, the query can't use the non-prepared plan cache and should be optimized as usual.
+func GetPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, stmt ast.StmtNode,
+	is infoschema.InfoSchema) (p Plan, names types.NameSlice, ok bool, err error) {
+	sessVars := sctx.GetSessionVars()
+	stmtCtx := sessVars.StmtCtx
+	// UseCache is already set when the statement is optimized for a prepared statement or the non-prepared plan cache.
+	if !sessVars.EnableNonPreparedPlanCache || !PreparedPlanCacheEnabled() || sctx.PreparedPlanCache() == nil ||
+		stmtCtx.UseCache || sessVars.InRestrictedSQL || variable.RestrictedReadOnly.Load() {
+		return nil, nil, false, nil
+	}
+	if cacheable, reason := NonPreparedPlanCacheableWithCtx(sctx, stmt, is); !cacheable {
+		if reason != "" && stmtCtx.InExplainStmt {
+			stmtCtx.AppendWarning(errors.Errorf("skip non-prepared plan cache: %s", reason))
+		}
+		return nil, nil, false, nil
+	}
+
+	replacer := parameterizeWhereClause(stmt)
+	defer restoreWhereClause(stmt, replacer)
+	params := replacer.params
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return nil, nil, false, nil
+	}
+	// The parameterized statement is digested as it is, because normalizing it would also replace the constants
+	// which are not parameterized, such as the ones in the field list, and the plans for them may differ.
+	sqlDigest := parser.DigestNormalized(sb.String())
+	cacheKey := NewNonPreparedPlanCacheKey(sessVars, sqlDigest.String(), is.SchemaMetaVersion())
+
+	tps := make([]*types.FieldType, len(params))
+	sessVars.PreparedParams = sessVars.PreparedParams[:0]
+	for i, param := range params {
+		tps[i] = types.NewFieldType(mysql.TypeUnspecified)
+		types.DefaultParamTypeForValue(param.GetValue(), tps[i])
+		sessVars.PreparedParams = append(sessVars.PreparedParams, param.Datum)
+	}
+	stmtCtx.UseCache = true
+
+	if cacheValue, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
+		cached := cacheValue.(*nonPreparedPlanCacheValue)
+		p, err = getCachedNonPreparedPlan(sctx, is, stmt, cacheKey, cached, tps)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if p != nil {
+			if err := sessVars.SetSystemVar(variable.TiDBFoundInPlanCache, variable.On); err != nil {
+				return nil, nil, false, err
+			}
+			if metrics.ResettablePlanCacheCounterFortTest {
+				metrics.PlanCacheCounter.WithLabelValues("non-prepare").Inc()
+			} else {
+				nonPreparedPlanCacheCounter.Inc()
+			}
+			stmtCtx.SetPlanDigest(cached.normalizedPlan, cached.planDigest)
+			if stmtCtx.InExplainStmt {
+				stmtCtx.AppendWarning(errors.New("use non-prepared plan cache"))
+			}
+			return p, cached.OutPutNames, true, nil
+		}
+	}
+
+	if metrics.ResettablePlanCacheCounterFortTest {
+		metrics.PlanCacheMissCounter.WithLabelValues("non-prepare").Inc()
+	} else {
+		nonPreparedPlanCacheMissCounter.Inc()
+	}
+	// The warnings are truncated if the parameterized statement fails to be optimized, so that the statement can be
+	// optimized again as usual without duplicated warnings.
+	warnCnt := len(stmtCtx.GetWarnings())
+	builder, _ := NewPlanBuilder().Init(sctx, is, &hint.BlockHintProcessor{})
+	_, err = builder.Build(ctx, stmt)
+	if err == nil {
+		p, names, err = OptimizeAstNode(ctx, sctx, stmt, is)
+	}
+	if err != nil {
+		logutil.BgLogger().Debug("optimize the parameterized statement failed", zap.Error(err))
+		stmtCtx.TruncateWarnings(warnCnt)
+		stmtCtx.UseCache = false
+		stmtCtx.MaybeOverOptimized4PlanCache = false
+		sessVars.PreparedParams = sessVars.PreparedParams[:0]
+		return nil, nil, false, nil
+	}
+	if err := sessVars.SetSystemVar(variable.TiDBFoundInPlanCache, variable.Off); err != nil {
+		return nil, nil, false, err
+	}
+	if _, isTableDual := p.(*PhysicalTableDual); !isTableDual && !stmtCtx.MaybeOverOptimized4PlanCache {
+		cached := &nonPreparedPlanCacheValue{
+			PSTMTPlanCacheValue: NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps),
+			visitInfos:          builder.GetVisitInfo(),
+		}
+		cached.normalizedPlan, cached.planDigest = NormalizePlan(p)
+		stmtCtx.SetPlanDigest(cached.normalizedPlan, cached.planDigest)
+		sctx.PreparedPlanCache().Put(cacheKey, cached)
+	}
+	if stmtCtx.InExplainStmt {
+		stmtCtx.AppendWarning(errors.New("non-prepared plan cache miss"))
+	}
+	return p, names, true, nil
+}
+
+// getCachedNonPreparedPlan checks whether the cached plan can be reused, and rebuilds its ranges with the current
+// parameters. It returns nil if the plan can't be reused.
+func getCachedNonPreparedPlan(sctx sessionctx.Context, is infoschema.InfoSchema, stmt ast.StmtNode, cacheKey kvcache.Key,
+	cached *nonPreparedPlanCacheValue, tps []*types.FieldType) (Plan, error) {
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		visitInfo := VisitInfo4PrivCheck(is, stmt, cached.visitInfos)
+		if err := CheckPrivilege(sctx.GetSessionVars().ActiveRoles, pm, visitInfo); err != nil {
+			return nil, err
+		}
+	}
+	if err := CheckTableLock(sctx, is, cached.visitInfos); err != nil {
+		return nil, err
+	}
+	if !cached.UserVarTypes.Equal(tps) {
+		return nil, nil
+	}
+	for tblInfo, unionScan := range cached.TblInfo2UnionScan {
+		if !unionScan && tableHasDirtyContent(sctx, tblInfo) {
+			sctx.PreparedPlanCache().Delete(cacheKey)
+			return nil, nil
+		}
+	}
+	if err := (&Execute{}).rebuildRange(cached.Plan); err != nil {
+		logutil.BgLogger().Debug("rebuild range failed", zap.Error(err))
+		return nil, nil
+	}
+	return cached.Plan, nil
+}
+
+// whereClauseOf returns the WHERE clause of the statements supported by the non-prepared plan cache.
+func whereClauseOf(stmt ast.StmtNode) *ast.ExprNode {
+	switch x := stmt.(type) {
+	case *ast.SelectStmt:
+		return &x.Where
+	case *ast.UpdateStmt:
+		return &x.Where
+	case *ast.DeleteStmt:
+		return &x.Where
+	}
+	return nil
+}
+
+// parameterizeWhereClause replaces the constants in the WHERE clause of the statement with parameter markers.
+// The markers are ordered by their positions in the returned paramReplacer, and the original constants are kept in the
+// markers as the parameters.
+func parameterizeWhereClause(stmt ast.StmtNode) *paramReplacer {
+	replacer := &paramReplacer{}
+	where := whereClauseOf(stmt)
+	if where == nil || *where == nil {
+		return replacer
+	}
+	node, _ := (*where).Accept(replacer)
+	*where = node.(ast.ExprNode)
+	return replacer
+}
+
+// restoreWhereClause replaces the parameter markers generated by parameterizeWhereClause with the original constants.
+func restoreWhereClause(stmt ast.StmtNode, replacer *paramReplacer) {
+	where := whereClauseOf(stmt)
+	if len(replacer.params) == 0 || where == nil || *where == nil {
+		return
+	}
+	replacer.restore = true
+	node, _ := (*where).Accept(replacer)
+	*where = node.(ast.ExprNode)
+}
+
+// paramReplacer replaces the constants with parameter markers, or replaces them back if restore is true.
+type paramReplacer struct {
+	params  []*driver.ParamMarkerExpr
+	origins []*driver.ValueExpr
+	restore bool
+}
+
+// Enter implements Visitor interface.
+func (r *paramReplacer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (r *paramReplacer) Leave(in ast.Node) (out ast.Node, ok bool) {
+	if r.restore {
+		if param, isParam := in.(*driver.ParamMarkerExpr); isParam && param.Order < len(r.params) && r.params[param.Order] == param {
+			return r.origins[param.Order], true
+		}
+		return in, true
+	}
+	value, isValue := in.(*driver.ValueExpr)
+	if !isValue || !canBeParameterized(value) {
+		return in, true
+	}
+	param := &driver.ParamMarkerExpr{
+		ValueExpr: *value,
+		Order:     len(r.params),
+		InExecute: true,
+	}
+	r.params = append(r.params, param)
+	r.origins = append(r.origins, value)
+	return param, true
+}
+
+// canBeParameterized checks whether the constant keeps its type when it's replaced with a parameter marker.
+// The NULL and boolean constants, and the strings in non-default charsets are kept in the statement.
+func canBeParameterized(value *driver.ValueExpr) bool {
+	if value.Datum.IsNull() || value.Type.Flag&mysql.IsBooleanFlag != 0 {
+		return false
+	}
+	tp := types.NewFieldType(mysql.TypeUnspecified)
+	types.DefaultParamTypeForValue(value.GetValue(), tp)
+	return tp.Tp == value.Type.Tp && tp.Charset == value.Type.Charset && tp.Collate == value.Type.Collate
+}
@@ -63,6 +63,7 @@ var (
 	_ PhysicalPlan = &PhysicalMergeJoin{}
 	_ PhysicalPlan = &PhysicalUnionScan{}
 	_ PhysicalPlan = &PhysicalWindow{}
+	_ PhysicalPlan = &PhysicalExpand{}
 	_ PhysicalPlan = &PhysicalShuffle{}
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
@@ -1290,6 +1291,25 @@ func (p *PhysicalWindow) ExtractCorrelatedCols() []*expression.CorrelatedColumn
 	return corCols
 }
 
+// PhysicalExpand is the physical operator of Expand.
+type PhysicalExpand struct {
+	physicalSchemaProducer
+
+	GroupByExprs  []expression.Expression
+	GroupByCols   []*expression.Column
+	GroupingSets  [][]int
+	GroupingIDCol *expression.Column
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalExpand) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.GroupByExprs))
+	for _, expr := range p.GroupByExprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
 // PhysicalShuffle represents a shuffle plan.
 // `Tails` and `DataSources` are the last plan within and the first plan following the "shuffle", respectively,
 //  to build the child executors chain.
@@ -95,6 +95,7 @@ type tableHintInfo struct {
 	indexMergeHintList          []indexHintInfo
 	timeRangeHint               ast.HintTimeRange
 	limitHints                  limitHintInfo
+	leadingJoinOrder            []hintTableInfo
 }
 
 type limitHintInfo struct {
@@ -507,6 +508,12 @@ type PlanBuilder struct {
 	// correlatedAggMapper stores columns for correlated aggregates which should be evaluated in outer query.
 	correlatedAggMapper map[*ast.AggregateFuncExpr]*expression.CorrelatedColumn
 
+	// rollup is set when building the query block with `GROUP BY ... WITH ROLLUP`.
+	rollup *rollupInfo
+
+	// mergedView is set when building the DML statement written through an updatable view.
+	mergedView *mergedView
+
 	// isForUpdateRead should be true in either of the following situations
 	// 1. use `inside insert`, `update`, `delete` or `select for update` statement
 	// 2. isolation level is RC
@@ -1057,6 +1064,11 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// Multi-valued index has an entry for every array element, it can only be accessed
+			// by the index merge paths built from the JSON predicates.
+			if index.MVIndex {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1555,6 +1567,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.MVIndex {
+			// Skip checking multi-valued index, it has an entry for every array element rather than every row.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1708,7 +1724,8 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		// The statistics of multi-valued index are not supported yet.
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -1923,7 +1940,7 @@ func getColOffsetForAnalyze(colsInfo []*model.ColumnInfo, colID int64) int {
 func getModifiedIndexesInfoForAnalyze(tblInfo *model.TableInfo, allColumns bool, colsInfo []*model.ColumnInfo) []*model.IndexInfo {
 	idxsInfo := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
 	for _, originIdx := range tblInfo.Indices {
-		if originIdx.State != model.StatePublic {
+		if originIdx.State != model.StatePublic || originIdx.MVIndex {
 			continue
 		}
 		if allColumns {
@@ -2167,7 +2184,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		return b.buildAnalyzeTable(as, opts, version)
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -2972,11 +2989,18 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	}
 	tableInfo := tn.TableInfo
 	if tableInfo.IsView() {
-		err := errors.Errorf("insert into view %s is not supported now.", tableInfo.Name.O)
-		if insert.IsReplace {
-			err = errors.Errorf("replace into view %s is not supported now.", tableInfo.Name.O)
+		mv, err := b.mergeTargetView(insert.Table.TableRefs, "INSERT")
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
+		defer mv.restore()
+		if err = mv.mergeInsertStmt(insert); err != nil {
+			return nil, err
+		}
+		b.mergedView = mv
+		defer func() { b.mergedView = nil }()
+		tn = mv.base
+		tableInfo = tn.TableInfo
 	}
 	if tableInfo.IsSequence() {
 		err := errors.Errorf("insert into sequence %s is not supported now.", tableInfo.Name.O)
@@ -3019,15 +3043,6 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		return nil, ErrPartitionClauseOnNonpartitioned
 	}
 
-	user := b.ctx.GetSessionVars().User
-	var authErr error
-	if user != nil {
-		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
-	}
-
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
-		tableInfo.Name.L, "", authErr)
-
 	// `REPLACE INTO` requires both INSERT + DELETE privilege
 	// `ON DUPLICATE KEY UPDATE` requires both INSERT + UPDATE privilege
 	var extraPriv mysql.PrivilegeType
@@ -3036,12 +3051,32 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	} else if insert.OnDuplicate != nil {
 		extraPriv = mysql.UpdatePriv
 	}
-	if extraPriv != 0 {
+	if b.mergedView != nil {
+		privs := []mysql.PrivilegeType{mysql.InsertPriv}
+		if extraPriv != 0 {
+			privs = append(privs, extraPriv)
+		}
+		if err = b.appendMergedViewVisitInfo(b.mergedView.updatableView, privs...); err != nil {
+			return nil, err
+		}
+		if insertPlan.ViewChecks, err = b.buildViewChecks(ctx, b.mergedView.updatableView); err != nil {
+			return nil, err
+		}
+	} else {
+		user := b.ctx.GetSessionVars().User
+		var authErr error
 		if user != nil {
-			cmd := strings.ToUpper(mysql.Priv2Str[extraPriv])
-			authErr = ErrTableaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
+			tableInfo.Name.L, "", authErr)
+		if extraPriv != 0 {
+			if user != nil {
+				cmd := strings.ToUpper(mysql.Priv2Str[extraPriv])
+				authErr = ErrTableaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
 	}
 
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
@@ -3088,6 +3123,10 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	if err != nil {
 		return nil, err
 	}
+	err = insertPlan.buildOnInsertFKTriggers(b.ctx, b.is, tn.DBInfo.Name, onDupColSet)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate generated columns.
 	mockTablePlan.schema = insertPlan.tableSchema
@@ -3913,6 +3952,13 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 		if len(v.Cols) != schema.Len() {
 			return nil, ddl.ErrViewWrongList
 		}
+		if v.CheckOption != model.CheckOptionNone && !isUpdatableViewSelect(v.Algorithm, v.Select) {
+			dbName := v.ViewName.Schema.O
+			if dbName == "" {
+				dbName = b.ctx.GetSessionVars().CurrentDB
+			}
+			return nil, ErrViewNonupdCheck.GenWithStackByArgs(dbName, v.ViewName.Name.O)
+		}
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE VIEW", b.ctx.GetSessionVars().User.AuthUsername,
 				b.ctx.GetSessionVars().User.AuthHostname, v.ViewName.Name.L)
@@ -1368,6 +1368,9 @@ func buildPointUpdatePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 	updatePlan.tblID2Table = map[int64]table.Table{
 		tbl.ID: t,
 	}
+	if err := updatePlan.buildOnUpdateFKTriggers(ctx, is, updatePlan.tblID2Table); err != nil {
+		return nil
+	}
 	if tbl.GetPartitionInfo() != nil {
 		pt := t.(table.PartitionedTable)
 		var updateTableList []*ast.TableName
@@ -1471,6 +1474,11 @@ func buildPointDeletePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 			},
 		},
 	}.Init(ctx)
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	t, _ := is.TableByID(tbl.ID)
+	if err := delPlan.buildOnDeleteFKTriggers(ctx, is, map[int64]table.Table{tbl.ID: t}); err != nil {
+		return nil
+	}
 	return delPlan
 }
 
@@ -1544,6 +1552,35 @@ func getPartitionInfo(ctx sessionctx.Context, tbl *model.TableInfo, pairs []name
 				return &pi.Definitions[pos], i, false
 			}
 		}
+	case model.PartitionTypeKey:
+		// The table being partitioned is regarded as a key partition without columns.
+		if len(pi.Columns) == 0 {
+			return nil, 0, false
+		}
+		// The values of all the partition key columns are needed to locate the key partition.
+		vals := make([]types.Datum, len(pi.Columns))
+		firstPos := -1
+		for i, col := range pi.Columns {
+			pos := -1
+			for j, pair := range pairs {
+				if col.L == pair.colName {
+					pos = j
+					break
+				}
+			}
+			if pos < 0 {
+				return nil, 0, false
+			}
+			vals[i] = pairs[pos].value
+			if firstPos < 0 {
+				firstPos = pos
+			}
+		}
+		idx, err := partitionExpr.ForKeyPruning.LocatePartition(ctx.GetSessionVars().StmtCtx, pi, vals)
+		if err != nil {
+			return nil, 0, false
+		}
+		return &pi.Definitions[idx], firstPos, false
 	case model.PartitionTypeRange:
 		// left range columns partition for future development
 		if len(pi.Columns) == 0 {
@@ -2005,3 +2005,87 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPrepareSerialSuite) TestNonPreparedPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+	pb := &dto.Metric{}
+	metrics.ResettablePlanCacheCounterFortTest = true
+	metrics.PlanCacheCounter.Reset()
+	metrics.PlanCacheMissCounter.Reset()
+	counter := metrics.PlanCacheCounter.WithLabelValues("non-prepare")
+	missCounter := metrics.PlanCacheMissCounter.WithLabelValues("non-prepare")
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t(a int, b int, key(a))")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+
+	// The non-prepared plan cache is disabled by default.
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select b from t where a = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select b from t where a = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select b from t where a > 1 order by b").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select b from t where a > 2 order by b").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	err = counter.Write(pb)
+	c.Assert(err, IsNil)
+	c.Check(pb.GetCounter().GetValue(), Equals, float64(2))
+	err = missCounter.Write(pb)
+	c.Assert(err, IsNil)
+	c.Check(pb.GetCounter().GetValue(), Equals, float64(2))
+
+	// The constants out of the WHERE clause are not parameterized.
+	tk.MustQuery("select b + 1 from t where a = 3").Check(testkit.Rows("4"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The parameters of different types can't share the plan.
+	tk.MustQuery("select b from t where a = '3'").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("update t set b = b + 10 where a = 1")
+	tk.MustExec("update t set b = b + 10 where a = 2")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select b from t order by a").Check(testkit.Rows("11", "12", "3"))
+
+	// The cached plan without UnionScan is invalid after the table is modified in the transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (4, 4)")
+	tk.MustQuery("select b from t where a > 2 order by b").Check(testkit.Rows("3", "4"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustExec("rollback")
+
+	// The hit and miss are visible in the warnings of EXPLAIN.
+	tk.MustExec("explain select a from t where b = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 non-prepared plan cache miss"))
+	tk.MustExec("explain select a from t where b = 2")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 use non-prepared plan cache"))
+	tk.MustExec("explain select * from t, t2 where t.a = t2.a and t.b = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip non-prepared plan cache: query doesn't access exactly one table"))
+	tk.MustExec("explain select * from t where a in (select a from t2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip non-prepared plan cache: query has sub-queries"))
+	tk.MustExec("explain select /*+ use_index(t, a) */ * from t where a = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip non-prepared plan cache: query has hints"))
+}
@@ -478,6 +478,30 @@ func (p *PhysicalWindow) ResolveIndices() (err error) {
 	return nil
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalExpand) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	childLen := p.Schema().Len() - len(p.GroupByCols) - 1
+	for i := 0; i < childLen; i++ {
+		col := p.Schema().Columns[i]
+		newCol, err := col.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.Schema().Columns[i] = newCol.(*expression.Column)
+	}
+	for i, expr := range p.GroupByExprs {
+		p.GroupByExprs[i], err = expr.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalShuffle) ResolveIndices() (err error) {
 	err = p.basePhysicalPlan.ResolveIndices()
@@ -64,6 +64,13 @@ func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childS
 	}
 }
 
+// BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
+func (p *LogicalExpand) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	// Every input row is replicated, so the keys of the child no longer hold.
+	selfSchema.Keys = nil
+	p.maxOneRow = false
+}
+
 // If a condition is the form of (uniqueKey = constant) or (uniqueKey = Correlated column), it returns at most one row.
 // This function will check it.
 func (p *LogicalSelection) checkMaxOneRowCond(eqColIDs map[int64]struct{}, childSchema *expression.Schema) bool {
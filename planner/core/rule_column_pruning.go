@@ -467,6 +467,35 @@ func (p *LogicalWindow) extractUsedCols(parentUsedCols []*expression.Column) []*
 	return parentUsedCols
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalExpand) PruneColumns(parentUsedCols []*expression.Column) error {
+	expandColumns := p.GetExpandResultColumns()
+	used := make([]*expression.Column, 0, len(parentUsedCols)+len(p.GroupByExprs))
+	for _, col := range parentUsedCols {
+		isExpandCol := false
+		for _, expandColumn := range expandColumns {
+			if expandColumn.Equal(nil, col) {
+				isExpandCol = true
+				break
+			}
+		}
+		if !isExpandCol {
+			used = append(used, col)
+		}
+	}
+	for _, expr := range p.GroupByExprs {
+		used = append(used, expression.ExtractColumns(expr)...)
+	}
+	err := p.children[0].PruneColumns(used)
+	if err != nil {
+		return err
+	}
+
+	p.SetSchema(p.children[0].Schema().Clone())
+	p.Schema().Append(expandColumns...)
+	return nil
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalLimit) PruneColumns(parentUsedCols []*expression.Column) error {
 	if len(parentUsedCols) == 0 { // happens when LIMIT appears in UPDATE.
@@ -289,6 +289,12 @@ func (p *LogicalWindow) replaceExprColumns(replace map[string]*expression.Column
 	}
 }
 
+func (p *LogicalExpand) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, expr := range p.GroupByExprs {
+		ResolveExprAndReplace(expr, replace)
+	}
+}
+
 func (*projectionEliminator) name() string {
 	return "projection_eliminate"
 }
@@ -18,33 +18,153 @@ import (
 	"context"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 )
 
+// joinGroupResult is the result of extracting a join group.
+type joinGroupResult struct {
+	group      []LogicalPlan
+	eqEdges    []*expression.ScalarFunction
+	joinTypes  []*joinTypeWithExtMsg
+	otherConds []expression.Expression
+	// innerSideLeaves are the inner sides of the outer joins and semi joins in the group. They can only be
+	// joined by the outer joins or semi joins they belong to.
+	innerSideLeaves []LogicalPlan
+	hasOuterJoin    bool
+	leadingHints    []*tableHintInfo
+}
+
+// joinTypeWithExtMsg is the join type of an equal edge in the join group. For the edges of outer joins and semi
+// joins, the other conditions of the original join are bound to the edges as outerBindCondition, since they can
+// only be evaluated by the join using these edges.
+type joinTypeWithExtMsg struct {
+	JoinType
+	outerBindCondition []expression.Expression
+}
+
+var innerJoinTypeWithExtMsg = &joinTypeWithExtMsg{JoinType: InnerJoin}
+
 // extractJoinGroup extracts all the join nodes connected with continuous
-// InnerJoins to construct a join group. This join group is further used to
+// reorderable joins to construct a join group. This join group is further used to
 // construct a new join order based on a reorder algorithm.
 //
 // For example: "InnerJoin(InnerJoin(a, b), LeftJoin(c, d))"
 // results in a join group {a, b, LeftJoin(c, d)}.
-func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) {
+//
+// If tidb_enable_outer_join_reorder is on, the outer joins and semi joins are also extracted into the
+// join group. Only their outer sides are expanded, while their inner sides are kept as single nodes.
+// For example: "LeftJoin(InnerJoin(a, b), c) on a.id = c.id" results in a join group {a, b, c}, which can
+// be reordered to "InnerJoin(LeftJoin(a, c), b)".
+func extractJoinGroup(p LogicalPlan) *joinGroupResult {
 	join, isJoin := p.(*LogicalJoin)
-	if !isJoin || join.preferJoinType > uint(0) || join.JoinType != InnerJoin || join.StraightJoin {
-		return []LogicalPlan{p}, nil, nil
+	if !isJoin || join.preferJoinType > uint(0) || join.StraightJoin || !canReorderJoinType(join) {
+		return &joinGroupResult{group: []LogicalPlan{p}}
 	}
 
-	lhsGroup, lhsEqualConds, lhsOtherConds := extractJoinGroup(join.children[0])
-	rhsGroup, rhsEqualConds, rhsOtherConds := extractJoinGroup(join.children[1])
+	conds := make([]expression.Expression, 0, len(join.EqualConditions)+len(join.LeftConditions)+len(join.RightConditions)+len(join.OtherConditions))
+	conds = append(conds, expression.ScalarFuncs2Exprs(join.EqualConditions)...)
+	conds = append(conds, join.LeftConditions...)
+	conds = append(conds, join.RightConditions...)
+	conds = append(conds, join.OtherConditions...)
+
+	result := &joinGroupResult{}
+	// The outer side of an outer join or a semi join is expanded, while its inner side is kept as a whole.
+	if join.JoinType == RightOuterJoin {
+		result.appendInnerSide(join.children[0])
+	} else {
+		result.appendOuterSide(join, join.children[0], conds)
+	}
+	switch join.JoinType {
+	case LeftOuterJoin, SemiJoin, AntiSemiJoin:
+		result.appendInnerSide(join.children[1])
+	default:
+		result.appendOuterSide(join, join.children[1], conds)
+	}
 
-	group = append(group, lhsGroup...)
-	group = append(group, rhsGroup...)
-	eqEdges = append(eqEdges, join.EqualConditions...)
-	eqEdges = append(eqEdges, lhsEqualConds...)
-	eqEdges = append(eqEdges, rhsEqualConds...)
-	otherConds = append(otherConds, join.OtherConditions...)
-	otherConds = append(otherConds, lhsOtherConds...)
-	otherConds = append(otherConds, rhsOtherConds...)
-	return group, eqEdges, otherConds
+	joinType := innerJoinTypeWithExtMsg
+	if join.JoinType == InnerJoin {
+		result.otherConds = append(result.otherConds, conds[len(join.EqualConditions):]...)
+	} else {
+		result.hasOuterJoin = true
+		joinType = &joinTypeWithExtMsg{
+			JoinType:           join.JoinType,
+			outerBindCondition: conds[len(join.EqualConditions):],
+		}
+	}
+	for _, eqCond := range join.EqualConditions {
+		result.eqEdges = append(result.eqEdges, eqCond)
+		result.joinTypes = append(result.joinTypes, joinType)
+	}
+	if join.preferJoinOrder {
+		result.leadingHints = append(result.leadingHints, join.hintInfo)
+	}
+	return result
+}
+
+// canReorderJoinType checks whether the join type of the join can be reordered.
+func canReorderJoinType(join *LogicalJoin) bool {
+	switch join.JoinType {
+	case InnerJoin:
+		return true
+	case LeftOuterJoin, RightOuterJoin, SemiJoin, AntiSemiJoin:
+		// The outer joins with default values are generated by aggregation push down, which can't be reordered.
+		return join.ctx.GetSessionVars().EnableOuterJoinReorder && len(join.EqualConditions) > 0 && join.DefaultValues == nil
+	}
+	return false
+}
+
+// appendInnerSide appends the inner side of an outer join or a semi join to the join group as a single node.
+func (r *joinGroupResult) appendInnerSide(child LogicalPlan) {
+	r.group = append(r.group, child)
+	r.innerSideLeaves = append(r.innerSideLeaves, child)
+}
+
+// appendOuterSide appends the join group extracted from the outer side of the join, or the outer side itself if it
+// can't be expanded. The outer side can't be expanded if:
+// 1. The join conditions refer to the inner sides of the outer joins or semi joins in it, because these inner sides
+// must be joined before the join conditions are evaluated.
+// 2. The join is an outer join or a semi join, and its conditions refer to more than one node of the outer side.
+// Then the join can't be pushed down to any of these nodes.
+func (r *joinGroupResult) appendOuterSide(join *LogicalJoin, child LogicalPlan, conds []expression.Expression) {
+	childResult := extractJoinGroup(child)
+	canExpand := len(childResult.group) > 1
+	for _, leaf := range childResult.innerSideLeaves {
+		if !canExpand {
+			break
+		}
+		canExpand = !exprsReferSchema(conds, leaf.Schema())
+	}
+	if canExpand && join.JoinType != InnerJoin {
+		referredCnt := 0
+		for _, leaf := range childResult.group {
+			if exprsReferSchema(conds, leaf.Schema()) {
+				referredCnt++
+			}
+		}
+		canExpand = referredCnt <= 1
+	}
+	if !canExpand {
+		r.group = append(r.group, child)
+		return
+	}
+	r.group = append(r.group, childResult.group...)
+	r.eqEdges = append(r.eqEdges, childResult.eqEdges...)
+	r.joinTypes = append(r.joinTypes, childResult.joinTypes...)
+	r.otherConds = append(r.otherConds, childResult.otherConds...)
+	r.innerSideLeaves = append(r.innerSideLeaves, childResult.innerSideLeaves...)
+	r.hasOuterJoin = r.hasOuterJoin || childResult.hasOuterJoin
+	r.leadingHints = append(r.leadingHints, childResult.leadingHints...)
+}
+
+// exprsReferSchema checks whether any of the expressions refers to the columns of the schema.
+func exprsReferSchema(exprs []expression.Expression, schema *expression.Schema) bool {
+	for _, col := range expression.ExtractColumnsFromExpressions(nil, exprs, nil) {
+		if schema.Contains(col) {
+			return true
+		}
+	}
+	return false
 }
 
 type joinReOrderSolver struct {
@@ -62,7 +182,8 @@ func (s *joinReOrderSolver) optimize(ctx context.Context, p LogicalPlan) (Logica
 // optimizeRecursive recursively collects join groups and applies join reorder algorithm for each group.
 func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalPlan) (LogicalPlan, error) {
 	var err error
-	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
+	result := extractJoinGroup(p)
+	curJoinGroup := result.group
 	if len(curJoinGroup) > 1 {
 		for i := range curJoinGroup {
 			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i])
@@ -72,13 +193,23 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 		}
 		baseGroupSolver := &baseSingleGroupJoinOrderSolver{
 			ctx:        ctx,
-			otherConds: otherConds,
+			otherConds: result.otherConds,
+			eqEdges:    result.eqEdges,
+			joinTypes:  result.joinTypes,
+		}
+		if leadingHintInfo := checkLeadingHints(ctx, result.leadingHints); leadingHintInfo != nil {
+			ok, leftJoinGroup := baseGroupSolver.generateLeadingJoinGroup(curJoinGroup, leadingHintInfo, result.hasOuterJoin)
+			if ok {
+				curJoinGroup = leftJoinGroup
+			} else {
+				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("leading hint is inapplicable, check if the leading hint table is valid"))
+			}
 		}
 		originalSchema := p.Schema()
-		if len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
+		// The DP algorithm only supports the inner joins, and it can't start from the leading join.
+		if baseGroupSolver.leadingJoinGroup != nil || result.hasOuterJoin || len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
 			groupSolver := &joinReorderGreedySolver{
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
-				eqEdges:                        eqEdges,
 			}
 			p, err = groupSolver.solve(curJoinGroup)
 		} else {
@@ -86,7 +217,7 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
 			}
 			dpSolver.newJoin = dpSolver.newJoinWithEdges
-			p, err = dpSolver.solve(curJoinGroup, expression.ScalarFuncs2Exprs(eqEdges))
+			p, err = dpSolver.solve(curJoinGroup, expression.ScalarFuncs2Exprs(result.eqEdges))
 		}
 		if err != nil {
 			return nil, err
@@ -112,6 +243,9 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 		}
 		return p, nil
 	}
+	if join, ok := p.(*LogicalJoin); ok && join.preferJoinOrder {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("leading hint is inapplicable, check the join type or the join algorithm hint"))
+	}
 	newChildren := make([]LogicalPlan, 0, len(p.Children()))
 	for _, child := range p.Children() {
 		newChild, err := s.optimizeRecursive(ctx, child)
@@ -124,11 +258,143 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	return p, nil
 }
 
+// checkLeadingHints returns the leading hint of the join group. The joins in a group may come from different query
+// blocks with different leading hints, then none of them is used.
+func checkLeadingHints(ctx sessionctx.Context, leadingHints []*tableHintInfo) *tableHintInfo {
+	if len(leadingHints) == 0 {
+		return nil
+	}
+	for _, leadingHint := range leadingHints[1:] {
+		if !isSameLeadingHint(leadingHint, leadingHints[0]) {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("We can only use one leading hint at most, when multiple leading hints are used, all leading hints will be invalid"))
+			return nil
+		}
+	}
+	return leadingHints[0]
+}
+
+// isSameLeadingHint checks whether the two hint infos have the same leading hint. The hint infos of the same query
+// block may be different copies, so they are compared by the tables in the leading hints.
+func isSameLeadingHint(a, b *tableHintInfo) bool {
+	if len(a.leadingJoinOrder) != len(b.leadingJoinOrder) {
+		return false
+	}
+	for i := range a.leadingJoinOrder {
+		x, y := a.leadingJoinOrder[i], b.leadingJoinOrder[i]
+		if x.dbName.L != y.dbName.L || x.tblName.L != y.tblName.L || x.selectOffset != y.selectOffset {
+			return false
+		}
+	}
+	return true
+}
+
 // nolint:structcheck
 type baseSingleGroupJoinOrderSolver struct {
 	ctx          sessionctx.Context
 	curJoinGroup []*jrNode
 	otherConds   []expression.Expression
+	eqEdges      []*expression.ScalarFunction
+	joinTypes    []*joinTypeWithExtMsg
+	// leadingJoinGroup is the join tree built by the leading hint, which is used as the first node to reorder.
+	leadingJoinGroup LogicalPlan
+}
+
+// generateLeadingJoinGroup joins the nodes specified by the leading hint in order. It returns false if any table
+// in the hint can't be found in the join group, or the nodes can't be joined without cartesian products when there
+// are outer joins in the group. Otherwise, it returns the remaining nodes of the group.
+func (s *baseSingleGroupJoinOrderSolver) generateLeadingJoinGroup(curJoinGroup []LogicalPlan, hintInfo *tableHintInfo, hasOuterJoin bool) (bool, []LogicalPlan) {
+	leadingJoinGroup := make([]LogicalPlan, 0, len(hintInfo.leadingJoinOrder))
+	leftJoinGroup := make([]LogicalPlan, len(curJoinGroup))
+	copy(leftJoinGroup, curJoinGroup)
+	for _, hintTbl := range hintInfo.leadingJoinOrder {
+		matchIdx := -1
+		for i, node := range leftJoinGroup {
+			tableAlias := extractTableAlias(node, hintTbl.selectOffset)
+			if tableAlias != nil && hintTbl.dbName.L == tableAlias.dbName.L && hintTbl.tblName.L == tableAlias.tblName.L &&
+				hintTbl.selectOffset == tableAlias.selectOffset {
+				matchIdx = i
+				break
+			}
+		}
+		if matchIdx < 0 {
+			return false, nil
+		}
+		leadingJoinGroup = append(leadingJoinGroup, leftJoinGroup[matchIdx])
+		leftJoinGroup = append(leftJoinGroup[:matchIdx], leftJoinGroup[matchIdx+1:]...)
+	}
+	leadingJoin := leadingJoinGroup[0]
+	for _, node := range leadingJoinGroup[1:] {
+		leftNode, rightNode, usedEdges, joinType := s.checkConnection(leadingJoin, node)
+		if hasOuterJoin && len(usedEdges) == 0 {
+			// The cartesian product is not allowed if there are outer joins in the group.
+			return false, nil
+		}
+		leadingJoin, s.otherConds = s.makeJoin(leftNode, rightNode, usedEdges, joinType)
+	}
+	s.leadingJoinGroup = leadingJoin
+	return true, leftJoinGroup
+}
+
+// checkConnection finds the equal edges connecting the two nodes, and returns the join type of these edges.
+// The nodes are swapped if they are connected by an outer join or a semi join in the reverse order.
+func (s *baseSingleGroupJoinOrderSolver) checkConnection(leftPlan, rightPlan LogicalPlan) (leftNode, rightNode LogicalPlan, usedEdges []*expression.ScalarFunction, joinType *joinTypeWithExtMsg) {
+	joinType = innerJoinTypeWithExtMsg
+	leftNode, rightNode = leftPlan, rightPlan
+	for idx, edge := range s.eqEdges {
+		lCol := edge.GetArgs()[0].(*expression.Column)
+		rCol := edge.GetArgs()[1].(*expression.Column)
+		if leftPlan.Schema().Contains(lCol) && rightPlan.Schema().Contains(rCol) {
+			joinType = s.joinTypes[idx]
+			usedEdges = append(usedEdges, edge)
+		} else if rightPlan.Schema().Contains(lCol) && leftPlan.Schema().Contains(rCol) {
+			joinType = s.joinTypes[idx]
+			if joinType.JoinType != InnerJoin {
+				leftNode, rightNode = rightPlan, leftPlan
+				usedEdges = append(usedEdges, edge)
+			} else {
+				newSf := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
+				usedEdges = append(usedEdges, newSf)
+			}
+		}
+	}
+	return
+}
+
+// makeJoin builds the join of the two nodes with the equal edges, and attaches the other conditions which can be
+// evaluated by the join. It returns the join and the remaining other conditions.
+func (s *baseSingleGroupJoinOrderSolver) makeJoin(leftPlan, rightPlan LogicalPlan, eqEdges []*expression.ScalarFunction, joinType *joinTypeWithExtMsg) (LogicalPlan, []expression.Expression) {
+	remainOtherConds := make([]expression.Expression, len(s.otherConds))
+	copy(remainOtherConds, s.otherConds)
+	var otherConds, leftConds, rightConds []expression.Expression
+	mergedSchema := expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema())
+	remainOtherConds, leftConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
+		return expression.ExprFromSchema(expr, leftPlan.Schema()) && !expression.ExprFromSchema(expr, rightPlan.Schema())
+	})
+	remainOtherConds, rightConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
+		return expression.ExprFromSchema(expr, rightPlan.Schema()) && !expression.ExprFromSchema(expr, leftPlan.Schema())
+	})
+	remainOtherConds, otherConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
+		return expression.ExprFromSchema(expr, mergedSchema)
+	})
+	if joinType.JoinType != InnerJoin {
+		// The other conditions of the group come from the inner joins, they can't be evaluated by the outer join
+		// because the outer join would append the null rows which are filtered out by these conditions originally.
+		remainOtherConds = append(remainOtherConds, leftConds...)
+		remainOtherConds = append(remainOtherConds, rightConds...)
+		remainOtherConds = append(remainOtherConds, otherConds...)
+		leftConds, rightConds, otherConds = nil, nil, nil
+		for _, cond := range joinType.outerBindCondition {
+			switch {
+			case expression.ExprFromSchema(cond, leftPlan.Schema()) && !expression.ExprFromSchema(cond, rightPlan.Schema()):
+				leftConds = append(leftConds, cond)
+			case expression.ExprFromSchema(cond, rightPlan.Schema()) && !expression.ExprFromSchema(cond, leftPlan.Schema()):
+				rightConds = append(rightConds, cond)
+			default:
+				otherConds = append(otherConds, cond)
+			}
+		}
+	}
+	return s.newJoinWithConds(leftPlan, rightPlan, eqEdges, otherConds, leftConds, rightConds, joinType.JoinType), remainOtherConds
 }
 
 // baseNodeCumCost calculate the cumulative cost of the node in the join group.
@@ -180,9 +446,25 @@ func (s *baseSingleGroupJoinOrderSolver) newCartesianJoin(lChild, rChild Logical
 }
 
 func (s *baseSingleGroupJoinOrderSolver) newJoinWithEdges(lChild, rChild LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) LogicalPlan {
+	return s.newJoinWithConds(lChild, rChild, eqEdges, otherConds, nil, nil, InnerJoin)
+}
+
+// newJoinWithConds builds a join of the join type with the conditions. The left and right conditions of an inner
+// join are pushed down to its children as selections.
+func (s *baseSingleGroupJoinOrderSolver) newJoinWithConds(lChild, rChild LogicalPlan, eqEdges []*expression.ScalarFunction,
+	otherConds, leftConds, rightConds []expression.Expression, joinType JoinType) LogicalPlan {
 	newJoin := s.newCartesianJoin(lChild, rChild)
+	newJoin.JoinType = joinType
+	newJoin.SetSchema(buildLogicalJoinSchema(joinType, newJoin))
 	newJoin.EqualConditions = eqEdges
 	newJoin.OtherConditions = otherConds
+	if joinType == InnerJoin {
+		addSelection(newJoin, lChild, leftConds, 0)
+		addSelection(newJoin, rChild, rightConds, 1)
+	} else {
+		newJoin.LeftConditions = leftConds
+		newJoin.RightConditions = rightConds
+	}
 	return newJoin
 }
 
@@ -19,12 +19,10 @@ import (
 	"sort"
 
 	"github.com/pingcap/tidb/expression"
-	"github.com/pingcap/tidb/parser/ast"
 )
 
 type joinReorderGreedySolver struct {
 	*baseSingleGroupJoinOrderSolver
-	eqEdges []*expression.ScalarFunction
 }
 
 // solve reorders the join nodes in the group based on a greedy algorithm.
@@ -41,6 +39,9 @@ type joinReorderGreedySolver struct {
 //
 // For the nodes and join trees which don't have a join equal condition to
 // connect them, we make a bushy join tree to do the cartesian joins finally.
+//
+// If there is a join tree built by the leading hint, it's always used as the
+// first join tree to be constructed.
 func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan) (LogicalPlan, error) {
 	for _, node := range joinNodePlans {
 		_, err := node.recursiveDeriveStats(nil)
@@ -55,6 +56,17 @@ func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan) (LogicalPla
 	sort.SliceStable(s.curJoinGroup, func(i, j int) bool {
 		return s.curJoinGroup[i].cumCost < s.curJoinGroup[j].cumCost
 	})
+	if s.leadingJoinGroup != nil {
+		_, err := s.leadingJoinGroup.recursiveDeriveStats(nil)
+		if err != nil {
+			return nil, err
+		}
+		leadingNode := &jrNode{
+			p:       s.leadingJoinGroup,
+			cumCost: s.baseNodeCumCost(s.leadingJoinGroup),
+		}
+		s.curJoinGroup = append([]*jrNode{leadingNode}, s.curJoinGroup...)
+	}
 
 	var cartesianGroup []LogicalPlan
 	for len(s.curJoinGroup) > 0 {
@@ -65,7 +77,15 @@ func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan) (LogicalPla
 		cartesianGroup = append(cartesianGroup, newNode.p)
 	}
 
-	return s.makeBushyJoin(cartesianGroup), nil
+	p := s.makeBushyJoin(cartesianGroup)
+	// The remaining other conditions can't be evaluated by the outer joins, so they are evaluated after all the
+	// nodes are joined.
+	if len(s.otherConds) > 0 {
+		sel := LogicalSelection{Conditions: s.otherConds}.Init(s.ctx, p.SelectBlockOffset())
+		sel.SetChildren(p)
+		p = sel
+	}
+	return p, nil
 }
 
 func (s *joinReorderGreedySolver) constructConnectedJoinTree() (*jrNode, error) {
@@ -107,27 +127,10 @@ func (s *joinReorderGreedySolver) constructConnectedJoinTree() (*jrNode, error)
 	return curJoinTree, nil
 }
 
-func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftNode, rightNode LogicalPlan) (LogicalPlan, []expression.Expression) {
-	var usedEdges []*expression.ScalarFunction
-	remainOtherConds := make([]expression.Expression, len(s.otherConds))
-	copy(remainOtherConds, s.otherConds)
-	for _, edge := range s.eqEdges {
-		lCol := edge.GetArgs()[0].(*expression.Column)
-		rCol := edge.GetArgs()[1].(*expression.Column)
-		if leftNode.Schema().Contains(lCol) && rightNode.Schema().Contains(rCol) {
-			usedEdges = append(usedEdges, edge)
-		} else if rightNode.Schema().Contains(lCol) && leftNode.Schema().Contains(rCol) {
-			newSf := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
-			usedEdges = append(usedEdges, newSf)
-		}
-	}
+func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftPlan, rightPlan LogicalPlan) (LogicalPlan, []expression.Expression) {
+	leftPlan, rightPlan, usedEdges, joinType := s.checkConnection(leftPlan, rightPlan)
 	if len(usedEdges) == 0 {
 		return nil, nil
 	}
-	var otherConds []expression.Expression
-	mergedSchema := expression.MergeSchema(leftNode.Schema(), rightNode.Schema())
-	remainOtherConds, otherConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
-		return expression.ExprFromSchema(expr, mergedSchema)
-	})
-	return s.newJoinWithEdges(leftNode, rightNode, usedEdges, otherConds), remainOtherConds
+	return s.makeJoin(leftPlan, rightPlan, usedEdges, joinType)
 }
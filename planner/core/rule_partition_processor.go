@@ -279,6 +279,88 @@ func (s *partitionProcessor) pruneHashPartition(ctx sessionctx.Context, tbl tabl
 	return used, nil
 }
 
+// findUsedKeyPartitions finds the used key partitions. The partition can be located only if there are equal
+// conditions on all the partition key columns, since the key partition hashes the values of all the columns.
+func (s *partitionProcessor) findUsedKeyPartitions(ctx sessionctx.Context, tbl table.Table, partitionNames []model.CIStr,
+	conds []expression.Expression, columns []*expression.Column, names types.NameSlice) ([]int, error) {
+	pi := tbl.Meta().Partition
+	partExpr, err := tbl.(partitionTable).PartitionExpr()
+	if err != nil {
+		return nil, err
+	}
+	if len(pi.Columns) == 0 {
+		// The table being partitioned is regarded as a key partition without columns.
+		or := partitionRangeOR{partitionRange{0, len(pi.Definitions)}}
+		return s.convertToIntSlice(or, pi, partitionNames), nil
+	}
+	keyCols := make([]*expression.Column, 0, len(pi.Columns))
+	colLen := make([]int, 0, len(pi.Columns))
+	for _, colName := range pi.Columns {
+		idx := expression.FindFieldNameIdxByColName(names, colName.L)
+		if idx < 0 {
+			return nil, errors.Trace(fmt.Errorf("information of column %v is not found", colName.O))
+		}
+		keyCols = append(keyCols, columns[idx])
+		colLen = append(colLen, types.UnspecifiedLength)
+	}
+	detachedResult, err := ranger.DetachCondAndBuildRangeForPartition(ctx, conds, keyCols, colLen)
+	if err != nil {
+		return nil, err
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	used := make([]int, 0, len(detachedResult.Ranges))
+	for _, r := range detachedResult.Ranges {
+		if len(r.LowVal) != len(keyCols) || !r.IsPointNullable(sc) {
+			used = []int{FullRange}
+			break
+		}
+		idx, err := partExpr.ForKeyPruning.LocatePartition(sc, pi, r.LowVal)
+		if err != nil {
+			// If we failed to get the point position, we can just skip the pruning.
+			used = []int{FullRange}
+			break
+		}
+		if len(partitionNames) > 0 && !s.findByName(partitionNames, pi.Definitions[idx].Name.L) {
+			continue
+		}
+		used = append(used, idx)
+	}
+	if len(used) == 1 && used[0] == FullRange {
+		or := partitionRangeOR{partitionRange{0, len(pi.Definitions)}}
+		return s.convertToIntSlice(or, pi, partitionNames), nil
+	}
+	sort.Ints(used)
+	ret := used[:0]
+	for i := 0; i < len(used); i++ {
+		if i == 0 || used[i] != used[i-1] {
+			ret = append(ret, used[i])
+		}
+	}
+	return ret, nil
+}
+
+func (s *partitionProcessor) pruneKeyPartition(ctx sessionctx.Context, tbl table.Table, partitionNames []model.CIStr,
+	conds []expression.Expression, columns []*expression.Column, names types.NameSlice) ([]int, error) {
+	return s.findUsedKeyPartitions(ctx, tbl, partitionNames, conds, columns, names)
+}
+
+func (s *partitionProcessor) processKeyPartition(ds *DataSource, pi *model.PartitionInfo) (LogicalPlan, error) {
+	names, err := s.reconstructTableColNames(ds)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.pruneKeyPartition(ds.SCtx(), ds.table, ds.partitionNames, ds.allConds, ds.TblCols, names)
+	if err != nil {
+		return nil, err
+	}
+	if used != nil {
+		return s.makeUnionAllChildren(ds, pi, convertToRangeOr(used, pi))
+	}
+	tableDual := LogicalTableDual{RowCount: 0}.Init(ds.SCtx(), ds.blockOffset)
+	tableDual.schema = ds.Schema()
+	return tableDual, nil
+}
+
 // reconstructTableColNames reconstructs FieldsNames according to ds.TblCols.
 // ds.names may not match ds.TblCols since ds.names is pruned while ds.TblCols contains all original columns.
 // please see https://github.com/pingcap/tidb/issues/22635 for more details.
@@ -635,6 +717,8 @@ func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
 		return s.processHashPartition(ds, pi)
 	case model.PartitionTypeList:
 		return s.processListPartition(ds, pi)
+	case model.PartitionTypeKey:
+		return s.processKeyPartition(ds, pi)
 	}
 
 	// We haven't implement partition by list and so on.
@@ -623,6 +623,24 @@ func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression) ([
 	return canNotBePushed, p
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalExpand) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	canBePushed := make([]expression.Expression, 0, len(predicates))
+	canNotBePushed := make([]expression.Expression, 0, len(predicates))
+	childSchema := p.children[0].Schema()
+	for _, cond := range predicates {
+		// The group by columns and the grouping id are generated by the Expand,
+		// so only the predicates on the child columns can be pushed down.
+		if expression.ExprFromSchema(cond, childSchema) {
+			canBePushed = append(canBePushed, cond)
+		} else {
+			canNotBePushed = append(canNotBePushed, cond)
+		}
+	}
+	p.baseLogicalPlan.PredicatePushDown(canBePushed)
+	return canNotBePushed, p
+}
+
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalMemTable) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
 	if p.Extractor != nil {
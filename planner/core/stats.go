@@ -30,6 +30,8 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -237,6 +239,8 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	}
 	if ds.statisticTable.Pseudo {
 		tableStats.StatsVersion = statistics.PseudoVersion
+	} else if ds.ctx.GetSessionVars().EnableExtendedStats {
+		tableStats.HistColl.ColGroupMCVs = ds.statisticTable.GetColGroupMCVs(ds.schema.Columns)
 	}
 	for _, col := range ds.schema.Columns {
 		tableStats.ColNDVs[col.UniqueID] = ds.getColumnNDV(col.ID)
@@ -419,7 +423,8 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		isReadOnlyTxn = false
 	}
 	// Consider the IndexMergePath. Now, we just generate `IndexMergePath` in DNF case.
-	isPossibleIdxMerge := len(ds.pushedDownConds) > 0 && len(ds.possibleAccessPaths) > 1
+	isPossibleIdxMerge := (len(ds.pushedDownConds) > 0 && len(ds.possibleAccessPaths) > 1) ||
+		(len(ds.allConds) > 0 && ds.hasPublicMVIndex())
 	sessionAndStmtPermission := (ds.ctx.GetSessionVars().GetEnableIndexMerge() || len(ds.indexMergeHints) > 0) && !ds.ctx.GetSessionVars().StmtCtx.NoIndexMergeHint
 	// If there is an index path, we current do not consider `IndexMergePath`.
 	needConsiderIndexMerge := true
@@ -431,7 +436,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 			}
 		}
 	}
-	if isPossibleIdxMerge && sessionAndStmtPermission && needConsiderIndexMerge && isReadOnlyTxn && ds.tableInfo.TempTableType != model.TempTableLocal {
+	if isPossibleIdxMerge && sessionAndStmtPermission && needConsiderIndexMerge && isReadOnlyTxn && !ds.tableInfo.PreserveRowsOnCommit() {
 		err := ds.generateAndPruneIndexMergePath(ds.indexMergeHints != nil)
 		if err != nil {
 			return nil, err
@@ -449,6 +454,7 @@ func (ds *DataSource) generateAndPruneIndexMergePath(needPrune bool) error {
 	if err != nil {
 		return err
 	}
+	ds.generateIndexMergeMVPaths()
 	// If without hints, it means that `enableIndexMerge` is true
 	if len(ds.indexMergeHints) == 0 {
 		return nil
@@ -709,6 +715,145 @@ func (ds *DataSource) buildIndexMergeOrPath(partialPaths []*util.AccessPath, cur
 	return indexMergePath
 }
 
+// hasPublicMVIndex checks whether the table has a public multi-valued index.
+func (ds *DataSource) hasPublicMVIndex() bool {
+	for _, idx := range ds.tableInfo.Indices {
+		if idx.MVIndex && idx.State == model.StatePublic {
+			return true
+		}
+	}
+	return false
+}
+
+// generateIndexMergeMVPaths generates IndexMerge paths on the multi-valued indexes for the JSON predicates
+// `val MEMBER OF (expr)`, `JSON_CONTAINS(expr, vals)` and `JSON_OVERLAPS(expr, vals)`, where `expr` is the
+// array expression of the index. MEMBER OF and JSON_CONTAINS access the index by a single point, while
+// JSON_OVERLAPS unions the points of all values. The JSON functions can't be pushed down, so they are looked
+// up in ds.allConds and still evaluated by the Selection above the DataSource.
+func (ds *DataSource) generateIndexMergeMVPaths() {
+	sessVars := ds.ctx.GetSessionVars()
+	for _, idx := range ds.tableInfo.Indices {
+		if !idx.MVIndex || idx.State != model.StatePublic || len(idx.Columns) != 1 {
+			continue
+		}
+		if (idx.Invisible && !sessVars.OptimizerUseInvisibleIndexes) || !ds.isInIndexMergeHints(idx.Name.L) {
+			continue
+		}
+		col := expression.ColInfo2Col(ds.TblCols, ds.tableInfo.Columns[idx.Columns[0].Offset])
+		if col == nil || col.VirtualExpr == nil {
+			continue
+		}
+		castFunc, ok := col.VirtualExpr.(*expression.ScalarFunction)
+		if !ok || castFunc.FuncName.L != ast.Cast {
+			continue
+		}
+		elemTp := col.RetType.Clone()
+		elemTp.Array = false
+		for _, cond := range ds.allConds {
+			vals, ok := ds.extractMVIndexValues(cond, castFunc.GetArgs()[0], elemTp)
+			if !ok {
+				continue
+			}
+			partialPaths := make([]*util.AccessPath, 0, len(vals))
+			countAfterAccess := 0.0
+			for _, val := range vals {
+				partialPath := ds.buildMVIndexPartialPath(idx, col, elemTp, val)
+				countAfterAccess += partialPath.CountAfterAccess
+				partialPaths = append(partialPaths, partialPath)
+			}
+			indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+			indexMergePath.TableFilters = append(indexMergePath.TableFilters, ds.pushedDownConds...)
+			indexMergePath.CountAfterAccess = math.Min(countAfterAccess, ds.tableStats.RowCount)
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, indexMergePath)
+		}
+	}
+}
+
+// extractMVIndexValues extracts the values to access the multi-valued index on `arrayExpr` from the condition.
+func (ds *DataSource) extractMVIndexValues(cond, arrayExpr expression.Expression, elemTp *types.FieldType) ([]types.Datum, bool) {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return nil, false
+	}
+	args := sf.GetArgs()
+	var jsonArg, valArg expression.Expression
+	switch sf.FuncName.L {
+	case ast.JSONMemberOf:
+		jsonArg, valArg = args[1], args[0]
+	case ast.JSONContains:
+		if len(args) != 2 {
+			return nil, false
+		}
+		jsonArg, valArg = args[0], args[1]
+	case ast.JSONOverlaps:
+		jsonArg, valArg = args[0], args[1]
+		if !jsonArg.Equal(ds.ctx, arrayExpr) {
+			jsonArg, valArg = valArg, jsonArg
+		}
+	default:
+		return nil, false
+	}
+	if !jsonArg.Equal(ds.ctx, arrayExpr) || len(expression.ExtractColumns(valArg)) > 0 ||
+		len(expression.ExtractCorColumns(valArg)) > 0 || expression.IsMutableEffectsExpr(valArg) ||
+		expression.MaybeOverOptimized4PlanCache(ds.ctx, []expression.Expression{valArg}) {
+		return nil, false
+	}
+	val, isNull, err := valArg.EvalJSON(ds.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil, false
+	}
+	elems := []json.BinaryJSON{val}
+	if sf.FuncName.L != ast.JSONMemberOf && val.TypeCode == json.TypeCodeArray {
+		elems = make([]json.BinaryJSON, 0, val.GetElemCount())
+		for i := 0; i < val.GetElemCount(); i++ {
+			elems = append(elems, val.ArrayGetElem(i))
+		}
+	}
+	if len(elems) == 0 {
+		return nil, false
+	}
+	// Any row containing all the values must contain the first one.
+	if sf.FuncName.L == ast.JSONContains {
+		elems = elems[:1]
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	vals := make([]types.Datum, 0, len(elems))
+	for _, elem := range elems {
+		d := types.NewDatumFromJSONScalar(elem, elemTp.Collate)
+		if d.Kind() == types.KindMysqlJSON {
+			return nil, false
+		}
+		d, err = d.ConvertTo(sc, elemTp)
+		if err != nil {
+			return nil, false
+		}
+		vals = append(vals, d)
+	}
+	return vals, true
+}
+
+// buildMVIndexPartialPath builds a partial path which accesses the multi-valued index by a point.
+func (ds *DataSource) buildMVIndexPartialPath(idx *model.IndexInfo, col *expression.Column, elemTp *types.FieldType, val types.Datum) *util.AccessPath {
+	// The index stores the elements of the array, so the index column is read as the element type.
+	elemCol := col.Clone().(*expression.Column)
+	elemCol.RetType = elemTp
+	elemCol.VirtualExpr = nil
+	path := &util.AccessPath{
+		Index:      idx,
+		IdxCols:    []*expression.Column{elemCol},
+		IdxColLens: []int{types.UnspecifiedLength},
+		Ranges:     []*ranger.Range{{LowVal: []types.Datum{val}, HighVal: []types.Datum{val}}},
+	}
+	path.FullIdxCols, path.FullIdxColLens = path.IdxCols, path.IdxColLens
+	count, err := ds.tableStats.HistColl.GetRowCountByIndexRanges(ds.ctx.GetSessionVars().StmtCtx, idx.ID, path.Ranges)
+	if err != nil {
+		count = ds.tableStats.RowCount * SelectionFactor
+	}
+	path.CountAfterAccess = count
+	path.CountAfterIndex = count
+	return path
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalSelection) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
@@ -1158,6 +1303,33 @@ func (p *LogicalMaxOneRow) DeriveStats(childStats []*property.StatsInfo, selfSch
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalExpand) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	numSets := float64(len(p.GroupingSets))
+	p.stats = &property.StatsInfo{
+		RowCount: childProfile.RowCount * numSets,
+		ColNDVs:  make(map[int64]float64, selfSchema.Len()),
+	}
+	childLen := selfSchema.Len() - len(p.GroupByCols) - 1
+	for i := 0; i < childLen; i++ {
+		id := selfSchema.Columns[i].UniqueID
+		p.stats.ColNDVs[id] = childProfile.ColNDVs[id]
+	}
+	for i, expr := range p.GroupByExprs {
+		ndv := childProfile.RowCount
+		if col, ok := expr.(*expression.Column); ok {
+			if colNDV, ok := childProfile.ColNDVs[col.UniqueID]; ok {
+				ndv = colNDV
+			}
+		}
+		// The rolled up rows add a NULL value.
+		p.stats.ColNDVs[p.GroupByCols[i].UniqueID] = ndv + 1
+	}
+	p.stats.ColNDVs[p.GroupingIDCol.UniqueID] = numSets
+	return p.stats, nil
+}
+
 func (p *LogicalWindow) getGroupNDVs(colGroups [][]*expression.Column, childStats []*property.StatsInfo) []property.GroupNDV {
 	if len(colGroups) > 0 {
 		return childStats[0].GroupNDVs
@@ -0,0 +1,717 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/table/temptable"
+)
+
+// ViewCheck is the WITH CHECK OPTION condition of a view, which the rows written through the view must satisfy.
+// DBName and ViewName are the view which the rows are written through, it may be an outer view of the view
+// which defines the condition.
+type ViewCheck struct {
+	DBName   model.CIStr
+	ViewName model.CIStr
+	// Expr is resolved against the public columns of the base table, the index of each column is its offset.
+	Expr expression.Expression
+}
+
+// viewLevel is one of the nested views which are merged into a DML statement.
+type viewLevel struct {
+	dbName model.CIStr
+	info   *model.TableInfo
+}
+
+// updatableView is a view selecting from a single table, which can be merged into the DML statements written
+// through it. The columns and the conditions of the view are expressed by the columns of the base table.
+// See https://dev.mysql.com/doc/refman/8.0/en/view-updatability.html
+type updatableView struct {
+	// chain contains the view and its underlying views, from the outermost to the innermost.
+	chain []viewLevel
+	base  *ast.TableName
+	// alias qualifies the columns of the base table in cols, where and checks.
+	alias model.CIStr
+	// cols are the expressions of the view columns, in the order of the view columns.
+	cols []ast.ExprNode
+	// where is the conjunction of the WHERE conditions of the view and its underlying views.
+	where ast.ExprNode
+	// checks are the conditions checked by WITH CHECK OPTION for the written rows.
+	checks []ast.ExprNode
+}
+
+// singleTableSource returns the table source if it's the only one in the table refs, otherwise returns nil.
+func singleTableSource(refs *ast.Join) *ast.TableSource {
+	for refs != nil && refs.Right == nil {
+		switch x := refs.Left.(type) {
+		case *ast.TableSource:
+			return x
+		case *ast.Join:
+			refs = x
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// nonUpdatableExprChecker finds the expressions which make a view not updatable.
+type nonUpdatableExprChecker struct {
+	found bool
+}
+
+// Enter implements Visitor interface.
+func (c *nonUpdatableExprChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch in.(type) {
+	case *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.SubqueryExpr:
+		c.found = true
+	}
+	return in, c.found
+}
+
+// Leave implements Visitor interface.
+func (c *nonUpdatableExprChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// isUpdatableViewSelect checks whether the view defined by the statement is updatable, that is, it selects from a
+// single table without aggregation, DISTINCT, LIMIT, set operators or subqueries in the field list.
+func isUpdatableViewSelect(algorithm model.ViewAlgorithm, stmt ast.Node) bool {
+	if algorithm == model.AlgorithmTemptable {
+		return false
+	}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || sel.Kind != ast.SelectStmtKindSelect || sel.With != nil || sel.Distinct || sel.GroupBy != nil ||
+		sel.Having != nil || len(sel.WindowSpecs) > 0 || sel.Limit != nil || sel.From == nil {
+		return false
+	}
+	ts := singleTableSource(sel.From.TableRefs)
+	if ts == nil {
+		return false
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok || tn.AsOf != nil || tn.TableSample != nil {
+		return false
+	}
+	checker := &nonUpdatableExprChecker{}
+	for _, field := range sel.Fields.Fields {
+		if field.Expr != nil {
+			field.Expr.Accept(checker)
+		}
+	}
+	return !checker.found
+}
+
+// mergeScope contains the names of the table sources and columns which are visible in a subquery.
+type mergeScope struct {
+	tables map[string]struct{}
+	cols   map[string]struct{}
+	// unknown indicates the columns of some table sources can't be determined.
+	unknown bool
+}
+
+func newMergeScope(sel *ast.SelectStmt) *mergeScope {
+	s := &mergeScope{tables: make(map[string]struct{}), cols: make(map[string]struct{})}
+	if sel.Fields != nil {
+		for _, field := range sel.Fields.Fields {
+			if field.AsName.L != "" {
+				s.cols[field.AsName.L] = struct{}{}
+			}
+		}
+	}
+	if sel.From != nil {
+		s.addResultSetNode(sel.From.TableRefs)
+	}
+	return s
+}
+
+func (s *mergeScope) addResultSetNode(node ast.ResultSetNode) {
+	switch x := node.(type) {
+	case *ast.Join:
+		s.addResultSetNode(x.Left)
+		if x.Right != nil {
+			s.addResultSetNode(x.Right)
+		}
+	case *ast.TableSource:
+		switch src := x.Source.(type) {
+		case *ast.TableName:
+			if x.AsName.L != "" {
+				s.tables[x.AsName.L] = struct{}{}
+			} else {
+				s.tables[src.Name.L] = struct{}{}
+			}
+			if src.TableInfo == nil {
+				s.unknown = true
+				return
+			}
+			for _, col := range src.TableInfo.Columns {
+				s.cols[col.Name.L] = struct{}{}
+			}
+		case *ast.SelectStmt:
+			s.tables[x.AsName.L] = struct{}{}
+			for _, field := range src.Fields.Fields {
+				if field.AsName.L != "" {
+					s.cols[field.AsName.L] = struct{}{}
+				} else if col, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+					s.cols[col.Name.Name.L] = struct{}{}
+				} else {
+					s.unknown = true
+				}
+			}
+		default:
+			s.tables[x.AsName.L] = struct{}{}
+			s.unknown = true
+		}
+	default:
+		s.unknown = true
+	}
+}
+
+// viewColumnMerger replaces the column references of a merged table source with the expressions of its columns.
+// The column references in subqueries are left untouched if they may refer to the table sources of the subqueries.
+type viewColumnMerger struct {
+	// schema and table qualify the merged table source, schema is empty if the table source is aliased.
+	schema model.CIStr
+	table  model.CIStr
+	names  []model.CIStr
+	exprs  []ast.ExprNode
+	// strict reports an error for the unresolved column references out of subqueries.
+	strict bool
+	clause clauseCode
+	scopes []*mergeScope
+	// merged maps the replaced column references to themselves, so that the statement can be restored.
+	merged map[*ast.ParenthesesExpr]*ast.ColumnNameExpr
+	undo   []func()
+	err    error
+}
+
+// resolve returns the expression of the column if it refers to the merged table source, otherwise returns nil.
+func (m *viewColumnMerger) resolve(name *ast.ColumnName) ast.ExprNode {
+	if name.Table.L != "" {
+		for _, s := range m.scopes {
+			if _, ok := s.tables[name.Table.L]; ok {
+				return nil
+			}
+		}
+		if name.Table.L != m.table.L || (name.Schema.L != "" && name.Schema.L != m.schema.L) {
+			return nil
+		}
+	} else {
+		for _, s := range m.scopes {
+			if _, ok := s.cols[name.Name.L]; ok || s.unknown {
+				return nil
+			}
+		}
+	}
+	for i, n := range m.names {
+		if n.L == name.Name.L {
+			return m.exprs[i]
+		}
+	}
+	if name.Table.L != "" || (m.strict && len(m.scopes) == 0) {
+		m.err = ErrUnknownColumn.GenWithStackByArgs(name.OrigColName(), clauseMsg[m.clause])
+	}
+	return nil
+}
+
+// resolveColumn is like resolve, but the column must refer to a column of the base table.
+func (m *viewColumnMerger) resolveColumn(name *ast.ColumnName) (*ast.ColumnName, error) {
+	expr := m.resolve(name)
+	if m.err != nil || expr == nil {
+		return nil, m.err
+	}
+	col, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, ErrNonupdateableColumn.GenWithStackByArgs(name.Name.O)
+	}
+	return col.Name, nil
+}
+
+// Enter implements Visitor interface.
+func (m *viewColumnMerger) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SelectStmt:
+		m.scopes = append(m.scopes, newMergeScope(x))
+	case *ast.ValuesExpr:
+		// The argument of VALUES() must be a column name, so it's replaced when leaving the node.
+		return in, true
+	}
+	return in, m.err != nil
+}
+
+// Leave implements Visitor interface.
+func (m *viewColumnMerger) Leave(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SelectStmt:
+		m.scopes = m.scopes[:len(m.scopes)-1]
+	case *ast.ColumnNameExpr:
+		if expr := m.resolve(x.Name); expr != nil {
+			if col, ok := expr.(*ast.ColumnNameExpr); ok {
+				expr = &ast.ColumnNameExpr{Name: col.Name}
+			}
+			p := &ast.ParenthesesExpr{Expr: expr}
+			if m.merged != nil {
+				m.merged[p] = x
+			}
+			return p, true
+		}
+	case *ast.ValuesExpr:
+		if x.Column == nil || m.err != nil {
+			break
+		}
+		col, err := m.resolveColumn(x.Column.Name)
+		if err != nil {
+			m.err = err
+		} else if col != nil {
+			orig := x.Column
+			x.Column = &ast.ColumnNameExpr{Name: col}
+			m.undo = append(m.undo, func() { x.Column = orig })
+		}
+	case *ast.DefaultExpr:
+		if x.Name == nil || m.err != nil {
+			break
+		}
+		col, err := m.resolveColumn(x.Name)
+		if err != nil {
+			m.err = err
+		} else if col != nil {
+			orig := x.Name
+			x.Name = col
+			m.undo = append(m.undo, func() { x.Name = orig })
+		}
+	}
+	return in, m.err == nil
+}
+
+// viewColumnRestorer restores the column references replaced by viewColumnMerger.
+type viewColumnRestorer struct {
+	merged map[*ast.ParenthesesExpr]*ast.ColumnNameExpr
+}
+
+// Enter implements Visitor interface.
+func (r *viewColumnRestorer) Enter(in ast.Node) (ast.Node, bool) {
+	if p, ok := in.(*ast.ParenthesesExpr); ok {
+		_, merged := r.merged[p]
+		return in, merged
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (r *viewColumnRestorer) Leave(in ast.Node) (ast.Node, bool) {
+	if p, ok := in.(*ast.ParenthesesExpr); ok {
+		if orig, ok := r.merged[p]; ok {
+			return orig, true
+		}
+	}
+	return in, true
+}
+
+// mergedView is the view merged into a DML statement. The statement is changed in place, so it must be restored
+// after the plan is built, because the statement may be planned again, e.g. when it's a prepared statement.
+type mergedView struct {
+	*updatableView
+	merger *viewColumnMerger
+}
+
+// mergeExpr replaces the column references of the view in the expression.
+func (m *mergedView) mergeExpr(expr *ast.ExprNode, clause clauseCode) error {
+	if *expr == nil {
+		return nil
+	}
+	orig := *expr
+	m.merger.clause = clause
+	node, _ := orig.Accept(m.merger)
+	*expr = node.(ast.ExprNode)
+	m.merger.undo = append(m.merger.undo, func() {
+		*expr = orig
+		orig.Accept(&viewColumnRestorer{merged: m.merger.merged})
+	})
+	return m.merger.err
+}
+
+// mergeColumn replaces the assigned column of the view with the column of the base table.
+func (m *mergedView) mergeColumn(col **ast.ColumnName) error {
+	m.merger.clause = fieldList
+	newCol, err := m.merger.resolveColumn(*col)
+	if err != nil || newCol == nil {
+		return err
+	}
+	orig := *col
+	*col = newCol
+	m.merger.undo = append(m.merger.undo, func() { *col = orig })
+	return nil
+}
+
+func (m *mergedView) mergeAssignments(list []*ast.Assignment) error {
+	for _, assign := range list {
+		if err := m.mergeColumn(&assign.Column); err != nil {
+			return err
+		}
+		if err := m.mergeExpr(&assign.Expr, fieldList); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mergedView) mergeOrderBy(orderBy *ast.OrderByClause) error {
+	if orderBy == nil {
+		return nil
+	}
+	for _, item := range orderBy.Items {
+		if err := m.mergeExpr(&item.Expr, orderByClause); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeUpdateStmt merges the view into the UPDATE statement.
+func (m *mergedView) mergeUpdateStmt(update *ast.UpdateStmt) error {
+	if err := m.mergeAssignments(update.List); err != nil {
+		return err
+	}
+	if err := m.mergeExpr(&update.Where, whereClause); err != nil {
+		return err
+	}
+	return m.mergeOrderBy(update.Order)
+}
+
+// mergeDeleteStmt merges the view into the DELETE statement.
+func (m *mergedView) mergeDeleteStmt(del *ast.DeleteStmt) error {
+	if err := m.mergeExpr(&del.Where, whereClause); err != nil {
+		return err
+	}
+	return m.mergeOrderBy(del.Order)
+}
+
+// mergeInsertStmt merges the view into the INSERT statement. Each column of the view must refer to a distinct
+// column of the base table, and the columns which are not in the view are filled with the default values.
+func (m *mergedView) mergeInsertStmt(insert *ast.InsertStmt) error {
+	viewCols := make([]*ast.ColumnName, 0, len(m.cols))
+	baseCols := make(map[string]struct{}, len(m.cols))
+	for _, expr := range m.cols {
+		col, ok := expr.(*ast.ColumnNameExpr)
+		if ok {
+			_, dup := baseCols[col.Name.Name.L]
+			ok = !dup
+		}
+		if !ok {
+			return ErrNonInsertableTable.GenWithStackByArgs(m.chain[0].info.Name.O, "INSERT")
+		}
+		baseCols[col.Name.Name.L] = struct{}{}
+		viewCols = append(viewCols, col.Name)
+	}
+	origCols := insert.Columns
+	m.merger.undo = append(m.merger.undo, func() { insert.Columns = origCols })
+	if len(origCols) > 0 {
+		insert.Columns = make([]*ast.ColumnName, len(origCols))
+		for i, col := range origCols {
+			newCol, err := m.merger.resolveColumn(col)
+			if err != nil {
+				return err
+			}
+			if newCol == nil {
+				return ErrUnknownColumn.GenWithStackByArgs(col.OrigColName(), clauseMsg[fieldList])
+			}
+			insert.Columns[i] = newCol
+		}
+	} else if len(insert.Setlist) == 0 && !(len(insert.Lists) > 0 && len(insert.Lists[0]) == 0) {
+		insert.Columns = viewCols
+	}
+	for _, list := range insert.Lists {
+		for i := range list {
+			if err := m.mergeExpr(&list[i], fieldList); err != nil {
+				return err
+			}
+		}
+	}
+	if err := m.mergeAssignments(insert.Setlist); err != nil {
+		return err
+	}
+	// The columns in ON DUPLICATE KEY UPDATE may refer to the columns of the SELECT part.
+	m.merger.strict = insert.Select == nil
+	return m.mergeAssignments(insert.OnDuplicate)
+}
+
+// restore restores the statement changed by merging the view.
+func (m *mergedView) restore() {
+	for i := len(m.merger.undo) - 1; i >= 0; i-- {
+		m.merger.undo[i]()
+	}
+	m.merger.undo = nil
+}
+
+// mergeTargetView merges the view which is the only table source of a DML statement into the statement. The view
+// is replaced by its base table aliased by the name of the view, and the column references of the view are
+// replaced by the expressions of the base table. It returns nil if the table source isn't a view.
+func (b *PlanBuilder) mergeTargetView(refs *ast.Join, stmtType string) (*mergedView, error) {
+	ts := singleTableSource(refs)
+	if ts == nil {
+		return nil, nil
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok || tn.TableInfo == nil || !tn.TableInfo.IsView() {
+		return nil, nil
+	}
+	dbName := tn.Schema
+	if dbName.L == "" {
+		dbName = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+	}
+	alias := ts.AsName
+	// INSERT resolves the columns against the base table without alias, so the columns are not qualified.
+	if alias.L == "" && stmtType != "INSERT" {
+		alias = tn.Name
+	}
+	v, err := b.resolveUpdatableView(dbName, tn.TableInfo, alias, stmtType, false)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]model.CIStr, 0, len(tn.TableInfo.Columns))
+	for _, col := range tn.TableInfo.Columns {
+		names = append(names, col.Name)
+	}
+	m := &mergedView{
+		updatableView: v,
+		merger: &viewColumnMerger{
+			table:  tn.Name,
+			names:  names,
+			exprs:  v.cols,
+			strict: true,
+			merged: make(map[*ast.ParenthesesExpr]*ast.ColumnNameExpr),
+		},
+	}
+	if ts.AsName.L != "" {
+		m.merger.table = ts.AsName
+	} else {
+		m.merger.schema = dbName
+	}
+	origSource, origAsName := ts.Source, ts.AsName
+	ts.Source, ts.AsName = v.base, alias
+	m.merger.undo = append(m.merger.undo, func() { ts.Source, ts.AsName = origSource, origAsName })
+	return m, nil
+}
+
+// resolveUpdatableView expresses the columns and the conditions of the view by the columns of its base table, the
+// columns of the base table are qualified by alias. forceCheck indicates an outer view has WITH CASCADED CHECK
+// OPTION, so the conditions of the view are checked regardless of its own check option.
+func (b *PlanBuilder) resolveUpdatableView(dbName model.CIStr, viewInfo *model.TableInfo, alias model.CIStr, stmtType string, forceCheck bool) (*updatableView, error) {
+	deferFunc, err := b.checkRecursiveView(dbName, viewInfo.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer deferFunc()
+
+	stmt, err := b.parseViewSelect(viewInfo)
+	if err != nil {
+		return nil, err
+	}
+	if !isUpdatableViewSelect(viewInfo.View.Algorithm, stmt) {
+		if stmtType == "INSERT" {
+			return nil, ErrNonInsertableTable.GenWithStackByArgs(viewInfo.Name.O, stmtType)
+		}
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(viewInfo.Name.O, stmtType)
+	}
+	sel := stmt.(*ast.SelectStmt)
+	ts := singleTableSource(sel.From.TableRefs)
+	tn := ts.Source.(*ast.TableName)
+	baseDBName := tn.Schema
+	if baseDBName.L == "" {
+		baseDBName = dbName
+	}
+	// Like selecting from the view, the local temporary tables are invisible to the view.
+	is := temptable.DetachLocalTemporaryTableInfoSchema(b.is)
+	tbl, err := is.TableByName(baseDBName, tn.Name)
+	if err != nil {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, viewInfo.Name.O)
+	}
+	tblInfo := tbl.Meta()
+
+	var (
+		inner     *updatableView
+		fromNames []model.CIStr
+	)
+	switch {
+	case tblInfo.IsView():
+		cascaded := forceCheck || viewInfo.View.GetCheckOption() == model.CheckOptionCascaded
+		inner, err = b.resolveUpdatableView(baseDBName, tblInfo, alias, stmtType, cascaded)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range tblInfo.Columns {
+			fromNames = append(fromNames, col.Name)
+		}
+	case tblInfo.IsSequence() || tbl.Type().IsVirtualTable():
+		if stmtType == "INSERT" {
+			return nil, ErrNonInsertableTable.GenWithStackByArgs(viewInfo.Name.O, stmtType)
+		}
+		return nil, ErrNonUpdatableTable.GenWithStackByArgs(viewInfo.Name.O, stmtType)
+	default:
+		dbInfo, _ := is.SchemaByName(baseDBName)
+		inner = &updatableView{
+			base:  &ast.TableName{Schema: baseDBName, Name: tblInfo.Name, DBInfo: dbInfo, TableInfo: tblInfo},
+			alias: alias,
+		}
+		for _, col := range tblInfo.Cols() {
+			fromNames = append(fromNames, col.Name)
+			inner.cols = append(inner.cols, &ast.ColumnNameExpr{Name: &ast.ColumnName{Table: alias, Name: col.Name}})
+		}
+	}
+
+	merger := &viewColumnMerger{table: tn.Name, names: fromNames, exprs: inner.cols, strict: true, clause: fieldList}
+	if ts.AsName.L != "" {
+		merger.table = ts.AsName
+	} else {
+		merger.schema = baseDBName
+	}
+	cols := make([]ast.ExprNode, 0, len(viewInfo.Columns))
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			cols = append(cols, inner.cols...)
+			continue
+		}
+		node, _ := field.Expr.Accept(merger)
+		// The column of the base table is kept as a column rather than the parenthesized one, so that it can be written.
+		if p, ok := node.(*ast.ParenthesesExpr); ok {
+			if col, ok := p.Expr.(*ast.ColumnNameExpr); ok {
+				node = col
+			}
+		}
+		cols = append(cols, node.(ast.ExprNode))
+	}
+	var where ast.ExprNode
+	if sel.Where != nil {
+		merger.clause = whereClause
+		node, _ := sel.Where.Accept(merger)
+		where = node.(ast.ExprNode)
+	}
+	if merger.err != nil || len(cols) != len(viewInfo.Columns) {
+		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, viewInfo.Name.O)
+	}
+
+	level := viewLevel{dbName: dbName, info: viewInfo}
+	v := &updatableView{
+		chain: append([]viewLevel{level}, inner.chain...),
+		base:  inner.base,
+		alias: alias,
+		cols:  cols,
+		where: inner.where,
+	}
+	if where != nil {
+		if v.where == nil {
+			v.where = where
+		} else {
+			v.where = &ast.BinaryOperationExpr{Op: opcode.LogicAnd, L: where, R: v.where}
+		}
+		if forceCheck || viewInfo.View.GetCheckOption() != model.CheckOptionNone {
+			v.checks = append(v.checks, where)
+		}
+	}
+	v.checks = append(v.checks, inner.checks...)
+	return v, nil
+}
+
+// parseViewSelect parses the SELECT statement which defines the view.
+func (b *PlanBuilder) parseViewSelect(viewInfo *model.TableInfo) (ast.StmtNode, error) {
+	charset, collation := b.ctx.GetSessionVars().GetCharsetInfo()
+	viewParser := parser.New()
+	viewParser.SetParserConfig(b.ctx.GetSessionVars().BuildParserConfig())
+	return viewParser.ParseOneStmt(viewInfo.View.SelectStmt, charset, collation)
+}
+
+// isMergedViewBase checks whether the table name is the base table of the view merged into the DML statement.
+// The privileges of the merged view are checked by appendMergedViewVisitInfo instead of the base table.
+func (b *PlanBuilder) isMergedViewBase(tn *ast.TableName) bool {
+	return b.mergedView != nil && b.mergedView.base == tn
+}
+
+// appendMergedViewVisitInfo checks the privileges of the DML statement written through the merged view. The
+// privileges on the view are checked for the current user. The privileges on the underlying views and the base
+// table are checked for the definer of the outer view if it's SQL SECURITY DEFINER, otherwise for the current user.
+func (b *PlanBuilder) appendMergedViewVisitInfo(v *updatableView, privs ...mysql.PrivilegeType) error {
+	sessionVars := b.ctx.GetSessionVars()
+	var definer *auth.UserIdentity
+	for i := 0; i <= len(v.chain); i++ {
+		dbName, tblName := v.base.Schema, v.base.Name
+		if i < len(v.chain) {
+			dbName, tblName = v.chain[i].dbName, v.chain[i].info.Name
+		}
+		for _, priv := range privs {
+			if definer == nil {
+				var authErr error
+				if sessionVars.User != nil {
+					authErr = ErrTableaccessDenied.FastGenByArgs(strings.ToUpper(mysql.Priv2Str[priv]),
+						sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tblName.L)
+				}
+				b.visitInfo = appendVisitInfo(b.visitInfo, priv, dbName.L, tblName.L, "", authErr)
+				continue
+			}
+			pm := privilege.GetPrivilegeManager(b.ctx)
+			if pm != nil && !pm.RequestVerificationWithUser(dbName.L, tblName.L, "", priv, definer) {
+				outer := v.chain[i-1]
+				return ErrViewInvalid.GenWithStackByArgs(outer.dbName.O, outer.info.Name.O)
+			}
+		}
+		if i < len(v.chain) {
+			viewInfo := v.chain[i].info
+			if sessionVars.StmtCtx.InExplainStmt && definer == nil {
+				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ShowViewPriv, dbName.L, tblName.L, "", ErrViewNoExplain)
+			}
+			if viewInfo.View.Security == model.SecurityDefiner {
+				definer = viewInfo.View.Definer
+			}
+		}
+	}
+	return nil
+}
+
+// buildViewChecks builds the WITH CHECK OPTION conditions of the view against the columns of its base table.
+func (b *PlanBuilder) buildViewChecks(ctx context.Context, v *updatableView) ([]*ViewCheck, error) {
+	if len(v.checks) == 0 {
+		return nil, nil
+	}
+	tblInfo := v.base.TableInfo
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(b.ctx, v.base.Schema, v.alias, tblInfo.Cols(), tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
+	mockTablePlan.SetSchema(expression.NewSchema(columns...))
+	mockTablePlan.names = names
+	view := v.chain[0]
+	checks := make([]*ViewCheck, 0, len(v.checks))
+	for _, cond := range v.checks {
+		expr, np, err := b.rewrite(ctx, cond, mockTablePlan, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		if np != mockTablePlan {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("subquery in the WHERE clause of the view with CHECK OPTION")
+		}
+		checks = append(checks, &ViewCheck{DBName: view.dbName, ViewName: view.info.Name, Expr: expr})
+	}
+	return checks, nil
+}
@@ -164,6 +164,17 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		useBinding = false
 	}
 
+	// The plans of the queries with bindings are not cached, since the bindings may change.
+	if bindRecord == nil && stmtNode != nil {
+		cachedPlan, cachedNames, usePlanCache, err := plannercore.GetPlanFromNonPreparedPlanCache(ctx, sctx, stmtNode, is)
+		if err != nil {
+			return nil, nil, err
+		}
+		if usePlanCache {
+			return cachedPlan, cachedNames, nil
+		}
+	}
+
 	var names types.NameSlice
 	var bestPlan, bestPlanFromBind plannercore.Plan
 	if useBinding {
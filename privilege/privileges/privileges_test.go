@@ -563,6 +563,39 @@ func TestSelectViewSecurity(t *testing.T) {
 	require.EqualError(t, err, core.ErrViewInvalid.GenWithStackByArgs("test", "selectviewsecurity").Error())
 }
 
+func TestWriteViewSecurity(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	se := newSession(t, store, dbName)
+	mustExec(t, se, `CREATE TABLE viewwrite(a int, b int);`)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil))
+	mustExec(t, se, `CREATE USER 'viewdefiner'@'localhost', 'viewwriter'@'localhost';`)
+	mustExec(t, se, `GRANT ALL ON test.viewwrite TO 'viewdefiner'@'localhost';`)
+	mustExec(t, se, `CREATE DEFINER='viewdefiner'@'localhost' SQL SECURITY DEFINER VIEW test.definerview AS SELECT * FROM test.viewwrite;`)
+	mustExec(t, se, `CREATE DEFINER='viewdefiner'@'localhost' SQL SECURITY INVOKER VIEW test.invokerview AS SELECT * FROM test.viewwrite;`)
+	mustExec(t, se, `GRANT SELECT, INSERT, UPDATE ON test.definerview TO 'viewwriter'@'localhost';`)
+	mustExec(t, se, `GRANT SELECT, INSERT, UPDATE ON test.invokerview TO 'viewwriter'@'localhost';`)
+
+	// The privileges on the base table of a SQL SECURITY DEFINER view are checked for the definer.
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "viewwriter", Hostname: "localhost"}, nil, nil))
+	mustExec(t, se, "INSERT INTO test.definerview VALUES (1, 1)")
+	mustExec(t, se, "UPDATE test.definerview SET b = 2 WHERE a = 1")
+	_, err := se.ExecuteInternal(context.Background(), "DELETE FROM test.definerview")
+	require.True(t, terror.ErrorEqual(err, core.ErrTableaccessDenied))
+
+	// The privileges on the base table of a SQL SECURITY INVOKER view are checked for the current user.
+	_, err = se.ExecuteInternal(context.Background(), "INSERT INTO test.invokerview VALUES (2, 2)")
+	require.True(t, terror.ErrorEqual(err, core.ErrTableaccessDenied))
+
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil))
+	mustExec(t, se, `REVOKE UPDATE ON test.viewwrite FROM 'viewdefiner'@'localhost';`)
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "viewwriter", Hostname: "localhost"}, nil, nil))
+	_, err = se.ExecuteInternal(context.Background(), "UPDATE test.definerview SET b = 3")
+	require.EqualError(t, err, core.ErrViewInvalid.GenWithStackByArgs("test", "definerview").Error())
+}
+
 func TestShowViewPriv(t *testing.T) {
 	t.Parallel()
 	store, clean := newStore(t)
@@ -818,6 +818,10 @@ func (h flashReplicaHandler) getTiFlashReplicaInfo(tblInfo *model.TableInfo, rep
 			})
 		}
 		for _, p := range pi.AddingDefinitions {
+			if pi.DDLState == model.StateDeleteReorganization {
+				// The reorganized partitions are already in the definitions.
+				break
+			}
 			replicaInfos = append(replicaInfos, &tableFlashReplicaInfo{
 				ID:             p.ID,
 				ReplicaCount:   tblInfo.TiFlashReplica.Count,
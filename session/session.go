@@ -595,17 +595,33 @@ func (s *session) commitTxnWithTemporaryData(ctx context.Context, txn kv.Transac
 			continue
 		}
 
-		if tbl.GetMeta().TempTableType != model.TempTableLocal {
+		// The rows of global temporary tables with ON COMMIT DELETE ROWS are discarded.
+		if !tbl.GetMeta().PreserveRowsOnCommit() {
 			continue
 		}
-		if _, ok := localTempTables.TableByID(tblID); !ok {
-			continue
+		if tbl.GetMeta().TempTableType == model.TempTableLocal {
+			if _, ok := localTempTables.TableByID(tblID); !ok {
+				continue
+			}
 		}
 
 		if stage == kv.InvalidStagingHandle {
+			if sessionData == nil {
+				var err error
+				if sessionData, err = temptable.EnsureSessionData(s); err != nil {
+					return err
+				}
+			}
 			stage = sessionData.Staging()
 		}
 
+		if tblInfo := tbl.GetMeta(); tblInfo.TempTableType == model.TempTableGlobal {
+			if sessVars.PreservedTempTableUpdateTS == nil {
+				sessVars.PreservedTempTableUpdateTS = make(map[int64]uint64)
+			}
+			sessVars.PreservedTempTableUpdateTS[tblID] = tblInfo.UpdateTS
+		}
+
 		tblPrefix := tablecodec.EncodeTablePrefix(tblID)
 		endKey := tablecodec.EncodeTablePrefix(tblID + 1)
 
@@ -2093,6 +2109,9 @@ func (s *session) NewTxn(ctx context.Context) error {
 		IsStaleness: false,
 		TxnScope:    s.sessionVars.CheckAndGetTxnScope(),
 	}
+	if err = temptable.ClearStalePreservedRows(s, is); err != nil {
+		return err
+	}
 	s.txn.SetOption(kv.SnapInterceptor, s.getSnapshotInterceptor())
 	return nil
 }
@@ -2768,6 +2787,12 @@ func (s *session) PrepareTxnCtx(ctx context.Context) {
 			s.sessionVars.TxnCtx.IsPessimistic = true
 		}
 	}
+	// The snapshot info schema may not contain the tables created later.
+	if latestIS, ok := is.(infoschema.InfoSchema); ok && s.sessionVars.SnapshotInfoschema == nil {
+		if err := temptable.ClearStalePreservedRows(s, latestIS); err != nil {
+			logutil.Logger(ctx).Warn("failed to clear the stale rows of temporary tables", zap.Error(err))
+		}
+	}
 }
 
 // PrepareTSFuture uses to try to get ts future.
@@ -4373,6 +4373,120 @@ func (s *testSessionSuite3) TestGlobalTemporaryTable(c *C) {
 	tk.MustQuery("select * from g_tmp").Check(testkit.Rows())
 }
 
+func (s *testSessionSuite3) TestGlobalTemporaryTablePreserveRows(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists g_tmp_preserve")
+	tk.MustExec("create global temporary table g_tmp_preserve (id int auto_increment primary key, b int, c int, index i_b(b)) on commit preserve rows")
+	defer tk.MustExec("drop table if exists g_tmp_preserve")
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp_preserve (b, c) values (3, 3)")
+	tk.MustExec("insert into g_tmp_preserve (b, c) values (7, 9)")
+	tk.MustExec("commit")
+
+	// The rows are kept after the transaction commits.
+	tk.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 3 3", "2 7 9"))
+	tk.MustQuery("select b from g_tmp_preserve where b > 3").Check(testkit.Rows("7"))
+	tk.MustQuery("select c from g_tmp_preserve where b = 3").Check(testkit.Rows("3"))
+	tk.MustQuery("select * from g_tmp_preserve where id = 1").Check(testkit.Rows("1 3 3"))
+	tk.MustQuery("select * from g_tmp_preserve where id in (1, 2, 3)").Check(testkit.Rows("1 3 3", "2 7 9"))
+
+	// The auto-increment IDs continue in the following transactions.
+	tk.MustExec("insert into g_tmp_preserve (b, c) values (8, 8)")
+	tk.MustQuery("select id from g_tmp_preserve order by id").Check(testkit.Rows("1", "2", "3"))
+
+	// The rolled back changes are discarded.
+	tk.MustExec("begin")
+	tk.MustExec("update g_tmp_preserve set c = 10 where id = 1")
+	tk.MustExec("delete from g_tmp_preserve where id = 2")
+	tk.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 3 10", "3 8 8"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 3 3", "2 7 9", "3 8 8"))
+
+	tk.MustExec("update g_tmp_preserve set c = 10 where id = 1")
+	tk.MustExec("delete from g_tmp_preserve where id = 2")
+	tk.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 3 10", "3 8 8"))
+	tk.MustGetErrCode("insert into g_tmp_preserve values (1, 1, 1)", errno.ErrDupEntry)
+
+	// The rows are only visible to the session.
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows())
+	tk1.MustExec("insert into g_tmp_preserve (b, c) values (1, 1)")
+	tk1.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("select * from g_tmp_preserve").Check(testkit.Rows("1 3 10", "3 8 8"))
+
+	// The committed rows count in the size limit of the table.
+	tk.MustExec("create global temporary table g_tmp_size (c1 int, c2 mediumtext) on commit preserve rows")
+	defer tk.MustExec("drop table if exists g_tmp_size")
+	tk.MustExec(fmt.Sprintf("set @@session.tidb_tmp_table_max_size = %d", 1<<20))
+	tk.MustExec("insert into g_tmp_size values (1, repeat('x', 512*1024))")
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp_size values (1, repeat('x', 512*1024))")
+	tk.MustGetErrCode("insert into g_tmp_size values (1, repeat('x', 512*1024))", errno.ErrRecordFileFull)
+	tk.MustExec("rollback")
+
+	// The size of each table is limited separately.
+	tk.MustExec("create global temporary table g_tmp_size2 (c1 int, c2 mediumtext) on commit preserve rows")
+	defer tk.MustExec("drop table if exists g_tmp_size2")
+	tk.MustExec("insert into g_tmp_size2 values (1, repeat('x', 512*1024))")
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp_size2 values (2, repeat('x', 256*1024))")
+	tk.MustExec("insert into g_tmp_size values (2, repeat('x', 256*1024))")
+	tk.MustExec("commit")
+	tk.MustQuery("select count(*) from g_tmp_size").Check(testkit.Rows("2"))
+	tk.MustQuery("select count(*) from g_tmp_size2").Check(testkit.Rows("2"))
+	tk.MustExec("insert into g_tmp_size2 values (3, repeat('x', 256*1024))")
+	tk.MustGetErrCode("insert into g_tmp_size2 values (4, repeat('x', 256*1024))", errno.ErrRecordFileFull)
+	tk.MustExec("insert into g_tmp_size values (3, repeat('x', 256*1024))")
+}
+
+func (s *testSessionSuite3) TestGlobalTemporaryTablePreserveRowsWithDDL(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists g_tmp_ddl")
+	tk.MustExec("create global temporary table g_tmp_ddl (id int auto_increment primary key, b int, c int) on commit preserve rows")
+	defer tk.MustExec("drop table if exists g_tmp_ddl")
+
+	// The committed rows are discarded after the table is altered by another session,
+	// so that they don't miss the entries of the new index.
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (1, 1), (2, 2)")
+	tk1.MustExec("alter table g_tmp_ddl add index i_b(b)")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows())
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (3, 3)")
+	tk.MustQuery("select b from g_tmp_ddl use index(i_b) where b > 0").Check(testkit.Rows("3"))
+	tk.MustQuery("select b from g_tmp_ddl ignore index(i_b) where b > 0").Check(testkit.Rows("3"))
+
+	// The rows in the transaction are kept until it finishes.
+	tk.MustExec("begin")
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (4, 4)")
+	tk1.MustExec("alter table g_tmp_ddl add column d int")
+	tk.MustQuery("select b from g_tmp_ddl").Check(testkit.Rows("3", "4"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows())
+
+	// The committed rows are discarded after the table is truncated by another session.
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (5, 5)")
+	tk1.MustExec("truncate table g_tmp_ddl")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows())
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (6, 6)")
+	tk.MustQuery("select b, c from g_tmp_ddl").Check(testkit.Rows("6 6"))
+
+	// The committed rows are discarded after the table is dropped by another session.
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (7, 7)")
+	tk1.MustExec("drop table g_tmp_ddl")
+	tk1.MustExec("create global temporary table g_tmp_ddl (id int auto_increment primary key, b int, c int) on commit preserve rows")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows())
+	tk.MustExec("insert into g_tmp_ddl (b, c) values (8, 8)")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows("1 8 8"))
+
+	// The rows of the tables not changed by others are kept.
+	tk.MustExec("create global temporary table g_tmp_ddl2 (id int) on commit preserve rows")
+	defer tk.MustExec("drop table if exists g_tmp_ddl2")
+	tk.MustExec("insert into g_tmp_ddl2 values (1)")
+	tk1.MustExec("truncate table g_tmp_ddl")
+	tk.MustQuery("select * from g_tmp_ddl").Check(testkit.Rows())
+	tk.MustQuery("select * from g_tmp_ddl2").Check(testkit.Rows("1"))
+}
+
 type testTxnStateSerialSuite struct {
 	testSessionSuiteBase
 }
@@ -380,6 +380,9 @@ type TemporaryTableData interface {
 type temporaryTableData struct {
 	kv.MemBuffer
 	tblSize map[int64]int64
+	// stagingSizes keeps the sizes of the tables before each staging buffer is created,
+	// they are restored when the staging buffer is discarded.
+	stagingSizes []map[int64]int64
 }
 
 // NewTemporaryTableData creates a new TemporaryTableData
@@ -390,6 +393,40 @@ func NewTemporaryTableData(memBuffer kv.MemBuffer) TemporaryTableData {
 	}
 }
 
+// Staging create a new staging buffer inside the MemBuffer.
+func (d *temporaryTableData) Staging() kv.StagingHandle {
+	tblSize := make(map[int64]int64, len(d.tblSize))
+	for tblID, size := range d.tblSize {
+		tblSize[tblID] = size
+	}
+	d.stagingSizes = append(d.stagingSizes, tblSize)
+	return d.MemBuffer.Staging()
+}
+
+// Release publish all modifications in the latest staging buffer to upper level.
+func (d *temporaryTableData) Release(h kv.StagingHandle) {
+	d.popStagingSizes()
+	d.MemBuffer.Release(h)
+}
+
+// Cleanup cleanups the resources referenced by the StagingHandle, the sizes of the tables are restored.
+func (d *temporaryTableData) Cleanup(h kv.StagingHandle) {
+	if tblSize, ok := d.popStagingSizes(); ok {
+		d.tblSize = tblSize
+	}
+	d.MemBuffer.Cleanup(h)
+}
+
+func (d *temporaryTableData) popStagingSizes() (map[int64]int64, bool) {
+	n := len(d.stagingSizes)
+	if n == 0 {
+		return nil, false
+	}
+	tblSize := d.stagingSizes[n-1]
+	d.stagingSizes = d.stagingSizes[:n-1]
+	return tblSize, true
+}
+
 // GetTableSize get the size of a table
 func (d *temporaryTableData) GetTableSize(tblID int64) int64 {
 	if tblSize, ok := d.tblSize[tblID]; ok {
@@ -917,6 +954,12 @@ type SessionVars struct {
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition
 	TiDBEnableExchangePartition bool
 
+	// EnableCheckConstraint indicates whether to enable check constraint.
+	EnableCheckConstraint bool
+
+	// ForeignKeyChecks indicates whether to check the foreign key constraints on the written rows.
+	ForeignKeyChecks bool
+
 	// AllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	AllowFallbackToTiKV map[kv.StoreType]struct{}
@@ -934,6 +977,12 @@ type SessionVars struct {
 	// EnablePseudoForOutdatedStats if using pseudo for outdated stats
 	EnablePseudoForOutdatedStats bool
 
+	// EnableNonPreparedPlanCache indicates whether to cache the plans of the text protocol queries.
+	EnableNonPreparedPlanCache bool
+
+	// EnableOuterJoinReorder indicates whether to reorder the outer joins and semi joins in the join reorder rule.
+	EnableOuterJoinReorder bool
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
@@ -941,6 +990,15 @@ type SessionVars struct {
 	// TemporaryTableData stores committed kv values for temporary table for current session.
 	TemporaryTableData TemporaryTableData
 
+	// preservedTempTableAllocators stores the autoID allocators of the global temporary tables with
+	// ON COMMIT PRESERVE ROWS, the allocated IDs are kept in the session along with the committed rows.
+	preservedTempTableAllocators map[int64]autoid.Allocator
+
+	// PreservedTempTableUpdateTS records the UpdateTS of the global temporary tables with ON COMMIT PRESERVE ROWS
+	// when their rows are committed into TemporaryTableData. The DDL doesn't maintain the rows in the sessions,
+	// so they are discarded once the table is altered, truncated or dropped.
+	PreservedTempTableUpdateTS map[int64]uint64
+
 	// MPPStoreLastFailTime records the lastest fail time that a TiFlash store failed.
 	MPPStoreLastFailTime map[string]time.Time
 
@@ -1645,6 +1703,26 @@ func (s *SessionVars) GetTemporaryTable(tblInfo *model.TableInfo) tableutil.Temp
 	return nil
 }
 
+// GetPreservedTempTableAllocator returns the autoID allocator of a global temporary table with ON COMMIT PRESERVE ROWS.
+// Unlike the other global temporary tables, the allocator is shared by all the transactions in the session.
+func (s *SessionVars) GetPreservedTempTableAllocator(tblInfo *model.TableInfo) autoid.Allocator {
+	if s.preservedTempTableAllocators == nil {
+		s.preservedTempTableAllocators = make(map[int64]autoid.Allocator)
+	}
+	alloc, ok := s.preservedTempTableAllocators[tblInfo.ID]
+	if !ok {
+		alloc = autoid.NewAllocatorFromTempTblInfo(tblInfo)
+		s.preservedTempTableAllocators[tblInfo.ID] = alloc
+	}
+	return alloc
+}
+
+// RemovePreservedTempTableAllocator removes the autoID allocator of a global temporary table with ON COMMIT
+// PRESERVE ROWS, which is no longer used after the table is truncated or dropped.
+func (s *SessionVars) RemovePreservedTempTableAllocator(tblID int64) {
+	delete(s.preservedTempTableAllocators, tblID)
+}
+
 // TableDelta stands for the changed count for one table or partition.
 type TableDelta struct {
 	Delta    int64
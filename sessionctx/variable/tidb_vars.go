@@ -489,6 +489,12 @@ const (
 	// TiDBEvolvePlanBaselines indicates whether the evolution of plan baselines is enabled.
 	TiDBEvolvePlanBaselines = "tidb_evolve_plan_baselines"
 
+	// TiDBEnablePlanRegressionDetection indicates whether to detect the plan regressions by the statement summary.
+	TiDBEnablePlanRegressionDetection = "tidb_enable_plan_regression_detection"
+
+	// TiDBPlanRegressionAutoBind indicates whether to create bindings pinning the previous plans of the regressed statements.
+	TiDBPlanRegressionAutoBind = "tidb_plan_regression_auto_bind"
+
 	// TiDBEnableExtendedStats indicates whether the extended statistics feature is enabled.
 	TiDBEnableExtendedStats = "tidb_enable_extended_stats"
 
@@ -562,6 +568,9 @@ const (
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition.
 	TiDBEnableExchangePartition = "tidb_enable_exchange_partition"
 
+	// TiDBEnableCheckConstraint indicates whether to enable check constraint.
+	TiDBEnableCheckConstraint = "tidb_enable_check_constraint"
+
 	// TiDBAllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	TiDBAllowFallbackToTiKV = "tidb_allow_fallback_to_tikv"
@@ -597,6 +606,14 @@ const (
 
 	// TiDBTmpTableMaxSize indicates the max memory size of temporary tables.
 	TiDBTmpTableMaxSize = "tidb_tmp_table_max_size"
+
+	// TiDBEnableNonPreparedPlanCache indicates whether to cache the plans of the text protocol queries.
+	// It only takes effect when the prepared plan cache is enabled.
+	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
+
+	// TiDBEnableOuterJoinReorder indicates whether to reorder the outer joins and semi joins along with the inner
+	// joins in the join reorder rule.
+	TiDBEnableOuterJoinReorder = "tidb_enable_outer_join_reorder"
 )
 
 // TiDB vars that have only global scope
@@ -756,6 +773,7 @@ const (
 	DefTiDBEnableIndexMergeJoin           = false
 	DefTiDBTrackAggregateMemoryUsage      = true
 	DefTiDBEnableExchangePartition        = false
+	DefTiDBEnableCheckConstraint          = false
 	DefCTEMaxRecursionDepth               = 1000
 	DefTiDBTopSQLEnable                   = false
 	DefTiDBTopSQLPrecisionSeconds         = 1
@@ -769,6 +787,10 @@ const (
 	DefTiDBEnableOrderedResultMode        = false
 	DefTiDBEnablePseudoForOutdatedStats   = true
 	DefEnablePlacementCheck               = true
+	DefTiDBEnablePlanRegressionDetection  = false
+	DefTiDBPlanRegressionAutoBind         = false
+	DefTiDBEnableNonPreparedPlanCache     = false
+	DefTiDBEnableOuterJoinReorder         = false
 )
 
 // Process global variables.
@@ -804,6 +826,9 @@ var (
 	MaxTSOBatchWaitInterval = atomic.NewInt64(DefTiDBTSOClientBatchMaxWaitTime)
 	EnableTSOFollowerProxy  = atomic.NewBool(DefTiDBEnableTSOFollowerProxy)
 	RestrictedReadOnly      = atomic.NewBool(DefTiDBRestrictedReadOnly)
+	// EnablePlanRegressionDetection and PlanRegressionAutoBind are read by the bindinfo worker.
+	EnablePlanRegressionDetection = atomic.NewBool(DefTiDBEnablePlanRegressionDetection)
+	PlanRegressionAutoBind        = atomic.NewBool(DefTiDBPlanRegressionAutoBind)
 )
 
 // TopSQL is the variable for control top sql feature.
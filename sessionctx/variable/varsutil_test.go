@@ -129,12 +129,13 @@ func TestVarsutil(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "OFF", val)
 
-	// 1/ON is not supported (generates a warning and sets to OFF)
+	// 1 converts to ON
 	err = SetSessionSystemVar(v, "foreign_key_checks", "1")
 	require.NoError(t, err)
 	val, err = GetSessionOrGlobalSystemVar(v, "foreign_key_checks")
 	require.NoError(t, err)
-	require.Equal(t, "OFF", val)
+	require.Equal(t, "ON", val)
+	require.True(t, v.ForeignKeyChecks)
 
 	err = SetSessionSystemVar(v, "sql_mode", "strict_trans_tables")
 	require.NoError(t, err)
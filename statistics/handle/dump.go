@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
@@ -79,6 +80,10 @@ func extendedStatsFromJSON(statsColl []*jsonExtendedStats) *statistics.ExtendedS
 			ScalarVals: js.ScalarVals,
 			StringVals: js.StringVals,
 		}
+		if js.Tp == ast.StatsTypeMCV && js.StringVals != "" {
+			// The MCV list is only used in the estimation, so it's ignored if it's invalid.
+			item.MCV, _ = statistics.DecodeMCVList(js.StringVals)
+		}
 		stats.Stats[js.StatsName] = item
 	}
 	return stats
@@ -947,6 +947,13 @@ func (h *Handle) extendedStatsFromStorage(reader *statsReader, table *statistics
 			} else {
 				item.StringVals = statsStr
 			}
+			if item.Tp == ast.StatsTypeMCV && statsStr != "" {
+				item.MCV, err = statistics.DecodeMCVList(statsStr)
+				if err != nil {
+					logutil.BgLogger().Error("[stats] decode MCV list failed", zap.String("stats", statsStr), zap.Error(err))
+					return nil, err
+				}
+			}
 			table.ExtendedStats.Stats[name] = item
 		}
 	}
@@ -1133,7 +1140,7 @@ func (h *Handle) SaveTableStatsToStorage(results *statistics.AnalyzeResults, nee
 		switch item.Tp {
 		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
+		case ast.StatsTypeDependency, ast.StatsTypeMCV:
 			statsStr = item.StringVals
 		}
 		if _, err = exec.ExecuteInternal(ctx, "replace into mysql.stats_extended values (%?, %?, %?, %?, %?, %?, %?)", name, item.Tp, tableID, strColIDs, statsStr, version, StatsStatusAnalyzed); err != nil {
@@ -1590,7 +1597,9 @@ func (h *Handle) ReloadExtendedStatistics() error {
 }
 
 // BuildExtendedStats build extended stats for column groups if needed based on the column samples.
-func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) (*statistics.ExtendedStatsColl, error) {
+// rowSampled indicates whether the samples of the columns are from the same sampled rows, i.e, the SampleItem.Ordinals
+// of the collectors are the positions of the rows, which is required to build the MCV lists.
+func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector, rowSampled bool) (*statistics.ExtendedStatsColl, error) {
 	ctx := context.Background()
 	const sql = "SELECT name, type, column_ids FROM mysql.stats_extended WHERE table_id = %? and status in (%?, %?)"
 	rows, _, err := h.execRestrictedSQL(ctx, sql, tableID, StatsStatusAnalyzed, StatsStatusInited)
@@ -1610,7 +1619,7 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 			logutil.BgLogger().Error("invalid column_ids in mysql.stats_extended, skip collecting extended stats for this row", zap.String("column_ids", colIDs), zap.Error(err))
 			continue
 		}
-		item = h.fillExtendedStatsItemVals(item, cols, collectors)
+		item = h.fillExtendedStatsItemVals(item, cols, collectors, rowSampled)
 		if item != nil {
 			statsColl.Stats[name] = item
 		}
@@ -1621,16 +1630,53 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 	return statsColl, nil
 }
 
-func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector, rowSampled bool) *statistics.ExtendedStatsItem {
 	switch item.Tp {
 	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
 		return nil
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
+	case ast.StatsTypeMCV:
+		if !rowSampled {
+			return nil
+		}
+		return h.fillExtStatsMCVVals(item, cols, collectors)
 	}
 	return nil
 }
 
+func (h *Handle) fillExtStatsMCVVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	groupCollectors := make([]*statistics.SampleCollector, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				if collectors[i] != nil {
+					groupCollectors = append(groupCollectors, collectors[i])
+				}
+				break
+			}
+		}
+	}
+	if len(groupCollectors) != len(item.ColIDs) {
+		return nil
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	mcv, err := statistics.BuildMCVList(sc, groupCollectors)
+	if err != nil {
+		logutil.BgLogger().Error("[stats] build MCV list failed", zap.Int64s("column_ids", item.ColIDs), zap.Error(err))
+		return nil
+	}
+	item.StringVals, err = statistics.EncodeMCVList(mcv)
+	if err != nil {
+		logutil.BgLogger().Error("[stats] encode MCV list failed", zap.Int64s("column_ids", item.ColIDs), zap.Error(err))
+		return nil
+	}
+	item.MCV = mcv
+	return item
+}
+
 func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
@@ -1730,7 +1776,7 @@ func (h *Handle) SaveExtendedStatsToStorage(tableID int64, extStats *statistics.
 		switch item.Tp {
 		case ast.StatsTypeCardinality, ast.StatsTypeCorrelation:
 			statsStr = fmt.Sprintf("%f", item.ScalarVals)
-		case ast.StatsTypeDependency:
+		case ast.StatsTypeDependency, ast.StatsTypeMCV:
 			statsStr = item.StringVals
 		}
 		// If isLoad is true, it's INSERT; otherwise, it's UPDATE.
@@ -2351,6 +2351,48 @@ func (s *statsSerialSuite) TestFeedbackWithGlobalStats(c *C) {
 	checkFeedbackOnPartitionTable(statsTblBefore, tblInfo)
 }
 
+func (s *testStatsSuite) TestMCVStatsCompute(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values(1,1),(1,1),(1,1),(2,2)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 mcv(a)")
+	c.Assert(err.Error(), Equals, "Only support MCV statistics type on at least 2 columns")
+	tk.MustExec("alter table t add stats_extended s1 mcv(a,b)")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Check(testkit.Rows("3 [1,2] <nil> 0"))
+
+	// The MCV lists are only built from the row samples of analyze version 2.
+	tk.MustExec("set @@session.tidb_analyze_version=1")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Check(testkit.Rows("3 [1,2] <nil> 0"))
+	tk.MustExec("set @@session.tidb_analyze_version=2")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, status from mysql.stats_extended").Check(testkit.Rows("3 [1,2] 1"))
+
+	do := s.do
+	is := do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(do.StatsHandle().Update(is), IsNil)
+	statsTbl := do.StatsHandle().GetTableStats(tbl.Meta())
+	c.Assert(statsTbl.ExtendedStats, NotNil)
+	item := statsTbl.ExtendedStats.Stats["s1"]
+	c.Assert(item, NotNil)
+	c.Assert(item.MCV, NotNil)
+	c.Assert(len(item.MCV.Items), Equals, 2)
+	c.Assert(item.MCV.Items[0].Fraction, Equals, 0.75)
+	c.Assert(item.MCV.Items[1].Fraction, Equals, 0.25)
+
+	// The correlated columns are estimated by the MCV list instead of the independence assumption.
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	c.Assert(rows[0][1], Equals, "3.00")
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where a = 1 and b = 1").Rows()
+	c.Assert(rows[0][1], Equals, "2.25")
+}
+
 func (s *testStatsSuite) TestExtendedStatsPartitionTable(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
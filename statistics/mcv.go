@@ -0,0 +1,375 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/ranger"
+)
+
+// maxMCVItems is the max number of the most common value combinations kept in a MCV list.
+const maxMCVItems = 100
+
+// MCVItem is a most common value combination of a column group and the fraction of rows having it.
+type MCVItem struct {
+	// Key is the encoded value combination, see EncodeMCVKey.
+	Key      []byte  `json:"key"`
+	Fraction float64 `json:"fraction"`
+}
+
+// MCVList is the list of the most common value combinations of a column group, it's built from the row samples of
+// ANALYZE and used to estimate the selectivity of the equal conditions on all the columns of the group, which can't
+// be estimated correctly with the independence assumption when the columns are correlated.
+type MCVList struct {
+	// Items are sorted by the fraction in descending order.
+	Items []MCVItem `json:"items"`
+}
+
+// EncodeMCVKey encodes the value combination of a column group. The string values should be the collate keys.
+func EncodeMCVKey(sc *stmtctx.StatementContext, vals []types.Datum) ([]byte, error) {
+	normalized := make([]types.Datum, len(vals))
+	for i := range vals {
+		normalized[i] = vals[i]
+		// The encoding of decimals depends on the precision and frac of the datums, which may differ between the samples
+		// and the constants, so they are encoded with the precision and frac of their values.
+		if vals[i].Kind() == types.KindMysqlDecimal {
+			normalized[i] = types.NewDecimalDatum(vals[i].GetMysqlDecimal())
+		}
+	}
+	return codec.EncodeKey(sc, nil, normalized...)
+}
+
+// BuildMCVList builds the MCV list of a column group from the row samples of its columns. The SampleItem.Ordinals of
+// the collectors must be the positions of the samples in the sampled rows, and the rows having null values in the
+// column group are skipped.
+func BuildMCVList(sc *stmtctx.StatementContext, collectors []*SampleCollector) (*MCVList, error) {
+	mcv := &MCVList{}
+	if len(collectors) == 0 || len(collectors[0].Samples) == 0 || collectors[0].Count == 0 {
+		return mcv, nil
+	}
+	// rows maps the ordinal of a sampled row to its values in the column group.
+	rows := make(map[int][]types.Datum, len(collectors[0].Samples))
+	for _, item := range collectors[0].Samples {
+		vals := make([]types.Datum, 1, len(collectors))
+		vals[0] = item.Value
+		rows[item.Ordinal] = vals
+	}
+	for _, collector := range collectors[1:] {
+		for _, item := range collector.Samples {
+			if vals, ok := rows[item.Ordinal]; ok {
+				rows[item.Ordinal] = append(vals, item.Value)
+			}
+		}
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, vals := range rows {
+		if len(vals) < len(collectors) {
+			continue
+		}
+		key, err := EncodeMCVKey(sc, vals)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		counts[string(key)]++
+	}
+	items := make([]MCVItem, 0, len(counts))
+	for key, cnt := range counts {
+		items = append(items, MCVItem{Key: []byte(key), Fraction: float64(cnt)})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Fraction != items[j].Fraction {
+			return items[i].Fraction > items[j].Fraction
+		}
+		return bytes.Compare(items[i].Key, items[j].Key) < 0
+	})
+	if len(items) > maxMCVItems {
+		items = items[:maxMCVItems]
+	}
+	// The samples of the first column only contain the non-null rows, so the number of the sampled rows is scaled by
+	// its null count.
+	first := collectors[0]
+	sampledRows := float64(len(first.Samples)) * float64(first.Count+first.NullCount) / float64(first.Count)
+	for i := range items {
+		items[i].Fraction /= sampledRows
+	}
+	mcv.Items = items
+	return mcv, nil
+}
+
+// Fraction returns the fraction of rows having the value combination, and whether the combination is in the list.
+func (l *MCVList) Fraction(key []byte) (float64, bool) {
+	for _, item := range l.Items {
+		if bytes.Equal(item.Key, key) {
+			return item.Fraction, true
+		}
+	}
+	return 0, false
+}
+
+// MinFraction returns the min fraction of the value combinations in the list, which is the upper bound of the
+// fraction of the combinations not in the list.
+func (l *MCVList) MinFraction() float64 {
+	if len(l.Items) == 0 {
+		return 0
+	}
+	return l.Items[len(l.Items)-1].Fraction
+}
+
+// EncodeMCVList encodes the MCV list into the string stored in mysql.stats_extended.
+func EncodeMCVList(l *MCVList) (string, error) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// DecodeMCVList decodes the MCV list from the string stored in mysql.stats_extended.
+func DecodeMCVList(s string) (*MCVList, error) {
+	l := &MCVList{}
+	if err := json.Unmarshal([]byte(s), l); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return l, nil
+}
+
+// ColGroupMCV is the MCV list of a column group used in the selectivity estimation.
+type ColGroupMCV struct {
+	// UniqueIDs are the unique IDs of the columns in the order of the values in the MCV keys.
+	UniqueIDs []int64
+	MCV       *MCVList
+}
+
+// GetColGroupMCVs returns the MCV lists of the column groups whose columns are all in the given columns.
+func (t *Table) GetColGroupMCVs(columns []*expression.Column) []*ColGroupMCV {
+	if t.ExtendedStats == nil || len(t.ExtendedStats.Stats) == 0 {
+		return nil
+	}
+	id2UniqueID := make(map[int64]int64, len(columns))
+	for _, col := range columns {
+		id2UniqueID[col.ID] = col.UniqueID
+	}
+	names := make([]string, 0, len(t.ExtendedStats.Stats))
+	for name, item := range t.ExtendedStats.Stats {
+		if item.Tp == ast.StatsTypeMCV && item.MCV != nil && len(item.MCV.Items) > 0 {
+			names = append(names, name)
+		}
+	}
+	// The groups with more columns are used first in the selectivity estimation, the names make the order stable.
+	sort.Slice(names, func(i, j int) bool {
+		li, lj := len(t.ExtendedStats.Stats[names[i]].ColIDs), len(t.ExtendedStats.Stats[names[j]].ColIDs)
+		if li != lj {
+			return li > lj
+		}
+		return names[i] < names[j]
+	})
+	var groups []*ColGroupMCV
+OUTER:
+	for _, name := range names {
+		item := t.ExtendedStats.Stats[name]
+		uniqueIDs := make([]int64, 0, len(item.ColIDs))
+		for _, id := range item.ColIDs {
+			uniqueID, ok := id2UniqueID[id]
+			if !ok {
+				continue OUTER
+			}
+			uniqueIDs = append(uniqueIDs, uniqueID)
+		}
+		groups = append(groups, &ColGroupMCV{UniqueIDs: uniqueIDs, MCV: item.MCV})
+	}
+	return groups
+}
+
+// colEqConstant returns the column and the constant of the `col = constant` condition.
+func colEqConstant(expr expression.Expression) (*expression.Column, *expression.Constant) {
+	f, ok := expr.(*expression.ScalarFunction)
+	if !ok || f.FuncName.L != ast.EQ {
+		return nil, nil
+	}
+	args := f.GetArgs()
+	if col, ok := args[0].(*expression.Column); ok {
+		if con, ok := args[1].(*expression.Constant); ok {
+			return col, con
+		}
+	}
+	if col, ok := args[1].(*expression.Column); ok {
+		if con, ok := args[0].(*expression.Constant); ok {
+			return col, con
+		}
+	}
+	return nil, nil
+}
+
+// mcvValueOfConstant converts the constant to the value stored in the MCV keys of the column. It returns false if the
+// constant can't be converted exactly.
+func (coll *HistColl) mcvValueOfConstant(sc *stmtctx.StatementContext, uniqueID int64, con *expression.Constant) (types.Datum, bool) {
+	var d types.Datum
+	// The constants which may change in the cached plans are skipped.
+	if con.ParamMarker != nil || con.DeferredExpr != nil {
+		return d, false
+	}
+	colHist := coll.Columns[uniqueID]
+	if colHist == nil || colHist.Info == nil {
+		return d, false
+	}
+	val, err := con.Eval(chunk.Row{})
+	if err != nil || val.IsNull() {
+		return d, false
+	}
+	ft := &colHist.Info.FieldType
+	if ft.EvalType() == types.ETString && ft.Tp != mysql.TypeEnum && ft.Tp != mysql.TypeSet {
+		if val.Kind() != types.KindString && val.Kind() != types.KindBytes {
+			return d, false
+		}
+		return types.NewBytesDatum(collate.GetCollator(ft.Collate).Key(val.GetString())), true
+	}
+	converted, err := val.ConvertTo(&stmtctx.StatementContext{TimeZone: sc.TimeZone}, ft)
+	if err != nil {
+		return d, false
+	}
+	cmp, err := converted.CompareDatum(sc, &val)
+	if err != nil || cmp != 0 {
+		return d, false
+	}
+	return converted, true
+}
+
+// selectivityByColGroupMCVs estimates the selectivity of the equal conditions covering all the columns of the column
+// groups by their MCV lists. It returns the selectivity and the remaining expressions.
+func (coll *HistColl) selectivityByColGroupMCVs(ctx sessionctx.Context, exprs []expression.Expression) (float64, []expression.Expression, error) {
+	if len(coll.ColGroupMCVs) == 0 {
+		return 1, exprs, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	ret := 1.0
+	used := make([]bool, len(exprs))
+	for _, group := range coll.ColGroupMCVs {
+		if coll.coveredByIndex(group.UniqueIDs) {
+			continue
+		}
+		vals := make([]types.Datum, 0, len(group.UniqueIDs))
+		offsets := make([]int, 0, len(group.UniqueIDs))
+		for _, uniqueID := range group.UniqueIDs {
+			for i, expr := range exprs {
+				if used[i] {
+					continue
+				}
+				col, con := colEqConstant(expr)
+				if col == nil || col.UniqueID != uniqueID {
+					continue
+				}
+				if val, ok := coll.mcvValueOfConstant(sc, uniqueID, con); ok {
+					vals = append(vals, val)
+					offsets = append(offsets, i)
+					break
+				}
+			}
+		}
+		if len(offsets) != len(group.UniqueIDs) {
+			continue
+		}
+		key, err := EncodeMCVKey(sc, vals)
+		if err != nil {
+			return 0, nil, errors.Trace(err)
+		}
+		selectivity, ok := group.MCV.Fraction(key)
+		if !ok {
+			// The combination isn't common, so its fraction is at most the min fraction in the list.
+			selectivity = 1.0
+			for _, offset := range offsets {
+				colSel, err := coll.columnSelectivity(ctx, exprs[offset])
+				if err != nil {
+					return 0, nil, errors.Trace(err)
+				}
+				selectivity *= colSel
+			}
+			if minFraction := group.MCV.MinFraction(); selectivity > minFraction {
+				selectivity = minFraction
+			}
+		}
+		ret *= selectivity
+		for _, offset := range offsets {
+			used[offset] = true
+		}
+	}
+	remained := make([]expression.Expression, 0, len(exprs))
+	for i, expr := range exprs {
+		if !used[i] {
+			remained = append(remained, expr)
+		}
+	}
+	return ret, remained, nil
+}
+
+// coveredByIndex checks whether the columns are the leading columns of an index, whose statistics are more accurate.
+func (coll *HistColl) coveredByIndex(uniqueIDs []int64) bool {
+	for _, colIDs := range coll.Idx2ColumnIDs {
+		if len(colIDs) < len(uniqueIDs) {
+			continue
+		}
+		covered := true
+		for _, id := range uniqueIDs {
+			found := false
+			for _, idxColID := range colIDs[:len(uniqueIDs)] {
+				if idxColID == id {
+					found = true
+					break
+				}
+			}
+			if !found {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// columnSelectivity estimates the selectivity of the `col = constant` condition by the column statistics.
+func (coll *HistColl) columnSelectivity(ctx sessionctx.Context, expr expression.Expression) (float64, error) {
+	col, _ := colEqConstant(expr)
+	colHist := coll.Columns[col.UniqueID]
+	_, ranges, _, err := getMaskAndRanges(ctx, []expression.Expression{expr}, ranger.ColumnRangeType, nil, nil, col)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	var cnt float64
+	if colHist.IsHandle {
+		cnt, err = coll.GetRowCountByIntColumnRanges(sc, col.UniqueID, ranges)
+	} else {
+		cnt, err = coll.GetRowCountByColumnRanges(sc, col.UniqueID, ranges)
+	}
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return cnt / float64(coll.Count), nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMCVList(t *testing.T) {
+	t.Parallel()
+	sc := &stmtctx.StatementContext{}
+	// The sampled rows are (1, 1), (1, 1), (1, 1), (2, 2), (2, null), (null, 3), so the rows having nulls are skipped.
+	newCollector := func(vals []interface{}) *SampleCollector {
+		c := &SampleCollector{}
+		for i, val := range vals {
+			if val == nil {
+				c.NullCount++
+				continue
+			}
+			c.Count++
+			c.Samples = append(c.Samples, &SampleItem{Value: types.NewIntDatum(int64(val.(int))), Ordinal: i})
+		}
+		return c
+	}
+	collectors := []*SampleCollector{
+		newCollector([]interface{}{1, 1, 1, 2, 2, nil}),
+		newCollector([]interface{}{1, 1, 1, 2, nil, 3}),
+	}
+	mcv, err := BuildMCVList(sc, collectors)
+	require.NoError(t, err)
+	require.Len(t, mcv.Items, 2)
+
+	key11, err := EncodeMCVKey(sc, []types.Datum{types.NewIntDatum(1), types.NewIntDatum(1)})
+	require.NoError(t, err)
+	key22, err := EncodeMCVKey(sc, []types.Datum{types.NewIntDatum(2), types.NewIntDatum(2)})
+	require.NoError(t, err)
+	key12, err := EncodeMCVKey(sc, []types.Datum{types.NewIntDatum(1), types.NewIntDatum(2)})
+	require.NoError(t, err)
+	fraction, ok := mcv.Fraction(key11)
+	require.True(t, ok)
+	require.InDelta(t, 0.5, fraction, 1e-9)
+	fraction, ok = mcv.Fraction(key22)
+	require.True(t, ok)
+	require.InDelta(t, 1.0/6, fraction, 1e-9)
+	_, ok = mcv.Fraction(key12)
+	require.False(t, ok)
+	require.InDelta(t, 1.0/6, mcv.MinFraction(), 1e-9)
+
+	// The MCV list is stored as a string in mysql.stats_extended.
+	str, err := EncodeMCVList(mcv)
+	require.NoError(t, err)
+	decoded, err := DecodeMCVList(str)
+	require.NoError(t, err)
+	require.Equal(t, mcv, decoded)
+
+	// The same decimals are encoded to the same key, no matter what the precision and frac of the datums are.
+	dec := new(types.MyDecimal)
+	require.NoError(t, dec.FromString([]byte("1.500")))
+	d1, d2 := types.NewDecimalDatum(dec), types.NewDecimalDatum(dec)
+	d2.SetLength(10)
+	d2.SetFrac(3)
+	key1, err := EncodeMCVKey(sc, []types.Datum{d1})
+	require.NoError(t, err)
+	key2, err := EncodeMCVKey(sc, []types.Datum{d2})
+	require.NoError(t, err)
+	require.Equal(t, key1, key2)
+}
@@ -211,6 +211,13 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 		}
 	}
 
+	// Deal with the equal conditions on the column groups having MCV lists.
+	mcvSelectivity, remainedExprs, err := coll.selectivityByColGroupMCVs(ctx, remainedExprs)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	ret *= mcvSelectivity
+
 	extractedCols := make([]*expression.Column, 0, len(coll.Columns))
 	extractedCols = expression.ExtractColumnsFromExpressions(extractedCols, remainedExprs, nil)
 	for id, colInfo := range coll.Columns {
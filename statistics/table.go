@@ -77,6 +77,8 @@ type ExtendedStatsItem struct {
 	Tp         uint8
 	ScalarVals float64
 	StringVals string
+	// MCV is decoded from the StringVals of the StatsTypeMCV extended stats.
+	MCV *MCVList
 }
 
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
@@ -106,6 +108,10 @@ type HistColl struct {
 	// The physical id is used when try to load column stats from storage.
 	HavePhysicalID bool
 	Pseudo         bool
+
+	// ColGroupMCVs are the MCV lists of the column groups used to calculate the selectivity. They are only set for
+	// the HistColl generated for a DataSource when the extended statistics are enabled.
+	ColGroupMCVs []*ColGroupMCV
 }
 
 // MemoryUsage returns the total memory usage of this Table.
@@ -0,0 +1,79 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta and the evaluable expression of a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	// ConstraintExpr is the check expression resolved against the public columns of the table,
+	// the index of each column in the expression is the offset of the column.
+	ConstraintExpr expression.Expression
+}
+
+// IsWritable checks whether the constraint should be checked for the written rows.
+// Writable states includes WriteOnly, WriteReorganization and Public.
+func (c *Constraint) IsWritable() bool {
+	if !c.Enforced {
+		return false
+	}
+	switch c.State {
+	case model.StateWriteOnly, model.StateWriteReorganization, model.StatePublic:
+		return true
+	}
+	return false
+}
+
+// CheckConstraintTable is implemented by the tables which carry check constraints.
+type CheckConstraintTable interface {
+	// WritableConstraints returns the enforced check constraints which the written rows must satisfy.
+	WritableConstraints() []*Constraint
+}
+
+// WritableConstraints returns the enforced check constraints of the table in writable states.
+// It returns nil if the table doesn't carry any check constraint.
+func WritableConstraints(t Table) []*Constraint {
+	if ct, ok := t.(CheckConstraintTable); ok {
+		return ct.WritableConstraints()
+	}
+	return nil
+}
+
+// CheckRowConstraint checks whether the row satisfies the given check constraints.
+// A constraint is satisfied when its expression is evaluated to TRUE or NULL.
+// The datums of the row should be arranged in the order of the column offsets.
+func CheckRowConstraint(ctx sessionctx.Context, constraints []*Constraint, row []types.Datum) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, constraint := range constraints {
+		ok, isNull, err := constraint.ConstraintExpr.EvalInt(ctx, r)
+		if err != nil {
+			return err
+		}
+		if ok == 0 && !isNull {
+			return ErrCheckConstraintViolated.FastGenByArgs(constraint.Name.O)
+		}
+	}
+	return nil
+}
@@ -100,6 +100,14 @@ var (
 	ErrRowDoesNotMatchGivenPartitionSet = dbterror.ClassTable.NewStd(mysql.ErrRowDoesNotMatchGivenPartitionSet)
 	// ErrTempTableFull returns a table is full error, it's used by temporary table now.
 	ErrTempTableFull = dbterror.ClassTable.NewStd(mysql.ErrRecordFileFull)
+	// ErrCheckConstraintViolated returns when a written row violates a check constraint.
+	ErrCheckConstraintViolated = dbterror.ClassTable.NewStd(mysql.ErrCheckConstraintViolated)
+	// ErrNoReferencedRow2 returns when a written child row refers to a parent row that doesn't exist.
+	ErrNoReferencedRow2 = dbterror.ClassTable.NewStd(mysql.ErrNoReferencedRow2)
+	// ErrRowIsReferenced2 returns when a deleted or updated parent row is still referenced by child rows.
+	ErrRowIsReferenced2 = dbterror.ClassTable.NewStd(mysql.ErrRowIsReferenced2)
+	// ErrFkDepthExceeded returns when the foreign key cascade goes too deep.
+	ErrFkDepthExceeded = dbterror.ClassTable.NewStd(mysql.ErrFkDepthExceeded)
 )
 
 // RecordIterFunc is used for low-level record iteration.
@@ -0,0 +1,71 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+// initTableConstraints initializes the check constraints of the TableCommon.
+// The constraint expressions are built on the public columns of the table,
+// so the row to check must be arranged in the order of the column offsets.
+func initTableConstraints(t *TableCommon) error {
+	tblInfo := t.meta
+	if len(tblInfo.Constraints) == 0 {
+		return nil
+	}
+	ctx := mock.NewContext()
+	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
+	columns, names, err := expression.ColumnInfos2ColumnsAndNames(ctx, dbName, tblInfo.Name, tblInfo.Cols(), tblInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	schema := expression.NewSchema(columns...)
+	p := parser.New()
+	t.Constraints = make([]*table.Constraint, 0, len(tblInfo.Constraints))
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.State == model.StateNone {
+			continue
+		}
+		expr, err := parseSimpleExprWithNames(p, ctx, constraintInfo.ExprString, schema, names)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		t.Constraints = append(t.Constraints, &table.Constraint{
+			ConstraintInfo: constraintInfo,
+			ConstraintExpr: expr,
+		})
+	}
+	return nil
+}
+
+// WritableConstraints implements table.CheckConstraintTable WritableConstraints interface.
+func (t *TableCommon) WritableConstraints() []*table.Constraint {
+	if len(t.Constraints) == 0 {
+		return nil
+	}
+	writableConstraints := make([]*table.Constraint, 0, len(t.Constraints))
+	for _, constraint := range t.Constraints {
+		if constraint.IsWritable() {
+			writableConstraints = append(writableConstraints, constraint)
+		}
+	}
+	return writableConstraints
+}
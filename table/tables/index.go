@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
@@ -140,10 +141,65 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 	return tablecodec.GenIndexKey(sc, c.tblInfo, c.idxInfo, idxTblID, indexedValues, h, buf)
 }
 
+// mvIndexValues expands the indexed values of a multi-valued index, every distinct element of the array column
+// makes up the values of an index entry. An empty array generates no entry and NULL generates a NULL entry.
+func (c *index) mvIndexValues(sc *stmtctx.StatementContext, indexedValues []types.Datum) ([][]types.Datum, error) {
+	for i, idxCol := range c.idxInfo.Columns {
+		col := c.tblInfo.Columns[idxCol.Offset]
+		if !col.FieldType.Array || indexedValues[i].Kind() != types.KindMysqlJSON {
+			continue
+		}
+		elemTp := col.FieldType.Clone()
+		elemTp.Array = false
+		arr := indexedValues[i].GetMysqlJSON()
+		elemCount := arr.GetElemCount()
+		elems := make([]json.BinaryJSON, 0, elemCount)
+		valsList := make([][]types.Datum, 0, elemCount)
+	nextElem:
+		for j := 0; j < elemCount; j++ {
+			elem := arr.ArrayGetElem(j)
+			for _, e := range elems {
+				if json.CompareBinary(e, elem) == 0 {
+					continue nextElem
+				}
+			}
+			elems = append(elems, elem)
+			d := types.NewDatumFromJSONScalar(elem, elemTp.Collate)
+			d, err := d.ConvertTo(sc, elemTp)
+			if err != nil {
+				return nil, err
+			}
+			vals := make([]types.Datum, len(indexedValues))
+			copy(vals, indexedValues)
+			vals[i] = d
+			valsList = append(valsList, vals)
+		}
+		return valsList, nil
+	}
+	return [][]types.Datum{indexedValues}, nil
+}
+
 // Create creates a new entry in the kvIndex data.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
+// For a multi-valued index, Create creates an entry for every element of the array column.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if !c.idxInfo.MVIndex {
+		return c.create(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+	}
+	valsList, err := c.mvIndexValues(sctx.GetSessionVars().StmtCtx, indexedValues)
+	if err != nil {
+		return nil, err
+	}
+	for _, vals := range valsList {
+		if dupHandle, err := c.create(sctx, txn, vals, h, handleRestoreData, opts...); err != nil {
+			return dupHandle, err
+		}
+	}
+	return nil, nil
+}
+
+func (c *index) create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -229,6 +285,22 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	if !c.idxInfo.MVIndex {
+		return c.delete(sc, txn, indexedValues, h)
+	}
+	valsList, err := c.mvIndexValues(sc, indexedValues)
+	if err != nil {
+		return err
+	}
+	for _, vals := range valsList {
+		if err := c.delete(sc, txn, vals, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *index) delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
@@ -355,6 +427,16 @@ func FindChangingCol(cols []*table.Column, idxInfo *model.IndexInfo) *table.Colu
 	return nil
 }
 
+// FindAddingCol finds the column being added from the columns of the index.
+func FindAddingCol(cols []*table.Column, idxInfo *model.IndexInfo) *table.Column {
+	for _, ic := range idxInfo.Columns {
+		if col := cols[ic.Offset]; col.State != model.StatePublic && col.ChangeStateInfo == nil {
+			return col
+		}
+	}
+	return nil
+}
+
 // IsIndexWritable check whether the index is writable.
 func IsIndexWritable(idx table.Index) bool {
 	s := idx.Meta().State
@@ -81,6 +81,9 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+	// reorgTable is the other partitioning scheme when the partitioning of the table is being changed,
+	// the records are written to both of the schemes.
+	reorgTable *partitionedTable
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -110,6 +113,12 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if pi.DDLState != model.StateNone {
+		ret.reorgTable, err = newReorganizedPartitionedTable(tbl, tblInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return ret, nil
 }
 
@@ -128,6 +137,8 @@ func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 		return generateHashPartitionExpr(ctx, pi, columns, names)
 	case model.PartitionTypeList:
 		return generateListPartitionExpr(ctx, tblInfo, columns, names)
+	case model.PartitionTypeKey:
+		return generateKeyPartitionExpr(pi, columns, names)
 	}
 	panic("cannot reach here")
 }
@@ -149,6 +160,8 @@ type PartitionExpr struct {
 	// InValues: x in (1,2); x in (3,4); x in (5,6), used for list partition.
 	InValues []expression.Expression
 	*ForListPruning
+	// Used in the key partition pruning process.
+	*ForKeyPruning
 }
 
 func initEvalBufferType(t *partitionedTable) {
@@ -924,6 +937,8 @@ func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.Par
 		idx, err = t.locateHashPartition(ctx, pi, r)
 	case model.PartitionTypeList:
 		idx, err = t.locateListPartition(ctx, pi, r)
+	case model.PartitionTypeKey:
+		idx, err = t.locateKeyPartition(ctx, pi, r)
 	}
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -1126,7 +1141,14 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = t.reorgAddRecord(ctx, recordID, r); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return recordID, nil
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1170,7 +1192,10 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	if err = tbl.RemoveRecord(ctx, h, r); err != nil {
+		return errors.Trace(err)
+	}
+	return t.reorgRemoveRecord(ctx, h, r)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1215,7 +1240,7 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err := t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1230,11 +1255,14 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
-		return nil
+		return t.reorgUpdateRecord(ctx, h, newHandle, currData, newData)
 	}
 
 	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+		return errors.Trace(err)
+	}
+	return t.reorgUpdateRecord(ctx, h, h, currData, newData)
 }
 
 // FindPartitionByName finds partition in table meta by name.
@@ -0,0 +1,243 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"math"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+// ForKeyPruning is used for key partition pruning.
+type ForKeyPruning struct {
+	// KeyPartCols are the partition key columns, in the order of the partition definition.
+	KeyPartCols []*expression.Column
+}
+
+// LocatePartition locates the key partition by the values of the partition key columns. The values
+// must have been converted to the types of the columns.
+func (kp *ForKeyPruning) LocatePartition(sc *stmtctx.StatementContext, pi *model.PartitionInfo, vals []types.Datum) (int, error) {
+	hash, err := kp.hash(sc, vals)
+	if err != nil {
+		return 0, err
+	}
+	return keyPartitionIdx(pi, hash), nil
+}
+
+// hash calculates the hash value of the partition key like `calculate_key_hash_value` in MySQL, so the rows
+// of the tables imported from MySQL are put into the same partitions.
+func (kp *ForKeyPruning) hash(sc *stmtctx.StatementContext, vals []types.Datum) (uint32, error) {
+	nr1, nr2 := uint64(1), uint64(4)
+	for i, col := range kp.KeyPartCols {
+		if vals[i].IsNull() {
+			nr1 ^= (nr1 << 1) | 1
+			continue
+		}
+		image, err := keyPartitionFieldImage(sc, col.RetType, vals[i])
+		if err != nil {
+			return 0, err
+		}
+		for _, b := range image {
+			nr1 ^= (((nr1 & 63) + nr2) * uint64(b)) + (nr1 << 8)
+			nr2 += 3
+		}
+	}
+	return uint32(nr1), nil
+}
+
+// keyPartitionIdx gets the partition index of the hash value, see `get_part_id_key` and
+// `get_part_id_from_linear_hash` in MySQL.
+func keyPartitionIdx(pi *model.PartitionInfo, hash uint32) int {
+	num := uint32(pi.Num)
+	if !pi.Linear {
+		return int(hash % num)
+	}
+	mask := uint32(1)
+	for mask < num {
+		mask <<= 1
+	}
+	mask--
+	idx := hash & mask
+	if idx >= num {
+		idx = hash & (((mask + 1) >> 1) - 1)
+	}
+	return int(idx)
+}
+
+// keyPartitionFieldImage returns the bytes that MySQL hashes for the value. It is the row format of MySQL for
+// the non-string types, and the collation weights for the string types.
+func keyPartitionFieldImage(sc *stmtctx.StatementContext, ft *types.FieldType, d types.Datum) ([]byte, error) {
+	switch ft.Tp {
+	case mysql.TypeTiny:
+		return appendLittleEndian(nil, uint64(d.GetInt64()), 1), nil
+	case mysql.TypeShort:
+		return appendLittleEndian(nil, uint64(d.GetInt64()), 2), nil
+	case mysql.TypeInt24:
+		return appendLittleEndian(nil, uint64(d.GetInt64()), 3), nil
+	case mysql.TypeLong:
+		return appendLittleEndian(nil, uint64(d.GetInt64()), 4), nil
+	case mysql.TypeLonglong:
+		return appendLittleEndian(nil, uint64(d.GetInt64()), 8), nil
+	case mysql.TypeYear:
+		y := d.GetInt64()
+		if y != 0 {
+			y -= 1900
+		}
+		return []byte{byte(y)}, nil
+	case mysql.TypeFloat:
+		return appendLittleEndian(nil, uint64(math.Float32bits(float32(d.GetFloat64()))), 4), nil
+	case mysql.TypeDouble:
+		return appendLittleEndian(nil, math.Float64bits(d.GetFloat64()), 8), nil
+	case mysql.TypeNewDecimal:
+		return d.GetMysqlDecimal().ToBin(ft.Flen, ft.Decimal)
+	case mysql.TypeDate:
+		t := d.GetMysqlTime()
+		return appendLittleEndian(nil, uint64(t.Year()*512+t.Month()*32+t.Day()), 3), nil
+	case mysql.TypeDatetime:
+		t := d.GetMysqlTime()
+		ymd := uint64((t.Year()*13+t.Month())<<5 | t.Day())
+		hms := uint64(t.Hour()<<12 | t.Minute()<<6 | t.Second())
+		image := appendBigEndian(nil, (ymd<<17|hms)+0x8000000000, 5)
+		return appendKeyPartitionFrac(image, ft.Decimal, int64(t.Microsecond())), nil
+	case mysql.TypeTimestamp:
+		t := d.GetMysqlTime()
+		var sec int64
+		if !t.IsZero() {
+			if sc.TimeZone != nil {
+				if err := t.ConvertTimeZone(sc.TimeZone, time.UTC); err != nil {
+					return nil, err
+				}
+			}
+			gt, err := t.GoTime(time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			sec = gt.Unix()
+		}
+		image := appendBigEndian(nil, uint64(sec), 4)
+		return appendKeyPartitionFrac(image, ft.Decimal, int64(t.Microsecond())), nil
+	case mysql.TypeDuration:
+		return keyPartitionDurationImage(ft.Decimal, d.GetMysqlDuration().Duration), nil
+	case mysql.TypeBit:
+		v, err := d.GetBinaryLiteral().ToInt(sc)
+		if err != nil {
+			return nil, err
+		}
+		return appendBigEndian(nil, v, (ft.Flen+7)/8), nil
+	case mysql.TypeEnum:
+		n := 1
+		if len(ft.Elems) >= 256 {
+			n = 2
+		}
+		return appendLittleEndian(nil, d.GetMysqlEnum().Value, n), nil
+	case mysql.TypeSet:
+		n := (len(ft.Elems) + 7) / 8
+		if n > 4 {
+			n = 8
+		}
+		return appendLittleEndian(nil, d.GetMysqlSet().Value, n), nil
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString:
+		s := d.GetString()
+		// BINARY(N) is padded with 0x00 in MySQL.
+		if ft.Tp == mysql.TypeString && ft.Collate == charset.CollationBin && len(s) < ft.Flen {
+			image := make([]byte, ft.Flen)
+			copy(image, s)
+			return image, nil
+		}
+		return collate.HashSortKey(ft.Collate, s), nil
+	}
+	return nil, errors.Errorf("unsupported type %s in key partition", types.TypeStr(ft.Tp))
+}
+
+// keyPartitionDurationImage returns the TIME(fsp) format of MySQL, see `my_time_packed_to_binary`.
+func keyPartitionDurationImage(fsp int, dur time.Duration) []byte {
+	neg := dur < 0
+	if neg {
+		dur = -dur
+	}
+	hour, minute := int64(dur/time.Hour), int64(dur/time.Minute%60)
+	second, usec := int64(dur/time.Second%60), int64(dur/time.Microsecond%1000000)
+	nr := ((hour<<12 | minute<<6 | second) << 24) + usec
+	if neg {
+		nr = -nr
+	}
+	if fsp == 5 || fsp == 6 {
+		return appendBigEndian(nil, uint64(nr+0x800000000000), 6)
+	}
+	image := appendBigEndian(nil, uint64((nr>>24)+0x800000), 3)
+	return appendKeyPartitionFrac(image, fsp, nr%(1<<24))
+}
+
+// appendKeyPartitionFrac appends the fractional part of the temporal types in MySQL format.
+func appendKeyPartitionFrac(b []byte, fsp int, usec int64) []byte {
+	switch fsp {
+	case 1, 2:
+		return append(b, byte(usec/10000))
+	case 3, 4:
+		return appendBigEndian(b, uint64(usec/100), 2)
+	case 5, 6:
+		return appendBigEndian(b, uint64(usec), 3)
+	}
+	return b
+}
+
+func appendLittleEndian(b []byte, v uint64, n int) []byte {
+	for i := 0; i < n; i++ {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+func appendBigEndian(b []byte, v uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+func generateKeyPartitionExpr(pi *model.PartitionInfo, columns []*expression.Column, names types.NameSlice) (*PartitionExpr, error) {
+	keyCols := make([]*expression.Column, 0, len(pi.Columns))
+	offset := make([]int, 0, len(pi.Columns))
+	for _, colName := range pi.Columns {
+		idx := expression.FindFieldNameIdxByColName(names, colName.L)
+		if idx < 0 {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(colName.O)
+		}
+		keyCols = append(keyCols, columns[idx])
+		offset = append(offset, idx)
+	}
+	return &PartitionExpr{
+		ColumnOffset:  offset,
+		ForKeyPruning: &ForKeyPruning{KeyPartCols: keyCols},
+	}, nil
+}
+
+func (t *partitionedTable) locateKeyPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
+	vals := make([]types.Datum, 0, len(t.partitionExpr.ColumnOffset))
+	for _, offset := range t.partitionExpr.ColumnOffset {
+		vals = append(vals, r[offset])
+	}
+	return t.partitionExpr.ForKeyPruning.LocatePartition(ctx.GetSessionVars().StmtCtx, pi, vals)
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+// newReorganizedPartitionedTable creates the partitioned table of the other partitioning scheme when the
+// partitioning of the table is being changed, see model.PartitionInfo.DDLState.
+func newReorganizedPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (*partitionedTable, error) {
+	pi := tblInfo.GetPartitionInfo()
+	defs := pi.ReorganizedDefinitions()
+	meta := tblInfo.Clone()
+	meta.Partition = &model.PartitionInfo{
+		Type:        pi.DDLType,
+		Expr:        pi.DDLExpr,
+		Columns:     pi.DDLColumns,
+		Linear:      pi.DDLLinear,
+		Enable:      true,
+		Definitions: defs,
+		Num:         uint64(len(defs)),
+	}
+	common := *tbl
+	common.meta = meta
+	ret, err := newPartitionedTable(&common, meta)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ret.(*partitionedTable), nil
+}
+
+// GetReorganizedPartitionedTable returns the partitioned table of the other partitioning scheme when the
+// partitioning of the table is being changed. It is used by the DDL worker to backfill the rows.
+func GetReorganizedPartitionedTable(t table.Table) (table.PartitionedTable, error) {
+	pt, ok := t.(*partitionedTable)
+	if !ok || pt.reorgTable == nil {
+		return nil, errors.Errorf("the partitioning of table %s is not being changed", t.Meta().Name.O)
+	}
+	return pt.reorgTable, nil
+}
+
+// reorgAddRecord writes the new record to the other partitioning scheme. It is skipped in the delete only
+// state, the rows are not visible there until all the TiDB servers remove the rows from the both schemes.
+func (t *partitionedTable) reorgAddRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	if t.reorgTable == nil || t.meta.Partition.DDLState == model.StateDeleteOnly {
+		return nil
+	}
+	pid, err := t.reorgTable.locatePartition(ctx, t.reorgTable.meta.Partition, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := t.partitions[pid]; ok {
+		// The partition is not reorganized, the record is already written there.
+		return nil
+	}
+	tblInfo := t.meta
+	if !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle {
+		// Keep the same _tidb_rowid in the both schemes.
+		cols := t.Cols()
+		row := make([]types.Datum, 0, len(cols)+1)
+		row = append(row, r[:len(cols)]...)
+		r = append(row, types.NewIntDatum(h.IntValue()))
+	}
+	_, err = t.reorgTable.GetPartition(pid).AddRecord(ctx, r)
+	return errors.Trace(err)
+}
+
+// reorgRemoveRecord removes the record from the other partitioning scheme.
+func (t *partitionedTable) reorgRemoveRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	if t.reorgTable == nil {
+		return nil
+	}
+	pid, err := t.reorgTable.locatePartition(ctx, t.reorgTable.meta.Partition, r)
+	if err != nil {
+		// The record can't be there if it does not belong to any partition of the scheme.
+		if terror.ErrorEqual(err, table.ErrNoPartitionForGivenValue) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if _, ok := t.partitions[pid]; ok {
+		// The partition is not reorganized, the record is already removed there.
+		return nil
+	}
+	return errors.Trace(t.reorgTable.GetPartition(pid).RemoveRecord(ctx, h, r))
+}
+
+// reorgUpdateRecord updates the record in the other partitioning scheme, newHandle is different from h if the
+// record is moved to another partition of the current scheme.
+func (t *partitionedTable) reorgUpdateRecord(ctx sessionctx.Context, h, newHandle kv.Handle, currData, newData []types.Datum) error {
+	if err := t.reorgRemoveRecord(ctx, h, currData); err != nil {
+		return errors.Trace(err)
+	}
+	return t.reorgAddRecord(ctx, newHandle, newData)
+}
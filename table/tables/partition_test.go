@@ -626,3 +626,48 @@ func TestIssue24746(t *testing.T) {
 	err = tk.ExecToErr("insert into t_24746 partition (p1) values(4,'ERROR, not allowed to read from partition p0',4) on duplicate key update a = a + 1, b = 'ERROR, not allowed to read from p0!'")
 	require.True(t, table.ErrRowDoesNotMatchGivenPartitionSet.Equal(err))
 }
+
+func TestKeyPartition(t *testing.T) {
+	t.Parallel()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3, t4")
+
+	// The rows are distributed in the same way as MySQL.
+	tk.MustExec("create table t1 (a int) partition by key(a) partitions 4")
+	tk.MustExec("insert into t1 values (0), (1), (2), (3), (4), (5), (6), (7), (null)")
+	tk.MustQuery("select * from t1 partition (p0) order by a").Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select * from t1 partition (p1) order by a").Check(testkit.Rows("0", "4"))
+	tk.MustQuery("select * from t1 partition (p2) order by a").Check(testkit.Rows("<nil>", "3", "7"))
+	tk.MustQuery("select * from t1 partition (p3) order by a").Check(testkit.Rows("2", "6"))
+	tk.MustQuery("select * from t1 where a = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select * from t1 where a is null").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("select * from t1 where a in (1, 2) order by a").Check(testkit.Rows("1", "2"))
+	rows := tk.MustQuery("explain format = 'brief' select * from t1 where a = 2").Rows()
+	require.Contains(t, rows[len(rows)-1][3], "partition:p3")
+
+	tk.MustExec("create table t2 (a int) partition by linear key(a) partitions 3")
+	tk.MustExec("insert into t2 values (0), (1), (2), (3), (4), (5), (6), (7), (null)")
+	tk.MustQuery("select * from t2 partition (p0) order by a").Check(testkit.Rows("1", "5"))
+	tk.MustQuery("select * from t2 partition (p1) order by a").Check(testkit.Rows("0", "2", "4", "6"))
+	tk.MustQuery("select * from t2 partition (p2) order by a").Check(testkit.Rows("<nil>", "3", "7"))
+
+	// The string columns are hashed by the collation weights.
+	tk.MustExec("create table t3 (a varchar(10) charset utf8mb4 collate utf8mb4_general_ci, b int) partition by key(a, b) partitions 4")
+	tk.MustExec("insert into t3 values ('a', 1), ('A', 1), ('b', 2), ('c', 3)")
+	tk.MustQuery("select * from t3 partition (p1)").Check(testkit.Rows("b 2"))
+	tk.MustQuery("select * from t3 partition (p2) order by a, b").Check(testkit.Rows("a 1", "A 1", "c 3"))
+	tk.MustQuery("select * from t3 where a = 'c' and b = 3").Check(testkit.Rows("c 3"))
+	tk.MustQuery("select * from t3 where b = 2").Check(testkit.Rows("b 2"))
+
+	// Point get on the primary key.
+	tk.MustExec("create table t4 (a varchar(10) primary key, b datetime(3)) partition by key() partitions 3")
+	tk.MustExec("insert into t4 values ('x', '2021-01-01 10:00:00.123'), ('y', '2021-01-02 10:00:00.456'), ('z', null)")
+	tk.MustQuery("select * from t4 where a = 'y'").Check(testkit.Rows("y 2021-01-02 10:00:00.456"))
+	tk.MustQuery("select * from t4 where a = 'w'").Check(testkit.Rows())
+	tk.MustExec("update t4 set b = null where a = 'x'")
+	tk.MustExec("delete from t4 where a = 'z'")
+	tk.MustQuery("select * from t4 order by a").Check(testkit.Rows("x <nil>", "y 2021-01-02 10:00:00.456"))
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
@@ -42,10 +43,12 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/generatedexpr"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tipb/go-binlog"
@@ -68,6 +71,10 @@ type TableCommon struct {
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
 	sequence                        *sequenceCommon
+	Constraints                     []*table.Constraint
+	// addingGenExprs are the expressions of the stored generated columns being added,
+	// the index of each column in the expression is the offset of the column.
+	addingGenExprs map[int64]expression.Expression
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -141,6 +148,12 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs)
+	if err := initTableConstraints(&t); err != nil {
+		return nil, err
+	}
+	if err := initAddingGeneratedColumns(&t); err != nil {
+		return nil, err
+	}
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
@@ -185,6 +198,26 @@ func initTableIndices(t *TableCommon) error {
 	return nil
 }
 
+// initAddingGeneratedColumns initializes the expressions of the stored generated columns being added.
+// The planner only evaluates the public generated columns, so the values of these columns
+// are evaluated here on the public columns of the written rows.
+func initAddingGeneratedColumns(t *TableCommon) error {
+	for _, col := range t.WritableColumns {
+		if !col.IsGenerated() || !col.GeneratedStored || col.State == model.StatePublic {
+			continue
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(mock.NewContext(), col.GeneratedExprString, t.meta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if t.addingGenExprs == nil {
+			t.addingGenExprs = make(map[int64]expression.Expression)
+		}
+		t.addingGenExprs[col.ID] = expr
+	}
+	return nil
+}
+
 func initTableCommonWithIndices(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators) error {
 	initTableCommon(t, tblInfo, physicalTableID, cols, allocs)
 	return initTableIndices(t)
@@ -378,6 +411,18 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 				}
 				newData[col.Offset] = value
 				touched[col.Offset] = touched[col.DependencyColumnOffset]
+			} else if t.needFillAddingColumn(col) {
+				value, err = t.getAddingColumnValue(ctx, sctx, col, newData, value)
+				if err != nil {
+					return err
+				}
+				var cmp int
+				cmp, err = value.CompareDatum(sctx.GetSessionVars().StmtCtx, &oldData[col.Offset])
+				if err != nil {
+					return err
+				}
+				newData[col.Offset] = value
+				touched[col.Offset] = cmp != 0
 			}
 		} else {
 			value = newData[col.Offset]
@@ -630,6 +675,43 @@ func checkTempTableSize(ctx sessionctx.Context, tmpTable tableutil.TempTable, tb
 	return nil
 }
 
+// needFillAddingColumn checks whether the non-public column is a stored generated column or an AUTO_INCREMENT column
+// being added, whose value should be evaluated or allocated rather than filled with the original default value.
+func (t *TableCommon) needFillAddingColumn(col *table.Column) bool {
+	if _, ok := t.addingGenExprs[col.ID]; ok {
+		return true
+	}
+	return col.ChangeStateInfo == nil && mysql.HasAutoIncrementFlag(col.Flag)
+}
+
+// getAddingColumnValue gets the value of the adding column checked by needFillAddingColumn.
+// The stored generated column is evaluated on the public columns of the row. The AUTO_INCREMENT column keeps
+// the old value if it's allocated, otherwise a new value is allocated. Like inserting rows,
+// zero or NULL means the value hasn't been allocated.
+func (t *TableCommon) getAddingColumnValue(ctx context.Context, sctx sessionctx.Context, col *table.Column, r []types.Datum, oldVal types.Datum) (types.Datum, error) {
+	if expr, ok := t.addingGenExprs[col.ID]; ok {
+		val, err := expr.Eval(chunk.MutRowFromDatums(r[:len(t.PublicColumns)]).ToRow())
+		if err != nil {
+			return types.Datum{}, err
+		}
+		return table.CastValue(sctx, val, col.ColumnInfo, false, false)
+	}
+	if !oldVal.IsNull() {
+		isAllocated, err := oldVal.ToBool(sctx.GetSessionVars().StmtCtx)
+		if err != nil {
+			return types.Datum{}, err
+		}
+		if isAllocated != 0 {
+			return oldVal, nil
+		}
+	}
+	autoID, err := table.AllocAutoIncrementValue(ctx, t, sctx)
+	if err != nil {
+		return types.Datum{}, err
+	}
+	return table.CastValue(sctx, types.NewIntDatum(autoID), col.ColumnInfo, false, false)
+}
+
 // AddRecord implements table.Table AddRecord interface.
 func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (recordID kv.Handle, err error) {
 	txn, err := sctx.Txn(true)
@@ -749,6 +831,24 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 			colIDs = append(colIDs, col.ID)
 			continue
 		}
+		if col.State != model.StatePublic && t.needFillAddingColumn(col) {
+			var oldVal types.Datum
+			if opt.IsUpdate && col.Offset < len(r) {
+				oldVal = r[col.Offset]
+			}
+			value, err = t.getAddingColumnValue(ctx, sctx, col, r, oldVal)
+			if err != nil {
+				return nil, err
+			}
+			if col.Offset < len(r) {
+				r[col.Offset] = value
+			} else {
+				r = append(r, value)
+			}
+			row = append(row, value)
+			colIDs = append(colIDs, col.ID)
+			continue
+		}
 		if col.State != model.StatePublic &&
 			// Update call `AddRecord` will already handle the write only column default value.
 			// Only insert should add default value for write only column.
@@ -1437,6 +1537,12 @@ func (t *TableCommon) Allocators(ctx sessionctx.Context) autoid.Allocators {
 	} else if ctx.GetSessionVars().IDAllocator == nil {
 		// Use an independent allocator for global temporary tables.
 		if t.meta.TempTableType == model.TempTableGlobal {
+			if t.meta.TempTablePreserveRows {
+				if alloc := ctx.GetSessionVars().GetPreservedTempTableAllocator(t.meta); alloc != nil {
+					return autoid.Allocators{alloc}
+				}
+				return t.allocs
+			}
 			if alloc := ctx.GetSessionVars().GetTemporaryTable(t.meta).GetAutoIDAllocator(); alloc != nil {
 				return autoid.Allocators{alloc}
 			}
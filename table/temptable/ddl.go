@@ -46,7 +46,7 @@ type temporaryTableDDL struct {
 }
 
 func (d *temporaryTableDDL) CreateLocalTemporaryTable(db *model.DBInfo, info *model.TableInfo) error {
-	if _, err := ensureSessionData(d.sctx); err != nil {
+	if _, err := EnsureSessionData(d.sctx); err != nil {
 		return err
 	}
 
@@ -65,7 +65,7 @@ func (d *temporaryTableDDL) DropLocalTemporaryTable(schema model.CIStr, tblName
 	}
 
 	getLocalTemporaryTables(d.sctx).RemoveTable(schema, tblName)
-	return d.clearTemporaryTableRecords(tbl.Meta().ID)
+	return clearTemporaryTableRecords(getSessionData(d.sctx), tbl.Meta().ID)
 }
 
 func (d *temporaryTableDDL) TruncateLocalTemporaryTable(schema model.CIStr, tblName model.CIStr) error {
@@ -87,11 +87,10 @@ func (d *temporaryTableDDL) TruncateLocalTemporaryTable(schema model.CIStr, tblN
 		return err
 	}
 
-	return d.clearTemporaryTableRecords(oldTblInfo.ID)
+	return clearTemporaryTableRecords(getSessionData(d.sctx), oldTblInfo.ID)
 }
 
-func (d *temporaryTableDDL) clearTemporaryTableRecords(tblID int64) error {
-	sessionData := getSessionData(d.sctx)
+func clearTemporaryTableRecords(sessionData variable.TemporaryTableData, tblID int64) error {
 	if sessionData == nil {
 		return nil
 	}
@@ -123,6 +122,34 @@ func (d *temporaryTableDDL) clearTemporaryTableRecords(tblID int64) error {
 	return nil
 }
 
+// ClearStalePreservedRows discards the rows of the global temporary tables with ON COMMIT PRESERVE ROWS kept in the
+// session if the tables are altered, truncated or dropped by others after the rows are committed. The DDL doesn't
+// maintain the rows in the sessions, so the stale rows may miss the index entries or belong to a dropped table.
+// It should be called before a transaction starts.
+func ClearStalePreservedRows(sctx sessionctx.Context, is infoschema.InfoSchema) error {
+	sessVars := sctx.GetSessionVars()
+	sessionData := getSessionData(sctx)
+	if sessionData == nil || len(sessVars.PreservedTempTableUpdateTS) == 0 {
+		return nil
+	}
+
+	for tblID, updateTS := range sessVars.PreservedTempTableUpdateTS {
+		tbl, ok := is.TableByID(tblID)
+		if ok && tbl.Meta().UpdateTS <= updateTS {
+			continue
+		}
+
+		if err := clearTemporaryTableRecords(sessionData, tblID); err != nil {
+			return err
+		}
+		delete(sessVars.PreservedTempTableUpdateTS, tblID)
+		if !ok {
+			sessVars.RemovePreservedTempTableAllocator(tblID)
+		}
+	}
+	return nil
+}
+
 func checkLocalTemporaryExistsAndReturn(sctx sessionctx.Context, schema model.CIStr, tblName model.CIStr) (table.Table, error) {
 	ident := ast.Ident{Schema: schema, Name: tblName}
 	localTemporaryTables := getLocalTemporaryTables(sctx)
@@ -142,7 +169,8 @@ func getSessionData(sctx sessionctx.Context) variable.TemporaryTableData {
 	return sctx.GetSessionVars().TemporaryTableData
 }
 
-func ensureSessionData(sctx sessionctx.Context) (variable.TemporaryTableData, error) {
+// EnsureSessionData returns the data of the temporary tables in the session, it's created if it doesn't exist.
+func EnsureSessionData(sctx sessionctx.Context) (variable.TemporaryTableData, error) {
 	sessVars := sctx.GetSessionVars()
 	if sessVars.TemporaryTableData == nil {
 		// Create this txn just for getting a MemBuffer. It's a little tricky
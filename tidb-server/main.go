@@ -63,6 +63,7 @@ import (
 	"github.com/pingcap/tidb/util/profile"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/signal"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/sys/linux"
 	storageSys "github.com/pingcap/tidb/util/sys/storage"
 	"github.com/pingcap/tidb/util/systimemon"
@@ -189,6 +190,7 @@ func main() {
 	setGlobalVars()
 	setCPUAffinity()
 	setupLog()
+	setupStmtSummary()
 	setHeapProfileTracker()
 	setupTracing() // Should before createServer and after setup config.
 	printInfo()
@@ -653,6 +655,12 @@ func setupLog() {
 	util.InternalHTTPClient()
 }
 
+func setupStmtSummary() {
+	cfg := config.GetGlobalConfig()
+	err := stmtsummary.StmtSummaryByDigestMap.SetupPersistence(cfg.StmtSummary)
+	terror.MustNil(err)
+}
+
 func printInfo() {
 	// Make sure the TiDB info is always printed.
 	level := log.GetLevel()
@@ -727,6 +735,7 @@ func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, gracefu
 	closeDomainAndStorage(storage, dom)
 	disk.CleanUp()
 	topsql.Close()
+	stmtsummary.StmtSummaryByDigestMap.ClosePersistence()
 }
 
 func stringToList(repairString string) []string {
@@ -828,6 +828,10 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 	if d.k == KindNull {
 		return Datum{}, nil
 	}
+	if target.Array {
+		// The value of an array type is a JSON array whose elements are already cast to the element type.
+		return d.convertToMysqlJSON(sc, target)
+	}
 	switch target.Tp { // TODO: implement mysql types convert when "CAST() AS" syntax are supported.
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
 		unsigned := mysql.HasUnsignedFlag(target.Flag)
@@ -1966,6 +1970,24 @@ func NewJSONDatum(j json.BinaryJSON) (d Datum) {
 	return d
 }
 
+// NewDatumFromJSONScalar creates a new Datum for a JSON scalar. A JSON string becomes a string Datum with the collation,
+// numbers become numeric Datums, and other JSON values are kept as JSON Datums.
+func NewDatumFromJSONScalar(j json.BinaryJSON, collation string) (d Datum) {
+	switch j.TypeCode {
+	case json.TypeCodeString:
+		d.SetString(string(j.GetString()), collation)
+	case json.TypeCodeInt64:
+		d.SetInt64(j.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(j.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(j.GetFloat64())
+	default:
+		d.SetMysqlJSON(j)
+	}
+	return d
+}
+
 // NewBinaryLiteralDatum creates a new BinaryLiteral Datum for a BinaryLiteral value.
 func NewBinaryLiteralDatum(b BinaryLiteral) (d Datum) {
 	d.SetBinaryLiteral(b)
@@ -1021,6 +1021,59 @@ func ContainsBinary(obj, target BinaryJSON) bool {
 	}
 }
 
+// MemberOfBinary checks whether the target is a member of the JSON document according the following rules:
+// 1) if the document is an array, the target is a member if it is equal to some element of the array;
+// 2) otherwise, the target is a member if it is equal to the document.
+func MemberOfBinary(target, obj BinaryJSON) bool {
+	if obj.TypeCode != TypeCodeArray {
+		return CompareBinary(obj, target) == 0
+	}
+	elemCount := obj.GetElemCount()
+	for i := 0; i < elemCount; i++ {
+		if CompareBinary(obj.arrayGetElem(i), target) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OverlapsBinary checks whether two JSON documents have any key-value pairs or array elements in common:
+// 1) two arrays overlap if they share at least one element;
+// 2) two objects overlap if they share at least one key with equal values;
+// 3) an array and a nonarray overlap if the nonarray is an element of the array;
+// 4) otherwise, two documents overlap if they are equal.
+func OverlapsBinary(a, b BinaryJSON) bool {
+	if a.TypeCode != TypeCodeArray && b.TypeCode == TypeCodeArray {
+		a, b = b, a
+	}
+	switch a.TypeCode {
+	case TypeCodeArray:
+		if b.TypeCode != TypeCodeArray {
+			return MemberOfBinary(b, a)
+		}
+		elemCount := b.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if MemberOfBinary(b.arrayGetElem(i), a) {
+				return true
+			}
+		}
+		return false
+	case TypeCodeObject:
+		if b.TypeCode != TypeCodeObject {
+			return false
+		}
+		elemCount := b.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if val, exists := a.objectSearchKey(b.objectGetKey(i)); exists && CompareBinary(val, b.objectGetVal(i)) == 0 {
+				return true
+			}
+		}
+		return false
+	default:
+		return CompareBinary(a, b) == 0
+	}
+}
+
 // GetElemDepth for JSON_DEPTH
 // Returns the maximum depth of a JSON document
 // rules referenced by MySQL JSON_DEPTH function
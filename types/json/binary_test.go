@@ -400,6 +400,52 @@ func TestBinaryJSONContains(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONMemberOfAndOverlaps(t *testing.T) {
+	t.Parallel()
+
+	var memberOfTests = []struct {
+		target   string
+		obj      string
+		expected bool
+	}{
+		{`1`, `[1,2]`, true},
+		{`3`, `[1,2]`, false},
+		{`"1"`, `[1,2]`, false},
+		{`[1]`, `[[1],2]`, true},
+		{`[1]`, `[1,2]`, false},
+		{`{"a":1}`, `[{"a":1}]`, true},
+		{`1`, `1`, true},
+		{`{"a":1}`, `{"a":1}`, true},
+	}
+	for _, test := range memberOfTests {
+		target := mustParseBinaryFromString(t, test.target)
+		obj := mustParseBinaryFromString(t, test.obj)
+		require.Equal(t, test.expected, MemberOfBinary(target, obj))
+	}
+
+	var overlapsTests = []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{`[1,2]`, `[2,3]`, true},
+		{`[1,2]`, `[3,4]`, false},
+		{`[1,2]`, `2`, true},
+		{`2`, `[1,2]`, true},
+		{`[[1,2]]`, `[1,2]`, false},
+		{`{"a":1,"b":2}`, `{"b":2,"c":3}`, true},
+		{`{"a":1,"b":2}`, `{"b":3}`, false},
+		{`{"a":1}`, `[{"a":1}]`, true},
+		{`{"a":1}`, `1`, false},
+		{`1`, `1`, true},
+	}
+	for _, test := range overlapsTests {
+		a := mustParseBinaryFromString(t, test.a)
+		b := mustParseBinaryFromString(t, test.b)
+		require.Equal(t, test.expected, OverlapsBinary(a, b))
+	}
+}
+
 func TestBinaryJSONCopy(t *testing.T) {
 	t.Parallel()
 
@@ -111,6 +111,9 @@ func IsJobRollbackable(job *model.Job) bool {
 		}
 	case model.ActionAddTablePartition:
 		return job.SchemaState == model.StateNone || job.SchemaState == model.StateReplicaOnly
+	case model.ActionAlterTablePartitioning, model.ActionRemovePartitioning, model.ActionReorganizePartition:
+		// The table is read from the new partitions in StateDeleteReorganization.
+		return job.SchemaState != model.StateDeleteReorganization
 	case model.ActionDropColumn, model.ActionDropColumns, model.ActionDropTablePartition,
 		model.ActionRebaseAutoID, model.ActionShardRowID,
 		model.ActionTruncateTable, model.ActionAddForeignKey,
@@ -125,7 +128,8 @@ func IsJobRollbackable(job *model.Job) bool {
 
 // MayNeedBackfill returns whether the action type may need to backfill the data.
 func MayNeedBackfill(tp model.ActionType) bool {
-	return tp == model.ActionAddIndex || tp == model.ActionAddPrimaryKey || tp == model.ActionModifyColumn
+	return tp == model.ActionAddIndex || tp == model.ActionAddPrimaryKey || tp == model.ActionModifyColumn || tp == model.ActionAddColumn ||
+		tp == model.ActionAlterTablePartitioning || tp == model.ActionRemovePartitioning || tp == model.ActionReorganizePartition
 }
 
 // CancelJobs cancels the DDL jobs.
@@ -170,6 +170,10 @@ func bytesToI64Slice(b []byte) (i64s []int64) {
 const varElemLen = -1
 
 func getFixedLen(colType *types.FieldType) int {
+	if colType.Array {
+		// The value of an array type is a JSON array.
+		return varElemLen
+	}
 	switch colType.Tp {
 	case mysql.TypeFloat:
 		return 4
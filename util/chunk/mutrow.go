@@ -82,6 +82,9 @@ func MutRowFromTypes(types []*types.FieldType) MutRow {
 }
 
 func zeroValForType(tp *types.FieldType) interface{} {
+	if tp.Array {
+		return json.CreateBinary(nil)
+	}
 	switch tp.Tp {
 	case mysql.TypeFloat:
 		return float32(0)
@@ -341,3 +341,28 @@ func init() {
 	newCollatorMap["utf8mb4_zh_pinyin_tidb_as_cs"] = &zhPinyinTiDBASCSCollator{}
 	newCollatorIDMap[CollationName2ID("utf8mb4_zh_pinyin_tidb_as_cs")] = &zhPinyinTiDBASCSCollator{}
 }
+
+// HashSortKey returns the bytes which MySQL feeds to its hash function when it hashes str under the
+// collation, see `hash_sort` of the collations in MySQL. It is used by key partitioning to distribute
+// rows the same way as MySQL, so the result does not depend on whether the new collation is enabled.
+func HashSortKey(collate string, str string) []byte {
+	switch collate {
+	case charset.CollationBin:
+		return []byte(str)
+	case "utf8_general_ci", "utf8mb4_general_ci":
+		// MySQL hashes the low byte of the weight first.
+		str = truncateTailingSpace(str)
+		buf := make([]byte, 0, len(str)*2)
+		r := rune(0)
+		for i := 0; i < len(str); {
+			r, i = decodeRune(str, i)
+			w := convertRuneGeneralCI(r)
+			buf = append(buf, byte(w), byte(w>>8))
+		}
+		return buf
+	}
+	if collator, ok := newCollatorMap[collate]; ok {
+		return collator.Key(str)
+	}
+	return []byte(truncateTailingSpace(str))
+}
@@ -180,3 +180,18 @@ func TestGetCollator(t *testing.T) {
 	require.IsType(t, &gbkBinCollator{}, GetCollator("gbk_bin"))
 	require.IsType(t, &gbkBinCollator{}, GetCollatorByID(87))
 }
+
+func TestHashSortKey(t *testing.T) {
+	// The result should not depend on whether the new collation is enabled.
+	for _, enabled := range []bool{false, true} {
+		SetNewCollationEnabledForTest(enabled)
+		require.Equal(t, []byte("a  "), HashSortKey("binary", "a  "))
+		require.Equal(t, []byte("a"), HashSortKey("utf8mb4_bin", "a  "))
+		require.Equal(t, []byte("A"), HashSortKey("latin1_bin", "A"))
+		require.Equal(t, []byte{0x41, 0x00, 0x42, 0x00}, HashSortKey("utf8mb4_general_ci", "ab "))
+		require.Equal(t, HashSortKey("utf8_general_ci", "AB"), HashSortKey("utf8_general_ci", "ab"))
+		require.Equal(t, []byte{0x0E, 0x33}, HashSortKey("utf8mb4_unicode_ci", "a "))
+		require.Equal(t, HashSortKey("utf8_unicode_ci", "A"), HashSortKey("utf8_unicode_ci", "a"))
+	}
+	SetNewCollationEnabledForTest(false)
+}
@@ -0,0 +1,434 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// persistInterval is the interval to persist the expired summaries.
+var persistInterval = time.Minute
+
+// persistChanSize is the max number of the pending batches of records to persist.
+const persistChanSize = 1024
+
+// backupTimeFormat is the time format in the names of the rotated history files, which is decided by lumberjack.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// historyPersistence persists the expired summaries into rotating local files, so that the history evicted from
+// memory or lost on restart can still be read from `statements_summary_history`.
+// The summaries of the evicted statements, which are merged into the `other` summary, are not persisted.
+type historyPersistence struct {
+	filename string
+	writer   *lumberjack.Logger
+	// recordsCh passes the records to the persistence goroutine, so that the files are not written while
+	// holding the lock of ssMap or on the path of executing statements.
+	recordsCh chan []*persistedRecord
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// persistedRecord is a persisted summary, which is written into the history file as a JSON line.
+type persistedRecord struct {
+	BeginTime int64    `json:"begin_time"`
+	EndTime   int64    `json:"end_time"`
+	AuthUsers []string `json:"auth_users"`
+	// Columns maps the column names of `statements_summary_history` to the values.
+	Columns map[string]interface{} `json:"columns"`
+}
+
+// SetupPersistence starts to persist the expired summaries if it is enabled in the config.
+func (ssMap *stmtSummaryByDigestMap) SetupPersistence(cfg config.StmtSummary) error {
+	if !cfg.EnablePersistent {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Filename), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	p := &historyPersistence{
+		filename: cfg.Filename,
+		writer: &lumberjack.Logger{
+			Filename:   cfg.Filename,
+			MaxSize:    cfg.FileMaxSize,
+			MaxAge:     cfg.FileMaxDays,
+			MaxBackups: cfg.FileMaxBackups,
+			LocalTime:  true,
+		},
+		recordsCh: make(chan []*persistedRecord, persistChanSize),
+		closeCh:   make(chan struct{}),
+	}
+	ssMap.Lock()
+	ssMap.persistence = p
+	ssMap.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(persistInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case records := <-p.recordsCh:
+				p.write(records)
+			case <-ticker.C:
+				ssMap.persistExpired(time.Now().Unix())
+			case <-p.closeCh:
+				p.drain()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// ClosePersistence persists all the expired summaries and stops the persistence.
+func (ssMap *stmtSummaryByDigestMap) ClosePersistence() {
+	p := ssMap.getPersistence()
+	if p == nil {
+		return
+	}
+	close(p.closeCh)
+	p.wg.Wait()
+	ssMap.persistExpired(time.Now().Unix())
+
+	ssMap.Lock()
+	ssMap.persistence = nil
+	ssMap.Unlock()
+	p.drain()
+	if err := p.writer.Close(); err != nil {
+		logutil.BgLogger().Warn("close statement summary history file failed", zap.Error(err))
+	}
+}
+
+func (ssMap *stmtSummaryByDigestMap) getPersistence() *historyPersistence {
+	ssMap.Lock()
+	defer ssMap.Unlock()
+	return ssMap.persistence
+}
+
+// persistExpired writes the summaries which expire before now and are not persisted yet. It's only called by
+// the persistence goroutine or after the goroutine exits.
+func (ssMap *stmtSummaryByDigestMap) persistExpired(now int64) {
+	ssMap.Lock()
+	p := ssMap.persistence
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+	if p == nil {
+		return
+	}
+	for _, value := range values {
+		p.write(value.(*stmtSummaryByDigest).collectExpired(now))
+	}
+}
+
+// persistElements persists the summaries removed from the history of ssbd.
+func (ssMap *stmtSummaryByDigestMap) persistElements(ssbd *stmtSummaryByDigest, ssElements []*stmtSummaryByDigestElement) {
+	if len(ssElements) == 0 {
+		return
+	}
+	p := ssMap.getPersistence()
+	if p == nil {
+		return
+	}
+	records := make([]*persistedRecord, 0, len(ssElements))
+	for _, ssElement := range ssElements {
+		if record := ssElement.toPersistedRecord(ssbd, time.Now().Unix()); record != nil {
+			records = append(records, record)
+		}
+	}
+	p.persist(records)
+}
+
+// collectExpired converts the summaries which expire before now and are not persisted yet to records.
+func (ssbd *stmtSummaryByDigest) collectExpired(now int64) []*persistedRecord {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+
+	if !ssbd.initialized {
+		return nil
+	}
+	var records []*persistedRecord
+	for listElement := ssbd.history.Front(); listElement != nil; listElement = listElement.Next() {
+		ssElement := listElement.Value.(*stmtSummaryByDigestElement)
+		if record := ssElement.toPersistedRecord(ssbd, now); record != nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// toPersistedRecord converts the summary to a record and marks it persisted. It returns nil if the summary
+// doesn't expire before now or has been persisted.
+func (ssElement *stmtSummaryByDigestElement) toPersistedRecord(ssbd *stmtSummaryByDigest, now int64) *persistedRecord {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+
+	if ssElement.persisted || ssElement.endTime > now {
+		return nil
+	}
+	ssElement.persisted = true
+	record := &persistedRecord{
+		BeginTime: ssElement.beginTime,
+		EndTime:   ssElement.endTime,
+		AuthUsers: make([]string, 0, len(ssElement.authUsers)),
+		Columns:   make(map[string]interface{}, len(columnValueFactoryMap)),
+	}
+	for user := range ssElement.authUsers {
+		record.AuthUsers = append(record.AuthUsers, user)
+	}
+	for name, factory := range columnValueFactoryMap {
+		value := factory(ssElement, ssbd)
+		if t, ok := value.(types.Time); ok {
+			value = t.String()
+		}
+		record.Columns[name] = value
+	}
+	return record
+}
+
+// persist hands the records to the persistence goroutine without blocking. The records are dropped if there
+// are too many pending ones.
+func (p *historyPersistence) persist(records []*persistedRecord) {
+	if len(records) == 0 {
+		return
+	}
+	select {
+	case p.recordsCh <- records:
+	default:
+		logutil.BgLogger().Warn("too many pending statement summary history to persist, drop them", zap.Int("count", len(records)))
+	}
+}
+
+// drain writes the pending records.
+func (p *historyPersistence) drain() {
+	for {
+		select {
+		case records := <-p.recordsCh:
+			p.write(records)
+		default:
+			return
+		}
+	}
+}
+
+func (p *historyPersistence) write(records []*persistedRecord) {
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err == nil {
+			line = append(line, '\n')
+			_, err = p.writer.Write(line)
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("persist statement summary history failed", zap.Error(err))
+			return
+		}
+	}
+}
+
+// historyFiles returns the history files which may contain the summaries in the time range, the current file is the last one.
+func (p *historyPersistence) historyFiles(timeRange *persistedTimeRange) ([]string, error) {
+	dir := filepath.Dir(p.filename)
+	base := filepath.Base(p.filename)
+	ext := filepath.Ext(base)
+	prefix := base[:len(base)-len(ext)] + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	type backupFile struct {
+		name       string
+		rotateTime time.Time
+	}
+	backups := make([]backupFile, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		// The summaries in a rotated file are written before the file is rotated.
+		rotateTime, err := time.ParseInLocation(backupTimeFormat, name[len(prefix):len(name)-len(ext)], time.Local)
+		if err != nil || (timeRange != nil && timeRange.startTime > 0 && rotateTime.Unix() < timeRange.startTime) {
+			continue
+		}
+		backups = append(backups, backupFile{name: name, rotateTime: rotateTime})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotateTime.Before(backups[j].rotateTime) })
+
+	files := make([]string, 0, len(backups)+1)
+	for _, backup := range backups {
+		files = append(files, filepath.Join(dir, backup.name))
+	}
+	return append(files, p.filename), nil
+}
+
+// readRecords reads the persisted records in the time range, and calls fn for each of them.
+func (p *historyPersistence) readRecords(timeRange *persistedTimeRange, fn func(record *persistedRecord) error) error {
+	files, err := p.historyFiles(timeRange)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := readRecordsInFile(file, timeRange, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRecordsInFile(file string, timeRange *persistedTimeRange, fn func(record *persistedRecord) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		// The file may be removed by rotation.
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return errors.Trace(err)
+		}
+		// The last line may be incomplete if it is being written.
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			record := &persistedRecord{}
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			if decodeErr := decoder.Decode(record); decodeErr != nil {
+				logutil.BgLogger().Warn("decode statement summary history failed", zap.String("file", file), zap.Error(decodeErr))
+			} else if timeRange.contains(record) {
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// persistedTimeRange is used to filter the persisted summaries, whose time range overlaps with [startTime, endTime].
+// The times are unix timestamps in seconds, and 0 means unbounded.
+type persistedTimeRange struct {
+	startTime int64
+	endTime   int64
+}
+
+func (r *persistedTimeRange) contains(record *persistedRecord) bool {
+	if r == nil {
+		return true
+	}
+	return (r.startTime == 0 || record.EndTime >= r.startTime) && (r.endTime == 0 || record.BeginTime <= r.endTime)
+}
+
+// persistedKey identifies a summary, which is used to remove the persisted summaries that are still in memory.
+func persistedKey(schemaName, digest, planDigest, prevSQL string, beginTime int64) string {
+	return strings.Join([]string{schemaName, digest, planDigest, prevSQL, strconv.FormatInt(beginTime, 10)}, "\x00")
+}
+
+func (record *persistedRecord) key() string {
+	str := func(name string) string {
+		s, _ := record.Columns[name].(string)
+		return s
+	}
+	return persistedKey(str(SchemaNameStr), str(DigestStr), str(PlanDigestStr), str(PrevSampleTextStr), record.BeginTime)
+}
+
+func (record *persistedRecord) isAuthed(user string) bool {
+	for _, authUser := range record.AuthUsers {
+		if authUser == user {
+			return true
+		}
+	}
+	return false
+}
+
+// toDatums converts the record to a row of the columns.
+func (record *persistedRecord) toDatums(columns []*model.ColumnInfo, instanceAddr string) ([]types.Datum, error) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	datums := make([]types.Datum, len(columns))
+	for i, col := range columns {
+		if col.Name.O == util.ClusterTableInstanceColumnName {
+			datums[i] = types.NewDatum(instanceAddr)
+			continue
+		}
+		switch v := record.Columns[col.Name.O].(type) {
+		case nil:
+			datums[i].SetNull()
+		case bool:
+			datums[i] = types.NewDatum(v)
+		case string:
+			if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime {
+				datums[i] = types.NewStringDatum(v)
+				continue
+			}
+			t, err := types.ParseTime(sc, v, col.Tp, 0)
+			if err != nil {
+				return nil, err
+			}
+			datums[i] = types.NewTimeDatum(t)
+		case json.Number:
+			switch {
+			case col.Tp == mysql.TypeDouble || col.Tp == mysql.TypeFloat:
+				f, err := v.Float64()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				datums[i] = types.NewFloat64Datum(f)
+			case mysql.HasUnsignedFlag(col.Flag):
+				u, err := strconv.ParseUint(v.String(), 10, 64)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				datums[i] = types.NewUintDatum(u)
+			default:
+				n, err := v.Int64()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				datums[i] = types.NewIntDatum(n)
+			}
+		default:
+			return nil, errors.Errorf("unexpected value %v of column %s in statement summary history", v, col.Name.O)
+		}
+	}
+	return datums, nil
+}
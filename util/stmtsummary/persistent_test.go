@@ -0,0 +1,122 @@
+// Copyright 2022 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistHistory(t *testing.T) {
+	t.Parallel()
+	ssMap := newStmtSummaryByDigestMap()
+	err := ssMap.SetRefreshInterval("10", false)
+	require.NoError(t, err)
+	err = ssMap.SetHistorySize("10", false)
+	require.NoError(t, err)
+
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{
+		EnablePersistent: true,
+		Filename:         filename,
+		FileMaxSize:      64,
+		FileMaxDays:      3,
+	}
+	require.NoError(t, ssMap.SetupPersistence(cfg))
+	defer ssMap.ClosePersistence()
+
+	now := time.Now().Unix()
+	stmtExecInfo1 := generateAnyExecInfo()
+	for i := 0; i < 6; i++ {
+		ssMap.beginTimeForCurInterval = now + int64(i+1)*10
+		ssMap.AddStatement(stmtExecInfo1)
+	}
+	reader := newStmtSummaryReaderForTest(ssMap)
+	datums, err := reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 6)
+
+	// All the summaries expire, and each of them is persisted only once.
+	ssMap.persistExpired(now + 100)
+	ssMap.persistExpired(now + 100)
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, 6, bytes.Count(content, []byte("\n")))
+
+	// The persisted summaries which are still in memory are not read again.
+	datums, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 6)
+
+	ssMap.Clear()
+	datums = reader.GetStmtSummaryHistoryRows()
+	require.Len(t, datums, 0)
+	datums, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 6)
+	require.Equal(t, stmtExecInfo1.SchemaName, datums[0][3].GetString())
+	require.Equal(t, stmtExecInfo1.Digest, datums[0][4].GetString())
+	require.Equal(t, int64(1), datums[0][9].GetInt64())
+
+	// Only the summaries overlapping with the time range are read.
+	reader.SetPersistedTimeRange(now+45, 0)
+	datums, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 3)
+	reader.SetPersistedTimeRange(0, now+25)
+	datums, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 2)
+
+	// The summaries of other users are invisible to the users without the process privilege.
+	reader.user = &auth.UserIdentity{Username: "other_user"}
+	reader.hasProcessPriv = false
+	reader.SetPersistedTimeRange(0, 0)
+	datums, err = reader.GetStmtSummaryHistoryRowsWithPersisted()
+	require.NoError(t, err)
+	require.Len(t, datums, 0)
+}
+
+func TestPersistInBackground(t *testing.T) {
+	t.Parallel()
+	ssMap := newStmtSummaryByDigestMap()
+	filename := filepath.Join(t.TempDir(), "tidb-statements.log")
+	cfg := config.StmtSummary{
+		EnablePersistent: true,
+		Filename:         filename,
+		FileMaxSize:      64,
+		FileMaxDays:      3,
+	}
+	require.NoError(t, ssMap.SetupPersistence(cfg))
+
+	// The records are written by the persistence goroutine, and the pending ones are written when closing.
+	p := ssMap.getPersistence()
+	for i := 0; i < 10; i++ {
+		p.persist([]*persistedRecord{{BeginTime: int64(i), EndTime: int64(i + 1)}})
+	}
+	p.persist(nil)
+	ssMap.ClosePersistence()
+	require.Nil(t, ssMap.getPersistence())
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, 10, bytes.Count(content, []byte("\n")))
+}
@@ -40,6 +40,8 @@ type stmtSummaryReader struct {
 	ssMap                *stmtSummaryByDigestMap
 	columnValueFactories []columnValueFactory
 	checker              *stmtSummaryChecker
+	// persistedTimeRange is used to filter the persisted history.
+	persistedTimeRange *persistedTimeRange
 }
 
 // NewStmtSummaryReader return a new statement summaries reader.
@@ -99,6 +101,46 @@ func (ssr *stmtSummaryReader) GetStmtSummaryCurrentRows() [][]types.Datum {
 
 // GetStmtSummaryHistoryRows gets all history statement summaries rows.
 func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
+	return ssr.getStmtSummaryHistoryRows(nil)
+}
+
+// GetStmtSummaryHistoryRowsWithPersisted gets all history statement summaries rows in memory, and the rows persisted
+// in the history files if the persistence is enabled.
+func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRowsWithPersisted() ([][]types.Datum, error) {
+	p := ssr.ssMap.getPersistence()
+	if p == nil {
+		return ssr.GetStmtSummaryHistoryRows(), nil
+	}
+	// The persisted summaries may still be in memory, so the ones in memory are skipped when reading the files.
+	keys := make(map[string]struct{})
+	rows := ssr.getStmtSummaryHistoryRows(keys)
+	err := p.readRecords(ssr.persistedTimeRange, func(record *persistedRecord) error {
+		if _, ok := keys[record.key()]; ok {
+			return nil
+		}
+		if ssr.checker != nil {
+			if digest, _ := record.Columns[DigestStr].(string); !ssr.checker.isDigestValid(digest) {
+				return nil
+			}
+		}
+		if ssr.user != nil && !ssr.hasProcessPriv && !record.isAuthed(ssr.user.Username) {
+			return nil
+		}
+		row, err := record.toDatums(ssr.columns, ssr.instanceAddr)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// getStmtSummaryHistoryRows gets all history statement summaries rows in memory, and adds their keys into keys if it's not nil.
+func (ssr *stmtSummaryReader) getStmtSummaryHistoryRows(keys map[string]struct{}) [][]types.Datum {
 	ssMap := ssr.ssMap
 	ssMap.Lock()
 	values := ssMap.summaryMap.Values()
@@ -112,7 +154,7 @@ func (ssr *stmtSummaryReader) GetStmtSummaryHistoryRows() [][]types.Datum {
 		if ssr.checker != nil && !ssr.checker.isDigestValid(ssbd.digest) {
 			continue
 		}
-		records := ssr.getStmtByDigestHistoryRow(ssbd, historySize)
+		records := ssr.getStmtByDigestHistoryRow(ssbd, historySize, keys)
 		rows = append(rows, records...)
 	}
 
@@ -127,6 +169,12 @@ func (ssr *stmtSummaryReader) SetChecker(checker *stmtSummaryChecker) {
 	ssr.checker = checker
 }
 
+// SetPersistedTimeRange sets the time range of the persisted history to read, the times are unix timestamps in seconds
+// and 0 means unbounded.
+func (ssr *stmtSummaryReader) SetPersistedTimeRange(startTime, endTime int64) {
+	ssr.persistedTimeRange = &persistedTimeRange{startTime: startTime, endTime: endTime}
+}
+
 func (ssr *stmtSummaryReader) getStmtByDigestRow(ssbd *stmtSummaryByDigest, beginTimeForCurInterval int64) []types.Datum {
 	var ssElement *stmtSummaryByDigestElement
 
@@ -158,12 +206,16 @@ func (ssr *stmtSummaryReader) getStmtByDigestElementRow(ssElement *stmtSummaryBy
 	return datums
 }
 
-func (ssr *stmtSummaryReader) getStmtByDigestHistoryRow(ssbd *stmtSummaryByDigest, historySize int) [][]types.Datum {
+func (ssr *stmtSummaryReader) getStmtByDigestHistoryRow(ssbd *stmtSummaryByDigest, historySize int, keys map[string]struct{}) [][]types.Datum {
 	// Collect all history summaries to an array.
 	ssElements := ssbd.collectHistorySummaries(historySize)
 
 	rows := make([][]types.Datum, 0, len(ssElements))
 	for _, ssElement := range ssElements {
+		if keys != nil {
+			// `beginTime` and `prevSQL` won't change since `ssElement` is created, so locking is not needed here.
+			keys[persistedKey(ssbd.schemaName, ssbd.digest, ssbd.planDigest, ssElement.prevSQL, ssElement.beginTime)] = struct{}{}
+		}
 		isAuthed := true
 		if ssr.user != nil && !ssr.hasProcessPriv {
 			_, isAuthed = ssElement.authUsers[ssr.user.Username]
@@ -75,6 +75,9 @@ type stmtSummaryByDigestMap struct {
 
 	// other stores summary of evicted data.
 	other *stmtSummaryByDigestEvicted
+
+	// persistence persists the expired summaries, it's nil if the persistence is not enabled.
+	persistence *historyPersistence
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -201,6 +204,8 @@ type stmtSummaryByDigestElement struct {
 	// pessimistic execution retry information.
 	execRetryCount uint
 	execRetryTime  time.Duration
+	// persisted indicates whether the summary has been written into the history file.
+	persisted bool
 }
 
 // StmtExecInfo records execution information of each statement.
@@ -252,6 +257,12 @@ func newStmtSummaryByDigestMap() *stmtSummaryByDigestMap {
 	}
 	newSsMap.summaryMap.SetOnEvict(func(k kvcache.Key, v kvcache.Value) {
 		historySize := newSsMap.historySize()
+		// The evicted summaries can't be found any more, so persist the expired ones before they are merged.
+		// It's called inside the lock of ssMap, so ssMap.persistence can be read directly, and the records are
+		// written by the persistence goroutine.
+		if p := newSsMap.persistence; p != nil {
+			p.persist(v.(*stmtSummaryByDigest).collectExpired(time.Now().Unix()))
+		}
 		newSsMap.other.AddEvicted(k.(*stmtSummaryByDigestKey), v.(*stmtSummaryByDigest), historySize)
 	})
 	return newSsMap
@@ -320,7 +331,8 @@ func (ssMap *stmtSummaryByDigestMap) AddStatement(sei *StmtExecInfo) {
 	}()
 	// Lock a single entry, not the whole cache.
 	if summary != nil {
-		summary.add(sei, beginTime, intervalSeconds, historySize)
+		removed := summary.add(sei, beginTime, intervalSeconds, historySize)
+		ssMap.persistElements(summary, removed)
 	}
 }
 
@@ -401,6 +413,68 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanCntBindableStmt(cnt int64) []*Bi
 	return stmts
 }
 
+// BindablePlanStats is the execution statistics of a plan of a bindable statement, summed over the history summaries.
+// It is used to detect the plan regressions.
+type BindablePlanStats struct {
+	BindableStmt
+	Digest     string
+	PlanDigest string
+	ExecCount  int64
+	SumLatency time.Duration
+	// LastSeen is the last time the plan is executed.
+	LastSeen time.Time
+}
+
+// GetBindablePlanStats gets the statistics of the plans of users' select/update/delete SQLs which are executed at least
+// minExecCount times.
+func (ssMap *stmtSummaryByDigestMap) GetBindablePlanStats(minExecCount int64) []*BindablePlanStats {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	stats := make([]*BindablePlanStats, 0, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		func() {
+			ssbd.Lock()
+			defer ssbd.Unlock()
+			if !ssbd.initialized || ssbd.planDigest == "" || !(ssbd.stmtType == "Select" || ssbd.stmtType == "Delete" || ssbd.stmtType == "Update") {
+				return
+			}
+			planStats := &BindablePlanStats{
+				BindableStmt: BindableStmt{Schema: ssbd.schemaName},
+				Digest:       ssbd.digest,
+				PlanDigest:   ssbd.planDigest,
+			}
+			isInternal := true
+			for listElement := ssbd.history.Front(); listElement != nil; listElement = listElement.Next() {
+				ssElement := listElement.Value.(*stmtSummaryByDigestElement)
+				ssElement.Lock()
+				planStats.ExecCount += ssElement.execCount
+				planStats.SumLatency += ssElement.sumLatency
+				if ssElement.lastSeen.After(planStats.LastSeen) {
+					planStats.LastSeen = ssElement.lastSeen
+				}
+				// Use the latest sample, as BindableStmt in GetMoreThanCntBindableStmt.
+				planStats.Query = ssElement.sampleSQL
+				planStats.PlanHint = ssElement.planHint
+				planStats.Charset = ssElement.charset
+				planStats.Collation = ssElement.collation
+				if ssElement.prepared {
+					planStats.Query = ssbd.normalizedSQL
+				}
+				// Empty auth users means that it is an internal queries.
+				isInternal = isInternal && len(ssElement.authUsers) == 0
+				ssElement.Unlock()
+			}
+			if !isInternal && planStats.ExecCount >= minExecCount {
+				stats = append(stats, planStats)
+			}
+		}()
+	}
+	return stats
+}
+
 // SetEnabled enables or disables statement summary in global(cluster) or session(server) scope.
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value string, inSession bool) error {
 	if err := ssMap.sysVars.setVariable(typeEnable, value, inSession); err != nil {
@@ -515,7 +589,9 @@ func (ssbd *stmtSummaryByDigest) init(sei *StmtExecInfo, beginTime int64, interv
 	ssbd.initialized = true
 }
 
-func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, intervalSeconds int64, historySize int) {
+// add adds the statement to the summary, and returns the expired summaries removed from the history.
+func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, intervalSeconds int64, historySize int) []*stmtSummaryByDigestElement {
+	var removed []*stmtSummaryByDigestElement
 	// Enclose this block in a function to ensure the lock will always be released.
 	ssElement, isElementNew := func() (*stmtSummaryByDigestElement, bool) {
 		ssbd.Lock()
@@ -546,7 +622,7 @@ func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, interva
 		// `historySize` might be modified anytime, so check expiration every time.
 		// Even if history is set to 0, current summary is still needed.
 		for ssbd.history.Len() > historySize && ssbd.history.Len() > 1 {
-			ssbd.history.Remove(ssbd.history.Front())
+			removed = append(removed, ssbd.history.Remove(ssbd.history.Front()).(*stmtSummaryByDigestElement))
 		}
 
 		return ssElement, isElementNew
@@ -556,6 +632,7 @@ func (ssbd *stmtSummaryByDigest) add(sei *StmtExecInfo, beginTime int64, interva
 	if !isElementNew {
 		ssElement.add(sei, intervalSeconds)
 	}
+	return removed
 }
 
 // collectHistorySummaries puts at most `historySize` summaries to an array.
@@ -17,6 +17,7 @@ package stmtsummary
 import (
 	"container/list"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -1159,6 +1160,40 @@ func TestGetMoreThanCntBindableStmt(t *testing.T) {
 	require.Equal(t, 1, len(stmts))
 }
 
+func TestGetBindablePlanStats(t *testing.T) {
+	t.Parallel()
+	ssMap := newStmtSummaryByDigestMap()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.OriginalSQL = "insert 1"
+	stmtExecInfo1.NormalizedSQL = "insert ?"
+	stmtExecInfo1.StmtCtx.StmtType = "Insert"
+	ssMap.AddStatement(stmtExecInfo1)
+	require.Len(t, ssMap.GetBindablePlanStats(1), 0)
+
+	stmtExecInfo1.NormalizedSQL = "select ?"
+	stmtExecInfo1.Digest = "digest1"
+	stmtExecInfo1.StmtCtx.StmtType = "Select"
+	ssMap.AddStatement(stmtExecInfo1)
+	ssMap.AddStatement(stmtExecInfo1)
+	require.Len(t, ssMap.GetBindablePlanStats(3), 0)
+
+	stmtExecInfo2 := *stmtExecInfo1
+	stmtExecInfo2.PlanDigest = "plan_digest2"
+	stmtExecInfo2.TotalLatency = 3 * stmtExecInfo1.TotalLatency
+	ssMap.AddStatement(&stmtExecInfo2)
+	stats := ssMap.GetBindablePlanStats(1)
+	require.Len(t, stats, 2)
+	sort.Slice(stats, func(i, j int) bool { return stats[i].PlanDigest < stats[j].PlanDigest })
+	require.Equal(t, "digest1", stats[0].Digest)
+	require.Equal(t, stmtExecInfo1.PlanDigest, stats[0].PlanDigest)
+	require.Equal(t, int64(2), stats[0].ExecCount)
+	require.Equal(t, 2*stmtExecInfo1.TotalLatency, stats[0].SumLatency)
+	require.Equal(t, "plan_digest2", stats[1].PlanDigest)
+	require.Equal(t, int64(1), stats[1].ExecCount)
+	require.Equal(t, stmtExecInfo2.TotalLatency, stats[1].SumLatency)
+}
+
 // Test `formatBackoffTypes`.
 func TestFormatBackoffTypes(t *testing.T) {
 	t.Parallel()